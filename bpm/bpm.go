@@ -0,0 +1,110 @@
+package bpm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/juho05/crossonic-server/ffmpeg"
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/juho05/crossonic-server/util"
+	"github.com/juho05/log"
+)
+
+const (
+	// sampleRate is the rate songs are resampled to before estimating the tempo.
+	sampleRate = 11025
+	// excerptDuration is the length of the part of each song that is analyzed.
+	excerptDuration = 90 * time.Second
+	// excerptOffset skips intros of songs that are long enough.
+	excerptOffset = 30 * time.Second
+)
+
+var ErrAlreadyAnalyzing = errors.New("bpm detection already in progress")
+
+// Detector detects the tempo of songs without a BPM tag.
+// Detected values are stored separately and never replace tag values.
+type Detector struct {
+	lock sync.Mutex
+
+	db       repos.DB
+	analyzer *ffmpeg.Analyzer
+}
+
+func New(db repos.DB, analyzer *ffmpeg.Analyzer) *Detector {
+	return &Detector{
+		db:       db,
+		analyzer: analyzer,
+	}
+}
+
+// Analyze detects the tempo of all songs without a BPM tag that have not been analyzed yet.
+// Songs without a detectable beat are marked as analyzed so that they are not decoded again.
+// Returns ErrAlreadyAnalyzing if another analysis is in progress.
+func (d *Detector) Analyze(ctx context.Context) error {
+	if !d.lock.TryLock() {
+		return ErrAlreadyAnalyzing
+	}
+	defer d.lock.Unlock()
+
+	songs, err := d.db.Song().FindMissingBPM(ctx)
+	if err != nil {
+		return fmt.Errorf("detect bpm: find songs: %w", err)
+	}
+	if len(songs) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	log.Infof("Detecting BPM of %d songs...", len(songs))
+
+	var detected, analyzed atomic.Int32
+	err = util.ForEachParallel(ctx, songs, ffmpeg.MaxAnalyzerProcesses, func(song *repos.SongAnalysisInfo) error {
+		bpm, err := d.detect(ctx, song)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Errorf("detect bpm of %s: %s", song.Path, err)
+			}
+			return nil
+		}
+		err = d.db.Song().SetDetectedBPM(ctx, song.ID, bpm)
+		if err != nil {
+			if errors.Is(err, repos.ErrNotFound) {
+				// deleted in the meantime
+				return nil
+			}
+			return fmt.Errorf("set detected bpm of %s: %w", song.ID, err)
+		}
+		analyzed.Add(1)
+		if bpm != nil {
+			detected.Add(1)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("detect bpm: %w", err)
+	}
+	log.Infof("Detected BPM of %d/%d songs in %s.", detected.Load(), analyzed.Load(), time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// detect decodes an excerpt of the song and estimates its tempo. Returns nil if the song does not have a clear beat.
+func (d *Detector) detect(ctx context.Context, song *repos.SongAnalysisInfo) (*int, error) {
+	var offset time.Duration
+	if song.Duration.ToStd() >= excerptOffset+excerptDuration {
+		offset = excerptOffset
+	}
+	samples, err := d.analyzer.Samples(ctx, song.Path, sampleRate, offset, excerptDuration)
+	if err != nil {
+		return nil, err
+	}
+	bpm, ok := Estimate(samples, sampleRate)
+	if !ok {
+		return nil, nil
+	}
+	return util.ToPtr(int(math.Round(bpm))), nil
+}
@@ -0,0 +1,104 @@
+package bpm
+
+import (
+	"math"
+)
+
+const (
+	frameSize = 512
+	hopSize   = 128
+
+	minBPM = 60
+	maxBPM = 200
+	// preferredBPM is the center of the weighting that decides between tempos
+	// that are multiples of each other.
+	preferredBPM = 120
+	// octaveWidth is the standard deviation of the weighting in octaves.
+	octaveWidth = 1.0
+
+	// minConfidence is the minimum ratio between the autocorrelation at the beat period and
+	// the variance of the onset envelope for the tempo to be considered detectable.
+	minConfidence = 0.2
+)
+
+// Estimate estimates the tempo of mono samples with sampleRate in beats per minute.
+// ok is false if the samples are too short or do not have a clear beat.
+func Estimate(samples []float32, sampleRate int) (bpm float64, ok bool) {
+	envelope := onsetEnvelope(samples)
+	framesPerMinute := 60 * float64(sampleRate) / hopSize
+	minLag := int(math.Floor(framesPerMinute / maxBPM))
+	maxLag := int(math.Ceil(framesPerMinute / minBPM))
+	if minLag < 1 || len(envelope) < 2*maxLag {
+		return 0, false
+	}
+
+	var variance float64
+	for _, e := range envelope {
+		variance += e * e
+	}
+	variance /= float64(len(envelope))
+	if variance == 0 {
+		return 0, false
+	}
+
+	correlation := make([]float64, maxLag+2)
+	for lag := minLag - 1; lag <= maxLag+1; lag++ {
+		var sum float64
+		for i := 0; i+lag < len(envelope); i++ {
+			sum += envelope[i] * envelope[i+lag]
+		}
+		correlation[lag] = sum / float64(len(envelope)-lag)
+	}
+
+	bestLag := -1
+	var bestScore float64
+	for lag := minLag; lag <= maxLag; lag++ {
+		if correlation[lag] < correlation[lag-1] || correlation[lag] < correlation[lag+1] {
+			continue
+		}
+		octaves := math.Log2(framesPerMinute / float64(lag) / preferredBPM)
+		score := correlation[lag] * math.Exp(-0.5*(octaves/octaveWidth)*(octaves/octaveWidth))
+		if score > bestScore {
+			bestScore = score
+			bestLag = lag
+		}
+	}
+	if bestLag < 0 || correlation[bestLag]/variance < minConfidence {
+		return 0, false
+	}
+
+	// parabolic interpolation between the neighbouring lags
+	lag := float64(bestLag)
+	prev, cur, next := correlation[bestLag-1], correlation[bestLag], correlation[bestLag+1]
+	if d := prev - 2*cur + next; d != 0 {
+		lag += 0.5 * (prev - next) / d
+	}
+	return framesPerMinute / lag, true
+}
+
+// onsetEnvelope returns the zero-mean positive changes of the log energy of consecutive frames.
+func onsetEnvelope(samples []float32) []float64 {
+	if len(samples) < frameSize {
+		return nil
+	}
+	frameCount := (len(samples)-frameSize)/hopSize + 1
+	envelope := make([]float64, frameCount)
+	var prevEnergy, sum float64
+	for i := range frameCount {
+		var energy float64
+		for _, s := range samples[i*hopSize : i*hopSize+frameSize] {
+			energy += float64(s) * float64(s)
+		}
+		energy = math.Log(energy/frameSize + 1e-10)
+		if i > 0 {
+			envelope[i] = max(energy-prevEnergy, 0)
+			sum += envelope[i]
+		}
+		prevEnergy = energy
+	}
+	mean := sum / float64(frameCount)
+	for i := range envelope {
+		envelope[i] -= mean
+	}
+	return envelope
+}
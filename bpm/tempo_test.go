@@ -0,0 +1,60 @@
+package bpm
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSampleRate = 11025
+
+// clickTrack generates a beat of decaying noise bursts with an accent on every fourth beat.
+func clickTrack(bpm float64, seconds int) []float32 {
+	rng := rand.New(rand.NewPCG(1, 2))
+	samples := make([]float32, seconds*testSampleRate)
+	for i := range samples {
+		samples[i] = float32(rng.NormFloat64() * 0.01)
+	}
+	beat := 60 / bpm * testSampleRate
+	for n := 0; ; n++ {
+		start := int(float64(n) * beat)
+		if start >= len(samples) {
+			return samples
+		}
+		amp := 0.4
+		if n%4 == 0 {
+			amp = 0.8
+		}
+		for i := 0; i < testSampleRate/20 && start+i < len(samples); i++ {
+			decay := math.Exp(-float64(i) / (testSampleRate / 100))
+			samples[start+i] += float32(rng.NormFloat64() * amp * decay)
+		}
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	for _, bpm := range []float64{72, 90, 100, 120, 128, 140, 174} {
+		got, ok := Estimate(clickTrack(bpm, 60), testSampleRate)
+		if assert.True(t, ok, "%.0f BPM", bpm) {
+			assert.InDelta(t, bpm, got, 1.5, "%.0f BPM", bpm)
+		}
+	}
+}
+
+func TestEstimate_noBeat(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+	noise := make([]float32, 60*testSampleRate)
+	for i := range noise {
+		noise[i] = float32(rng.NormFloat64() * 0.3)
+	}
+	_, ok := Estimate(noise, testSampleRate)
+	assert.False(t, ok)
+
+	_, ok = Estimate(make([]float32, 60*testSampleRate), testSampleRate)
+	assert.False(t, ok, "silence")
+
+	_, ok = Estimate(clickTrack(120, 1), testSampleRate)
+	assert.False(t, ok, "too short")
+}
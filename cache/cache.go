@@ -26,18 +26,15 @@ type Cache struct {
 	objectsLock sync.RWMutex
 
 	cleaning atomic.Bool
-
-	close chan<- struct{}
 }
 
+// New loads the cache in cacheDir. Clean has to be called periodically to enforce maxSize and maxUnusedTime.
 func New(cacheDir string, maxSize int64, maxUnusedTime time.Duration) (*Cache, error) {
-	closeFn := make(chan struct{})
 	c := &Cache{
 		dir:           cacheDir,
 		objects:       make(map[string]*Object),
 		maxSize:       maxSize,
 		maxUnusedTime: maxUnusedTime,
-		close:         closeFn,
 	}
 	err := os.MkdirAll(cacheDir, 0755)
 	if err != nil {
@@ -47,20 +44,7 @@ func New(cacheDir string, maxSize int64, maxUnusedTime time.Duration) (*Cache, e
 	if err != nil {
 		return nil, fmt.Errorf("new cache: %w", err)
 	}
-	ticker := time.NewTicker(5 * time.Minute)
-	go func() {
-		c.clean()
-	loop:
-		for {
-			select {
-			case <-closeFn:
-				break loop
-			case <-ticker.C:
-				c.clean()
-			}
-		}
-		ticker.Stop()
-	}()
+	c.Clean()
 	return c, nil
 }
 
@@ -156,7 +140,10 @@ func (c *Cache) evict(o *Object) bool {
 	return true
 }
 
-func (c *Cache) clean() {
+// Clean evicts objects that have not been used for longer than maxUnusedTime and
+// the least recently used objects until the cache is smaller than maxSize.
+// Objects with active readers are never evicted.
+func (c *Cache) Clean() {
 	if !c.cleaning.CompareAndSwap(false, true) {
 		return
 	}
@@ -274,7 +261,6 @@ func (c *Cache) keyFromPath(path string) (string, error) {
 }
 
 func (c *Cache) Close() error {
-	close(c.close)
 	c.objectsLock.Lock()
 	defer c.objectsLock.Unlock()
 	for _, o := range c.objects {
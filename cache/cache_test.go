@@ -174,7 +174,7 @@ func TestCleanEvictsUnused(t *testing.T) {
 	path := c.keyToPath("a")
 
 	time.Sleep(5 * time.Millisecond)
-	c.clean()
+	c.Clean()
 
 	_, ok := c.GetObject("a")
 	assert.False(t, ok)
@@ -190,13 +190,13 @@ func TestCleanKeepsObjectsWithActiveReaders(t *testing.T) {
 	require.NoError(t, err)
 
 	time.Sleep(5 * time.Millisecond)
-	c.clean()
+	c.Clean()
 	_, ok := c.GetObject("a")
 	assert.True(t, ok, "object with an active reader must not be evicted")
 
 	require.NoError(t, r.Close())
 	time.Sleep(5 * time.Millisecond)
-	c.clean()
+	c.Clean()
 	_, ok = c.GetObject("a")
 	assert.False(t, ok, "object must be evicted once the reader is released")
 }
@@ -214,7 +214,7 @@ func TestCleanSizeEviction(t *testing.T) {
 	time.Sleep(2 * time.Millisecond)
 	makeComplete(t, c, "c", payload)
 
-	c.clean()
+	c.Clean()
 
 	// total 60 bytes > 30: evict oldest (a, then b) until at/under maxSize.
 	_, okA := c.GetObject("a")
@@ -546,7 +546,7 @@ func TestConcurrentReadWriteClean(t *testing.T) {
 		}()
 	}
 
-	// dedicated cleaner, mirroring the production ticker (one clean at a time)
+	// dedicated cleaner, mirroring the scheduled cleanup (one clean at a time)
 	stop := make(chan struct{})
 	go func() {
 		for {
@@ -554,7 +554,7 @@ func TestConcurrentReadWriteClean(t *testing.T) {
 			case <-stop:
 				return
 			default:
-				c.clean()
+				c.Clean()
 				time.Sleep(time.Millisecond)
 			}
 		}
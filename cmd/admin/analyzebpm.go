@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/juho05/crossonic-server/bpm"
+	"github.com/juho05/crossonic-server/ffmpeg"
+	"github.com/juho05/crossonic-server/repos"
+)
+
+func analyzeBPM(db repos.DB) error {
+	analyzer, err := ffmpeg.NewAnalyzer()
+	if err != nil {
+		return fmt.Errorf("analyze bpm: %w", err)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	fmt.Println("Detecting BPM of songs without BPM tags...")
+	err = bpm.New(db, analyzer).Analyze(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Done.")
+	return nil
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/juho05/crossonic-server/ffmpeg"
+	"github.com/juho05/crossonic-server/replaygain"
+	"github.com/juho05/crossonic-server/repos"
+)
+
+func analyzeReplayGain(db repos.DB) error {
+	analyzer, err := ffmpeg.NewAnalyzer()
+	if err != nil {
+		return fmt.Errorf("analyze replay gain: %w", err)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	fmt.Println("Analyzing songs without ReplayGain values...")
+	// the transcode cache belongs to the server, normalized transcodes it already cached expire on their own
+	err = replaygain.New(db, analyzer, nil).Analyze(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Done.")
+	return nil
+}
@@ -35,7 +35,7 @@ func genEncryptionKey() error {
 
 func run(args []string, conf config.Config) error {
 	if len(args) < 2 {
-		fmt.Println("USAGE:", args[0], "<command>\n\nCOMMANDS:\n  gen-encryption-key\n  users\n  remove-crossonic-metadata")
+		fmt.Println("USAGE:", args[0], "<command>\n\nCOMMANDS:\n  gen-encryption-key\n  users\n  remove-crossonic-metadata\n  analyze-replaygain\n  analyze-bpm\n  scan-problems")
 		os.Exit(1)
 	}
 	dsn := fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=disable", conf.DBUser, conf.DBPassword, conf.DBHost, conf.DBPort, conf.DBName)
@@ -50,9 +50,15 @@ func run(args []string, conf config.Config) error {
 		err = users(args, db, conf)
 	case "remove-crossonic-metadata":
 		err = removeCrossonicMetadata(args, db, conf)
+	case "analyze-replaygain":
+		err = analyzeReplayGain(db)
+	case "analyze-bpm":
+		err = analyzeBPM(db)
+	case "scan-problems":
+		err = scanProblems(args, db)
 	default:
 		fmt.Println("Unknown command")
-		fmt.Println("USAGE:", args[0], "<command>\n\nCOMMANDS:\n  gen-encryption-key\n  users\n  remove-crossonic-metadata")
+		fmt.Println("USAGE:", args[0], "<command>\n\nCOMMANDS:\n  gen-encryption-key\n  users\n  remove-crossonic-metadata\n  analyze-replaygain\n  analyze-bpm\n  scan-problems")
 		os.Exit(1)
 	}
 
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/fs"
 	"mime"
@@ -32,6 +33,10 @@ func removeCrossonicMetadata(args []string, db repos.DB, conf config.Config) err
 		os.Exit(1)
 	}
 
+	if conf.ReadOnlyLibrary {
+		return errors.New("remove crossonic id: the library is read-only (READ_ONLY_LIBRARY)")
+	}
+
 	var paths []string
 	if len(args) == 4 {
 		paths = []string{args[3]}
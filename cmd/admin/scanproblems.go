@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/juho05/crossonic-server/util"
+)
+
+func scanProblems(args []string, db repos.DB) error {
+	var types []repos.ScanProblemType
+	for _, t := range args[2:] {
+		if !slices.Contains(repos.ScanProblemTypes, repos.ScanProblemType(t)) {
+			fmt.Println("USAGE:", args[0], "scan-problems [type...]\n\nTYPES:\n  "+strings.Join(util.Map(repos.ScanProblemTypes, func(t repos.ScanProblemType) string {
+				return string(t)
+			}), "\n  "))
+			os.Exit(1)
+		}
+		types = append(types, repos.ScanProblemType(t))
+	}
+
+	problems, err := db.ScanProblem().FindAll(context.Background(), repos.FindScanProblemsParams{
+		Types: types,
+	})
+	if err != nil {
+		return fmt.Errorf("find scan problems: %w", err)
+	}
+	fmt.Printf("Scan problems (%d):\n", len(problems))
+	for _, p := range problems {
+		fmt.Printf("  - %s\n      %s: %s\n", p.Path, p.Type, p.Message)
+	}
+	return nil
+}
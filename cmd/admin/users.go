@@ -5,12 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"slices"
 	"time"
 
 	"github.com/juho05/crossonic-server/config"
 	"github.com/juho05/crossonic-server/repos"
-	"github.com/juho05/log"
+	"github.com/juho05/crossonic-server/scanner"
 )
 
 func usersList(db repos.DB) error {
@@ -20,7 +19,11 @@ func usersList(db repos.DB) error {
 	}
 	fmt.Printf("Users (%d):\n", len(users))
 	for _, u := range users {
-		fmt.Println("  -", u.Name)
+		if u.Admin {
+			fmt.Println("  -", u.Name, "(admin)")
+		} else {
+			fmt.Println("  -", u.Name)
+		}
 	}
 	return nil
 }
@@ -58,50 +61,36 @@ func usersCreate(args []string, db repos.DB, conf config.Config) error {
 		return err
 	}
 
-	err = createMusicFolderAssociationsForUser(tx, userName, conf)
+	err = scanner.CreateMusicFolderAssociationsForUser(context.Background(), tx, userName, conf)
 	if err != nil {
 		return fmt.Errorf("create music folder associations: %w", err)
 	}
 
-	err = tx.Commit()
+	// the first user needs to be an admin to manage the server
+	users, err := tx.User().FindAll(context.Background())
 	if err != nil {
-		return fmt.Errorf("commit transaction: %w", err)
+		return fmt.Errorf("find all users: %w", err)
 	}
-
-	fmt.Printf("Created user '%s'.\n", args[3])
-	return nil
-}
-
-func createMusicFolderAssociationsForUser(tx repos.Tx, user string, conf config.Config) error {
-	lastMusicDirConfig, err := tx.System().MusicDirConfig(context.Background())
-	if errors.Is(err, repos.ErrNotFound) {
-		// user associations don't exist yet, nothing to do
-		return nil
-	}
-	if err != nil {
-		return fmt.Errorf("get last music folder: %w", err)
+	admin := len(users) == 1
+	if admin {
+		err = tx.User().Update(context.Background(), userName, repos.UpdateUserParams{
+			Admin: repos.NewOptionalFull(true),
+		})
+		if err != nil {
+			return fmt.Errorf("update user admin flag in db: %w", err)
+		}
 	}
 
-	musicDirs, err := conf.GetMusicDirs()
+	err = tx.Commit()
 	if err != nil {
-		return fmt.Errorf("get music dirs: %w", err)
-	}
-	currentMusicDirConfig := config.GenerateMusicDirConfigString(musicDirs)
-	if lastMusicDirConfig != currentMusicDirConfig {
-		log.Warnf("The music dir configuration has changed since the last scan. Please execute a scan or restart crossonic-server " +
-			"before using this user.")
-		return nil
+		return fmt.Errorf("commit transaction: %w", err)
 	}
 
-	for _, dir := range musicDirs {
-		if dir.Users == nil || slices.Contains(dir.Users, user) {
-			err = tx.MusicFolder().CreateUserAssociations(context.Background(), dir.ID, []string{user})
-			if err != nil {
-				return fmt.Errorf("create user association for music folder %d: %w", dir.ID, err)
-			}
-		}
+	if admin {
+		fmt.Printf("Created user '%s' as admin.\n", args[3])
+	} else {
+		fmt.Printf("Created user '%s'.\n", args[3])
 	}
-
 	return nil
 }
 
@@ -132,7 +121,7 @@ func usersDelete(args []string, db repos.DB) error {
 
 func usersUpdate(args []string, db repos.DB) error {
 	if len(args) < 5 {
-		fmt.Println("USAGE:", args[0], "users update <name/password> <name>")
+		fmt.Println("USAGE:", args[0], "users update <name/password/admin> <name>")
 		os.Exit(1)
 	}
 	switch args[3] {
@@ -140,8 +129,10 @@ func usersUpdate(args []string, db repos.DB) error {
 		return usersChangeName(args[4], db)
 	case "password":
 		return usersChangePassword(args[4], db)
+	case "admin":
+		return usersChangeAdmin(args[4], db)
 	default:
-		fmt.Println("USAGE:", args[0], "users update <name/password> <name>")
+		fmt.Println("USAGE:", args[0], "users update <name/password/admin> <name>")
 		os.Exit(1)
 	}
 	return nil
@@ -184,6 +175,32 @@ func usersChangePassword(user string, db repos.DB) error {
 	return nil
 }
 
+func usersChangeAdmin(user string, db repos.DB) error {
+	u, err := db.User().FindByName(context.Background(), user)
+	if err != nil {
+		if errors.Is(err, repos.ErrNotFound) {
+			return fmt.Errorf("user '%s' does not exist", user)
+		}
+		return fmt.Errorf("find user in db: %w", err)
+	}
+
+	admin := areYouSure(fmt.Sprintf("Should '%s' be an admin?", user), u.Admin)
+
+	err = db.User().Update(context.Background(), user, repos.UpdateUserParams{
+		Admin: repos.NewOptionalFull(admin),
+	})
+	if err != nil {
+		return fmt.Errorf("update user admin flag in db: %w", err)
+	}
+
+	if admin {
+		fmt.Printf("'%s' is now an admin.\n", user)
+	} else {
+		fmt.Printf("'%s' is no longer an admin.\n", user)
+	}
+	return nil
+}
+
 func usersApiKeys(args []string, db repos.DB) error {
 	if len(args) < 5 {
 		fmt.Println("USAGE:", args[0], "users api-keys <command> <user_name>\n\nCOMMANDS:\n  create\n  list\n  delete")
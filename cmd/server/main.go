@@ -15,14 +15,21 @@ import (
 
 	"github.com/joho/godotenv"
 	"github.com/juho05/crossonic-server"
+	"github.com/juho05/crossonic-server/bpm"
 	"github.com/juho05/crossonic-server/cache"
 	"github.com/juho05/crossonic-server/config"
 	"github.com/juho05/crossonic-server/ffmpeg"
 	"github.com/juho05/crossonic-server/handlers"
+	"github.com/juho05/crossonic-server/jukebox"
 	"github.com/juho05/crossonic-server/lastfm"
 	"github.com/juho05/crossonic-server/listenbrainz"
+	"github.com/juho05/crossonic-server/metadata"
+	"github.com/juho05/crossonic-server/podcast"
+	"github.com/juho05/crossonic-server/replaygain"
 	"github.com/juho05/crossonic-server/repos/postgres"
 	"github.com/juho05/crossonic-server/scanner"
+	"github.com/juho05/crossonic-server/scheduler"
+	"github.com/juho05/crossonic-server/similarity"
 	"github.com/juho05/log"
 )
 
@@ -50,7 +57,11 @@ func run(conf config.Config) error {
 	}
 	defer db.Close()
 
-	transcoder, err := ffmpeg.NewTranscoder()
+	transcodingProfiles, err := conf.GetTranscodingProfiles()
+	if err != nil {
+		return err
+	}
+	transcoder, err := ffmpeg.NewTranscoder(transcodingProfiles)
 	if err != nil {
 		return err
 	}
@@ -69,28 +80,88 @@ func run(conf config.Config) error {
 	}
 	defer coverCache.Close()
 
-	mediaScanner, err := scanner.New(db, conf, coverCache, transcodeCache)
+	// 100 MB
+	waveformCache, err := cache.New(filepath.Join(conf.CacheDir, "waveforms"), 1e8, 30*24*time.Hour)
+	if err != nil {
+		return err
+	}
+	defer waveformCache.Close()
+
+	mediaScanner, err := scanner.New(db, conf, coverCache, transcodeCache, waveformCache)
 	if err != nil {
 		return err
 	}
 
 	lBrainz := listenbrainz.New(db, conf)
-	defer lBrainz.Close()
+
+	analyzer, err := ffmpeg.NewAnalyzer()
+	if err != nil {
+		return err
+	}
+	replayGain := replaygain.New(db, analyzer, transcodeCache)
+	bpmDetector := bpm.New(db, analyzer)
+	mediaScanner.OnScanCompleted(func() {
+		// both analyses run at the same time in the process pool of the analyzer
+		go func() {
+			err := bpmDetector.Analyze(context.Background())
+			if err != nil && !errors.Is(err, bpm.ErrAlreadyAnalyzing) {
+				log.Errorf("analyze bpm: %s", err)
+			}
+		}()
+		err := replayGain.Analyze(context.Background())
+		if err != nil && !errors.Is(err, replaygain.ErrAlreadyAnalyzing) {
+			log.Errorf("analyze replay gain: %s", err)
+		}
+	})
+
+	podcasts, err := podcast.New(db, conf, transcodeCache)
+	if err != nil {
+		return err
+	}
+	defer podcasts.Close()
+
+	var lfm *lastfm.LastFm
+	if conf.LastFMApiKey != "" {
+		lfm = lastfm.New(conf.LastFMApiKey)
+	}
+	metadataRefresher := metadata.New(db, lfm)
+
+	sched := newScheduler(conf, db, mediaScanner, lBrainz, metadataRefresher, transcodeCache, coverCache, waveformCache)
+	defer sched.Close()
+
+	mediaScanner.StartWatching(db)
+	defer mediaScanner.StopWatching()
 
 	go func() {
 		err = mediaScanner.Scan(db, false)
-		if err != nil {
+		if err != nil && !errors.Is(err, scanner.ErrScanCanceled) {
 			log.Errorf("scan media: %s", err)
 		}
-		lBrainz.StartPeriodicSync(3 * time.Hour)
+		if conf.ListenBrainzSyncSchedule != nil {
+			err = sched.RunNow(jobListenBrainzSync)
+			if err != nil && !errors.Is(err, scheduler.ErrAlreadyRunning) {
+				log.Errorf("sync listenbrainz: %s", err)
+			}
+		}
+		sched.Start()
+		podcasts.StartPeriodicRefresh(6 * time.Hour)
 	}()
 
-	var lfm *lastfm.LastFm
-	if conf.LastFMApiKey != "" {
-		lfm = lastfm.New(conf.LastFMApiKey)
+	var jBox *jukebox.Jukebox
+	if conf.JukeboxOutput != config.JukeboxOutputDisabled {
+		output, err := jukebox.NewOutput(conf)
+		if err != nil {
+			return err
+		}
+		player, err := ffmpeg.NewPlayer()
+		if err != nil {
+			return err
+		}
+		jBox = jukebox.New(db, player, output)
+		defer jBox.Close()
 	}
 
-	handler, err := handlers.New(conf, db, mediaScanner, lBrainz, lfm, transcoder, transcodeCache, coverCache)
+	handler, err := handlers.New(conf, db, mediaScanner, lBrainz, lfm, transcoder, podcasts, similarity.New(db, lfm), metadataRefresher, sched, jBox, analyzer, transcodeCache, coverCache, waveformCache)
 	defer handler.Close()
 	if err != nil {
 		return fmt.Errorf("create handler: %s", err)
@@ -123,9 +194,8 @@ func run(conf config.Config) error {
 		<-sigint
 		timeout, cancelTimeout := context.WithTimeout(context.Background(), 5*time.Second)
 		log.Info("Shutting down...")
-		if lBrainz != nil {
-			_ = lBrainz.Close()
-		}
+		_ = sched.Close()
+		_ = podcasts.Close()
 		err = server.Shutdown(timeout)
 		if err != nil {
 			log.Errorf("shutdown: %s", err)
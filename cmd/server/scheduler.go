@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/juho05/crossonic-server/cache"
+	"github.com/juho05/crossonic-server/config"
+	"github.com/juho05/crossonic-server/listenbrainz"
+	"github.com/juho05/crossonic-server/metadata"
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/juho05/crossonic-server/scanner"
+	"github.com/juho05/crossonic-server/scheduler"
+	"github.com/juho05/log"
+)
+
+const (
+	jobQuickScan        = "quickScan"
+	jobFullScan         = "fullScan"
+	jobListenBrainzSync = "listenBrainzSync"
+	jobCacheCleanup     = "cacheCleanup"
+	jobMetadataRefresh  = "metadataRefresh"
+)
+
+// newScheduler registers all background jobs with the schedules from the config.
+// The scheduler has to be started with Start.
+func newScheduler(conf config.Config, db repos.DB, mediaScanner *scanner.Scanner, lBrainz *listenbrainz.ListenBrainz, metadataRefresher *metadata.Refresher, caches ...*cache.Cache) *scheduler.Scheduler {
+	sched := scheduler.New()
+
+	scan := func(fullScan bool) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			if ctx.Err() != nil {
+				return nil
+			}
+			// the scanner has its own context, stop the scan when the scheduler is closed
+			done := make(chan struct{})
+			defer close(done)
+			go func() {
+				select {
+				case <-ctx.Done():
+					_ = mediaScanner.Cancel()
+				case <-done:
+				}
+			}()
+			err := mediaScanner.Scan(db, fullScan)
+			if errors.Is(err, scanner.ErrAlreadyScanning) {
+				log.Infof("Skipping scheduled scan because another scan is in progress.")
+				return nil
+			}
+			if errors.Is(err, scanner.ErrScanCanceled) {
+				return nil
+			}
+			return err
+		}
+	}
+	sched.Add(jobQuickScan, conf.QuickScanSchedule, scan(false))
+	sched.Add(jobFullScan, conf.FullScanSchedule, scan(true))
+
+	sched.Add(jobListenBrainzSync, conf.ListenBrainzSyncSchedule, lBrainz.Sync)
+
+	sched.Add(jobCacheCleanup, conf.CacheCleanupSchedule, func(ctx context.Context) error {
+		for _, c := range caches {
+			c.Clean()
+		}
+		return nil
+	})
+
+	metadataSchedule := conf.MetadataRefreshSchedule
+	if conf.LastFMApiKey == "" {
+		metadataSchedule = nil
+	}
+	sched.Add(jobMetadataRefresh, metadataSchedule, func(ctx context.Context) error {
+		err := metadataRefresher.Refresh(ctx)
+		if errors.Is(err, metadata.ErrAlreadyRefreshing) {
+			return nil
+		}
+		return err
+	})
+
+	return sched
+}
@@ -4,9 +4,11 @@ import (
 	"encoding/base64"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
+	"github.com/juho05/crossonic-server/scheduler"
 	"github.com/juho05/log"
 )
 
@@ -16,6 +18,15 @@ type environment map[string]string
 
 const CoverArtPriorityEmbedded = "embedded"
 
+// audio outputs the jukebox can play through
+const (
+	JukeboxOutputDisabled = ""
+	JukeboxOutputALSA     = "alsa"
+	JukeboxOutputPulse    = "pulse"
+	JukeboxOutputFile     = "file"
+	JukeboxOutputNull     = "null"
+)
+
 type Config struct {
 	BaseURL             string
 	DBUser              string
@@ -25,6 +36,7 @@ type Config struct {
 	DBPort              int
 	DataDir             string
 	CacheDir            string
+	PodcastDir          string
 	EncryptionKey       []byte
 	ListenAddr          string
 	AutoMigrate         bool
@@ -36,9 +48,24 @@ type Config struct {
 	FrontendDir         string
 	CoverArtPriority    []string
 	ArtistImagePriority []string
-
-	musicDir       string
-	musicDirConfig string
+	JukeboxOutput       string
+	// JukeboxDevice is the ALSA or PulseAudio device or the path of the file the jukebox plays to.
+	JukeboxDevice string
+	// WatchMusicDirs enables scanning changed directories as soon as the file system reports changes.
+	WatchMusicDirs bool
+	// ReadOnlyLibrary prevents the scanner from writing to media files. Files are identified and checked for changes
+	// by content hashes instead of ID tags and modification times.
+	ReadOnlyLibrary bool
+	// Schedules of the background jobs. Jobs without a schedule are disabled.
+	QuickScanSchedule        *scheduler.Schedule
+	FullScanSchedule         *scheduler.Schedule
+	ListenBrainzSyncSchedule *scheduler.Schedule
+	CacheCleanupSchedule     *scheduler.Schedule
+	MetadataRefreshSchedule  *scheduler.Schedule
+
+	musicDir                  string
+	musicDirConfig            string
+	transcodingProfilesConfig string
 }
 
 // Load loads the configuration from environment variables into Options.
@@ -98,6 +125,8 @@ func Load(environ []string) (Config, []error) {
 		errors = append(errors, err)
 	}
 
+	config.PodcastDir = loadPodcastDir(env, config.DataDir)
+
 	config.EncryptionKey, err = loadEncryptionKey(env)
 	if err != nil {
 		errors = append(errors, err)
@@ -128,12 +157,59 @@ func Load(environ []string) (Config, []error) {
 		errors = append(errors, err)
 	}
 
+	config.WatchMusicDirs, err = loadWatchMusicDirs(env)
+	if err != nil {
+		errors = append(errors, err)
+	}
+
+	config.ReadOnlyLibrary, err = loadReadOnlyLibrary(env)
+	if err != nil {
+		errors = append(errors, err)
+	}
+
+	config.QuickScanSchedule, err = loadQuickScanSchedule(env)
+	if err != nil {
+		errors = append(errors, err)
+	}
+
+	config.FullScanSchedule, err = loadFullScanSchedule(env)
+	if err != nil {
+		errors = append(errors, err)
+	}
+
+	config.ListenBrainzSyncSchedule, err = loadListenBrainzSyncSchedule(env)
+	if err != nil {
+		errors = append(errors, err)
+	}
+
+	config.CacheCleanupSchedule, err = loadCacheCleanupSchedule(env)
+	if err != nil {
+		errors = append(errors, err)
+	}
+
+	config.MetadataRefreshSchedule, err = loadMetadataRefreshSchedule(env)
+	if err != nil {
+		errors = append(errors, err)
+	}
+
 	config.FrontendDir = loadFrontendDir(env)
 
 	config.CoverArtPriority = loadCoverArtPriority(env)
 
 	config.ArtistImagePriority = loadArtistImagePriority(env)
 
+	config.JukeboxOutput, err = loadJukeboxOutput(env)
+	if err != nil {
+		errors = append(errors, err)
+	}
+
+	config.JukeboxDevice, err = loadJukeboxDevice(env, config.JukeboxOutput)
+	if err != nil {
+		errors = append(errors, err)
+	}
+
+	config.transcodingProfilesConfig = loadTranscodingProfilesConfig(env)
+
 	config.musicDir = loadMusicDir(env)
 	config.musicDirConfig = loadMusicDirConfig(env)
 
@@ -185,6 +261,10 @@ func loadCacheDir(env environment) (string, error) {
 	return requiredString(env, "CACHE_DIR")
 }
 
+func loadPodcastDir(env environment, dataDir string) string {
+	return optionalString(env, "PODCAST_DIR", filepath.Join(dataDir, "podcasts"))
+}
+
 func loadEncryptionKey(env environment) ([]byte, error) {
 	key := "ENCRYPTION_KEY"
 	str := env[key]
@@ -261,6 +341,34 @@ func loadScanHidden(env environment) (bool, error) {
 	return boolean(env, "SCAN_HIDDEN", false)
 }
 
+func loadWatchMusicDirs(env environment) (bool, error) {
+	return boolean(env, "WATCH_MUSIC_DIRS", false)
+}
+
+func loadReadOnlyLibrary(env environment) (bool, error) {
+	return boolean(env, "READ_ONLY_LIBRARY", false)
+}
+
+func loadQuickScanSchedule(env environment) (*scheduler.Schedule, error) {
+	return schedule(env, "SCHEDULE_QUICK_SCAN", "")
+}
+
+func loadFullScanSchedule(env environment) (*scheduler.Schedule, error) {
+	return schedule(env, "SCHEDULE_FULL_SCAN", "")
+}
+
+func loadListenBrainzSyncSchedule(env environment) (*scheduler.Schedule, error) {
+	return schedule(env, "SCHEDULE_LISTENBRAINZ_SYNC", "0 */3 * * *")
+}
+
+func loadCacheCleanupSchedule(env environment) (*scheduler.Schedule, error) {
+	return schedule(env, "SCHEDULE_CACHE_CLEANUP", "*/5 * * * *")
+}
+
+func loadMetadataRefreshSchedule(env environment) (*scheduler.Schedule, error) {
+	return schedule(env, "SCHEDULE_METADATA_REFRESH", "0 4 * * *")
+}
+
 func loadFrontendDir(env environment) string {
 	return optionalString(env, "FRONTEND_DIR", "")
 }
@@ -277,6 +385,10 @@ func loadMusicDirConfig(env environment) string {
 	return optionalString(env, "MUSIC_DIR_CONFIG", "")
 }
 
+func loadTranscodingProfilesConfig(env environment) string {
+	return optionalString(env, "TRANSCODING_PROFILES", "")
+}
+
 func loadMusicDir(env environment) string {
 	return optionalString(env, "MUSIC_DIR", "")
 }
@@ -293,6 +405,29 @@ func loadArtistImagePriority(env environment) []string {
 	return list
 }
 
+func loadJukeboxOutput(env environment) (string, error) {
+	key := "JUKEBOX_OUTPUT"
+	output := strings.ToLower(optionalString(env, key, JukeboxOutputDisabled))
+	switch output {
+	case JukeboxOutputDisabled, JukeboxOutputALSA, JukeboxOutputPulse, JukeboxOutputFile, JukeboxOutputNull:
+		return output, nil
+	default:
+		return JukeboxOutputDisabled, newError(key, "invalid output: valid values: alsa, pulse, file, null")
+	}
+}
+
+func loadJukeboxDevice(env environment, output string) (string, error) {
+	key := "JUKEBOX_DEVICE"
+	switch output {
+	case JukeboxOutputALSA:
+		return optionalString(env, key, "default"), nil
+	case JukeboxOutputFile:
+		return requiredString(env, key)
+	default:
+		return optionalString(env, key, ""), nil
+	}
+}
+
 func optionalString(env environment, key, def string) string {
 	str := env[key]
 	if str == "" {
@@ -351,3 +486,16 @@ func boolean(env environment, key string, def bool) (bool, error) {
 	}
 	return b, nil
 }
+
+// schedule parses a cron expression. Returns nil if the job is disabled.
+func schedule(env environment, key, def string) (*scheduler.Schedule, error) {
+	str := optionalString(env, key, def)
+	if str == "" || str == "disabled" {
+		return nil, nil
+	}
+	s, err := scheduler.Parse(str)
+	if err != nil {
+		return nil, newError(key, fmt.Sprintf("must be a cron expression or 'disabled': %s", err))
+	}
+	return s, nil
+}
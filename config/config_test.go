@@ -7,6 +7,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/juho05/crossonic-server/scheduler"
 	"github.com/juho05/log"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -28,6 +29,7 @@ func TestLoad(t *testing.T) {
 		musicDir:   "/test/music",
 		DataDir:    "/test/data",
 		CacheDir:   "/test/cache",
+		PodcastDir: "/test/podcasts",
 		EncryptionKey: []byte{0xdd, 0xd5, 0xc1, 0xd3, 0x0c, 0xf8, 0x99, 0x1f, 0xdf, 0x7f, 0xe2,
 			0x58, 0x13, 0x8e, 0xda, 0xb0, 0xc0, 0x37, 0xa1, 0x4a, 0xa2, 0x54, 0x5b, 0x86, 0xe6, 0xe4, 0x86, 0x7f, 0x68, 0x27, 0xf4, 0xad},
 		ListenAddr:          "test:4321",
@@ -39,6 +41,16 @@ func TestLoad(t *testing.T) {
 		FrontendDir:         "/test/frontend",
 		CoverArtPriority:    []string{"embedded", "test.*", "bla.jpg"},
 		ArtistImagePriority: []string{"lastfm", "test.*", "bla.jpg"},
+		JukeboxOutput:       "alsa",
+		JukeboxDevice:       "hw:1,0",
+		WatchMusicDirs:      true,
+		ReadOnlyLibrary:     true,
+
+		QuickScanSchedule:        mustParseSchedule("*/30 * * * *"),
+		FullScanSchedule:         mustParseSchedule("@weekly"),
+		ListenBrainzSyncSchedule: mustParseSchedule("15 * * * *"),
+		CacheCleanupSchedule:     nil,
+		MetadataRefreshSchedule:  mustParseSchedule("0 3 * * sun"),
 	}
 
 	defaultConfig := Config{
@@ -51,6 +63,7 @@ func TestLoad(t *testing.T) {
 		musicDir:   "/test/music",
 		DataDir:    "/test/data",
 		CacheDir:   "/test/cache",
+		PodcastDir: "/test/data/podcasts",
 		EncryptionKey: []byte{0xdd, 0xd5, 0xc1, 0xd3, 0x0c, 0xf8, 0x99, 0x1f, 0xdf, 0x7f, 0xe2,
 			0x58, 0x13, 0x8e, 0xda, 0xb0, 0xc0, 0x37, 0xa1, 0x4a, 0xa2, 0x54, 0x5b, 0x86, 0xe6, 0xe4, 0x86, 0x7f, 0x68, 0x27, 0xf4, 0xad},
 		ListenAddr:          "0.0.0.0:8080",
@@ -62,6 +75,12 @@ func TestLoad(t *testing.T) {
 		FrontendDir:         "",
 		CoverArtPriority:    []string{"cover.*", "folder.*", "front.*", "embedded"},
 		ArtistImagePriority: []string{"artist.*"},
+		JukeboxOutput:       "",
+		JukeboxDevice:       "",
+
+		ListenBrainzSyncSchedule: mustParseSchedule("0 */3 * * *"),
+		CacheCleanupSchedule:     mustParseSchedule("*/5 * * * *"),
+		MetadataRefreshSchedule:  mustParseSchedule("0 4 * * *"),
 	}
 
 	logFileName := filepath.Join(t.TempDir(), "test.log")
@@ -75,6 +94,7 @@ func TestLoad(t *testing.T) {
 		"MUSIC_DIR=" + fullConfig.musicDir,
 		"DATA_DIR=" + fullConfig.DataDir,
 		"CACHE_DIR=" + fullConfig.CacheDir,
+		"PODCAST_DIR=" + fullConfig.PodcastDir,
 		"ENCRYPTION_KEY=3dXB0wz4mR/ff+JYE47asMA3oUqiVFuG5uSGf2gn9K0",
 		"LISTEN_ADDR=" + fullConfig.ListenAddr,
 		"AUTO_MIGRATE=" + strconv.FormatBool(fullConfig.AutoMigrate),
@@ -87,6 +107,15 @@ func TestLoad(t *testing.T) {
 		"FRONTEND_DIR=" + fullConfig.FrontendDir,
 		"COVER_ART_PRIORITY=" + strings.Join(fullConfig.CoverArtPriority, ","),
 		"ARTIST_IMAGE_PRIORITY=" + strings.Join(fullConfig.ArtistImagePriority, ","),
+		"JUKEBOX_OUTPUT=" + fullConfig.JukeboxOutput,
+		"JUKEBOX_DEVICE=" + fullConfig.JukeboxDevice,
+		"WATCH_MUSIC_DIRS=" + strconv.FormatBool(fullConfig.WatchMusicDirs),
+		"READ_ONLY_LIBRARY=" + strconv.FormatBool(fullConfig.ReadOnlyLibrary),
+		"SCHEDULE_QUICK_SCAN=" + fullConfig.QuickScanSchedule.String(),
+		"SCHEDULE_FULL_SCAN=" + fullConfig.FullScanSchedule.String(),
+		"SCHEDULE_LISTENBRAINZ_SYNC=" + fullConfig.ListenBrainzSyncSchedule.String(),
+		"SCHEDULE_CACHE_CLEANUP=disabled",
+		"SCHEDULE_METADATA_REFRESH=" + fullConfig.MetadataRefreshSchedule.String(),
 	}
 
 	envRequired := []string{
@@ -130,6 +159,7 @@ func TestLoad(t *testing.T) {
 			assert.Equal(t, tt.config.musicDir, conf.musicDir)
 			assert.Equal(t, tt.config.DataDir, conf.DataDir)
 			assert.Equal(t, tt.config.CacheDir, conf.CacheDir)
+			assert.Equal(t, tt.config.PodcastDir, conf.PodcastDir)
 			assert.Equal(t, tt.config.EncryptionKey, conf.EncryptionKey)
 			assert.Equal(t, tt.config.ListenAddr, conf.ListenAddr)
 			assert.Equal(t, tt.config.AutoMigrate, conf.AutoMigrate)
@@ -140,6 +170,15 @@ func TestLoad(t *testing.T) {
 			assert.Equal(t, tt.config.FrontendDir, conf.FrontendDir)
 			assert.Equal(t, tt.config.CoverArtPriority, conf.CoverArtPriority)
 			assert.Equal(t, tt.config.ArtistImagePriority, conf.ArtistImagePriority)
+			assert.Equal(t, tt.config.JukeboxOutput, conf.JukeboxOutput)
+			assert.Equal(t, tt.config.JukeboxDevice, conf.JukeboxDevice)
+			assert.Equal(t, tt.config.WatchMusicDirs, conf.WatchMusicDirs)
+			assert.Equal(t, tt.config.ReadOnlyLibrary, conf.ReadOnlyLibrary)
+			assert.Equal(t, tt.config.QuickScanSchedule, conf.QuickScanSchedule)
+			assert.Equal(t, tt.config.FullScanSchedule, conf.FullScanSchedule)
+			assert.Equal(t, tt.config.ListenBrainzSyncSchedule, conf.ListenBrainzSyncSchedule)
+			assert.Equal(t, tt.config.CacheCleanupSchedule, conf.CacheCleanupSchedule)
+			assert.Equal(t, tt.config.MetadataRefreshSchedule, conf.MetadataRefreshSchedule)
 			if tt.hasLogFile {
 				assert.Equal(t, logFileName, conf.LogFile.Name())
 				conf.LogFile.Close()
@@ -685,6 +724,68 @@ func Test_loadMusicDir(t *testing.T) {
 	}
 }
 
+func Test_loadTranscodingProfilesConfig(t *testing.T) {
+	key := "TRANSCODING_PROFILES"
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"empty value", "", ""},
+		{"existing value", "/test/profiles.json", "/test/profiles.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := loadTranscodingProfilesConfig(map[string]string{
+				key: tt.value,
+			})
+			assert.Equal(t, tt.want, v)
+		})
+	}
+}
+
+func TestConfig_GetTranscodingProfiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []TranscodingProfile
+		wantErr bool
+	}{
+		{"empty list", `[]`, []TranscodingProfile{}, false},
+		{"lossy", `[{"name":"MP3","encoder":"libshine","container":"mp3","mime":"audio/mpeg","minBitRate":32,"maxBitRate":192}]`, []TranscodingProfile{
+			{Name: "mp3", Codec: "mp3", Encoder: "libshine", Container: "mp3", Mime: "audio/mpeg", MinBitRate: 32, DefaultBitRate: 192, MaxBitRate: 192, MaxBitRatePerChannel: 192},
+		}, false},
+		{"lossless", `[{"name":"wavpack","encoder":"wavpack","container":"wv","mime":"audio/wavpack","extraArgs":["-compression_level","3"]}]`, []TranscodingProfile{
+			{Name: "wavpack", Codec: "wavpack", Encoder: "wavpack", Container: "wv", Mime: "audio/wavpack", ExtraArgs: []string{"-compression_level", "3"}},
+		}, false},
+		{"missing name", `[{"encoder":"aac","container":"adts","mime":"audio/aac"}]`, nil, true},
+		{"reserved name", `[{"name":"raw","encoder":"aac","container":"adts","mime":"audio/aac"}]`, nil, true},
+		{"duplicate name", `[{"name":"a","encoder":"aac","container":"adts","mime":"audio/aac"},{"name":"A","encoder":"aac","container":"adts","mime":"audio/aac"}]`, nil, true},
+		{"missing encoder", `[{"name":"aac","container":"adts","mime":"audio/aac"}]`, nil, true},
+		{"min greater than max", `[{"name":"aac","encoder":"aac","container":"adts","mime":"audio/aac","minBitRate":320,"maxBitRate":128}]`, nil, true},
+		{"default out of range", `[{"name":"aac","encoder":"aac","container":"adts","mime":"audio/aac","minBitRate":32,"defaultBitRate":512,"maxBitRate":320}]`, nil, true},
+		{"bounds without max", `[{"name":"aac","encoder":"aac","container":"adts","mime":"audio/aac","minBitRate":32}]`, nil, true},
+		{"invalid json", `{`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "profiles.json")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0644))
+			profiles, err := Config{transcodingProfilesConfig: path}.GetTranscodingProfiles()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, profiles)
+		})
+	}
+
+	profiles, err := Config{}.GetTranscodingProfiles()
+	assert.NoError(t, err)
+	assert.Nil(t, profiles, "should return nil if no config file is configured")
+}
+
 func Test_loadScanHidden(t *testing.T) {
 	key := "SCAN_HIDDEN"
 	tests := []struct {
@@ -708,6 +809,87 @@ func Test_loadScanHidden(t *testing.T) {
 	}
 }
 
+func Test_loadListenBrainzSyncSchedule(t *testing.T) {
+	key := "SCHEDULE_LISTENBRAINZ_SYNC"
+	tests := []struct {
+		name    string
+		value   string
+		want    *scheduler.Schedule
+		wantErr bool
+	}{
+		{"empty value", "", mustParseSchedule("0 */3 * * *"), false},
+		{"disabled", "disabled", nil, false},
+		{"invalid value", "3h", nil, true},
+		{"out of range", "0 24 * * *", nil, true},
+		{"valid value", "*/10 8-20 * * mon-fri", mustParseSchedule("*/10 8-20 * * mon-fri"), false},
+		{"macro", "@hourly", mustParseSchedule("@hourly"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := loadListenBrainzSyncSchedule(map[string]string{
+				key: tt.value,
+			})
+			assertEqualOrErr(t, key, tt.want, v, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_loadJukeboxOutput(t *testing.T) {
+	key := "JUKEBOX_OUTPUT"
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"empty value", "", "", false},
+		{"invalid value", "speaker", "", true},
+		{"valid value", "pulse", "pulse", false},
+		{"uppercase value", "ALSA", "alsa", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := loadJukeboxOutput(map[string]string{
+				key: tt.value,
+			})
+			assertEqualOrErr(t, key, tt.want, v, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_loadJukeboxDevice(t *testing.T) {
+	key := "JUKEBOX_DEVICE"
+	tests := []struct {
+		name    string
+		output  string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"alsa default", "alsa", "", "default", false},
+		{"alsa device", "alsa", "hw:1,0", "hw:1,0", false},
+		{"pulse default", "pulse", "", "", false},
+		{"file without path", "file", "", "", true},
+		{"file", "file", "/tmp/jukebox.wav", "/tmp/jukebox.wav", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := loadJukeboxDevice(map[string]string{
+				key: tt.value,
+			}, tt.output)
+			assertEqualOrErr(t, key, tt.want, v, tt.wantErr, err)
+		})
+	}
+}
+
+func mustParseSchedule(expr string) *scheduler.Schedule {
+	s, err := scheduler.Parse(expr)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
 func assertEqualOrErr[T any](t *testing.T, key string, want, got T, wantErr bool, err error) {
 	t.Helper()
 	if wantErr {
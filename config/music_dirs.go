@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 )
@@ -13,6 +14,8 @@ type MusicDir struct {
 	Name  string   `json:"name"`
 	Path  string   `json:"path"`
 	Users []string `json:"users"`
+	// Exclude contains gitignore-style patterns relative to Path of files and directories that are not scanned.
+	Exclude []string `json:"exclude"`
 }
 
 func (c Config) GetMusicDirs() ([]MusicDir, error) {
@@ -66,6 +69,12 @@ func (c Config) GetMusicDirs() ([]MusicDir, error) {
 			return nil, fmt.Errorf("failed to make music dir path of %d absolute: %w", dir.ID, err)
 		}
 		musicDirs[i].Path = filepath.Clean(strings.TrimSpace(abs))
+		for _, pattern := range dir.Exclude {
+			err = checkExcludePattern(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid exclude pattern of music dir %d: %s: %w", dir.ID, pattern, err)
+			}
+		}
 	}
 
 	err = checkIfPathsSubdirOfEachOther(musicDirs)
@@ -87,10 +96,27 @@ func GenerateMusicDirConfigString(musicDirs []MusicDir) string {
 			sb.WriteString(":")
 			sb.WriteString(u)
 		}
+		// changing the exclude patterns changes the config so that the next scan re-evaluates all files
+		for _, e := range dir.Exclude {
+			sb.WriteString("|")
+			sb.WriteString(e)
+		}
 	}
 	return sb.String()
 }
 
+// checkExcludePattern checks that every path segment of the gitignore-style pattern is a valid glob.
+func checkExcludePattern(pattern string) error {
+	pattern = strings.TrimPrefix(strings.TrimPrefix(pattern, "!"), "\\")
+	for _, segment := range strings.Split(pattern, "/") {
+		_, err := path.Match(segment, "")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func checkIfPathsSubdirOfEachOther(musicDirs []MusicDir) error {
 	for _, a := range musicDirs {
 		for _, b := range musicDirs {
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// TranscodingProfile describes a format songs can be transcoded to. Bitrates are in kbit/s.
+// Profiles without a MaxBitRate are treated as lossless and ignore requested bitrates.
+type TranscodingProfile struct {
+	// Name is used to select the profile in the format parameter of stream requests.
+	Name string `json:"name"`
+	// Codec is the codec name reported to clients. Defaults to Name.
+	Codec string `json:"codec"`
+	// Encoder is the ffmpeg audio encoder, e.g. libmp3lame.
+	Encoder string `json:"encoder"`
+	// Container is the ffmpeg muxer, e.g. ogg.
+	Container            string   `json:"container"`
+	Mime                 string   `json:"mime"`
+	MinBitRate           int      `json:"minBitRate"`
+	DefaultBitRate       int      `json:"defaultBitRate"`
+	MaxBitRate           int      `json:"maxBitRate"`
+	MaxBitRatePerChannel int      `json:"maxBitRatePerChannel"`
+	ExtraArgs            []string `json:"extraArgs"`
+}
+
+// GetTranscodingProfiles returns the profiles of the TRANSCODING_PROFILES config file.
+// Returns nil if no config file is configured.
+func (c Config) GetTranscodingProfiles() ([]TranscodingProfile, error) {
+	if c.transcodingProfilesConfig == "" {
+		return nil, nil
+	}
+
+	configFile, err := os.Open(c.transcodingProfilesConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open transcoding profiles config file: %w", err)
+	}
+	defer configFile.Close()
+
+	var profiles []TranscodingProfile
+	err = json.NewDecoder(configFile).Decode(&profiles)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transcoding profiles config file: %w", err)
+	}
+
+	names := make(map[string]struct{}, len(profiles))
+	for i, p := range profiles {
+		p.Name = strings.ToLower(strings.TrimSpace(p.Name))
+		if p.Name == "" {
+			return nil, fmt.Errorf("transcoding profile %d does not have a name", i+1)
+		}
+		if p.Name == "raw" {
+			return nil, fmt.Errorf("transcoding profile %d: name raw is reserved", i+1)
+		}
+		if _, ok := names[p.Name]; ok {
+			return nil, fmt.Errorf("transcoding profile %s is defined multiple times", p.Name)
+		}
+		names[p.Name] = struct{}{}
+		if p.Codec == "" {
+			p.Codec = p.Name
+		}
+		if p.Encoder == "" {
+			return nil, fmt.Errorf("transcoding profile %s does not have an encoder", p.Name)
+		}
+		if p.Container == "" {
+			return nil, fmt.Errorf("transcoding profile %s does not have a container", p.Name)
+		}
+		if p.Mime == "" {
+			return nil, fmt.Errorf("transcoding profile %s does not have a mime type", p.Name)
+		}
+		if p.MaxBitRate > 0 {
+			if p.MinBitRate <= 0 || p.MinBitRate > p.MaxBitRate {
+				return nil, fmt.Errorf("transcoding profile %s: minBitRate must be between 1 and maxBitRate", p.Name)
+			}
+			if p.DefaultBitRate == 0 {
+				p.DefaultBitRate = p.MaxBitRate
+			}
+			if p.DefaultBitRate < p.MinBitRate || p.DefaultBitRate > p.MaxBitRate {
+				return nil, fmt.Errorf("transcoding profile %s: defaultBitRate must be between minBitRate and maxBitRate", p.Name)
+			}
+			if p.MaxBitRatePerChannel <= 0 {
+				p.MaxBitRatePerChannel = p.MaxBitRate
+			}
+		} else if p.MinBitRate != 0 || p.DefaultBitRate != 0 || p.MaxBitRatePerChannel != 0 {
+			return nil, fmt.Errorf("transcoding profile %s: bitrate bounds require maxBitRate", p.Name)
+		}
+		profiles[i] = p
+	}
+	return profiles, nil
+}
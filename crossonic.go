@@ -24,7 +24,7 @@ var MigrationsFS fs.FS
 
 var GenID func() string
 var IDAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-~"
-var IDRegex = regexp.MustCompile(fmt.Sprintf("^(tr|al|ar|pl|irs)_[%s]{12}$", strings.ReplaceAll(IDAlphabet, "-", "\\-")))
+var IDRegex = regexp.MustCompile(fmt.Sprintf("^(tr|al|ar|pl|irs|sh|pc|pe|dir)_[%s]{12}$", strings.ReplaceAll(IDAlphabet, "-", "\\-")))
 
 func init() {
 	var err error
@@ -46,6 +46,10 @@ const (
 	IDTypeArtist               IDType = "ar"
 	IDTypePlaylist             IDType = "pl"
 	IDTypeInternetRadioStation IDType = "irs"
+	IDTypeShare                IDType = "sh"
+	IDTypePodcastChannel       IDType = "pc"
+	IDTypePodcastEpisode       IDType = "pe"
+	IDTypeDirectory            IDType = "dir"
 )
 
 func GenIDSong() string {
@@ -68,13 +72,30 @@ func GenIDInternetRadioStation() string {
 	return string(IDTypeInternetRadioStation) + "_" + GenID()
 }
 
+func GenIDShare() string {
+	return string(IDTypeShare) + "_" + GenID()
+}
+
+func GenIDPodcastChannel() string {
+	return string(IDTypePodcastChannel) + "_" + GenID()
+}
+
+func GenIDPodcastEpisode() string {
+	return string(IDTypePodcastEpisode) + "_" + GenID()
+}
+
+func GenIDDirectory() string {
+	return string(IDTypeDirectory) + "_" + GenID()
+}
+
 func GetIDType(id string) (IDType, bool) {
 	parts := strings.Split(id, "_")
 	if len(parts) != 2 {
 		return "", false
 	}
 	types := []IDType{
-		IDTypeSong, IDTypeAlbum, IDTypeArtist, IDTypePlaylist, IDTypeInternetRadioStation,
+		IDTypeSong, IDTypeAlbum, IDTypeArtist, IDTypePlaylist, IDTypeInternetRadioStation, IDTypeShare,
+		IDTypePodcastChannel, IDTypePodcastEpisode, IDTypeDirectory,
 	}
 	if !slices.Contains(types, IDType(parts[0])) {
 		return "", false
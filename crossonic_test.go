@@ -13,6 +13,10 @@ func TestIDRegex(t *testing.T) {
 		"ar_abcdefghijkl",
 		"pl_abcdefghijkl",
 		"irs_abcdefghijkl",
+		"sh_abcdefghijkl",
+		"pc_abcdefghijkl",
+		"pe_abcdefghijkl",
+		"dir_abcdefghijkl",
 		// all alphabet characters
 		"tr_ABCDEFGHIJKL",
 		"tr_0123456789-~",
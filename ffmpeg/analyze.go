@@ -0,0 +1,148 @@
+package ffmpeg
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// replayGainReference is the target loudness of ReplayGain 2.0 in LUFS.
+const replayGainReference = -18
+
+// silenceLoudness is the lowest loudness reported by the ebur128 filter.
+const silenceLoudness = -70
+
+// MaxAnalyzerProcesses is the maximum number of ffmpeg processes an Analyzer runs at the same time.
+const MaxAnalyzerProcesses = 4
+
+// Analyzer measures properties of the audio of files.
+// All measurements share a pool of MaxAnalyzerProcesses ffmpeg processes.
+type Analyzer struct {
+	processes chan struct{}
+}
+
+// NewAnalyzer creates a new analyzer and looks up
+// the ffmpeg binary path. If ffmpeg cannot be found, an error will be returned.
+func NewAnalyzer() (*Analyzer, error) {
+	err := initialize()
+	if err != nil {
+		return nil, fmt.Errorf("new analyzer: %w", err)
+	}
+	return &Analyzer{
+		processes: make(chan struct{}, MaxAnalyzerProcesses),
+	}, nil
+}
+
+// Loudness is the result of an EBU R128 measurement.
+type Loudness struct {
+	// Integrated is the integrated loudness in LUFS.
+	Integrated float64
+	// TruePeak is the true peak in dBTP.
+	TruePeak float64
+}
+
+// ReplayGain returns the ReplayGain 2.0 gain in dB and the linear peak of the measurement.
+// Silent files result in a gain of 0.
+func (l Loudness) ReplayGain() (gain, peak float64) {
+	if l.Integrated > silenceLoudness {
+		gain = replayGainReference - l.Integrated
+	}
+	return gain, math.Pow(10, l.TruePeak/20)
+}
+
+// Loudness measures the loudness of the first audio stream of the file at path with the ebur128 filter.
+func (a *Analyzer) Loudness(ctx context.Context, path string) (Loudness, error) {
+	stderr := new(bytes.Buffer)
+	cmd := exec.CommandContext(ctx, ffmpegPath, "-hide_banner", "-nostats", "-nostdin", "-i", path, "-map", "0:a:0", "-vn",
+		"-af", "ebur128=peak=true:framelog=verbose", "-f", "null", "-")
+	cmd.Stderr = stderr
+	err := a.run(ctx, cmd)
+	if err != nil {
+		return Loudness{}, fmt.Errorf("ffmpeg: measure loudness: %w\n%s", err, stderr.String())
+	}
+	loudness, err := parseEBUR128Summary(stderr.String())
+	if err != nil {
+		return Loudness{}, fmt.Errorf("ffmpeg: measure loudness: %w", err)
+	}
+	return loudness, nil
+}
+
+// Samples decodes up to duration of the first audio stream of the file at path starting at timeOffset
+// to mono 32-bit float samples with sampleRate.
+func (a *Analyzer) Samples(ctx context.Context, path string, sampleRate int, timeOffset, duration time.Duration) ([]float32, error) {
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	cmd := exec.CommandContext(ctx, ffmpegPath, "-v", "error", "-nostdin", "-ss", fmt.Sprintf("%dus", timeOffset.Microseconds()), "-t", fmt.Sprintf("%dus", duration.Microseconds()),
+		"-i", path, "-map", "0:a:0", "-vn", "-ac", "1", "-ar", strconv.Itoa(sampleRate), "-f", "f32le", "-")
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	err := a.run(ctx, cmd)
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg: decode samples: %w\n%s", err, stderr.String())
+	}
+	return decodeFloat32LE(stdout.Bytes()), nil
+}
+
+// run waits for a free process slot and runs cmd.
+func (a *Analyzer) run(ctx context.Context, cmd *exec.Cmd) error {
+	select {
+	case a.processes <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() {
+		<-a.processes
+	}()
+	return cmd.Run()
+}
+
+func decodeFloat32LE(data []byte) []float32 {
+	samples := make([]float32, len(data)/4)
+	for i := range samples {
+		samples[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:]))
+	}
+	return samples
+}
+
+var (
+	ebur128IntegratedRegex = regexp.MustCompile(`(?m)^\s*I:\s+(\S+) LUFS`)
+	ebur128PeakRegex       = regexp.MustCompile(`(?m)^\s*Peak:\s+(\S+) dBFS`)
+)
+
+// parseEBUR128Summary parses the summary the ebur128 filter prints at the end of the stream.
+func parseEBUR128Summary(output string) (Loudness, error) {
+	index := strings.LastIndex(output, "Summary:")
+	if index < 0 {
+		return Loudness{}, errors.New("missing ebur128 summary")
+	}
+	summary := output[index:]
+
+	var loudness Loudness
+	match := ebur128IntegratedRegex.FindStringSubmatch(summary)
+	if match == nil {
+		return Loudness{}, errors.New("missing integrated loudness in ebur128 summary")
+	}
+	var err error
+	loudness.Integrated, err = strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return Loudness{}, fmt.Errorf("parse integrated loudness: %w", err)
+	}
+
+	match = ebur128PeakRegex.FindStringSubmatch(summary)
+	if match == nil {
+		return Loudness{}, errors.New("missing true peak in ebur128 summary")
+	}
+	loudness.TruePeak, err = strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return Loudness{}, fmt.Errorf("parse true peak: %w", err)
+	}
+	return loudness, nil
+}
@@ -0,0 +1,72 @@
+package ffmpeg
+
+import (
+	"context"
+	"math"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEBUR128Summary(t *testing.T) {
+	output := `Input #0, flac, from 'song.flac':
+  Duration: 00:03:12.45, start: 0.000000, bitrate: 912 kb/s
+[Parsed_ebur128_0 @ 0x55d0c8a1f2c0] Summary:
+
+  Integrated loudness:
+    I:         -11.3 LUFS
+    Threshold: -21.6 LUFS
+
+  Loudness range:
+    LRA:         6.1 LU
+    Threshold: -31.7 LUFS
+    LRA low:   -15.9 LUFS
+    LRA high:   -9.8 LUFS
+
+  True peak:
+    Peak:        0.4 dBFS
+`
+	loudness, err := parseEBUR128Summary(output)
+	require.NoError(t, err)
+	assert.Equal(t, Loudness{Integrated: -11.3, TruePeak: 0.4}, loudness)
+
+	_, err = parseEBUR128Summary("Input #0, flac, from 'song.flac':")
+	assert.Error(t, err)
+
+	loudness, err = parseEBUR128Summary("Summary:\n  I: -70.0 LUFS\n  Peak: -inf dBFS\n")
+	require.NoError(t, err)
+	assert.True(t, math.IsInf(loudness.TruePeak, -1))
+}
+
+func TestDecodeFloat32LE(t *testing.T) {
+	data := []byte{
+		0x00, 0x00, 0x80, 0x3f, // 1
+		0x00, 0x00, 0x00, 0xbf, // -0.5
+		0x00, 0x00, // incomplete
+	}
+	assert.Equal(t, []float32{1, -0.5}, decodeFloat32LE(data))
+}
+
+func TestLoudness_ReplayGain(t *testing.T) {
+	gain, peak := Loudness{Integrated: -11.3, TruePeak: 0.4}.ReplayGain()
+	assert.InDelta(t, -6.7, gain, 0.0001)
+	assert.InDelta(t, 1.0471, peak, 0.0001)
+
+	gain, peak = Loudness{Integrated: -70, TruePeak: math.Inf(-1)}.ReplayGain()
+	assert.Equal(t, 0.0, gain, "silence should not be amplified")
+	assert.Equal(t, 0.0, peak)
+}
+
+func TestAnalyzer_run(t *testing.T) {
+	a := &Analyzer{processes: make(chan struct{}, 1)}
+	a.processes <- struct{}{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cmd := exec.Command("ffmpeg")
+	err := a.run(ctx, cmd)
+	assert.ErrorIs(t, err, context.Canceled, "should not wait for a free process slot after ctx was canceled")
+	assert.Nil(t, cmd.Process, "should not start the process without a free slot")
+}
@@ -0,0 +1,37 @@
+package ffmpeg
+
+import (
+	"bufio"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// availableEncoders returns the names of all audio encoders supported by ffmpeg.
+func availableEncoders() (map[string]struct{}, error) {
+	out, err := exec.Command(ffmpegPath, "-hide_banner", "-encoders").Output()
+	if err != nil {
+		return nil, fmt.Errorf("list encoders: %w", err)
+	}
+	return parseAudioEncoders(string(out)), nil
+}
+
+// parseAudioEncoders parses the output of ffmpeg -encoders. Each encoder line starts with
+// a capability column whose first character is A for audio encoders, followed by the encoder name.
+func parseAudioEncoders(output string) map[string]struct{} {
+	encoders := make(map[string]struct{})
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	listStarted := false
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !listStarted {
+			listStarted = strings.HasPrefix(line, "---")
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && strings.HasPrefix(fields[0], "A") {
+			encoders[fields[1]] = struct{}{}
+		}
+	}
+	return encoders
+}
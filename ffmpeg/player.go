@@ -0,0 +1,91 @@
+package ffmpeg
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/juho05/log"
+)
+
+type Player struct {
+}
+
+// NewPlayer creates a new player and looks up
+// the ffmpeg binary path. If ffmpeg cannot be found, an error will be returned.
+func NewPlayer() (*Player, error) {
+	err := initialize()
+	if err != nil {
+		return nil, fmt.Errorf("new player: %w", err)
+	}
+	return &Player{}, nil
+}
+
+// Playback is a running ffmpeg process that plays a single file in real time.
+type Playback struct {
+	cmd  *exec.Cmd
+	done chan struct{}
+	err  error
+}
+
+// Play starts playing the file at path beginning at timeOffset with the volume multiplied by gain.
+// outputArgs are passed to ffmpeg after the input options and must specify the output format and destination,
+// e.g. []string{"-f", "alsa", "default"}.
+func (p *Player) Play(path string, timeOffset time.Duration, gain float64, outputArgs []string) (*Playback, error) {
+	args := []string{"-v", "error", "-nostdin", "-re", "-ss", fmt.Sprintf("%dus", timeOffset.Microseconds()), "-i", path, "-map", "0:a:0", "-vn",
+		"-af", "volume=" + strconv.FormatFloat(gain, 'f', 3, 64)}
+	args = append(args, outputArgs...)
+
+	stderr := new(bytes.Buffer)
+	cmd := exec.Command(ffmpegPath, args...)
+	cmd.Stderr = stderr
+
+	err := cmd.Start()
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg: play: %w", err)
+	}
+	playback := &Playback{
+		cmd:  cmd,
+		done: make(chan struct{}),
+	}
+	go func() {
+		defer close(playback.done)
+		err := cmd.Wait()
+		if err != nil && cmd.ProcessState != nil && !cmd.ProcessState.Exited() {
+			// killed by Stop
+			return
+		}
+		if err != nil {
+			log.Errorf("ffmpeg: play: %s\n%s", err, stderr.String())
+			playback.err = fmt.Errorf("ffmpeg: play: %w", err)
+		}
+	}()
+	return playback, nil
+}
+
+// Done is closed when the playback ended.
+func (p *Playback) Done() <-chan struct{} {
+	return p.done
+}
+
+// Err returns the error that caused the playback to end prematurely.
+// It must only be called after Done was closed.
+func (p *Playback) Err() error {
+	return p.err
+}
+
+// Stop kills the ffmpeg process and waits for it to exit.
+func (p *Playback) Stop() {
+	select {
+	case <-p.done:
+		return
+	default:
+	}
+	err := p.cmd.Process.Kill()
+	if err != nil {
+		log.Errorf("ffmpeg: play: stop: %s", err)
+	}
+	<-p.done
+}
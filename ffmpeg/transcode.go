@@ -4,28 +4,40 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"maps"
+	"math"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/juho05/crossonic-server/config"
 	"github.com/juho05/log"
 )
 
 type Format struct {
-	Name                  string
+	// Name is the unique name used to select the format.
+	Name string
+	// Codec is the name of the audio codec produced by the format.
+	Codec                 string
 	Mime                  string
 	outFormat             string
 	encoder               string
+	extraArgs             []string
 	minBitRateK           int
 	defaultBitRateK       int
 	maxBitRateK           int
 	maxBitRatePerChannelK int
 }
 
-var formats = map[string]Format{
+// fragmentedMP4Args allow writing MP4 files to a pipe.
+var fragmentedMP4Args = []string{"-movflags", "frag_keyframe+empty_moov"}
+
+var builtinFormats = map[string]Format{
 	"mp3": {
 		Name:                  "mp3",
+		Codec:                 "mp3",
 		outFormat:             "mp3",
 		Mime:                  "audio/mpeg",
 		encoder:               "libmp3lame",
@@ -36,6 +48,7 @@ var formats = map[string]Format{
 	},
 	"opus": {
 		Name:                  "opus",
+		Codec:                 "opus",
 		outFormat:             "ogg",
 		Mime:                  "audio/ogg",
 		encoder:               "libopus",
@@ -45,7 +58,8 @@ var formats = map[string]Format{
 		maxBitRatePerChannelK: 256,
 	},
 	"ogg": {
-		Name:                  "vorbis",
+		Name:                  "ogg",
+		Codec:                 "vorbis",
 		outFormat:             "ogg",
 		Mime:                  "audio/ogg",
 		encoder:               "libvorbis",
@@ -56,6 +70,7 @@ var formats = map[string]Format{
 	},
 	"vorbis": {
 		Name:                  "vorbis",
+		Codec:                 "vorbis",
 		outFormat:             "ogg",
 		Mime:                  "audio/ogg",
 		encoder:               "libvorbis",
@@ -64,52 +79,224 @@ var formats = map[string]Format{
 		maxBitRateK:           480,
 		maxBitRatePerChannelK: 240,
 	},
+	"aac": {
+		Name:                  "aac",
+		Codec:                 "aac",
+		outFormat:             "adts",
+		Mime:                  "audio/aac",
+		encoder:               "aac",
+		minBitRateK:           32,
+		defaultBitRateK:       192,
+		maxBitRateK:           512,
+		maxBitRatePerChannelK: 256,
+	},
+	"m4a": {
+		Name:                  "m4a",
+		Codec:                 "aac",
+		outFormat:             "mp4",
+		Mime:                  "audio/mp4",
+		encoder:               "aac",
+		extraArgs:             fragmentedMP4Args,
+		minBitRateK:           32,
+		defaultBitRateK:       192,
+		maxBitRateK:           512,
+		maxBitRatePerChannelK: 256,
+	},
+	"flac": {
+		Name:      "flac",
+		Codec:     "flac",
+		outFormat: "flac",
+		Mime:      "audio/flac",
+		encoder:   "flac",
+	},
+	"alac": {
+		Name:      "alac",
+		Codec:     "alac",
+		outFormat: "mp4",
+		Mime:      "audio/mp4",
+		encoder:   "alac",
+		extraArgs: fragmentedMP4Args,
+	},
+}
+
+// HLSSegmentFormat is used for all HLS media segments. AAC in MPEG-TS is the combination
+// supported by every HLS client.
+var HLSSegmentFormat = Format{
+	Name:                  "aac",
+	Codec:                 "aac",
+	outFormat:             "mpegts",
+	Mime:                  "video/mp2t",
+	encoder:               "aac",
+	minBitRateK:           32,
+	defaultBitRateK:       192,
+	maxBitRateK:           512,
+	maxBitRatePerChannelK: 256,
+}
+
+// NewFormat creates a format from a transcoding profile.
+func NewFormat(profile config.TranscodingProfile) Format {
+	return Format{
+		Name:                  profile.Name,
+		Codec:                 profile.Codec,
+		Mime:                  profile.Mime,
+		outFormat:             profile.Container,
+		encoder:               profile.Encoder,
+		extraArgs:             profile.ExtraArgs,
+		minBitRateK:           profile.MinBitRate,
+		defaultBitRateK:       profile.DefaultBitRate,
+		maxBitRateK:           profile.MaxBitRate,
+		maxBitRatePerChannelK: profile.MaxBitRatePerChannel,
+	}
+}
+
+// ClampBitRate clamps maxBitRateK to a bitrate supported by the format for channel count.
+// A maxBitRateK of 0 results in the default bitrate of the format.
+// Lossless formats always return 0.
+func (f Format) ClampBitRate(channels, maxBitRateK int) int {
+	if f.Lossless() {
+		return 0
+	}
+	if maxBitRateK == 0 {
+		return f.defaultBitRateK
+	}
+	maxBitRateK = min(f.maxBitRateK, channels*f.maxBitRatePerChannelK, maxBitRateK)
+	return max(f.minBitRateK, maxBitRateK)
+}
+
+// Lossless reports whether the format ignores bitrates.
+func (f Format) Lossless() bool {
+	return f.maxBitRateK == 0
+}
+
+// Container returns the ffmpeg muxer name of the format.
+func (f Format) Container() string {
+	return f.outFormat
+}
+
+// Normalization is a ReplayGain adjustment applied while transcoding.
+type Normalization struct {
+	GainDB float64
+	// Peak is the linear sample peak of the source or 0 if it is unknown.
+	Peak float64
+}
+
+// filter returns an ffmpeg audio filter that applies the gain without clipping.
+// With a known peak the gain is reduced so that the peak does not exceed full scale,
+// otherwise positive gains are followed by a limiter.
+func (n Normalization) filter() string {
+	gain := n.GainDB
+	if n.Peak > 0 {
+		gain = min(gain, -20*math.Log10(n.Peak))
+	} else if gain > 0 {
+		return fmt.Sprintf("volume=%.2fdB,alimiter=limit=1:level=0", gain)
+	}
+	return fmt.Sprintf("volume=%.2fdB", gain)
 }
 
+// Transcoder transcodes files with ffmpeg.
+// The zero value only supports the built-in formats and is only useful for selecting formats.
 type Transcoder struct {
+	formats map[string]Format
 }
 
-// NewTranscoder creates a new transcoder and looks up
-// the ffmpeg binary path. If ffmpeg cannot be found, an error will be returned.
-func NewTranscoder() (*Transcoder, error) {
+// NewTranscoder creates a new transcoder with the built-in formats and profiles and looks up
+// the ffmpeg binary path. Profiles replace built-in formats with the same name.
+// If ffmpeg cannot be found or does not support the encoder of one of profiles, an error will be returned.
+// Built-in formats with unsupported encoders are disabled.
+func NewTranscoder(profiles []config.TranscodingProfile) (*Transcoder, error) {
 	err := initialize()
 	if err != nil {
 		return nil, fmt.Errorf("new transcoder: %w", err)
 	}
-	return &Transcoder{}, nil
+	encoders, err := availableEncoders()
+	if err != nil {
+		return nil, fmt.Errorf("new transcoder: %w", err)
+	}
+
+	formats := make(map[string]Format, len(builtinFormats)+len(profiles))
+	for name, f := range builtinFormats {
+		if _, ok := encoders[f.encoder]; !ok {
+			log.Warnf("FFmpeg does not support the %s encoder. Disabling %s transcoding...", f.encoder, name)
+			continue
+		}
+		formats[name] = f
+	}
+	for _, p := range profiles {
+		if _, ok := encoders[p.Encoder]; !ok {
+			return nil, fmt.Errorf("new transcoder: transcoding profile %s: ffmpeg does not support the %s encoder", p.Name, p.Encoder)
+		}
+		formats[p.Name] = NewFormat(p)
+	}
+	return &Transcoder{formats: formats}, nil
+}
+
+func (t *Transcoder) getFormats() map[string]Format {
+	if t.formats == nil {
+		return builtinFormats
+	}
+	return t.formats
 }
 
 // SelectFormat returns a Format by name and clamps maxBitRateK to a bitrate supported by the
 // format for channel count. If no matching format can be found it defaults to mp3. A maxBitRateK of 0
 // results in the default bitrate for the format being used. The special name "raw" results in an empty format
-// with a maxBitRateK of 0 being returned.
+// with a maxBitRateK of 0 being returned. If mp3 is not available either, the result is the same as for "raw".
 func (t *Transcoder) SelectFormat(name string, channels, maxBitRateK int) (Format, int) {
 	if name == "raw" {
 		return Format{}, 0
 	}
 	format := strings.ToLower(name)
-	f, ok := formats[format]
+	f, ok := t.getFormats()[format]
 	if !ok {
 		if format != "" {
 			log.Warnf("Requested transcoding format %s not supported. Falling back to mp3...", format)
 		}
-		f = formats["mp3"]
+		f, ok = t.getFormats()["mp3"]
+		if !ok {
+			return Format{}, 0
+		}
 	}
-	if maxBitRateK == 0 {
-		return f, f.defaultBitRateK
+	return f, f.ClampBitRate(channels, maxBitRateK)
+}
+
+// LookupFormat returns the format with name. Unlike SelectFormat it does not fall back to mp3.
+func (t *Transcoder) LookupFormat(name string) (Format, bool) {
+	f, ok := t.getFormats()[strings.ToLower(name)]
+	return f, ok
+}
+
+// FindFormat returns the name of a format that encodes codec into container.
+// If multiple formats match, the alphabetically first one is returned.
+func (t *Transcoder) FindFormat(codec, container string) (string, bool) {
+	formats := t.getFormats()
+	names := slices.Sorted(maps.Keys(formats))
+	for _, name := range names {
+		f := formats[name]
+		if strings.EqualFold(f.Codec, codec) && strings.EqualFold(f.outFormat, container) {
+			return name, true
+		}
 	}
-	maxBitRateK = min(f.maxBitRateK, channels*f.maxBitRatePerChannelK, maxBitRateK)
-	maxBitRateK = max(f.minBitRateK, maxBitRateK)
-	return f, maxBitRateK
+	return "", false
 }
 
-func (t *Transcoder) Transcode(path string, channels int, format Format, maxBitRateK int, timeOffset time.Duration, w io.Writer, onDone func(err error)) (bitRate int, err error) {
-	if maxBitRateK == 0 {
-		maxBitRateK = format.defaultBitRateK
+// Transcode transcodes the file at path to format starting at timeOffset. If normalization is not nil,
+// its gain is applied to the output.
+func (t *Transcoder) Transcode(path string, channels int, format Format, maxBitRateK int, timeOffset time.Duration, normalization *Normalization, w io.Writer, onDone func(err error)) (bitRate int, err error) {
+	return t.transcode(path, channels, format, maxBitRateK, timeOffset, 0, normalization, w, onDone)
+}
+
+// TranscodeSegment transcodes duration of the file starting at timeOffset to HLSSegmentFormat.
+// The timestamps of the segment continue at timeOffset so that consecutive segments can be played back seamlessly.
+func (t *Transcoder) TranscodeSegment(path string, channels int, maxBitRateK int, timeOffset, duration time.Duration, w io.Writer, onDone func(err error)) (bitRate int, err error) {
+	return t.transcode(path, channels, HLSSegmentFormat, maxBitRateK, timeOffset, duration, nil, w, onDone)
+}
+
+func (t *Transcoder) transcode(path string, channels int, format Format, maxBitRateK int, timeOffset, duration time.Duration, normalization *Normalization, w io.Writer, onDone func(err error)) (bitRate int, err error) {
+	maxBitRateK = format.ClampBitRate(channels, maxBitRateK)
+	var bitRateFlags []string
+	if !format.Lossless() {
+		bitRateFlags = []string{"-b:a", fmt.Sprintf("%dk", maxBitRateK)}
 	}
-	maxBitRateK = min(format.maxBitRateK, channels*format.maxBitRatePerChannelK, maxBitRateK)
-	maxBitRateK = max(format.minBitRateK, maxBitRateK)
-	bitRateFlags := []string{"-b:a", fmt.Sprintf("%dk", maxBitRateK)}
 	if format.encoder == "libvorbis" {
 		// FIXME: the resulting bitrate seems to be a bit higher than requested in most cases
 		bitRateFlags = []string{"-q:a"}
@@ -136,8 +323,16 @@ func (t *Transcoder) Transcode(path string, channels int, format Format, maxBitR
 		}
 	}
 	args := []string{"-v", "error", "-ss", fmt.Sprintf("%dus", timeOffset.Microseconds()), "-i", path, "-map", "0:a:0", "-vn"}
+	if duration > 0 {
+		args = append(args, "-t", fmt.Sprintf("%dus", duration.Microseconds()), "-output_ts_offset", fmt.Sprintf("%dus", timeOffset.Microseconds()))
+	}
+	if normalization != nil {
+		args = append(args, "-af", normalization.filter())
+	}
 	args = append(args, bitRateFlags...)
-	args = append(args, "-c:a", format.encoder, "-f", format.outFormat, "-")
+	args = append(args, "-c:a", format.encoder)
+	args = append(args, format.extraArgs...)
+	args = append(args, "-f", format.outFormat, "-")
 
 	stderr := new(bytes.Buffer)
 	cmd := exec.Command(ffmpegPath, args...)
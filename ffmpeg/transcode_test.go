@@ -1,10 +1,12 @@
 package ffmpeg
 
 import (
-	"github.com/juho05/log"
-	"github.com/stretchr/testify/assert"
 	"os"
 	"testing"
+
+	"github.com/juho05/crossonic-server/config"
+	"github.com/juho05/log"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestMain(m *testing.M) {
@@ -22,15 +24,15 @@ func TestTranscoder_SelectFormat(t1 *testing.T) {
 		wantMaxBitrateK int
 	}{
 		{"raw should result in an empty format", "raw", 2, 10, Format{}, 0},
-		{"select mp3", "mp3", 2, 320, formats["mp3"], 320},
-		{"mp3 bitrate too large", "mp3", 2, 500, formats["mp3"], 320},
-		{"mp3 bitrate too small", "mp3", 2, 10, formats["mp3"], 64},
-		{"select opus", "opus", 2, 512, formats["opus"], 512},
-		{"select opus mono", "opus", 1, 512, formats["opus"], 256},
-		{"opus bitrate too large", "opus", 4, 1024, formats["opus"], 512},
-		{"select ogg (bitrate too small)", "ogg", 2, 10, formats["ogg"], 96},
-		{"select vorbis default bitrate", "vorbis", 2, 0, formats["vorbis"], 192},
-		{"select unknown format", "asdf", 2, 500, formats["mp3"], 320},
+		{"select mp3", "mp3", 2, 320, builtinFormats["mp3"], 320},
+		{"mp3 bitrate too large", "mp3", 2, 500, builtinFormats["mp3"], 320},
+		{"mp3 bitrate too small", "mp3", 2, 10, builtinFormats["mp3"], 64},
+		{"select opus", "opus", 2, 512, builtinFormats["opus"], 512},
+		{"select opus mono", "opus", 1, 512, builtinFormats["opus"], 256},
+		{"opus bitrate too large", "opus", 4, 1024, builtinFormats["opus"], 512},
+		{"select ogg (bitrate too small)", "ogg", 2, 10, builtinFormats["ogg"], 96},
+		{"select vorbis default bitrate", "vorbis", 2, 0, builtinFormats["vorbis"], 192},
+		{"select unknown format", "asdf", 2, 500, builtinFormats["mp3"], 320},
 	}
 	for _, tt := range tests {
 		t1.Run(tt.name, func(t1 *testing.T) {
@@ -41,3 +43,117 @@ func TestTranscoder_SelectFormat(t1 *testing.T) {
 		})
 	}
 }
+
+func TestTranscoder_FindFormat(t1 *testing.T) {
+	tests := []struct {
+		name      string
+		codec     string
+		container string
+		want      string
+		wantOK    bool
+	}{
+		{"mp3", "mp3", "mp3", "mp3", true},
+		{"opus in ogg", "opus", "ogg", "opus", true},
+		{"vorbis in ogg", "vorbis", "ogg", "ogg", true},
+		{"aac in mp4", "aac", "mp4", "m4a", true},
+		{"flac", "flac", "flac", "flac", true},
+		{"case insensitive", "MP3", "MP3", "mp3", true},
+		{"wrong container", "mp3", "ogg", "", false},
+		{"unknown codec", "wma", "asf", "", false},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			t := &Transcoder{}
+			name, ok := t.FindFormat(tt.codec, tt.container)
+			assert.Equal(t1, tt.wantOK, ok)
+			assert.Equal(t1, tt.want, name)
+		})
+	}
+}
+
+func TestTranscoder_SelectFormatProfiles(t *testing.T) {
+	profile := config.TranscodingProfile{
+		Name:                 "mp3",
+		Codec:                "mp3",
+		Encoder:              "libshine",
+		Container:            "mp3",
+		Mime:                 "audio/mpeg",
+		MinBitRate:           32,
+		DefaultBitRate:       128,
+		MaxBitRate:           160,
+		MaxBitRatePerChannel: 160,
+	}
+	transcoder := &Transcoder{formats: map[string]Format{"mp3": NewFormat(profile)}}
+
+	f, b := transcoder.SelectFormat("mp3", 2, 320)
+	assert.Equal(t, "libshine", f.encoder)
+	assert.Equal(t, 160, b)
+
+	f, b = transcoder.SelectFormat("opus", 2, 0)
+	assert.Equal(t, "libshine", f.encoder, "unknown formats should fall back to mp3")
+	assert.Equal(t, 128, b)
+
+	transcoder = &Transcoder{formats: map[string]Format{}}
+	f, b = transcoder.SelectFormat("opus", 2, 0)
+	assert.Equal(t, Format{}, f, "should fall back to raw if mp3 is not available")
+	assert.Equal(t, 0, b)
+}
+
+func TestNormalization_filter(t *testing.T) {
+	tests := []struct {
+		name          string
+		normalization Normalization
+		want          string
+	}{
+		{"negative gain", Normalization{GainDB: -6.5, Peak: 0.9}, "volume=-6.50dB"},
+		{"gain limited by peak", Normalization{GainDB: 8, Peak: 0.5}, "volume=6.02dB"},
+		{"gain below peak limit", Normalization{GainDB: 3, Peak: 0.5}, "volume=3.00dB"},
+		{"unknown peak", Normalization{GainDB: 2}, "volume=2.00dB,alimiter=limit=1:level=0"},
+		{"unknown peak negative gain", Normalization{GainDB: -2}, "volume=-2.00dB"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.normalization.filter())
+		})
+	}
+}
+
+func TestParseAudioEncoders(t *testing.T) {
+	output := `Encoders:
+ V..... = Video
+ A..... = Audio
+ ------
+ V....D libx264              libx264 H.264 / AVC / MPEG-4 AVC / MPEG-4 part 10 (codec h264)
+ A....D aac                  AAC (Advanced Audio Coding)
+ A....D libmp3lame           libmp3lame MP3 (MPEG audio layer 3) (codec mp3)
+ A....D flac                 FLAC (Free Lossless Audio Codec)
+ S..... srt                  SubRip subtitle
+`
+	assert.Equal(t, map[string]struct{}{
+		"aac":        {},
+		"libmp3lame": {},
+		"flac":       {},
+	}, parseAudioEncoders(output))
+}
+
+func TestFormat_ClampBitRate(t *testing.T) {
+	tests := []struct {
+		name        string
+		format      Format
+		channels    int
+		maxBitRateK int
+		want        int
+	}{
+		{"zero results in default", HLSSegmentFormat, 2, 0, 192},
+		{"within bounds", HLSSegmentFormat, 2, 128, 128},
+		{"too small", HLSSegmentFormat, 2, 8, 32},
+		{"too large for mono", HLSSegmentFormat, 1, 320, 256},
+		{"too large", builtinFormats["mp3"], 6, 1000, 320},
+		{"lossless", builtinFormats["flac"], 2, 320, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.format.ClampBitRate(tt.channels, tt.maxBitRateK))
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package ffmpeg
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+// waveformSampleRate is the rate audio is resampled to before computing peaks.
+// It is high enough to keep transients visible and low enough to decode songs quickly.
+const waveformSampleRate = 8000
+
+// Peaks decodes the first audio stream of the file at path and returns the highest absolute
+// amplitude of each of buckets equally long parts of the song in the range [0,1].
+// duration is used to size the buckets and should be the duration of the song.
+func (a *Analyzer) Peaks(ctx context.Context, path string, duration time.Duration, buckets int) ([]float32, error) {
+	if buckets <= 0 {
+		return nil, fmt.Errorf("ffmpeg: compute peaks: invalid bucket count: %d", buckets)
+	}
+	peaks := newPeakWriter(int64(duration.Seconds()*waveformSampleRate), buckets)
+	stderr := new(bytes.Buffer)
+	cmd := exec.CommandContext(ctx, ffmpegPath, "-v", "error", "-nostdin", "-i", path, "-map", "0:a:0", "-vn",
+		"-ac", "1", "-ar", strconv.Itoa(waveformSampleRate), "-f", "f32le", "-")
+	cmd.Stdout = peaks
+	cmd.Stderr = stderr
+	err := cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg: compute peaks: %w\n%s", err, stderr.String())
+	}
+	return peaks.peaks, nil
+}
+
+// peakWriter consumes 32-bit little-endian float samples and keeps the peak of each bucket
+// without storing the samples.
+type peakWriter struct {
+	samplesPerBucket float64
+	peaks            []float32
+
+	index   int64
+	partial []byte
+}
+
+func newPeakWriter(totalSamples int64, buckets int) *peakWriter {
+	return &peakWriter{
+		samplesPerBucket: max(float64(totalSamples)/float64(buckets), 1),
+		peaks:            make([]float32, buckets),
+	}
+}
+
+func (p *peakWriter) Write(data []byte) (int, error) {
+	n := len(data)
+	if len(p.partial) > 0 {
+		missing := 4 - len(p.partial)
+		if len(data) < missing {
+			p.partial = append(p.partial, data...)
+			return n, nil
+		}
+		p.partial = append(p.partial, data[:missing]...)
+		p.add(math.Float32frombits(binary.LittleEndian.Uint32(p.partial)))
+		p.partial = p.partial[:0]
+		data = data[missing:]
+	}
+	for len(data) >= 4 {
+		p.add(math.Float32frombits(binary.LittleEndian.Uint32(data)))
+		data = data[4:]
+	}
+	p.partial = append(p.partial, data...)
+	return n, nil
+}
+
+// add adds a sample to its bucket. Samples beyond the expected duration belong to the last bucket.
+func (p *peakWriter) add(sample float32) {
+	bucket := min(int(float64(p.index)/p.samplesPerBucket), len(p.peaks)-1)
+	p.index++
+	sample = min(float32(math.Abs(float64(sample))), 1)
+	if sample > p.peaks[bucket] {
+		p.peaks[bucket] = sample
+	}
+}
@@ -0,0 +1,47 @@
+package ffmpeg
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeakWriter(t *testing.T) {
+	samples := []float32{0.1, -0.5, 0.2, 0.3, -0.05, 0.01, 2, 0.4}
+	data := make([]byte, 0, len(samples)*4)
+	for _, s := range samples {
+		data = binary.LittleEndian.AppendUint32(data, math.Float32bits(s))
+	}
+
+	t.Run("aligned writes", func(t *testing.T) {
+		w := newPeakWriter(int64(len(samples)), 4)
+		_, err := w.Write(data)
+		assert.NoError(t, err)
+		assert.Equal(t, []float32{0.5, 0.3, 0.05, 1}, w.peaks)
+	})
+
+	t.Run("split samples", func(t *testing.T) {
+		w := newPeakWriter(int64(len(samples)), 4)
+		for i := 0; i < len(data); i += 3 {
+			_, err := w.Write(data[i:min(i+3, len(data))])
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, []float32{0.5, 0.3, 0.05, 1}, w.peaks)
+	})
+
+	t.Run("longer than expected", func(t *testing.T) {
+		w := newPeakWriter(4, 2)
+		_, err := w.Write(data)
+		assert.NoError(t, err)
+		assert.Equal(t, []float32{0.5, 1}, w.peaks)
+	})
+
+	t.Run("more buckets than samples", func(t *testing.T) {
+		w := newPeakWriter(2, 4)
+		_, err := w.Write(data[:8])
+		assert.NoError(t, err)
+		assert.Equal(t, []float32{0.1, 0.5, 0, 0}, w.peaks)
+	})
+}
@@ -0,0 +1,312 @@
+// Package fingerprint identifies media files by their audio payload independently of their tags.
+package fingerprint
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+)
+
+type Fingerprint struct {
+	// Audio is the hash of the audio payload of the file. It does not change if only the tags of the file are edited.
+	Audio string
+	// Content is the hash of the whole file.
+	Content string
+}
+
+// span is a byte range [start, end) of a file.
+type span struct {
+	start int64
+	end   int64
+}
+
+// File reads the file at path once and returns the hashes of its audio payload and its content.
+// Tags are excluded from the audio payload for MP3 (ID3v1, ID3v2, APEv2, Lyrics3v2), FLAC, Ogg (Vorbis, Opus, FLAC),
+// MP4, WAV and AIFF files. The whole file is used as the audio payload of other formats.
+func File(path string) (Fingerprint, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Fingerprint{}, fmt.Errorf("fingerprint: open: %w", err)
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return Fingerprint{}, fmt.Errorf("fingerprint: stat: %w", err)
+	}
+
+	spans, err := audioSpans(file, stat.Size())
+	if err != nil {
+		return Fingerprint{}, fmt.Errorf("fingerprint: find audio payload: %w", err)
+	}
+
+	contentHash := sha256.New()
+	audioHash := &spanWriter{hash: sha256.New(), spans: spans}
+	_, err = io.Copy(io.MultiWriter(contentHash, audioHash), io.NewSectionReader(file, 0, stat.Size()))
+	if err != nil {
+		return Fingerprint{}, fmt.Errorf("fingerprint: read: %w", err)
+	}
+	return Fingerprint{
+		Audio:   hex.EncodeToString(audioHash.hash.Sum(nil)),
+		Content: hex.EncodeToString(contentHash.Sum(nil)),
+	}, nil
+}
+
+// spanWriter hashes only the bytes written at the offsets of spans. spans must be sorted and must not overlap.
+type spanWriter struct {
+	hash   hash.Hash
+	spans  []span
+	offset int64
+}
+
+func (w *spanWriter) Write(p []byte) (int, error) {
+	start := w.offset
+	end := start + int64(len(p))
+	for len(w.spans) > 0 && w.spans[0].end <= start {
+		w.spans = w.spans[1:]
+	}
+	for _, s := range w.spans {
+		if s.start >= end {
+			break
+		}
+		w.hash.Write(p[max(s.start, start)-start : min(s.end, end)-start])
+	}
+	w.offset = end
+	return len(p), nil
+}
+
+// audioSpans returns the byte ranges of the audio payload of the file.
+func audioSpans(r io.ReaderAt, size int64) ([]span, error) {
+	start := skipID3v2(r, size)
+	end := trailingTagsStart(r, start, size)
+
+	magic := make([]byte, 12)
+	n, _ := r.ReadAt(magic, start)
+	magic = magic[:n]
+
+	switch {
+	case bytes.HasPrefix(magic, []byte("fLaC")):
+		return flacSpans(r, start, end)
+	case bytes.HasPrefix(magic, []byte("OggS")):
+		return oggSpans(r, start, end)
+	case len(magic) >= 12 && bytes.Equal(magic[0:4], []byte("RIFF")) && bytes.Equal(magic[8:12], []byte("WAVE")):
+		return chunkSpans(r, start+12, end, binary.LittleEndian, "data")
+	case len(magic) >= 12 && bytes.Equal(magic[0:4], []byte("FORM")) && (bytes.Equal(magic[8:12], []byte("AIFF")) || bytes.Equal(magic[8:12], []byte("AIFC"))):
+		return chunkSpans(r, start+12, end, binary.BigEndian, "SSND")
+	case len(magic) >= 8 && bytes.Equal(magic[4:8], []byte("ftyp")):
+		return mp4Spans(r, start, end)
+	}
+	return []span{{start: start, end: end}}, nil
+}
+
+// skipID3v2 returns the offset after all ID3v2 tags at the beginning of the file.
+func skipID3v2(r io.ReaderAt, size int64) int64 {
+	var offset int64
+	header := make([]byte, 10)
+	for {
+		_, err := r.ReadAt(header, offset)
+		if err != nil || !bytes.Equal(header[0:3], []byte("ID3")) {
+			return offset
+		}
+		tagSize := 10 + int64(syncsafe(header[6:10]))
+		if header[5]&0x10 != 0 {
+			// footer
+			tagSize += 10
+		}
+		if offset+tagSize > size {
+			return offset
+		}
+		offset += tagSize
+	}
+}
+
+// trailingTagsStart returns the offset of the first ID3v1, APEv2 or Lyrics3v2 tag at the end of the file.
+func trailingTagsStart(r io.ReaderAt, start, end int64) int64 {
+	for {
+		switch {
+		case end-start >= 128 && hasMagic(r, end-128, "TAG"):
+			end -= 128
+		case end-start >= 32 && hasMagic(r, end-32, "APETAGEX"):
+			footer := make([]byte, 32)
+			if _, err := r.ReadAt(footer, end-32); err != nil {
+				return end
+			}
+			// the size includes the footer but not the header
+			tagSize := int64(binary.LittleEndian.Uint32(footer[12:16]))
+			if binary.LittleEndian.Uint32(footer[20:24])&(1<<31) != 0 {
+				tagSize += 32
+			}
+			if tagSize < 32 || end-tagSize < start {
+				return end
+			}
+			end -= tagSize
+		case end-start >= 15 && hasMagic(r, end-9, "LYRICS200"):
+			sizeStr := make([]byte, 6)
+			if _, err := r.ReadAt(sizeStr, end-15); err != nil {
+				return end
+			}
+			var tagSize int64
+			if _, err := fmt.Sscanf(string(sizeStr), "%06d", &tagSize); err != nil || end-15-tagSize < start {
+				return end
+			}
+			end -= tagSize + 15
+		default:
+			return end
+		}
+	}
+}
+
+// flacSpans skips the metadata blocks of a FLAC stream at start.
+func flacSpans(r io.ReaderAt, start, end int64) ([]span, error) {
+	offset := start + 4
+	header := make([]byte, 4)
+	for {
+		_, err := r.ReadAt(header, offset)
+		if err != nil {
+			return nil, fmt.Errorf("read flac metadata block header: %w", err)
+		}
+		offset += 4 + (int64(header[1])<<16 | int64(header[2])<<8 | int64(header[3]))
+		if header[0]&0x80 != 0 {
+			break
+		}
+	}
+	if offset > end {
+		return nil, errors.New("flac metadata blocks exceed file")
+	}
+	return []span{{start: offset, end: end}}, nil
+}
+
+// chunkSpans returns the payloads of the chunks with the ID of a RIFF or IFF file.
+func chunkSpans(r io.ReaderAt, offset, end int64, order binary.ByteOrder, id string) ([]span, error) {
+	var spans []span
+	header := make([]byte, 8)
+	for offset+8 <= end {
+		_, err := r.ReadAt(header, offset)
+		if err != nil {
+			return nil, fmt.Errorf("read chunk header: %w", err)
+		}
+		size := int64(order.Uint32(header[4:8]))
+		if string(header[0:4]) == id {
+			spans = append(spans, span{start: offset + 8, end: min(offset+8+size, end)})
+		}
+		// chunks are padded to an even size
+		offset += 8 + size + size%2
+	}
+	return spans, nil
+}
+
+// mp4Spans returns the payloads of the top-level mdat boxes of an MP4 file.
+func mp4Spans(r io.ReaderAt, offset, end int64) ([]span, error) {
+	var spans []span
+	header := make([]byte, 16)
+	for offset+8 <= end {
+		_, err := r.ReadAt(header[:8], offset)
+		if err != nil {
+			return nil, fmt.Errorf("read box header: %w", err)
+		}
+		size := int64(binary.BigEndian.Uint32(header[0:4]))
+		headerSize := int64(8)
+		switch size {
+		case 0:
+			// the box extends to the end of the file
+			size = end - offset
+		case 1:
+			_, err := r.ReadAt(header[8:16], offset+8)
+			if err != nil {
+				return nil, fmt.Errorf("read extended box size: %w", err)
+			}
+			size = int64(binary.BigEndian.Uint64(header[8:16]))
+			headerSize = 16
+		}
+		if size < headerSize {
+			return nil, fmt.Errorf("invalid box size %d", size)
+		}
+		if string(header[4:8]) == "mdat" {
+			spans = append(spans, span{start: offset + headerSize, end: min(offset+size, end)})
+		}
+		offset += size
+	}
+	return spans, nil
+}
+
+// oggSpans returns the payloads of all Ogg pages after the header packets of the codec.
+// The page headers are excluded because their sequence numbers and checksums depend on the size of the comment header.
+func oggSpans(r io.ReaderAt, offset, end int64) ([]span, error) {
+	var spans []span
+	headerPackets := -1
+	packets := 0
+	header := make([]byte, 27)
+	lacing := make([]byte, 255)
+	for offset+27 <= end {
+		_, err := r.ReadAt(header, offset)
+		if err != nil {
+			return nil, fmt.Errorf("read ogg page header: %w", err)
+		}
+		if !bytes.Equal(header[0:4], []byte("OggS")) {
+			return nil, errors.New("invalid ogg page")
+		}
+		segments := lacing[:header[26]]
+		_, err = r.ReadAt(segments, offset+27)
+		if err != nil {
+			return nil, fmt.Errorf("read ogg lacing values: %w", err)
+		}
+		payloadStart := offset + 27 + int64(len(segments))
+		var payloadSize int64
+		for _, l := range segments {
+			payloadSize += int64(l)
+		}
+
+		if headerPackets < 0 {
+			headerPackets = oggHeaderPacketCount(r, payloadStart, payloadSize)
+		}
+		if packets >= headerPackets {
+			spans = append(spans, span{start: payloadStart, end: min(payloadStart+payloadSize, end)})
+		} else {
+			for _, l := range segments {
+				if l < 255 {
+					packets++
+				}
+			}
+		}
+		offset = payloadStart + payloadSize
+	}
+	return spans, nil
+}
+
+// oggHeaderPacketCount returns the number of header packets of the codec of the first packet of an Ogg stream.
+// Returns 0 for unknown codecs so that all pages are part of the audio payload.
+func oggHeaderPacketCount(r io.ReaderAt, offset, size int64) int {
+	packet := make([]byte, min(size, 16))
+	_, err := r.ReadAt(packet, offset)
+	if err != nil {
+		return 0
+	}
+	switch {
+	case bytes.HasPrefix(packet, []byte("\x01vorbis")):
+		// identification, comment and setup header
+		return 3
+	case bytes.HasPrefix(packet, []byte("OpusHead")):
+		// identification and comment header
+		return 2
+	case bytes.HasPrefix(packet, []byte("\x7fFLAC")) && len(packet) >= 9:
+		// mapping header followed by the number of metadata block packets
+		return 1 + int(binary.BigEndian.Uint16(packet[7:9]))
+	}
+	return 0
+}
+
+func hasMagic(r io.ReaderAt, offset int64, magic string) bool {
+	b := make([]byte, len(magic))
+	_, err := r.ReadAt(b, offset)
+	return err == nil && string(b) == magic
+}
+
+func syncsafe(b []byte) uint32 {
+	return uint32(b[0]&0x7f)<<21 | uint32(b[1]&0x7f)<<14 | uint32(b[2]&0x7f)<<7 | uint32(b[3]&0x7f)
+}
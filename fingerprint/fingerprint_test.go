@@ -0,0 +1,141 @@
+package fingerprint
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFile(t *testing.T) {
+	audio := bytes.Repeat([]byte("audio frame "), 500)
+	otherAudio := bytes.Repeat([]byte("other frame "), 500)
+
+	tests := []struct {
+		name string
+		// build creates a file with the audio payload and tags of the given size
+		build func(audio []byte, tagSize int) []byte
+	}{
+		{"mp3", func(audio []byte, tagSize int) []byte {
+			return concat(id3v2(tagSize), audio, apev2(tagSize), id3v1())
+		}},
+		{"flac", func(audio []byte, tagSize int) []byte {
+			return concat([]byte("fLaC"), flacBlock(0, 34, false), flacBlock(4, tagSize, true), audio)
+		}},
+		{"flac with id3v2", func(audio []byte, tagSize int) []byte {
+			return concat(id3v2(tagSize), []byte("fLaC"), flacBlock(0, 34, true), audio)
+		}},
+		{"wav", func(audio []byte, tagSize int) []byte {
+			body := concat([]byte("WAVE"), riffChunk("fmt ", make([]byte, 16)), riffChunk("LIST", make([]byte, tagSize)), riffChunk("data", audio))
+			return concat([]byte("RIFF"), le32(len(body)), body)
+		}},
+		{"mp4", func(audio []byte, tagSize int) []byte {
+			return concat(mp4Box("ftyp", []byte("M4A \x00\x00\x00\x00")), mp4Box("moov", make([]byte, tagSize)), mp4Box("mdat", audio))
+		}},
+		{"opus", func(audio []byte, tagSize int) []byte {
+			return concat(
+				oggPage([]byte("OpusHead"+"\x01\x02\x38\x01\x80\xbb\x00\x00\x00\x00\x00")),
+				oggPage(append([]byte("OpusTags"), make([]byte, tagSize)...)),
+				oggPage(audio[:len(audio)/2]),
+				oggPage(audio[len(audio)/2:]),
+			)
+		}},
+		{"vorbis", func(audio []byte, tagSize int) []byte {
+			return concat(
+				oggPage([]byte("\x01vorbis\x00\x00\x00\x00\x02")),
+				oggPage(append([]byte("\x03vorbis"), make([]byte, tagSize)...), []byte("\x05vorbis setup")),
+				oggPage(audio),
+			)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := fingerprintBytes(t, tt.build(audio, 10))
+			b := fingerprintBytes(t, tt.build(audio, 300))
+			c := fingerprintBytes(t, tt.build(otherAudio, 10))
+			assert.Equal(t, a.Audio, b.Audio, "audio fingerprint should not depend on the tags")
+			assert.NotEqual(t, a.Content, b.Content, "content hash should depend on the tags")
+			assert.NotEqual(t, a.Audio, c.Audio, "audio fingerprint should depend on the audio")
+		})
+	}
+}
+
+func TestFile_unknownFormat(t *testing.T) {
+	a := fingerprintBytes(t, []byte("some unknown format"))
+	b := fingerprintBytes(t, []byte("some unknown format!"))
+	assert.Equal(t, a.Audio, a.Content, "whole file should be the audio payload")
+	assert.NotEqual(t, a.Audio, b.Audio)
+}
+
+func fingerprintBytes(t *testing.T, data []byte) Fingerprint {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(path, data, 0644))
+	fp, err := File(path)
+	require.NoError(t, err)
+	return fp
+}
+
+func concat(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+func le32(v int) []byte {
+	return binary.LittleEndian.AppendUint32(nil, uint32(v))
+}
+
+func be32(v int) []byte {
+	return binary.BigEndian.AppendUint32(nil, uint32(v))
+}
+
+func id3v2(size int) []byte {
+	header := []byte{'I', 'D', '3', 4, 0, 0, byte(size >> 21 & 0x7f), byte(size >> 14 & 0x7f), byte(size >> 7 & 0x7f), byte(size & 0x7f)}
+	return concat(header, make([]byte, size))
+}
+
+func id3v1() []byte {
+	return concat([]byte("TAG"), make([]byte, 125))
+}
+
+func apev2(size int) []byte {
+	footer := concat([]byte("APETAGEX"), le32(2000), le32(size+32), le32(0), le32(0), make([]byte, 8))
+	return concat(make([]byte, size), footer)
+}
+
+func flacBlock(typ byte, size int, last bool) []byte {
+	if last {
+		typ |= 0x80
+	}
+	return concat([]byte{typ, byte(size >> 16), byte(size >> 8), byte(size)}, make([]byte, size))
+}
+
+func riffChunk(id string, data []byte) []byte {
+	chunk := concat([]byte(id), le32(len(data)), data)
+	if len(data)%2 == 1 {
+		chunk = append(chunk, 0)
+	}
+	return chunk
+}
+
+func mp4Box(typ string, data []byte) []byte {
+	return concat(be32(8+len(data)), []byte(typ), data)
+}
+
+// oggPage creates an Ogg page containing the packets. Checksums and sequence numbers are not set.
+func oggPage(packets ...[]byte) []byte {
+	var lacing []byte
+	for _, p := range packets {
+		n := len(p)
+		for n >= 255 {
+			lacing = append(lacing, 255)
+			n -= 255
+		}
+		lacing = append(lacing, byte(n))
+	}
+	header := concat([]byte("OggS"), make([]byte, 22), []byte{byte(len(lacing))})
+	return concat(header, lacing, concat(packets...))
+}
@@ -6,13 +6,20 @@ import (
 
 func (h *Handler) registerCrossonicRoutes(r chi.Router) {
 	r.Use(h.subsonicMiddleware)
-	registerRoute(r, "/connectListenBrainz", h.handleConnectListenbrainz)
-	registerRoute(r, "/updateListenBrainzConfig", h.handleUpdateListenbrainzConfig)
+	registerRoute(r, "/connectListenBrainz", h.requirePermission(permissionListenBrainz, h.handleConnectListenbrainz))
+	registerRoute(r, "/updateListenBrainzConfig", h.requirePermission(permissionListenBrainz, h.handleUpdateListenbrainzConfig))
 	registerRoute(r, "/getListenBrainzConfig", h.handleGetListenbrainzConfig)
-	registerRoute(r, "/setPlaylistCover", h.handleSetPlaylistCover)
+	registerRoute(r, "/updatePlaybackConfig", h.requirePermission(permissionSettings, h.handleUpdatePlaybackConfig))
+	registerRoute(r, "/getPlaybackConfig", h.handleGetPlaybackConfig)
+	registerRoute(r, "/setPlaylistCover", h.requirePermission(permissionPlaylist, h.handleSetPlaylistCover))
 	registerRoute(r, "/getRecap", h.handleGetRecap)
 	registerRoute(r, "/getTopSongsRecap", h.handleGetTopSongsRecap)
 	registerRoute(r, "/getAppearsOn", h.handleGetAppearsOn)
 	registerRoute(r, "/getSongs", h.handleGetSongs)
 	registerRoute(r, "/getAlternateAlbumVersions", h.handleGetAlternateAlbumVersions)
+	registerRoute(r, "/getWaveform", h.handleGetWaveform)
+	registerRoute(r, "/getSchedulerStatus", h.requirePermission(permissionAdmin, h.handleGetSchedulerStatus))
+	registerRoute(r, "/cancelScan", h.requirePermission(permissionScan, h.handleCancelScan))
+	registerRoute(r, "/getScanErrors", h.requirePermission(permissionScan, h.handleGetScanErrors))
+	registerRoute(r, "/getScanProblems", h.requirePermission(permissionScan, h.handleGetScanProblems))
 }
@@ -27,6 +27,10 @@ func (h *Handler) handleGetSongs(w http.ResponseWriter, r *http.Request) {
 	if !ok {
 		return
 	}
+	detectedBPM, ok := q.BoolDef("detectedBpm", false)
+	if !ok {
+		return
+	}
 
 	fromYear, ok := q.Int("fromYear")
 	if !ok {
@@ -76,6 +80,7 @@ func (h *Handler) handleGetSongs(w http.ResponseWriter, r *http.Request) {
 		OnlyStarred:    onlyStarred,
 		MinBPM:         minBPM,
 		MaxBPM:         maxBPM,
+		UseDetectedBPM: detectedBPM,
 		FromYear:       fromYear,
 		ToYear:         toYear,
 		Genres:         genres,
@@ -92,6 +97,14 @@ func (h *Handler) handleGetSongs(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if detectedBPM {
+		for _, s := range songs {
+			if s.BPM == nil {
+				s.BPM = s.DetectedBPM
+			}
+		}
+	}
+
 	response := responses.New()
 	response.Songs = &responses.Songs{
 		Songs: responses.NewSongs(songs, h.Config),
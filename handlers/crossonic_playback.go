@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/juho05/crossonic-server/handlers/responses"
+	"github.com/juho05/crossonic-server/repos"
+)
+
+func (h *Handler) handleUpdatePlaybackConfig(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	var normalize repos.Optional[repos.NormalizeMode]
+	if q.Has("normalize") {
+		mode, ok := parseNormalizeMode(q.Str("normalize"))
+		if !ok {
+			q.invalidParameter("normalize")
+			return
+		}
+		normalize = repos.NewOptionalFull(mode)
+	}
+
+	err := h.DB.User().Update(r.Context(), q.User(), repos.UpdateUserParams{
+		Normalize: normalize,
+	})
+	if err != nil {
+		respondErr(w, q.Format(), fmt.Errorf("update playback config: %w", err))
+		return
+	}
+
+	h.handleGetPlaybackConfig(w, r)
+}
+
+func (h *Handler) handleGetPlaybackConfig(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+	user, err := h.DB.User().FindByName(r.Context(), q.User())
+	if err != nil {
+		respondInternalErr(w, q.Format(), fmt.Errorf("get playback config: %w", err))
+		return
+	}
+	res := responses.New()
+	res.PlaybackConfig = &responses.PlaybackConfig{
+		Normalize: string(user.Normalize),
+	}
+	res.EncodeOrLog(w, q.Format())
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/juho05/crossonic-server/handlers/responses"
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/juho05/crossonic-server/scanner"
+	"github.com/juho05/crossonic-server/util"
+)
+
+func (h *Handler) handleCancelScan(w http.ResponseWriter, r *http.Request) {
+	err := h.Scanner.Cancel()
+	if err != nil && !errors.Is(err, scanner.ErrNotScanning) {
+		respondInternalErr(w, getQuery(w, r).Format(), err)
+		return
+	}
+	h.handleGetScanStatus(w, r)
+}
+
+func (h *Handler) handleGetScanErrors(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	scanErrors := h.Scanner.ScanErrors()
+	errs := make([]*responses.ScanError, 0, len(scanErrors))
+	for _, e := range scanErrors {
+		scanErr := &responses.ScanError{
+			Message: e.Message,
+			Time:    e.Time,
+		}
+		if e.Path != "" {
+			scanErr.Path = &e.Path
+		}
+		errs = append(errs, scanErr)
+	}
+
+	res := responses.New()
+	res.ScanErrors = &responses.ScanErrors{
+		Errors: errs,
+	}
+	res.EncodeOrLog(w, q.Format())
+}
+
+func (h *Handler) handleGetScanProblems(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	params := repos.FindScanProblemsParams{}
+	for _, t := range q.Strs("type") {
+		if !slices.Contains(repos.ScanProblemTypes, repos.ScanProblemType(t)) {
+			q.invalidParameter("type")
+			return
+		}
+		params.Types = append(params.Types, repos.ScanProblemType(t))
+	}
+
+	if q.Has("musicFolderId") {
+		musicFolderIDs, ok := q.Ints("musicFolderId")
+		if !ok {
+			return
+		}
+		params.MusicFolderIDs = musicFolderIDs
+	}
+
+	var ok bool
+	params.Paginate, ok = q.Paginate("count", "offset", 100)
+	if !ok {
+		return
+	}
+
+	problems, err := h.DB.ScanProblem().FindAll(r.Context(), params)
+	if err != nil {
+		respondInternalErr(w, q.Format(), fmt.Errorf("get scan problems: find all: %w", err))
+		return
+	}
+
+	totalCount, err := h.DB.ScanProblem().Count(r.Context(), params)
+	if err != nil {
+		respondInternalErr(w, q.Format(), fmt.Errorf("get scan problems: count: %w", err))
+		return
+	}
+
+	res := responses.New()
+	res.ScanProblems = &responses.ScanProblems{
+		TotalCount: totalCount,
+		Problems: util.Map(problems, func(p *repos.ScanProblem) *responses.ScanProblem {
+			return &responses.ScanProblem{
+				Path:          p.Path,
+				Type:          string(p.Type),
+				Message:       p.Message,
+				MusicFolderID: p.MusicFolderID,
+				Created:       p.Created,
+				Updated:       p.Updated,
+			}
+		}),
+	}
+	res.EncodeOrLog(w, q.Format())
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/juho05/crossonic-server/handlers/responses"
+	"github.com/juho05/crossonic-server/util"
+)
+
+func (h *Handler) handleGetSchedulerStatus(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	status := h.Scheduler.Status()
+	jobs := make([]*responses.ScheduledJob, 0, len(status))
+	for _, s := range status {
+		job := &responses.ScheduledJob{
+			Name:    s.Name,
+			Running: s.Running,
+			LastRun: s.LastRun,
+			NextRun: s.NextRun,
+		}
+		if s.Schedule != nil {
+			job.Schedule = util.ToPtr(s.Schedule.String())
+		}
+		if s.LastRun != nil {
+			job.LastDurationMS = util.ToPtr(s.LastDuration.Milliseconds())
+		}
+		if s.LastError != nil {
+			job.LastError = util.ToPtr(s.LastError.Error())
+		}
+		jobs = append(jobs, job)
+	}
+
+	res := responses.New()
+	res.SchedulerStatus = &responses.SchedulerStatus{
+		Jobs: jobs,
+	}
+	res.EncodeOrLog(w, q.Format())
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/juho05/crossonic-server"
+	"github.com/juho05/crossonic-server/cache"
+	"github.com/juho05/crossonic-server/handlers/responses"
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/juho05/log"
+)
+
+const (
+	defaultWaveformBuckets = 500
+	maxWaveformBuckets     = 5000
+)
+
+func (h *Handler) handleGetWaveform(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	id, ok := q.IDTypeReq("id", []crossonic.IDType{crossonic.IDTypeSong})
+	if !ok {
+		return
+	}
+
+	buckets, ok := q.IntRange("buckets", 1, maxWaveformBuckets)
+	if !ok {
+		return
+	}
+	bucketCount := defaultWaveformBuckets
+	if buckets != nil {
+		bucketCount = *buckets
+	}
+
+	info, err := h.DB.Song().GetStreamInfo(r.Context(), id, q.User())
+	if err != nil {
+		respondErr(w, q.Format(), fmt.Errorf("get waveform: get stream info: %w", err))
+		return
+	}
+
+	peaks, err := h.getOrCreateWaveform(r.Context(), id, info, bucketCount)
+	if err != nil {
+		respondInternalErr(w, q.Format(), fmt.Errorf("get waveform: %w", err))
+		return
+	}
+
+	res := responses.New()
+	res.Waveform = &responses.Waveform{
+		Peaks: peaks,
+	}
+	res.EncodeOrLog(w, q.Format())
+}
+
+// getOrCreateWaveform returns the cached peaks of the song or computes and caches them.
+// Cache keys start with the song ID so that the scanner can invalidate them when the file changes.
+func (h *Handler) getOrCreateWaveform(ctx context.Context, id string, info *repos.SongStreamInfo, buckets int) ([]float32, error) {
+	cacheKey := id + "-" + strconv.Itoa(buckets)
+	if obj, ok := h.WaveformCache.GetObject(cacheKey); ok && obj.IsComplete() {
+		peaks, err := readWaveform(ctx, obj)
+		if err == nil && len(peaks) == buckets {
+			return peaks, nil
+		}
+		log.Errorf("get waveform: invalid cache object %s: %v", cacheKey, err)
+		err = h.WaveformCache.DeleteObject(cacheKey)
+		if err != nil {
+			return nil, fmt.Errorf("delete invalid cache object: %w", err)
+		}
+	}
+
+	peaks, err := h.Analyzer.Peaks(ctx, info.Path, info.Duration.ToStd(), buckets)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := h.WaveformCache.CreateObject(cacheKey)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			// another request computed the same waveform in the meantime
+			return peaks, nil
+		}
+		return nil, fmt.Errorf("create cache object: %w", err)
+	}
+	data := make([]byte, 0, len(peaks)*4)
+	for _, p := range peaks {
+		data = binary.LittleEndian.AppendUint32(data, math.Float32bits(p))
+	}
+	_, err = obj.Write(data)
+	if err == nil {
+		err = obj.SetComplete()
+	}
+	if err != nil {
+		deleteErr := h.WaveformCache.DeleteObject(cacheKey)
+		if deleteErr != nil {
+			log.Errorf("get waveform: %s", deleteErr)
+		}
+		return nil, fmt.Errorf("write cache object: %w", err)
+	}
+	return peaks, nil
+}
+
+func readWaveform(ctx context.Context, obj *cache.Object) ([]float32, error) {
+	r, err := obj.Reader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	peaks := make([]float32, len(data)/4)
+	for i := range peaks {
+		peaks[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:]))
+	}
+	return peaks, nil
+}
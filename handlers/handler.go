@@ -12,10 +12,15 @@ import (
 	"github.com/juho05/crossonic-server/cache"
 	"github.com/juho05/crossonic-server/config"
 	"github.com/juho05/crossonic-server/ffmpeg"
+	"github.com/juho05/crossonic-server/jukebox"
 	"github.com/juho05/crossonic-server/lastfm"
 	"github.com/juho05/crossonic-server/listenbrainz"
+	"github.com/juho05/crossonic-server/metadata"
+	"github.com/juho05/crossonic-server/podcast"
 	"github.com/juho05/crossonic-server/repos"
 	"github.com/juho05/crossonic-server/scanner"
+	"github.com/juho05/crossonic-server/scheduler"
+	"github.com/juho05/crossonic-server/similarity"
 	"github.com/juho05/log"
 )
 
@@ -26,9 +31,17 @@ type Handler struct {
 	ListenBrainz *listenbrainz.ListenBrainz
 	LastFM       *lastfm.LastFm
 	Transcoder   *ffmpeg.Transcoder
+	Analyzer     *ffmpeg.Analyzer
+	Podcasts     *podcast.Podcasts
+	Similarity   *similarity.Similarity
+	Metadata     *metadata.Refresher
+	Scheduler    *scheduler.Scheduler
+	// Jukebox is nil if no jukebox output is configured.
+	Jukebox *jukebox.Jukebox
 
 	CoverCache     *cache.Cache
 	TranscodeCache *cache.Cache
+	WaveformCache  *cache.Cache
 
 	Config config.Config
 
@@ -43,15 +56,22 @@ type Handler struct {
 	dummyEncryptedPassword []byte
 }
 
-func New(conf config.Config, db repos.DB, scanner *scanner.Scanner, listenBrainz *listenbrainz.ListenBrainz, lastFM *lastfm.LastFm, transcoder *ffmpeg.Transcoder, transcodeCache *cache.Cache, coverCache *cache.Cache) (*Handler, error) {
+func New(conf config.Config, db repos.DB, scanner *scanner.Scanner, listenBrainz *listenbrainz.ListenBrainz, lastFM *lastfm.LastFm, transcoder *ffmpeg.Transcoder, podcasts *podcast.Podcasts, similarity *similarity.Similarity, metadata *metadata.Refresher, scheduler *scheduler.Scheduler, jukebox *jukebox.Jukebox, analyzer *ffmpeg.Analyzer, transcodeCache *cache.Cache, coverCache *cache.Cache, waveformCache *cache.Cache) (*Handler, error) {
 	h := &Handler{
 		DB:              db,
 		Scanner:         scanner,
 		ListenBrainz:    listenBrainz,
 		LastFM:          lastFM,
 		Transcoder:      transcoder,
+		Analyzer:        analyzer,
+		Podcasts:        podcasts,
+		Similarity:      similarity,
+		Metadata:        metadata,
+		Scheduler:       scheduler,
+		Jukebox:         jukebox,
 		TranscodeCache:  transcodeCache,
 		CoverCache:      coverCache,
+		WaveformCache:   waveformCache,
 		Config:          conf,
 		authFailures:    make(map[string][]time.Time),
 		authCleanupStop: make(chan struct{}),
@@ -115,6 +135,7 @@ func (h *Handler) registerRoutes() {
 
 	r.Route("/rest/crossonic", h.registerCrossonicRoutes)
 	r.Route("/rest", h.registerSubsonicRoutes)
+	r.Route("/share/{shareID}", h.registerShareRoutes)
 	if h.Config.FrontendDir != "" {
 		r.Group(func(r chi.Router) {
 			r.Use(func(next http.Handler) http.Handler {
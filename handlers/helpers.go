@@ -21,6 +21,13 @@ func respondNotFoundErr(w http.ResponseWriter, format, message string) {
 	responses.EncodeError(w, format, message, responses.SubsonicErrorNotFound)
 }
 
+func respondNotAuthorizedErr(w http.ResponseWriter, format, message string) {
+	if message == "" {
+		message = "user is not authorized for the given operation"
+	}
+	responses.EncodeError(w, format, message, responses.SubsonicErrorUserNotAuthorized)
+}
+
 func respondErr(w http.ResponseWriter, format string, err error) {
 	if errors.Is(err, repos.ErrNotFound) {
 		log.Error(err)
@@ -15,6 +15,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/juho05/crossonic-server"
 	"github.com/juho05/crossonic-server/handlers/responses"
 	"github.com/juho05/crossonic-server/repos"
 	"github.com/juho05/log"
@@ -29,6 +31,7 @@ type ContextKey int
 
 const (
 	ContextKeyQuery ContextKey = iota
+	ContextKeyShare
 )
 
 var errAuthTypeNotSupported = errors.New("auth type not supported")
@@ -151,13 +154,51 @@ func (h *Handler) subsonicMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-func (h *Handler) passwordAuth(ctx context.Context, username, password string) (bool, error) {
-	if strings.HasPrefix(password, "enc:") {
-		decoded, err := hex.DecodeString(strings.TrimPrefix(password, "enc:"))
+// shareMiddleware resolves the share in the {shareID} URL parameter and makes its content
+// accessible without authentication using the permissions of the share owner.
+func (h *Handler) shareMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		values := r.URL.Query()
+		id := chi.URLParam(r, "shareID")
+		if !crossonic.IDRegex.MatchString(id) || !crossonic.IsIDType(id, crossonic.IDTypeShare) {
+			respondNotFoundErr(w, values.Get("f"), "share not found")
+			return
+		}
+		share, err := h.DB.Share().FindPublicByID(r.Context(), id)
 		if err != nil {
-			return false, fmt.Errorf("failed to decode hex encoded password: %w", err)
+			if errors.Is(err, repos.ErrNotFound) {
+				respondNotFoundErr(w, values.Get("f"), "share not found")
+				return
+			}
+			respondInternalErr(w, values.Get("f"), fmt.Errorf("share middleware: find share: %w", err))
+			return
 		}
-		password = string(decoded)
+		for _, k := range []string{"p", "t", "s", "apiKey"} {
+			values.Del(k)
+		}
+		values.Set("u", share.User)
+		ctx := context.WithValue(r.Context(), ContextKeyQuery, values)
+		ctx = context.WithValue(ctx, ContextKeyShare, share)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// decodePassword decodes passwords that are hex encoded with the enc: prefix.
+func decodePassword(password string) (string, error) {
+	if !strings.HasPrefix(password, "enc:") {
+		return password, nil
+	}
+	decoded, err := hex.DecodeString(strings.TrimPrefix(password, "enc:"))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode hex encoded password: %w", err)
+	}
+	return string(decoded), nil
+}
+
+func (h *Handler) passwordAuth(ctx context.Context, username, password string) (bool, error) {
+	password, err := decodePassword(password)
+	if err != nil {
+		return false, err
 	}
 
 	user, err := h.DB.User().FindByName(ctx, username)
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/juho05/crossonic-server/repos"
+)
+
+// permission is a capability that is not available to every authenticated user.
+type permission int
+
+const (
+	permissionAdmin permission = iota
+	permissionScan
+	permissionDownload
+	permissionPlaylist
+	permissionShare
+	permissionPodcast
+	permissionInternetRadio
+	permissionListenBrainz
+	permissionJukebox
+	permissionSettings
+	permissionScrobble
+)
+
+func (p permission) String() string {
+	switch p {
+	case permissionAdmin:
+		return "admin"
+	case permissionScan:
+		return "scan"
+	case permissionDownload:
+		return "download"
+	case permissionPlaylist:
+		return "playlist"
+	case permissionShare:
+		return "share"
+	case permissionPodcast:
+		return "podcast"
+	case permissionInternetRadio:
+		return "internet radio"
+	case permissionListenBrainz:
+		return "ListenBrainz"
+	case permissionJukebox:
+		return "jukebox"
+	case permissionSettings:
+		return "settings"
+	case permissionScrobble:
+		return "scrobble"
+	default:
+		return fmt.Sprintf("permission(%d)", int(p))
+	}
+}
+
+// grantedTo reports whether the roles of user include the permission.
+// Admins are granted every permission.
+func (p permission) grantedTo(user *repos.User) bool {
+	if user.Admin {
+		return true
+	}
+	switch p {
+	case permissionDownload:
+		return user.DownloadRole
+	case permissionPlaylist:
+		return user.PlaylistRole
+	case permissionShare:
+		return user.ShareRole
+	case permissionInternetRadio, permissionListenBrainz, permissionSettings:
+		return user.SettingsRole
+	case permissionJukebox:
+		return user.JukeboxRole
+	case permissionScrobble:
+		return user.ScrobbleRole
+	default:
+		return false
+	}
+}
+
+// requirePermission only forwards requests of users that were granted the permission
+// and responds with a not authorized error otherwise.
+func (h *Handler) requirePermission(p permission, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := getQuery(w, r)
+		if !h.checkPermission(w, r, q, p) {
+			return
+		}
+		next(w, r)
+	}
+}
+
+// checkPermission responds with a not authorized error and returns false if the requesting user
+// was not granted the permission.
+func (h *Handler) checkPermission(w http.ResponseWriter, r *http.Request, q UrlQuery, p permission) bool {
+	granted, err := h.hasPermission(r.Context(), q.User(), p)
+	if err != nil {
+		respondErr(w, q.Format(), fmt.Errorf("check %s permission: %w", p, err))
+		return false
+	}
+	if !granted {
+		respondNotAuthorizedErr(w, q.Format(), fmt.Sprintf("%s permission required", p))
+		return false
+	}
+	return true
+}
+
+func (h *Handler) hasPermission(ctx context.Context, username string, p permission) (bool, error) {
+	user, err := h.DB.User().FindByName(ctx, username)
+	if err != nil {
+		return false, fmt.Errorf("has permission: find user: %w", err)
+	}
+	return p.grantedTo(user), nil
+}
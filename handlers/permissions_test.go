@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPermissionGrantedTo(t *testing.T) {
+	all := []permission{
+		permissionAdmin,
+		permissionScan,
+		permissionDownload,
+		permissionPlaylist,
+		permissionShare,
+		permissionPodcast,
+		permissionInternetRadio,
+		permissionListenBrainz,
+		permissionJukebox,
+		permissionSettings,
+		permissionScrobble,
+	}
+
+	t.Run("admin is granted everything", func(t *testing.T) {
+		user := &repos.User{Admin: true}
+		for _, p := range all {
+			assert.True(t, p.grantedTo(user), p.String())
+		}
+	})
+
+	t.Run("stream only user is granted nothing", func(t *testing.T) {
+		user := &repos.User{}
+		for _, p := range all {
+			assert.False(t, p.grantedTo(user), p.String())
+		}
+	})
+
+	t.Run("roles", func(t *testing.T) {
+		user := &repos.User{
+			DownloadRole: true,
+			PlaylistRole: true,
+			ShareRole:    true,
+			SettingsRole: true,
+		}
+		assert.False(t, permissionAdmin.grantedTo(user))
+		assert.False(t, permissionScan.grantedTo(user))
+		assert.False(t, permissionPodcast.grantedTo(user))
+		assert.True(t, permissionDownload.grantedTo(user))
+		assert.True(t, permissionPlaylist.grantedTo(user))
+		assert.True(t, permissionShare.grantedTo(user))
+		assert.True(t, permissionInternetRadio.grantedTo(user))
+		assert.True(t, permissionListenBrainz.grantedTo(user))
+		assert.True(t, permissionSettings.grantedTo(user))
+		assert.False(t, permissionJukebox.grantedTo(user))
+		assert.False(t, permissionScrobble.grantedTo(user))
+
+		assert.False(t, permissionDownload.grantedTo(&repos.User{PlaylistRole: true}))
+		assert.False(t, permissionListenBrainz.grantedTo(&repos.User{DownloadRole: true}))
+		assert.True(t, permissionJukebox.grantedTo(&repos.User{JukeboxRole: true}))
+		assert.True(t, permissionScrobble.grantedTo(&repos.User{ScrobbleRole: true}))
+	})
+}
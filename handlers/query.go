@@ -229,6 +229,19 @@ func (q UrlQuery) IntPositiveReq(name string) (int, bool) {
 	return q.IntRangeReq(name, 0, math.MaxInt)
 }
 
+func (q UrlQuery) FloatRange(name string, min, max float64) (*float64, bool) {
+	v := q.Str(name)
+	if v == "" {
+		return nil, true
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f < min || f > max {
+		q.invalidParameter(name)
+		return nil, false
+	}
+	return &f, true
+}
+
 func (q UrlQuery) Ints(name string) ([]int, bool) {
 	strs := q.Strs(name)
 
@@ -1,11 +1,17 @@
 package responses
 
+import "time"
+
 type ListenBrainzConfig struct {
 	ListenBrainzUsername *string `xml:"listenBrainzUsername,attr" json:"listenBrainzUsername"`
 	SyncFeedback         *bool   `xml:"syncFeedback,attr" json:"syncFeedback"`
 	Scrobble             *bool   `xml:"scrobble,attr" json:"scrobble"`
 }
 
+type PlaybackConfig struct {
+	Normalize string `xml:"normalize,attr" json:"normalize"`
+}
+
 type Recap struct {
 	TotalDurationMS int64 `xml:"totalDurationMs,attr" json:"totalDurationMs"`
 	SongCount       int   `xml:"songCount,attr" json:"songCount"`
@@ -33,3 +39,48 @@ type AlbumVersions struct {
 type Songs struct {
 	Songs []*Song `xml:"song" json:"song"`
 }
+
+type Waveform struct {
+	Peaks []float32 `xml:"peak" json:"peak"`
+}
+
+type SchedulerStatus struct {
+	Jobs []*ScheduledJob `xml:"job" json:"job"`
+}
+
+type ScheduledJob struct {
+	Name string `xml:"name,attr" json:"name"`
+	// cron expression, not set if the job is disabled
+	Schedule       *string    `xml:"schedule,attr,omitempty" json:"schedule,omitempty"`
+	Running        bool       `xml:"running,attr" json:"running"`
+	LastRun        *time.Time `xml:"lastRun,attr,omitempty" json:"lastRun,omitempty"`
+	LastDurationMS *int64     `xml:"lastDurationMs,attr,omitempty" json:"lastDurationMs,omitempty"`
+	LastError      *string    `xml:"lastError,attr,omitempty" json:"lastError,omitempty"`
+	NextRun        *time.Time `xml:"nextRun,attr,omitempty" json:"nextRun,omitempty"`
+}
+
+type ScanErrors struct {
+	Errors []*ScanError `xml:"error" json:"error"`
+}
+
+type ScanError struct {
+	// file or directory, not set if the error is not related to a file
+	Path    *string   `xml:"path,attr,omitempty" json:"path,omitempty"`
+	Message string    `xml:"message,attr" json:"message"`
+	Time    time.Time `xml:"time,attr" json:"time"`
+}
+
+type ScanProblems struct {
+	// number of problems matching the filters, ignoring count and offset
+	TotalCount int            `xml:"totalCount,attr" json:"totalCount"`
+	Problems   []*ScanProblem `xml:"problem" json:"problem"`
+}
+
+type ScanProblem struct {
+	Path          string    `xml:"path,attr" json:"path"`
+	Type          string    `xml:"type,attr" json:"type"`
+	Message       string    `xml:"message,attr" json:"message"`
+	MusicFolderID int       `xml:"musicFolderId,attr" json:"musicFolderId"`
+	Created       time.Time `xml:"created,attr" json:"created"`
+	Updated       time.Time `xml:"updated,attr" json:"updated"`
+}
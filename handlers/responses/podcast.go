@@ -0,0 +1,112 @@
+package responses
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/juho05/crossonic-server/config"
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/juho05/crossonic-server/util"
+)
+
+type Podcasts struct {
+	Channels []*PodcastChannel `xml:"channel" json:"channel"`
+}
+
+type NewestPodcasts struct {
+	Episodes []*PodcastEpisode `xml:"episode" json:"episode"`
+}
+
+type PodcastChannel struct {
+	ID               string            `xml:"id,attr" json:"id"`
+	URL              string            `xml:"url,attr" json:"url"`
+	Title            *string           `xml:"title,attr,omitempty" json:"title,omitempty"`
+	Description      *string           `xml:"description,attr,omitempty" json:"description,omitempty"`
+	CoverArt         *string           `xml:"coverArt,attr,omitempty" json:"coverArt,omitempty"`
+	OriginalImageURL *string           `xml:"originalImageUrl,attr,omitempty" json:"originalImageUrl,omitempty"`
+	Status           string            `xml:"status,attr" json:"status"`
+	ErrorMessage     *string           `xml:"errorMessage,attr,omitempty" json:"errorMessage,omitempty"`
+	Episodes         []*PodcastEpisode `xml:"episode,omitempty" json:"episode,omitempty"`
+}
+
+type PodcastEpisode struct {
+	ID          string     `xml:"id,attr" json:"id"`
+	StreamID    *string    `xml:"streamId,attr,omitempty" json:"streamId,omitempty"`
+	ChannelID   string     `xml:"channelId,attr" json:"channelId"`
+	Parent      string     `xml:"parent,attr" json:"parent"`
+	IsDir       bool       `xml:"isDir,attr" json:"isDir"`
+	Title       string     `xml:"title,attr" json:"title"`
+	Description *string    `xml:"description,attr,omitempty" json:"description,omitempty"`
+	PublishDate *time.Time `xml:"publishDate,attr,omitempty" json:"publishDate,omitempty"`
+	Status      string     `xml:"status,attr" json:"status"`
+	Year        *int       `xml:"year,attr,omitempty" json:"year,omitempty"`
+	Genre       string     `xml:"genre,attr" json:"genre"`
+	CoverArt    *string    `xml:"coverArt,attr,omitempty" json:"coverArt,omitempty"`
+	Size        *int64     `xml:"size,attr,omitempty" json:"size,omitempty"`
+	ContentType *string    `xml:"contentType,attr,omitempty" json:"contentType,omitempty"`
+	Suffix      *string    `xml:"suffix,attr,omitempty" json:"suffix,omitempty"`
+	Duration    *int       `xml:"duration,attr,omitempty" json:"duration,omitempty"`
+	BitRate     *int       `xml:"bitRate,attr,omitempty" json:"bitRate,omitempty"`
+	Type        string     `xml:"type,attr" json:"type"`
+	MediaType   string     `xml:"mediaType,attr" json:"mediaType"`
+}
+
+func NewPodcastChannel(c *repos.PodcastChannel, episodes []*repos.PodcastEpisode, conf config.Config) *PodcastChannel {
+	channel := &PodcastChannel{
+		ID:               c.ID,
+		URL:              c.URL,
+		Title:            c.Title,
+		Description:      c.Description,
+		OriginalImageURL: c.ImageURL,
+		Status:           string(c.Status),
+		ErrorMessage:     c.ErrorMessage,
+	}
+	if HasCoverArt(c.ID, conf) {
+		channel.CoverArt = &c.ID
+	}
+	if episodes != nil {
+		channel.Episodes = NewPodcastEpisodes(episodes, conf)
+	}
+	return channel
+}
+
+func NewPodcastEpisode(e *repos.PodcastEpisode, conf config.Config) *PodcastEpisode {
+	episode := &PodcastEpisode{
+		ID:          e.ID,
+		ChannelID:   e.ChannelID,
+		Parent:      e.ChannelID,
+		Title:       e.Title,
+		Description: e.Description,
+		PublishDate: e.PublishDate,
+		Status:      string(e.Status),
+		Genre:       "Podcast",
+		Size:        e.Size,
+		ContentType: e.ContentType,
+		BitRate:     e.BitRate,
+		Type:        "podcast",
+		MediaType:   "podcast",
+	}
+	if e.Status == repos.PodcastStatusCompleted {
+		episode.StreamID = &e.ID
+	}
+	if e.PublishDate != nil {
+		episode.Year = util.ToPtr(e.PublishDate.Year())
+	}
+	if e.Path != nil {
+		episode.Suffix = util.ToPtr(strings.TrimPrefix(filepath.Ext(*e.Path), "."))
+	}
+	if e.Duration.Valid {
+		episode.Duration = util.ToPtr(e.Duration.Duration.Seconds())
+	}
+	if HasCoverArt(e.ChannelID, conf) {
+		episode.CoverArt = &e.ChannelID
+	}
+	return episode
+}
+
+func NewPodcastEpisodes(e []*repos.PodcastEpisode, conf config.Config) []*PodcastEpisode {
+	return util.Map(e, func(e *repos.PodcastEpisode) *PodcastEpisode {
+		return NewPodcastEpisode(e, conf)
+	})
+}
@@ -44,6 +44,9 @@ type Response struct {
 	AlbumList              *AlbumList              `xml:"albumList,omitempty" json:"albumList,omitempty"`
 	AlbumList2             *AlbumList2             `xml:"albumList2,omitempty" json:"albumList2,omitempty"`
 	RandomSongs            *RandomSongs            `xml:"randomSongs,omitempty" json:"randomSongs,omitempty"`
+	SimilarSongs           *SimilarSongs           `xml:"similarSongs,omitempty" json:"similarSongs,omitempty"`
+	SimilarSongs2          *SimilarSongs2          `xml:"similarSongs2,omitempty" json:"similarSongs2,omitempty"`
+	TopSongs               *TopSongs               `xml:"topSongs,omitempty" json:"topSongs,omitempty"`
 	Album                  *AlbumWithSongs         `xml:"album,omitempty" json:"album,omitempty"`
 	Artist                 *Artist                 `xml:"artist,omitempty" json:"artist,omitempty"`
 	NowPlaying             *NowPlaying             `xml:"nowPlaying,omitempty" json:"nowPlaying,omitempty"`
@@ -63,14 +66,30 @@ type Response struct {
 	InternetRadioStations  *InternetRadioStations  `xml:"internetRadioStations,omitempty" json:"internetRadioStations,omitempty"`
 	TokenInfo              *TokenInfo              `xml:"tokenInfo,omitempty" json:"tokenInfo,omitempty"`
 	MusicFolders           *MusicFolders           `xml:"musicFolders,omitempty" json:"musicFolders,omitempty"`
+	Shares                 *Shares                 `xml:"shares,omitempty" json:"shares,omitempty"`
+	Podcasts               *Podcasts               `xml:"podcasts,omitempty" json:"podcasts,omitempty"`
+	NewestPodcasts         *NewestPodcasts         `xml:"newestPodcasts,omitempty" json:"newestPodcasts,omitempty"`
+	PlayQueue              *PlayQueue              `xml:"playQueue,omitempty" json:"playQueue,omitempty"`
+	PlayQueueByIndex       *PlayQueueByIndex       `xml:"playQueueByIndex,omitempty" json:"playQueueByIndex,omitempty"`
+	Bookmarks              *Bookmarks              `xml:"bookmarks,omitempty" json:"bookmarks,omitempty"`
+	User                   *User                   `xml:"user,omitempty" json:"user,omitempty"`
+	Users                  *Users                  `xml:"users,omitempty" json:"users,omitempty"`
+	JukeboxStatus          *JukeboxStatus          `xml:"jukeboxStatus,omitempty" json:"jukeboxStatus,omitempty"`
+	JukeboxPlaylist        *JukeboxPlaylist        `xml:"jukeboxPlaylist,omitempty" json:"jukeboxPlaylist,omitempty"`
+	TranscodeDecision      *TranscodeDecision      `xml:"transcodeDecision,omitempty" json:"transcodeDecision,omitempty"`
 
 	// Crossonic
 	ListenBrainzConfig *ListenBrainzConfig `xml:"listenBrainzConfig,omitempty" json:"listenBrainzConfig,omitempty"`
+	PlaybackConfig     *PlaybackConfig     `xml:"playbackConfig,omitempty" json:"playbackConfig,omitempty"`
 	Recap              *Recap              `xml:"recap,omitempty" json:"recap,omitempty"`
 	TopSongsRecap      *TopSongsRecap      `xml:"topSongsRecap,omitempty" json:"topSongsRecap,omitempty"`
 	AppearsOn          *AppearsOn          `xml:"appearsOn,omitempty" json:"appearsOn,omitempty"`
 	Songs              *Songs              `xml:"songs,omitempty" json:"songs,omitempty"`
 	AlbumVersions      *AlbumVersions      `xml:"albumVersions,omitempty" json:"albumVersions,omitempty"`
+	Waveform           *Waveform           `xml:"waveform,omitempty" json:"waveform,omitempty"`
+	SchedulerStatus    *SchedulerStatus    `xml:"schedulerStatus,omitempty" json:"schedulerStatus,omitempty"`
+	ScanErrors         *ScanErrors         `xml:"scanErrors,omitempty" json:"scanErrors,omitempty"`
+	ScanProblems       *ScanProblems       `xml:"scanProblems,omitempty" json:"scanProblems,omitempty"`
 }
 
 func New() Response {
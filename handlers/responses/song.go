@@ -64,6 +64,7 @@ func NewSong(s *repos.CompleteSong, conf config.Config) *Song {
 		year = util.ToPtr(s.ReleaseDate.Year())
 	}
 
+	trackGain, trackPeak := s.TrackReplayGain()
 	song := &Song{
 		ID:            s.ID,
 		IsDir:         false,
@@ -83,8 +84,8 @@ func NewSong(s *repos.CompleteSong, conf config.Config) *Song {
 		BPM:           s.BPM,
 		MusicBrainzID: s.MusicBrainzID,
 		ReplayGain: &ReplayGain{
-			TrackGain: s.ReplayGain,
-			TrackPeak: s.ReplayGainPeak,
+			TrackGain: trackGain,
+			TrackPeak: trackPeak,
 		},
 		Type:                "music",
 		MediaType:           "song",
@@ -96,8 +97,7 @@ func NewSong(s *repos.CompleteSong, conf config.Config) *Song {
 		song.Album = s.AlbumName
 		song.AlbumID = s.AlbumID
 		song.Parent = song.AlbumID
-		song.ReplayGain.AlbumGain = s.AlbumReplayGain
-		song.ReplayGain.AlbumPeak = s.AlbumReplayGainPeak
+		song.ReplayGain.AlbumGain, song.ReplayGain.AlbumPeak = s.AlbumGain()
 	}
 
 	if s.SongAnnotations != nil {
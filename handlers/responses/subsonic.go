@@ -35,12 +35,19 @@ type ScanStatus struct {
 	// full or quick
 	FullScan  bool       `xml:"fullScan,attr" json:"fullScan"`
 	StartTime *time.Time `xml:"startTime,attr,omitempty" json:"startTime,omitempty"`
+
+	// Crossonic
+	Phase          *string `xml:"phase,attr,omitempty" json:"phase,omitempty"`
+	ProcessedCount *int    `xml:"processedCount,attr,omitempty" json:"processedCount,omitempty"`
+	ErrorCount     *int    `xml:"errorCount,attr,omitempty" json:"errorCount,omitempty"`
+	Canceling      bool    `xml:"canceling,attr,omitempty" json:"canceling,omitempty"`
 }
 
 type ArtistIndexes struct {
 	IgnoredArticles string   `xml:"ignoredArticles,attr" json:"ignoredArticles"`
 	LastModified    int64    `xml:"lastModified,attr" json:"lastModified"`
 	Index           []*Index `xml:"index" json:"index"`
+	Child           []*Song  `xml:"child,omitempty" json:"child,omitempty"`
 }
 
 type Index struct {
@@ -60,6 +67,18 @@ type RandomSongs struct {
 	Songs []*Song `xml:"song" json:"song"`
 }
 
+type SimilarSongs struct {
+	Songs []*Song `xml:"song" json:"song"`
+}
+
+type SimilarSongs2 struct {
+	Songs []*Song `xml:"song" json:"song"`
+}
+
+type TopSongs struct {
+	Songs []*Song `xml:"song" json:"song"`
+}
+
 type ReplayGain struct {
 	TrackGain    *float64 `xml:"trackGain,attr,omitempty" json:"trackGain,omitempty"`
 	AlbumGain    *float64 `xml:"albumGain,attr,omitempty" json:"albumGain,omitempty"`
@@ -118,6 +137,9 @@ type AlbumInfo struct {
 	SmallImageURL  *string `xml:"smallImageUrl,omitempty" json:"smallImageUrl,omitempty"`
 	MediumImageURL *string `xml:"mediumImageUrl,omitempty" json:"mediumImageUrl,omitempty"`
 	LargeImageURL  *string `xml:"largeImageUrl,omitempty" json:"largeImageUrl,omitempty"`
+
+	// non-standard: albums by similar artists of the album artists
+	SimilarAlbums []*Album `xml:"similarAlbum,omitempty" json:"similarAlbum,omitempty"`
 }
 
 type ArtistInfo struct {
@@ -127,6 +149,8 @@ type ArtistInfo struct {
 	SmallImageURL  *string `xml:"smallImageUrl,omitempty" json:"smallImageUrl,omitempty"`
 	MediumImageURL *string `xml:"mediumImageUrl,omitempty" json:"mediumImageUrl,omitempty"`
 	LargeImageURL  *string `xml:"largeImageUrl,omitempty" json:"largeImageUrl,omitempty"`
+
+	SimilarArtists []*Artist `xml:"similarArtist,omitempty" json:"similarArtist,omitempty"`
 }
 
 type Directory struct {
@@ -140,6 +164,14 @@ type Directory struct {
 	Child         []any      `xml:"child" json:"child"`
 }
 
+// DirectoryChild is a filesystem directory listed as a child of another directory.
+type DirectoryChild struct {
+	ID     string  `xml:"id,attr" json:"id"`
+	Parent *string `xml:"parent,attr,omitempty" json:"parent,omitempty"`
+	IsDir  bool    `xml:"isDir,attr" json:"isDir"`
+	Title  string  `xml:"title,attr" json:"title"`
+}
+
 type InternetRadioStations struct {
 	Stations []InternetRadioStation `xml:"internetRadioStation" json:"internetRadioStation"`
 }
@@ -154,3 +186,104 @@ type InternetRadioStation struct {
 type TokenInfo struct {
 	UserName string `xml:"username,attr" json:"username"`
 }
+
+type Shares struct {
+	Shares []*Share `xml:"share" json:"share"`
+}
+
+type Share struct {
+	ID          string     `xml:"id,attr" json:"id"`
+	URL         string     `xml:"url,attr" json:"url"`
+	Description *string    `xml:"description,attr,omitempty" json:"description,omitempty"`
+	Username    string     `xml:"username,attr" json:"username"`
+	Created     time.Time  `xml:"created,attr" json:"created"`
+	Expires     *time.Time `xml:"expires,attr,omitempty" json:"expires,omitempty"`
+	LastVisited *time.Time `xml:"lastVisited,attr,omitempty" json:"lastVisited,omitempty"`
+	VisitCount  int        `xml:"visitCount,attr" json:"visitCount"`
+	Entry       []*Song    `xml:"entry" json:"entry"`
+}
+
+type PlayQueue struct {
+	Current   *string   `xml:"current,attr,omitempty" json:"current,omitempty"`
+	Position  int64     `xml:"position,attr" json:"position"`
+	Username  string    `xml:"username,attr" json:"username"`
+	Changed   time.Time `xml:"changed,attr" json:"changed"`
+	ChangedBy string    `xml:"changedBy,attr" json:"changedBy"`
+	Entry     []*Song   `xml:"entry" json:"entry"`
+}
+
+type PlayQueueByIndex struct {
+	CurrentIndex *int      `xml:"currentIndex,attr,omitempty" json:"currentIndex,omitempty"`
+	Position     int64     `xml:"position,attr" json:"position"`
+	Username     string    `xml:"username,attr" json:"username"`
+	Changed      time.Time `xml:"changed,attr" json:"changed"`
+	ChangedBy    string    `xml:"changedBy,attr" json:"changedBy"`
+	Entry        []*Song   `xml:"entry" json:"entry"`
+}
+
+type JukeboxStatus struct {
+	CurrentIndex int     `xml:"currentIndex,attr" json:"currentIndex"`
+	Playing      bool    `xml:"playing,attr" json:"playing"`
+	Gain         float64 `xml:"gain,attr" json:"gain"`
+	Position     int     `xml:"position,attr" json:"position"`
+}
+
+type JukeboxPlaylist struct {
+	JukeboxStatus
+	Entry []*Song `xml:"entry" json:"entry"`
+}
+
+type TranscodeDecision struct {
+	CanDirectPlay   bool           `xml:"canDirectPlay,attr" json:"canDirectPlay"`
+	CanTranscode    bool           `xml:"canTranscode,attr" json:"canTranscode"`
+	TranscodeReason []string       `xml:"transcodeReason,omitempty" json:"transcodeReason,omitempty"`
+	ErrorReason     string         `xml:"errorReason,attr,omitempty" json:"errorReason,omitempty"`
+	TranscodeParams string         `xml:"transcodeParams,attr,omitempty" json:"transcodeParams,omitempty"`
+	SourceStream    *StreamDetails `xml:"sourceStream,omitempty" json:"sourceStream,omitempty"`
+	TranscodeStream *StreamDetails `xml:"transcodeStream,omitempty" json:"transcodeStream,omitempty"`
+}
+
+type StreamDetails struct {
+	Protocol        string `xml:"protocol,attr" json:"protocol"`
+	Container       string `xml:"container,attr" json:"container"`
+	Codec           string `xml:"codec,attr" json:"codec"`
+	AudioChannels   int    `xml:"audioChannels,attr,omitempty" json:"audioChannels,omitempty"`
+	AudioBitrate    int    `xml:"audioBitrate,attr,omitempty" json:"audioBitrate,omitempty"`
+	AudioSamplerate int    `xml:"audioSamplerate,attr,omitempty" json:"audioSamplerate,omitempty"`
+}
+
+type Bookmarks struct {
+	Bookmarks []*Bookmark `xml:"bookmark" json:"bookmark"`
+}
+
+type Bookmark struct {
+	Position int64     `xml:"position,attr" json:"position"`
+	Username string    `xml:"username,attr" json:"username"`
+	Comment  *string   `xml:"comment,attr,omitempty" json:"comment,omitempty"`
+	Created  time.Time `xml:"created,attr" json:"created"`
+	Changed  time.Time `xml:"changed,attr" json:"changed"`
+	Entry    *Song     `xml:"entry" json:"entry"`
+}
+
+type Users struct {
+	Users []*User `xml:"user" json:"user"`
+}
+
+type User struct {
+	Username            string  `xml:"username,attr" json:"username"`
+	Email               *string `xml:"email,attr,omitempty" json:"email,omitempty"`
+	ScrobblingEnabled   bool    `xml:"scrobblingEnabled,attr" json:"scrobblingEnabled"`
+	AdminRole           bool    `xml:"adminRole,attr" json:"adminRole"`
+	SettingsRole        bool    `xml:"settingsRole,attr" json:"settingsRole"`
+	DownloadRole        bool    `xml:"downloadRole,attr" json:"downloadRole"`
+	UploadRole          bool    `xml:"uploadRole,attr" json:"uploadRole"`
+	PlaylistRole        bool    `xml:"playlistRole,attr" json:"playlistRole"`
+	CoverArtRole        bool    `xml:"coverArtRole,attr" json:"coverArtRole"`
+	CommentRole         bool    `xml:"commentRole,attr" json:"commentRole"`
+	PodcastRole         bool    `xml:"podcastRole,attr" json:"podcastRole"`
+	StreamRole          bool    `xml:"streamRole,attr" json:"streamRole"`
+	JukeboxRole         bool    `xml:"jukeboxRole,attr" json:"jukeboxRole"`
+	ShareRole           bool    `xml:"shareRole,attr" json:"shareRole"`
+	VideoConversionRole bool    `xml:"videoConversionRole,attr" json:"videoConversionRole"`
+	Folder              []int   `xml:"folder" json:"folder"`
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/juho05/crossonic-server/handlers/responses"
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/juho05/log"
+)
+
+func (h *Handler) registerShareRoutes(r chi.Router) {
+	r.Use(h.shareMiddleware)
+	r.Get("/", h.handleGetPublicShare)
+	r.Get("/stream", h.restrictToShare(h.handleStream))
+	r.Head("/stream", h.restrictToShare(h.handleStream))
+	r.Get("/getCoverArt", h.restrictToShare(h.handleGetCoverArt))
+}
+
+func (h *Handler) handleGetPublicShare(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+	share := r.Context().Value(ContextKeyShare).(*repos.Share)
+
+	err := h.DB.Share().RegisterVisit(r.Context(), share.ID)
+	if err != nil {
+		log.Errorf("get public share: register visit: %s", err)
+	}
+
+	resShare, err := h.newShareResponse(r.Context(), share)
+	if err != nil {
+		respondErr(w, q.Format(), fmt.Errorf("get public share: %w", err))
+		return
+	}
+
+	res := responses.New()
+	res.Shares = &responses.Shares{
+		Shares: []*responses.Share{resShare},
+	}
+	res.EncodeOrLog(w, q.Format())
+}
+
+// restrictToShare only allows requests whose id parameter references an entry of the share,
+// one of the songs contained in the share or the album of one of those songs.
+func (h *Handler) restrictToShare(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := getQuery(w, r)
+		share := r.Context().Value(ContextKeyShare).(*repos.Share)
+
+		id, ok := q.IDReq("id")
+		if !ok {
+			return
+		}
+
+		entries, err := h.DB.Share().GetEntries(r.Context(), share.ID)
+		if err != nil {
+			respondErr(w, q.Format(), fmt.Errorf("restrict to share: get entries: %w", err))
+			return
+		}
+		for _, e := range entries {
+			if e == id {
+				next(w, r)
+				return
+			}
+		}
+
+		songs, err := h.getShareSongs(r.Context(), share)
+		if err != nil {
+			respondErr(w, q.Format(), fmt.Errorf("restrict to share: %w", err))
+			return
+		}
+		for _, s := range songs {
+			if s.ID == id || (s.AlbumID != nil && *s.AlbumID == id) {
+				next(w, r)
+				return
+			}
+		}
+
+		respondNotFoundErr(w, q.Format(), "")
+	}
+}
@@ -12,7 +12,7 @@ func (h *Handler) registerSubsonicRoutes(r chi.Router) {
 	registerRoute(r, "/getLicense", h.handleGetLicense)
 	registerRoute(r, "/getOpenSubsonicExtensions", h.handleGetOpenSubsonicExtensions)
 	registerRoute(r, "/tokenInfo", h.handleTokenInfo)
-	registerRoute(r, "/startScan", h.handleStartScan)
+	registerRoute(r, "/startScan", h.requirePermission(permissionScan, h.handleStartScan))
 	registerRoute(r, "/getScanStatus", h.handleGetScanStatus)
 	registerRoute(r, "/setRating", h.handleSetRating)
 	registerRoute(r, "/star", h.handleStar)
@@ -29,8 +29,12 @@ func (h *Handler) registerSubsonicRoutes(r chi.Router) {
 	registerRoute(r, "/getAlbum", h.handleGetAlbum)
 	registerRoute(r, "/getArtist", h.handleGetArtist)
 	registerRoute(r, "/stream", h.handleStream)
-	registerRoute(r, "/download", h.handleDownload)
-	registerRoute(r, "/scrobble", h.handleScrobble)
+	registerRoute(r, "/getTranscodeDecision", h.handleGetTranscodeDecision)
+	registerRoute(r, "/getTranscodeStream", h.handleGetTranscodeStream)
+	registerRoute(r, "/hls.m3u8", h.handleHLS)
+	registerRoute(r, "/hlsSegment.ts", h.handleHLSSegment)
+	registerRoute(r, "/download", h.requirePermission(permissionDownload, h.handleDownload))
+	registerRoute(r, "/scrobble", h.requirePermission(permissionScrobble, h.handleScrobble))
 	registerRoute(r, "/getNowPlaying", h.handleGetNowPlaying)
 	registerRoute(r, "/search2", h.handleSearch(false))
 	registerRoute(r, "/search3", h.handleSearch(true))
@@ -38,9 +42,9 @@ func (h *Handler) registerSubsonicRoutes(r chi.Router) {
 	registerRoute(r, "/getLyricsBySongId", h.handleGetLyricsBySongId)
 	registerRoute(r, "/getPlaylists", h.handleGetPlaylists)
 	registerRoute(r, "/getPlaylist", h.handleGetPlaylist)
-	registerRoute(r, "/createPlaylist", h.handleCreatePlaylist)
-	registerRoute(r, "/updatePlaylist", h.handleUpdatePlaylist)
-	registerRoute(r, "/deletePlaylist", h.handleDeletePlaylist)
+	registerRoute(r, "/createPlaylist", h.requirePermission(permissionPlaylist, h.handleCreatePlaylist))
+	registerRoute(r, "/updatePlaylist", h.requirePermission(permissionPlaylist, h.handleUpdatePlaylist))
+	registerRoute(r, "/deletePlaylist", h.requirePermission(permissionPlaylist, h.handleDeletePlaylist))
 	registerRoute(r, "/getSong", h.handleGetSong)
 	registerRoute(r, "/getStarred", h.handleGetStarred(1))
 	registerRoute(r, "/getStarred2", h.handleGetStarred(2))
@@ -49,8 +53,36 @@ func (h *Handler) registerSubsonicRoutes(r chi.Router) {
 	registerRoute(r, "/getAlbumInfo2", h.handleGetAlbumInfo2)
 	registerRoute(r, "/getArtistInfo", h.handleGetArtistInfo(1))
 	registerRoute(r, "/getArtistInfo2", h.handleGetArtistInfo(2))
+	registerRoute(r, "/getSimilarSongs", h.handleGetSimilarSongs(1))
+	registerRoute(r, "/getSimilarSongs2", h.handleGetSimilarSongs(2))
+	registerRoute(r, "/getTopSongs", h.handleGetTopSongs)
 	registerRoute(r, "/getInternetRadioStations", h.handleGetInternetRadioStations)
-	registerRoute(r, "/createInternetRadioStation", h.handleCreateInternetRadioStation)
-	registerRoute(r, "/updateInternetRadioStation", h.handleUpdateInternetRadioStation)
-	registerRoute(r, "/deleteInternetRadioStation", h.handleDeleteInternetRadioStation)
+	registerRoute(r, "/createInternetRadioStation", h.requirePermission(permissionInternetRadio, h.handleCreateInternetRadioStation))
+	registerRoute(r, "/updateInternetRadioStation", h.requirePermission(permissionInternetRadio, h.handleUpdateInternetRadioStation))
+	registerRoute(r, "/deleteInternetRadioStation", h.requirePermission(permissionInternetRadio, h.handleDeleteInternetRadioStation))
+	registerRoute(r, "/getShares", h.handleGetShares)
+	registerRoute(r, "/createShare", h.requirePermission(permissionShare, h.handleCreateShare))
+	registerRoute(r, "/updateShare", h.requirePermission(permissionShare, h.handleUpdateShare))
+	registerRoute(r, "/deleteShare", h.requirePermission(permissionShare, h.handleDeleteShare))
+	registerRoute(r, "/getPodcasts", h.handleGetPodcasts)
+	registerRoute(r, "/getNewestPodcasts", h.handleGetNewestPodcasts)
+	registerRoute(r, "/refreshPodcasts", h.requirePermission(permissionPodcast, h.handleRefreshPodcasts))
+	registerRoute(r, "/createPodcastChannel", h.requirePermission(permissionPodcast, h.handleCreatePodcastChannel))
+	registerRoute(r, "/deletePodcastChannel", h.requirePermission(permissionPodcast, h.handleDeletePodcastChannel))
+	registerRoute(r, "/deletePodcastEpisode", h.requirePermission(permissionPodcast, h.handleDeletePodcastEpisode))
+	registerRoute(r, "/downloadPodcastEpisode", h.requirePermission(permissionPodcast, h.handleDownloadPodcastEpisode))
+	registerRoute(r, "/getBookmarks", h.handleGetBookmarks)
+	registerRoute(r, "/createBookmark", h.handleCreateBookmark)
+	registerRoute(r, "/deleteBookmark", h.handleDeleteBookmark)
+	registerRoute(r, "/getPlayQueue", h.handleGetPlayQueue)
+	registerRoute(r, "/savePlayQueue", h.handleSavePlayQueue)
+	registerRoute(r, "/getPlayQueueByIndex", h.handleGetPlayQueueByIndex)
+	registerRoute(r, "/savePlayQueueByIndex", h.handleSavePlayQueueByIndex)
+	registerRoute(r, "/jukeboxControl", h.requirePermission(permissionJukebox, h.handleJukeboxControl))
+	registerRoute(r, "/getUser", h.handleGetUser)
+	registerRoute(r, "/getUsers", h.requirePermission(permissionAdmin, h.handleGetUsers))
+	registerRoute(r, "/createUser", h.requirePermission(permissionAdmin, h.handleCreateUser))
+	registerRoute(r, "/updateUser", h.requirePermission(permissionAdmin, h.handleUpdateUser))
+	registerRoute(r, "/deleteUser", h.requirePermission(permissionAdmin, h.handleDeleteUser))
+	registerRoute(r, "/changePassword", h.handleChangePassword)
 }
@@ -0,0 +1,341 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/juho05/crossonic-server"
+	"github.com/juho05/crossonic-server/handlers/responses"
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/juho05/crossonic-server/util"
+)
+
+// https://opensubsonic.netlify.app/docs/endpoints/getbookmarks/
+func (h *Handler) handleGetBookmarks(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	bookmarks, err := h.DB.Bookmark().FindAll(r.Context(), q.User())
+	if err != nil {
+		respondErr(w, q.Format(), fmt.Errorf("get bookmarks: %w", err))
+		return
+	}
+
+	songs, _, err := h.findAccessibleSongs(r.Context(), q.User(), util.Map(bookmarks, func(b *repos.Bookmark) string {
+		return b.SongID
+	}), repos.IncludeSongInfoFull(q.User()))
+	if err != nil {
+		respondErr(w, q.Format(), fmt.Errorf("get bookmarks: %w", err))
+		return
+	}
+	songMap := make(map[string]*repos.CompleteSong, len(songs))
+	for _, s := range songs {
+		songMap[s.ID] = s
+	}
+
+	resBookmarks := make([]*responses.Bookmark, 0, len(bookmarks))
+	for _, b := range bookmarks {
+		song, ok := songMap[b.SongID]
+		if !ok {
+			continue
+		}
+		resBookmarks = append(resBookmarks, &responses.Bookmark{
+			Position: b.Position.Millis(),
+			Username: b.User,
+			Comment:  b.Comment,
+			Created:  b.Created,
+			Changed:  b.Updated,
+			Entry:    responses.NewSong(song, h.Config),
+		})
+	}
+
+	res := responses.New()
+	res.Bookmarks = &responses.Bookmarks{
+		Bookmarks: resBookmarks,
+	}
+	res.EncodeOrLog(w, q.Format())
+}
+
+// https://opensubsonic.netlify.app/docs/endpoints/createbookmark/
+func (h *Handler) handleCreateBookmark(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	id, ok := q.IDTypeReq("id", []crossonic.IDType{crossonic.IDTypeSong})
+	if !ok {
+		return
+	}
+
+	position, ok := q.Int64("position")
+	if !ok {
+		return
+	}
+	if position == nil {
+		q.missingParameter("position")
+		return
+	}
+	if *position < 0 {
+		q.invalidParameter("position")
+		return
+	}
+
+	hasAccess, err := h.validateUserAccessToID(r.Context(), q.User(), id)
+	if err != nil {
+		respondErr(w, q.Format(), fmt.Errorf("create bookmark: %w", err))
+		return
+	}
+	if !hasAccess {
+		respondNotFoundErr(w, q.Format(), "song not found")
+		return
+	}
+
+	err = h.DB.Bookmark().CreateOrUpdate(r.Context(), q.User(), repos.CreateBookmarkParams{
+		SongID:   id,
+		Position: time.Duration(*position) * time.Millisecond,
+		Comment:  util.NilIfEmpty(q.Str("comment")),
+	})
+	if err != nil {
+		respondErr(w, q.Format(), fmt.Errorf("create bookmark: %w", err))
+		return
+	}
+
+	responses.New().EncodeOrLog(w, q.Format())
+}
+
+// https://opensubsonic.netlify.app/docs/endpoints/deletebookmark/
+func (h *Handler) handleDeleteBookmark(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	id, ok := q.IDTypeReq("id", []crossonic.IDType{crossonic.IDTypeSong})
+	if !ok {
+		return
+	}
+
+	err := h.DB.Bookmark().Delete(r.Context(), q.User(), id)
+	if err != nil {
+		respondErr(w, q.Format(), fmt.Errorf("delete bookmark: %w", err))
+		return
+	}
+
+	responses.New().EncodeOrLog(w, q.Format())
+}
+
+// https://opensubsonic.netlify.app/docs/endpoints/getplayqueue/
+func (h *Handler) handleGetPlayQueue(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	queue, songs, currentIndex, err := h.loadPlayQueue(r.Context(), q.User())
+	if err != nil {
+		if errors.Is(err, repos.ErrNotFound) {
+			responses.New().EncodeOrLog(w, q.Format())
+			return
+		}
+		respondErr(w, q.Format(), fmt.Errorf("get play queue: %w", err))
+		return
+	}
+
+	var current *string
+	if currentIndex != nil {
+		current = &songs[*currentIndex].ID
+	}
+
+	res := responses.New()
+	res.PlayQueue = &responses.PlayQueue{
+		Current:   current,
+		Position:  queue.Position.Millis(),
+		Username:  queue.User,
+		Changed:   queue.Changed,
+		ChangedBy: queue.ChangedBy,
+		Entry:     responses.NewSongs(songs, h.Config),
+	}
+	res.EncodeOrLog(w, q.Format())
+}
+
+// https://opensubsonic.netlify.app/docs/endpoints/getplayqueuebyindex/
+func (h *Handler) handleGetPlayQueueByIndex(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	queue, songs, currentIndex, err := h.loadPlayQueue(r.Context(), q.User())
+	if err != nil {
+		if errors.Is(err, repos.ErrNotFound) {
+			responses.New().EncodeOrLog(w, q.Format())
+			return
+		}
+		respondErr(w, q.Format(), fmt.Errorf("get play queue by index: %w", err))
+		return
+	}
+
+	res := responses.New()
+	res.PlayQueueByIndex = &responses.PlayQueueByIndex{
+		CurrentIndex: currentIndex,
+		Position:     queue.Position.Millis(),
+		Username:     queue.User,
+		Changed:      queue.Changed,
+		ChangedBy:    queue.ChangedBy,
+		Entry:        responses.NewSongs(songs, h.Config),
+	}
+	res.EncodeOrLog(w, q.Format())
+}
+
+// https://opensubsonic.netlify.app/docs/endpoints/saveplayqueue/
+func (h *Handler) handleSavePlayQueue(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	ids, ok := q.IDsType("id", []crossonic.IDType{crossonic.IDTypeSong})
+	if !ok {
+		return
+	}
+
+	var currentIndex *int
+	if q.Has("current") && len(ids) > 0 {
+		current, ok := q.IDTypeReq("current", []crossonic.IDType{crossonic.IDTypeSong})
+		if !ok {
+			return
+		}
+		index := slices.Index(ids, current)
+		if index < 0 {
+			respondGenericErr(w, q.Format(), "current must be part of the play queue")
+			return
+		}
+		currentIndex = &index
+	}
+
+	h.savePlayQueue(w, r, q, ids, currentIndex)
+}
+
+// https://opensubsonic.netlify.app/docs/endpoints/saveplayqueuebyindex/
+func (h *Handler) handleSavePlayQueueByIndex(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	ids, ok := q.IDsType("id", []crossonic.IDType{crossonic.IDTypeSong})
+	if !ok {
+		return
+	}
+
+	var currentIndex *int
+	if len(ids) > 0 {
+		index, ok := q.IntRangeReq("currentIndex", 0, len(ids)-1)
+		if !ok {
+			return
+		}
+		currentIndex = &index
+	}
+
+	h.savePlayQueue(w, r, q, ids, currentIndex)
+}
+
+func (h *Handler) savePlayQueue(w http.ResponseWriter, r *http.Request, q UrlQuery, ids []string, currentIndex *int) {
+	ctx := r.Context()
+
+	position, ok := q.Int64("position")
+	if !ok {
+		return
+	}
+	if position != nil && *position < 0 {
+		q.invalidParameter("position")
+		return
+	}
+
+	_, missing, err := h.findAccessibleSongs(ctx, q.User(), ids, repos.IncludeSongInfoBare())
+	if err != nil {
+		respondErr(w, q.Format(), fmt.Errorf("save play queue: %w", err))
+		return
+	}
+	if len(missing) > 0 {
+		respondNotFoundErr(w, q.Format(), fmt.Sprintf("song %s not found", missing[0]))
+		return
+	}
+
+	params := repos.SavePlayQueueParams{
+		SongIDs:      ids,
+		CurrentIndex: currentIndex,
+		ChangedBy:    q.Client(),
+	}
+	if position != nil {
+		params.Position = time.Duration(*position) * time.Millisecond
+	}
+	err = h.DB.PlayQueue().Save(ctx, q.User(), params)
+	if err != nil {
+		respondErr(w, q.Format(), fmt.Errorf("save play queue: %w", err))
+		return
+	}
+
+	responses.New().EncodeOrLog(w, q.Format())
+}
+
+// loadPlayQueue returns the play queue of the user with all songs the user still has access to.
+// The returned current index is adjusted to account for songs that were left out.
+func (h *Handler) loadPlayQueue(ctx context.Context, user string) (*repos.PlayQueue, []*repos.CompleteSong, *int, error) {
+	queue, err := h.DB.PlayQueue().Get(ctx, user)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("load play queue: %w", err)
+	}
+
+	ids, err := h.DB.PlayQueue().GetSongIDs(ctx, user)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("load play queue: get song ids: %w", err)
+	}
+
+	songs, _, err := h.findAccessibleSongs(ctx, user, ids, repos.IncludeSongInfoFull(user))
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("load play queue: %w", err)
+	}
+	songMap := make(map[string]*repos.CompleteSong, len(songs))
+	for _, s := range songs {
+		songMap[s.ID] = s
+	}
+
+	var currentIndex *int
+	queueSongs := make([]*repos.CompleteSong, 0, len(ids))
+	for i, id := range ids {
+		song, ok := songMap[id]
+		if !ok {
+			continue
+		}
+		if queue.CurrentIndex != nil && currentIndex == nil && i >= *queue.CurrentIndex {
+			currentIndex = util.ToPtr(len(queueSongs))
+		}
+		queueSongs = append(queueSongs, song)
+	}
+	if len(queueSongs) == 0 {
+		return nil, nil, nil, fmt.Errorf("load play queue: %w", repos.ErrNotFound)
+	}
+	if queue.CurrentIndex != nil && currentIndex == nil {
+		currentIndex = util.ToPtr(len(queueSongs) - 1)
+	}
+	return queue, queueSongs, currentIndex, nil
+}
+
+// findAccessibleSongs returns the songs with the given ids that are located in music folders the user has access to
+// together with the ids of all songs that were not found or are not accessible.
+func (h *Handler) findAccessibleSongs(ctx context.Context, user string, ids []string, include repos.IncludeSongInfo) ([]*repos.CompleteSong, []string, error) {
+	if len(ids) == 0 {
+		return nil, nil, nil
+	}
+	folderIDs, err := h.DB.MusicFolder().GetUserMusicFolderIDs(ctx, user, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("find accessible songs: get music folder ids: %w", err)
+	}
+	songs, err := h.DB.Song().FindByIDs(ctx, ids, include)
+	if err != nil {
+		return nil, nil, fmt.Errorf("find accessible songs: %w", err)
+	}
+	found := make(map[string]struct{}, len(songs))
+	accessible := make([]*repos.CompleteSong, 0, len(songs))
+	for _, s := range songs {
+		if s.MusicFolderID == nil || !slices.Contains(folderIDs, *s.MusicFolderID) {
+			continue
+		}
+		found[s.ID] = struct{}{}
+		accessible = append(accessible, s)
+	}
+	var missing []string
+	for _, id := range ids {
+		if _, ok := found[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	return accessible, missing, nil
+}
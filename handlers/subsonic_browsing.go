@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
 	"time"
 	"unicode"
@@ -12,12 +14,12 @@ import (
 	"github.com/juho05/crossonic-server"
 	"github.com/juho05/crossonic-server/handlers/responses"
 	"github.com/juho05/crossonic-server/lastfm"
+	"github.com/juho05/crossonic-server/metadata"
 	"github.com/juho05/crossonic-server/repos"
 	"github.com/juho05/crossonic-server/util"
+	"github.com/juho05/log"
 )
 
-const keepLastFMInfoFor = 30 * 24 * time.Hour
-
 func (h *Handler) handleGetMusicFolders(w http.ResponseWriter, r *http.Request) {
 	q := getQuery(w, r)
 
@@ -175,42 +177,53 @@ func (h *Handler) handleGetArtistsIndex(byID3 bool) func(w http.ResponseWriter,
 			return
 		}
 
-		artists, err := h.DB.Artist().FindAll(r.Context(), repos.FindArtistsParams{
-			OnlyAlbumArtists: true,
-			UpdatedAfter:     ifModifiedSince,
-			MusicFolderIDs:   musicFolderIDs,
-		}, repos.IncludeArtistInfoFull(q.User()))
+		lastModified, err := h.DB.System().LastScan(r.Context())
 		if err != nil {
-			respondInternalErr(w, q.Format(), fmt.Errorf("get artists: %w", err))
+			respondInternalErr(w, q.Format(), fmt.Errorf("get artists index: last scan: %w", err))
 			return
 		}
+
 		indexMap := make(map[rune]*responses.Index, 27)
-		for _, a := range artists {
-			name := a.Name
-			for _, i := range ignoredArticles {
-				before := len(name)
-				name = strings.TrimPrefix(name, i+" ")
-				if len(name) < before {
-					break
-				}
+		var children []*responses.Song
+		if byID3 {
+			artists, err := h.DB.Artist().FindAll(r.Context(), repos.FindArtistsParams{
+				OnlyAlbumArtists: true,
+				MusicFolderIDs:   musicFolderIDs,
+			}, repos.IncludeArtistInfoFull(q.User()))
+			if err != nil {
+				respondInternalErr(w, q.Format(), fmt.Errorf("get artists: %w", err))
+				return
 			}
-			name = strings.TrimSpace(name)
-			runes := []rune(name)
-			key := '#'
-			if len(runes) > 0 && unicode.IsLetter(runes[0]) {
-				key = unicode.ToLower(runes[0])
+			for _, a := range artists {
+				addToIndex(indexMap, a.Name, responses.NewArtist(a, h.Config))
 			}
-
-			artist := responses.NewArtist(a, h.Config)
-
-			if i, ok := indexMap[key]; ok {
-				i.Artist = append(i.Artist, artist)
-			} else {
-				indexMap[key] = &responses.Index{
-					Name:   strings.ToUpper(string(key)),
-					Artist: []*responses.Artist{artist},
-				}
+		} else if ifModifiedSince == nil || lastModified.After(*ifModifiedSince) {
+			// the top-level directories of all music folders are listed as artists, files in the music folder roots as children
+			roots, err := h.DB.Directory().FindRoots(r.Context(), musicFolderIDs)
+			if err != nil {
+				respondInternalErr(w, q.Format(), fmt.Errorf("get indexes: find root directories: %w", err))
+				return
 			}
+			rootIDs := util.Map(roots, func(d *repos.Directory) string {
+				return d.ID
+			})
+			dirs, err := h.DB.Directory().FindChildren(r.Context(), rootIDs, musicFolderIDs)
+			if err != nil {
+				respondInternalErr(w, q.Format(), fmt.Errorf("get indexes: find top-level directories: %w", err))
+				return
+			}
+			for _, d := range dirs {
+				addToIndex(indexMap, d.Name, &responses.Artist{
+					ID:   d.ID,
+					Name: d.Name,
+				})
+			}
+			songs, err := h.DB.Directory().GetSongs(r.Context(), rootIDs, repos.IncludeSongInfoFull(q.User()))
+			if err != nil {
+				respondInternalErr(w, q.Format(), fmt.Errorf("get indexes: get songs in root directories: %w", err))
+				return
+			}
+			children = responses.NewSongs(songs, h.Config)
 		}
 
 		indexList := make([]*responses.Index, 0, len(indexMap))
@@ -220,17 +233,12 @@ func (h *Handler) handleGetArtistsIndex(byID3 bool) func(w http.ResponseWriter,
 			}
 		}
 
-		lastModified, err := h.DB.System().LastScan(r.Context())
-		if err != nil {
-			respondInternalErr(w, q.Format(), fmt.Errorf("get artists index: last scan: %w", err))
-			return
-		}
-
 		res := responses.New()
 		index := &responses.ArtistIndexes{
 			IgnoredArticles: strings.Join(ignoredArticles, " "),
 			LastModified:    lastModified.UnixMilli(),
 			Index:           indexList,
+			Child:           children,
 		}
 		if byID3 {
 			res.Artists = index
@@ -241,6 +249,32 @@ func (h *Handler) handleGetArtistsIndex(byID3 bool) func(w http.ResponseWriter,
 	}
 }
 
+// addToIndex adds artist to the index of the first letter of name ignoring ignoredArticles.
+func addToIndex(indexMap map[rune]*responses.Index, name string, artist *responses.Artist) {
+	for _, i := range ignoredArticles {
+		before := len(name)
+		name = strings.TrimPrefix(name, i+" ")
+		if len(name) < before {
+			break
+		}
+	}
+	name = strings.TrimSpace(name)
+	runes := []rune(name)
+	key := '#'
+	if len(runes) > 0 && unicode.IsLetter(runes[0]) {
+		key = unicode.ToLower(runes[0])
+	}
+
+	if i, ok := indexMap[key]; ok {
+		i.Artist = append(i.Artist, artist)
+	} else {
+		indexMap[key] = &responses.Index{
+			Name:   strings.ToUpper(string(key)),
+			Artist: []*responses.Artist{artist},
+		}
+	}
+}
+
 func (h *Handler) handleGetAlbumInfo2(w http.ResponseWriter, r *http.Request) {
 	q := getQuery(w, r)
 
@@ -262,44 +296,43 @@ func (h *Handler) handleGetAlbumInfo2(w http.ResponseWriter, r *http.Request) {
 		id = *song.AlbumID
 	}
 
+	count, ok := q.IntPositiveDef("count", 10)
+	if !ok {
+		return
+	}
+
+	musicFolderIDs, ok := q.MusicFolderIDs(r.Context(), h.DB)
+	if !ok {
+		return
+	}
+
+	album, err := h.DB.Album().FindByID(r.Context(), id, q.User(), repos.IncludeAlbumInfo{
+		Artists: true,
+	})
+	if err != nil {
+		respondErr(w, q.Format(), fmt.Errorf("get album info: find album: %w", err))
+		return
+	}
+
 	info, err := h.DB.Album().GetInfo(r.Context(), id, q.User())
 	if err != nil && !errors.Is(err, repos.ErrNotFound) {
 		respondErr(w, q.Format(), fmt.Errorf("get album info: get info: %w", err))
 		return
 	}
-	if info.Updated != nil && time.Since(*info.Updated) > keepLastFMInfoFor {
+	if info.Updated != nil && time.Since(*info.Updated) > metadata.MaxInfoAge {
 		info.Updated = nil
 	}
 
-	if info.Updated == nil && h.LastFM != nil {
-		album, err := h.DB.Album().FindByID(r.Context(), id, q.User(), repos.IncludeAlbumInfo{
-			Artists: true,
-		})
-		if err != nil {
-			respondErr(w, q.Format(), fmt.Errorf("get album info: find album: %w", err))
+	if info.Updated == nil && h.LastFM != nil && len(album.Artists) > 0 {
+		lInfo, err := h.Metadata.UpdateAlbumInfo(r.Context(), id, album.Name, album.Artists[0].Name, album.MusicBrainzID)
+		if err != nil && !errors.Is(err, lastfm.ErrNotFound) {
+			respondErr(w, q.Format(), fmt.Errorf("get album info: %w", err))
 			return
 		}
-		if len(album.Artists) > 0 {
-			lInfo, err := h.LastFM.GetAlbumInfo(r.Context(), album.Name, album.Artists[0].Name, album.MusicBrainzID)
-			if err != nil && !errors.Is(err, lastfm.ErrNotFound) {
-				respondErr(w, q.Format(), fmt.Errorf("get album info: fetch last.fm data: %w", err))
-				return
-			}
-			info.Description = lInfo.Wiki.Content
-			info.LastFMMBID = lInfo.MBID
-			info.LastFMURL = lInfo.URL
-
-			if err == nil {
-				err = h.DB.Album().SetInfo(r.Context(), id, repos.SetAlbumInfo{
-					Description: info.Description,
-					LastFMURL:   info.LastFMURL,
-					LastFMMBID:  info.LastFMMBID,
-				})
-				if err != nil {
-					respondErr(w, q.Format(), fmt.Errorf("get album info: save new last.fm data in DB: %w", err))
-					return
-				}
-			}
+		if err == nil {
+			info.Description = lInfo.Description
+			info.LastFMMBID = lInfo.LastFMMBID
+			info.LastFMURL = lInfo.LastFMURL
 		}
 	}
 
@@ -321,6 +354,19 @@ func (h *Handler) handleGetAlbumInfo2(w http.ResponseWriter, r *http.Request) {
 		largeImageUrl = &lg
 	}
 
+	// similar albums are based on the similar artists of the album artists, which are only fetched together with the artist info
+	if len(album.Artists) > 0 {
+		_, err = h.getArtistInfo(r.Context(), album.Artists[0].ID, q.User())
+		if err != nil {
+			log.Errorf("get album info: get info of album artist: %s", err)
+		}
+	}
+	similar, err := h.DB.Album().FindSimilar(r.Context(), id, musicFolderIDs, count, repos.IncludeAlbumInfoFull(q.User()))
+	if err != nil {
+		respondErr(w, q.Format(), fmt.Errorf("get album info: find similar albums: %w", err))
+		return
+	}
+
 	res := responses.New()
 	res.AlbumInfo = &responses.AlbumInfo{
 		Notes:          info.Description,
@@ -330,6 +376,7 @@ func (h *Handler) handleGetAlbumInfo2(w http.ResponseWriter, r *http.Request) {
 		SmallImageURL:  smallImageUrl,
 		MediumImageURL: mediumImageUrl,
 		LargeImageURL:  largeImageUrl,
+		SimilarAlbums:  responses.NewAlbums(similar, h.Config),
 	}
 	res.EncodeOrLog(w, q.Format())
 }
@@ -343,6 +390,16 @@ func (h *Handler) handleGetArtistInfo(version int) func(w http.ResponseWriter, r
 			return
 		}
 
+		count, ok := q.IntPositiveDef("count", 20)
+		if !ok {
+			return
+		}
+
+		musicFolderIDs, ok := q.MusicFolderIDs(r.Context(), h.DB)
+		if !ok {
+			return
+		}
+
 		if typ, _ := crossonic.GetIDType(id); typ == crossonic.IDTypeSong {
 			song, err := h.DB.Song().FindByID(r.Context(), id, q.User(), repos.IncludeSongInfo{
 				Lists: true,
@@ -375,42 +432,11 @@ func (h *Handler) handleGetArtistInfo(version int) func(w http.ResponseWriter, r
 			}
 		}
 
-		info, err := h.DB.Artist().GetInfo(r.Context(), id, q.User())
+		info, err := h.getArtistInfo(r.Context(), id, q.User())
 		if err != nil {
-			respondErr(w, q.Format(), fmt.Errorf("get artist info: get info: %w", err))
+			respondErr(w, q.Format(), fmt.Errorf("get artist info: %w", err))
 			return
 		}
-		if info.Updated != nil && time.Since(*info.Updated) > keepLastFMInfoFor {
-			info.Updated = nil
-		}
-
-		if info.Updated == nil && h.LastFM != nil {
-			artist, err := h.DB.Artist().FindByID(r.Context(), id, q.User(), repos.IncludeArtistInfoBare())
-			if err != nil {
-				respondErr(w, q.Format(), fmt.Errorf("get album info: find album: %w", err))
-				return
-			}
-			lInfo, err := h.LastFM.GetArtistInfo(r.Context(), artist.Name, artist.MusicBrainzID)
-			if err != nil && !errors.Is(err, lastfm.ErrNotFound) {
-				respondErr(w, q.Format(), fmt.Errorf("get album info: fetch last.fm data: %w", err))
-				return
-			}
-			info.Biography = lInfo.Bio.Content
-			info.LastFMMBID = lInfo.MBID
-			info.LastFMURL = lInfo.URL
-
-			if err == nil {
-				err = h.DB.Artist().SetInfo(r.Context(), id, repos.SetArtistInfo{
-					Biography:  info.Biography,
-					LastFMURL:  info.LastFMURL,
-					LastFMMBID: info.LastFMMBID,
-				})
-				if err != nil {
-					respondErr(w, q.Format(), fmt.Errorf("get album info: save new last.fm data in DB: %w", err))
-					return
-				}
-			}
-		}
 
 		mbid := info.MusicBrainzID
 		if mbid == nil {
@@ -439,6 +465,14 @@ func (h *Handler) handleGetArtistInfo(version int) func(w http.ResponseWriter, r
 			MediumImageURL: mediumImageUrl,
 			LargeImageURL:  largeImageUrl,
 		}
+
+		similar, err := h.findSimilarArtists(r.Context(), id, q.User(), musicFolderIDs, count)
+		if err != nil {
+			respondErr(w, q.Format(), fmt.Errorf("get artist info: %w", err))
+			return
+		}
+		artistInfo.SimilarArtists = responses.NewArtists(similar, h.Config)
+
 		if version == 2 {
 			res.ArtistInfo2 = artistInfo
 		} else {
@@ -526,6 +560,51 @@ func (h *Handler) handleGetMusicDirectory(w http.ResponseWriter, r *http.Request
 		}
 		res.EncodeOrLog(w, q.Format())
 		return
+	} else if crossonic.IsIDType(id, crossonic.IDTypeDirectory) {
+		dir, err := h.DB.Directory().FindByID(r.Context(), id, musicFolderIDs)
+		if err != nil {
+			if errors.Is(err, repos.ErrNotFound) {
+				respondNotFoundErr(w, q.Format(), "directory not found")
+				return
+			}
+			respondInternalErr(w, q.Format(), fmt.Errorf("get music directory: find directory: %w", err))
+			return
+		}
+		dirs, err := h.DB.Directory().FindChildren(r.Context(), []string{id}, musicFolderIDs)
+		if err != nil {
+			respondInternalErr(w, q.Format(), fmt.Errorf("get music directory: find subdirectories: %w", err))
+			return
+		}
+		songs, err := h.DB.Directory().GetSongs(r.Context(), []string{id}, repos.IncludeSongInfoFull(q.User()))
+		if err != nil {
+			respondInternalErr(w, q.Format(), fmt.Errorf("get music directory: get directory songs: %w", err))
+			return
+		}
+
+		children := make([]any, 0, len(dirs)+len(songs))
+		for _, d := range dirs {
+			children = append(children, &responses.DirectoryChild{
+				ID:     d.ID,
+				Parent: &dir.ID,
+				IsDir:  true,
+				Title:  d.Name,
+			})
+		}
+		for _, s := range songs {
+			song := responses.NewSong(s, h.Config)
+			song.Parent = &dir.ID
+			children = append(children, song)
+		}
+
+		res := responses.New()
+		res.Directory = &responses.Directory{
+			ID:     dir.ID,
+			Name:   dir.Name,
+			Parent: dir.ParentID,
+			Child:  children,
+		}
+		res.EncodeOrLog(w, q.Format())
+		return
 	} else {
 		respondNotFoundErr(w, q.Format(), "invalid id type")
 	}
@@ -547,3 +626,224 @@ func (h *Handler) constructCoverURL(id string, query UrlQuery) string {
 	}
 	return u
 }
+
+// getArtistInfo returns the info of the artist and fetches it from last.fm if it is missing or older than metadata.MaxInfoAge.
+func (h *Handler) getArtistInfo(ctx context.Context, artistID, user string) (*repos.ArtistInfo, error) {
+	info, err := h.DB.Artist().GetInfo(ctx, artistID, user)
+	if err != nil {
+		return nil, fmt.Errorf("get info: %w", err)
+	}
+	if info.Updated != nil && time.Since(*info.Updated) <= metadata.MaxInfoAge || h.LastFM == nil {
+		return info, nil
+	}
+
+	artist, err := h.DB.Artist().FindByID(ctx, artistID, user, repos.IncludeArtistInfoBare())
+	if err != nil {
+		return nil, fmt.Errorf("find artist: %w", err)
+	}
+	lInfo, err := h.Metadata.UpdateArtistInfo(ctx, artistID, artist.Name, artist.MusicBrainzID)
+	if err != nil {
+		if errors.Is(err, lastfm.ErrNotFound) {
+			return info, nil
+		}
+		return nil, err
+	}
+	info.Biography = lInfo.Biography
+	info.LastFMMBID = lInfo.LastFMMBID
+	info.LastFMURL = lInfo.LastFMURL
+	return info, nil
+}
+
+// findSimilarArtists returns up to count artists in musicFolderIDs that are similar to the artist.
+// Matches of the similar artists reported by last.fm come first, the rest is filled up with the results of the local similarity engine.
+func (h *Handler) findSimilarArtists(ctx context.Context, artistID, user string, musicFolderIDs []int, count int) ([]*repos.CompleteArtist, error) {
+	similar, err := h.DB.Artist().FindSimilar(ctx, artistID, musicFolderIDs, count, repos.IncludeArtistInfoFull(user))
+	if err != nil {
+		return nil, fmt.Errorf("find similar artists: %w", err)
+	}
+	if len(similar) >= count {
+		return similar, nil
+	}
+
+	ids, err := h.Similarity.SimilarArtists(ctx, artistID, musicFolderIDs, count)
+	if err != nil {
+		log.Errorf("find similar artists: %s", err)
+		return similar, nil
+	}
+	ids = slices.DeleteFunc(ids, func(id string) bool {
+		return slices.ContainsFunc(similar, func(a *repos.CompleteArtist) bool {
+			return a.ID == id
+		})
+	})
+	if len(ids) == 0 {
+		return similar, nil
+	}
+	artists, err := h.DB.Artist().FindAll(ctx, repos.FindArtistsParams{
+		IDs:            ids,
+		MusicFolderIDs: musicFolderIDs,
+	}, repos.IncludeArtistInfoFull(user))
+	if err != nil {
+		return nil, fmt.Errorf("find similar artists: find artists: %w", err)
+	}
+	artistsByID := make(map[string]*repos.CompleteArtist, len(artists))
+	for _, a := range artists {
+		artistsByID[a.ID] = a
+	}
+	// keep the order of the similarity engine, IDs outside of the music folders are not found
+	for _, id := range ids {
+		if len(similar) >= count {
+			break
+		}
+		if artist, ok := artistsByID[id]; ok {
+			similar = append(similar, artist)
+		}
+	}
+	return similar, nil
+}
+
+// https://opensubsonic.netlify.app/docs/endpoints/getsimilarsongs/
+// https://opensubsonic.netlify.app/docs/endpoints/getsimilarsongs2/
+func (h *Handler) handleGetSimilarSongs(version int) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := getQuery(w, r)
+
+		id, ok := q.IDTypeReq("id", []crossonic.IDType{crossonic.IDTypeSong, crossonic.IDTypeAlbum, crossonic.IDTypeArtist})
+		if !ok {
+			return
+		}
+
+		count, ok := q.IntPositiveDef("count", 50)
+		if !ok {
+			return
+		}
+
+		musicFolderIDs, ok := q.MusicFolderIDs(r.Context(), h.DB)
+		if !ok {
+			return
+		}
+
+		var ids []string
+		var err error
+		switch typ, _ := crossonic.GetIDType(id); typ {
+		case crossonic.IDTypeSong:
+			_, err = h.DB.Song().FindByID(r.Context(), id, q.User(), repos.IncludeSongInfoBare())
+			if err != nil {
+				respondErr(w, q.Format(), fmt.Errorf("get similar songs: find song: %w", err))
+				return
+			}
+			ids, err = h.Similarity.SimilarSongs(r.Context(), []string{id}, musicFolderIDs, count)
+		case crossonic.IDTypeAlbum:
+			_, err = h.DB.Album().FindByID(r.Context(), id, q.User(), repos.IncludeAlbumInfoBare())
+			if err != nil {
+				respondErr(w, q.Format(), fmt.Errorf("get similar songs: find album: %w", err))
+				return
+			}
+			var tracks []*repos.CompleteSong
+			tracks, err = h.DB.Album().GetTracks(r.Context(), id, repos.IncludeSongInfoBare())
+			if err != nil {
+				respondErr(w, q.Format(), fmt.Errorf("get similar songs: get album tracks: %w", err))
+				return
+			}
+			ids, err = h.Similarity.SimilarSongs(r.Context(), util.Map(tracks, func(s *repos.CompleteSong) string {
+				return s.ID
+			}), musicFolderIDs, count)
+		case crossonic.IDTypeArtist:
+			_, err = h.DB.Artist().FindByID(r.Context(), id, q.User(), repos.IncludeArtistInfoBare())
+			if err != nil {
+				respondErr(w, q.Format(), fmt.Errorf("get similar songs: find artist: %w", err))
+				return
+			}
+			ids, err = h.Similarity.SimilarSongsForArtist(r.Context(), id, musicFolderIDs, count)
+		}
+		if err != nil {
+			respondErr(w, q.Format(), fmt.Errorf("get similar songs: %w", err))
+			return
+		}
+
+		songs, err := h.findSongsInOrder(r.Context(), ids, repos.IncludeSongInfoFull(q.User()))
+		if err != nil {
+			respondInternalErr(w, q.Format(), fmt.Errorf("get similar songs: %w", err))
+			return
+		}
+
+		res := responses.New()
+		if version == 2 {
+			res.SimilarSongs2 = &responses.SimilarSongs2{
+				Songs: responses.NewSongs(songs, h.Config),
+			}
+		} else {
+			res.SimilarSongs = &responses.SimilarSongs{
+				Songs: responses.NewSongs(songs, h.Config),
+			}
+		}
+		res.EncodeOrLog(w, q.Format())
+	}
+}
+
+// https://opensubsonic.netlify.app/docs/endpoints/gettopsongs/
+func (h *Handler) handleGetTopSongs(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	name, ok := q.StrReq("artist")
+	if !ok {
+		return
+	}
+
+	count, ok := q.IntPositiveDef("count", 50)
+	if !ok {
+		return
+	}
+
+	musicFolderIDs, ok := q.MusicFolderIDs(r.Context(), h.DB)
+	if !ok {
+		return
+	}
+
+	artists, err := h.DB.Artist().FindByNames(r.Context(), []string{name}, repos.IncludeArtistInfoBare())
+	if err != nil {
+		respondInternalErr(w, q.Format(), fmt.Errorf("get top songs: find artist: %w", err))
+		return
+	}
+
+	songs := make([]*repos.CompleteSong, 0, count)
+	if len(artists) > 0 {
+		ids, err := h.Similarity.TopSongs(r.Context(), artists[0].ID, musicFolderIDs, count)
+		if err != nil {
+			respondInternalErr(w, q.Format(), fmt.Errorf("get top songs: %w", err))
+			return
+		}
+		songs, err = h.findSongsInOrder(r.Context(), ids, repos.IncludeSongInfoFull(q.User()))
+		if err != nil {
+			respondInternalErr(w, q.Format(), fmt.Errorf("get top songs: %w", err))
+			return
+		}
+	}
+
+	res := responses.New()
+	res.TopSongs = &responses.TopSongs{
+		Songs: responses.NewSongs(songs, h.Config),
+	}
+	res.EncodeOrLog(w, q.Format())
+}
+
+// findSongsInOrder returns the songs with the given ids in the order of ids.
+func (h *Handler) findSongsInOrder(ctx context.Context, ids []string, include repos.IncludeSongInfo) ([]*repos.CompleteSong, error) {
+	if len(ids) == 0 {
+		return []*repos.CompleteSong{}, nil
+	}
+	songs, err := h.DB.Song().FindByIDs(ctx, ids, include)
+	if err != nil {
+		return nil, fmt.Errorf("find songs in order: %w", err)
+	}
+	byID := make(map[string]*repos.CompleteSong, len(songs))
+	for _, s := range songs {
+		byID[s.ID] = s
+	}
+	ordered := make([]*repos.CompleteSong, 0, len(songs))
+	for _, id := range ids {
+		if s, ok := byID[id]; ok {
+			ordered = append(ordered, s)
+		}
+	}
+	return ordered, nil
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/juho05/crossonic-server"
+	"github.com/juho05/crossonic-server/handlers/responses"
+	"github.com/juho05/crossonic-server/jukebox"
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/juho05/crossonic-server/util"
+)
+
+// https://opensubsonic.netlify.app/docs/endpoints/jukeboxcontrol/
+func (h *Handler) handleJukeboxControl(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	if h.Jukebox == nil {
+		respondGenericErr(w, q.Format(), "jukebox is disabled")
+		return
+	}
+
+	action, ok := q.StrReq("action")
+	if !ok {
+		return
+	}
+
+	var err error
+	switch action {
+	case "get":
+		status, ids := h.Jukebox.Playlist()
+		songs, err := h.findSongsInOrder(r.Context(), ids, repos.IncludeSongInfoFull(q.User()))
+		if err != nil {
+			respondInternalErr(w, q.Format(), fmt.Errorf("jukebox control: get: %w", err))
+			return
+		}
+		res := responses.New()
+		res.JukeboxPlaylist = &responses.JukeboxPlaylist{
+			JukeboxStatus: newJukeboxStatusResponse(status),
+			Entry:         responses.NewSongs(songs, h.Config),
+		}
+		res.EncodeOrLog(w, q.Format())
+		return
+	case "status":
+	case "set", "add":
+		ids, ok := h.parseJukeboxSongIDs(r.Context(), q)
+		if !ok {
+			return
+		}
+		if action == "set" {
+			err = h.Jukebox.Set(r.Context(), ids)
+		} else {
+			h.Jukebox.Add(ids)
+		}
+	case "start":
+		err = h.Jukebox.Start(r.Context())
+	case "stop":
+		h.Jukebox.Stop()
+	case "skip":
+		index, ok := q.IntPositiveReq("index")
+		if !ok {
+			return
+		}
+		offset, ok := q.IntPositiveDef("offset", 0)
+		if !ok {
+			return
+		}
+		err = h.Jukebox.Skip(r.Context(), index, time.Duration(offset)*time.Second)
+	case "clear":
+		h.Jukebox.Clear()
+	case "remove":
+		index, ok := q.IntPositiveReq("index")
+		if !ok {
+			return
+		}
+		err = h.Jukebox.Remove(r.Context(), index)
+	case "shuffle":
+		h.Jukebox.Shuffle()
+	case "setGain":
+		gain, ok := q.FloatRange("gain", 0, 1)
+		if !ok {
+			return
+		}
+		if gain == nil {
+			q.missingParameter("gain")
+			return
+		}
+		err = h.Jukebox.SetGain(r.Context(), *gain)
+	default:
+		q.invalidParameter("action")
+		return
+	}
+	if err != nil {
+		if errors.Is(err, jukebox.ErrIndexOutOfRange) {
+			q.invalidParameter("index")
+			return
+		}
+		respondInternalErr(w, q.Format(), fmt.Errorf("jukebox control: %s: %w", action, err))
+		return
+	}
+
+	res := responses.New()
+	res.JukeboxStatus = util.ToPtr(newJukeboxStatusResponse(h.Jukebox.Status()))
+	res.EncodeOrLog(w, q.Format())
+}
+
+// parseJukeboxSongIDs returns the id values of the query and verifies that all of them are songs the user has access to.
+func (h *Handler) parseJukeboxSongIDs(ctx context.Context, q UrlQuery) ([]string, bool) {
+	ids, ok := q.IDsType("id", []crossonic.IDType{crossonic.IDTypeSong})
+	if !ok {
+		return nil, false
+	}
+	musicFolderIDs, ok := q.MusicFolderIDs(ctx, h.DB)
+	if !ok {
+		return nil, false
+	}
+	songs, err := h.DB.Song().FindByIDs(ctx, ids, repos.IncludeSongInfoBare())
+	if err != nil {
+		respondInternalErr(q.responseWriter, q.Format(), fmt.Errorf("parse jukebox song ids: %w", err))
+		return nil, false
+	}
+	accessible := make(map[string]struct{}, len(songs))
+	for _, s := range songs {
+		if s.MusicFolderID != nil && slices.Contains(musicFolderIDs, *s.MusicFolderID) {
+			accessible[s.ID] = struct{}{}
+		}
+	}
+	for _, id := range ids {
+		if _, ok := accessible[id]; !ok {
+			respondNotFoundErr(q.responseWriter, q.Format(), fmt.Sprintf("song %s not found", id))
+			return nil, false
+		}
+	}
+	return ids, true
+}
+
+func newJukeboxStatusResponse(status jukebox.Status) responses.JukeboxStatus {
+	return responses.JukeboxStatus{
+		CurrentIndex: status.CurrentIndex,
+		Playing:      status.Playing,
+		Gain:         status.Gain,
+		Position:     int(status.Position.Seconds()),
+	}
+}
@@ -80,7 +80,7 @@ func (h *Handler) handleGetAlbumList(version int) func(w http.ResponseWriter, r
 			return
 		}
 		if listType == "byYear" && fromYear == nil {
-			responses.EncodeError(w, q.Format(), "missing fromYear parameter", responses.SubsonicErrorRequiredParameterMissing)
+			q.missingParameter("fromYear")
 			return
 		}
 
@@ -89,28 +89,47 @@ func (h *Handler) handleGetAlbumList(version int) func(w http.ResponseWriter, r
 			return
 		}
 		if listType == "byYear" && toYear == nil {
-			responses.EncodeError(w, q.Format(), "missing toYear parameter", responses.SubsonicErrorRequiredParameterMissing)
+			q.missingParameter("toYear")
 			return
 		}
 
 		genres := q.Strs("genre")
 		if listType == "byGenre" && len(genres) == 0 {
-			responses.EncodeError(w, q.Format(), "missing genre parameter", responses.SubsonicErrorRequiredParameterMissing)
+			q.missingParameter("genre")
+			return
+		}
+
+		recordLabels := q.Strs("recordLabel")
+		if listType == "byRecordLabel" && len(recordLabels) == 0 {
+			q.missingParameter("recordLabel")
+			return
+		}
+
+		releaseTypes := q.Strs("releaseType")
+		if listType == "byReleaseType" && len(releaseTypes) == 0 {
+			q.missingParameter("releaseType")
 			return
 		}
 
 		seed := util.NilIfEmpty(q.Str("seed"))
 
 		sortTypes := map[string]repos.FindAlbumSortBy{
-			"random":             repos.FindAlbumSortRandom,
-			"newest":             repos.FindAlbumSortByCreated,
-			"highest":            repos.FindAlbumSortByRating,
-			"alphabeticalByName": repos.FindAlbumSortByName,
-			"starred":            repos.FindAlbumSortByStarred,
-			"byYear":             repos.FindAlbumSortByReleaseDate,
-			"byGenre":            repos.FindAlbumSortByName,
-			"frequent":           repos.FindAlbumSortByFrequent,
-			"recent":             repos.FindAlbumSortByRecent,
+			"random":               repos.FindAlbumSortRandom,
+			"newest":               repos.FindAlbumSortByCreated,
+			"highest":              repos.FindAlbumSortByRating,
+			"alphabeticalByName":   repos.FindAlbumSortByName,
+			"alphabeticalByArtist": repos.FindAlbumSortByArtist,
+			"starred":              repos.FindAlbumSortByStarred,
+			"byYear":               repos.FindAlbumSortByReleaseDate,
+			"byGenre":              repos.FindAlbumSortByName,
+			"frequent":             repos.FindAlbumSortByFrequent,
+			"recent":               repos.FindAlbumSortByRecent,
+
+			// crossonic extensions
+			"byRecordLabel":  repos.FindAlbumSortByName,
+			"byReleaseType":  repos.FindAlbumSortByName,
+			"compilations":   repos.FindAlbumSortByName,
+			"recentByAnyone": repos.FindAlbumSortByRecentAll,
 		}
 
 		sortBy, ok := sortTypes[listType]
@@ -125,13 +144,16 @@ func (h *Handler) handleGetAlbumList(version int) func(w http.ResponseWriter, r
 		}
 
 		a, err := h.DB.Album().FindAll(r.Context(), repos.FindAlbumParams{
-			SortBy:         sortBy,
-			FromYear:       fromYear,
-			ToYear:         toYear,
-			Genres:         genres,
-			Paginate:       paginate,
-			RandomSeed:     seed,
-			MusicFolderIDs: musicFolderIDs,
+			SortBy:           sortBy,
+			FromYear:         fromYear,
+			ToYear:           toYear,
+			Genres:           genres,
+			RecordLabels:     recordLabels,
+			ReleaseTypes:     releaseTypes,
+			OnlyCompilations: listType == "compilations",
+			Paginate:         paginate,
+			RandomSeed:       seed,
+			MusicFolderIDs:   musicFolderIDs,
 		}, repos.IncludeAlbumInfoFull(q.User()))
 		if err != nil {
 			respondInternalErr(w, q.Format(), fmt.Errorf("get album list 2: find all: %w", err))
@@ -23,6 +23,30 @@ func (h *Handler) handleStartScan(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Crossonic extension: only scan a music folder or a file or directory inside of it
+	musicFolderID, ok := q.IntPositive("musicFolderId")
+	if !ok {
+		return
+	}
+	var targetPath string
+	if musicFolderID != nil {
+		var err error
+		targetPath, err = h.Scanner.MusicFolderPath(*musicFolderID, q.Str("path"))
+		if err != nil {
+			if errors.Is(err, scanner.ErrUnknownMusicFolder) {
+				respondNotFoundErr(w, q.Format(), "music folder not found")
+			} else if errors.Is(err, scanner.ErrPathOutsideMusicFolder) {
+				q.invalidParameter("path")
+			} else {
+				respondInternalErr(w, q.Format(), err)
+			}
+			return
+		}
+	} else if q.Has("path") {
+		q.missingParameter("musicFolderId")
+		return
+	}
+
 	startTime := time.Now()
 
 	var lastScan *time.Time
@@ -38,25 +62,25 @@ func (h *Handler) handleStartScan(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if h.Scanner.Scanning() {
-		res.ScanStatus = &responses.ScanStatus{
-			Scanning:  true,
-			Count:     util.ToPtr(h.Scanner.Count()),
-			LastScan:  lastScan,
-			FullScan:  h.Scanner.IsFullScan(),
-			StartTime: util.ToPtr(h.Scanner.ScanStart()),
-		}
+		res.ScanStatus = h.scanStatus(lastScan)
 		res.EncodeOrLog(w, q.Format())
 		return
 	}
 
 	go func() {
-		if fullScan {
-			log.Infof("manual full scan triggered by %s", q.User())
+		var err error
+		if targetPath != "" {
+			log.Infof("manual scan of %s (full scan: %t) triggered by %s", targetPath, fullScan, q.User())
+			err = h.Scanner.ScanPaths(h.DB, []string{targetPath}, fullScan)
 		} else {
-			log.Infof("manual quick scan triggered by %s", q.User())
+			if fullScan {
+				log.Infof("manual full scan triggered by %s", q.User())
+			} else {
+				log.Infof("manual quick scan triggered by %s", q.User())
+			}
+			err = h.Scanner.Scan(h.DB, fullScan)
 		}
-		err := h.Scanner.Scan(h.DB, fullScan)
-		if err != nil && !errors.Is(err, scanner.ErrAlreadyScanning) {
+		if err != nil && !errors.Is(err, scanner.ErrAlreadyScanning) && !errors.Is(err, scanner.ErrScanCanceled) {
 			log.Errorf("scan media full: %s", err)
 		}
 	}()
@@ -88,21 +112,30 @@ func (h *Handler) handleGetScanStatus(w http.ResponseWriter, r *http.Request) {
 		lastScan = &ls
 	}
 
-	if h.Scanner.Scanning() {
-		res.ScanStatus = &responses.ScanStatus{
-			Scanning:  true,
-			Count:     util.ToPtr(h.Scanner.Count()),
-			LastScan:  lastScan,
-			FullScan:  h.Scanner.IsFullScan(),
-			StartTime: util.ToPtr(h.Scanner.ScanStart()),
-		}
-	} else {
-		res.ScanStatus = &responses.ScanStatus{
+	res.ScanStatus = h.scanStatus(lastScan)
+	res.EncodeOrLog(w, q.Format())
+}
+
+// scanStatus returns the progress of the running scan or the result of the last scan if no scan is running.
+// Besides the Subsonic fields, the progress includes the current phase, the processed files and the errors so far.
+func (h *Handler) scanStatus(lastScan *time.Time) *responses.ScanStatus {
+	if !h.Scanner.Scanning() {
+		return &responses.ScanStatus{
 			Scanning: false,
 			Count:    util.ToPtr(h.Scanner.Count()),
 			LastScan: lastScan,
 		}
 	}
-
-	res.EncodeOrLog(w, q.Format())
+	progress := h.Scanner.Progress()
+	return &responses.ScanStatus{
+		Scanning:       true,
+		Count:          util.ToPtr(progress.DiscoveredFiles),
+		LastScan:       lastScan,
+		FullScan:       h.Scanner.IsFullScan(),
+		StartTime:      util.ToPtr(h.Scanner.ScanStart()),
+		Phase:          util.ToPtr(string(progress.Phase)),
+		ProcessedCount: util.ToPtr(progress.ProcessedFiles),
+		ErrorCount:     util.ToPtr(progress.Errors),
+		Canceling:      progress.Canceling,
+	}
 }
@@ -7,8 +7,11 @@ import (
 	"io"
 	"io/fs"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,6 +19,7 @@ import (
 	"github.com/disintegration/imaging"
 	"github.com/juho05/crossonic-server"
 	"github.com/juho05/crossonic-server/cache"
+	"github.com/juho05/crossonic-server/ffmpeg"
 	"github.com/juho05/crossonic-server/handlers/responses"
 	"github.com/juho05/crossonic-server/lastfm"
 	"github.com/juho05/crossonic-server/repos"
@@ -54,7 +58,18 @@ func (h *Handler) handleStream(w http.ResponseWriter, r *http.Request) {
 		timeOffset = time.Duration(*timeOffsetMsInt) * time.Millisecond
 	}
 
-	info, err := h.DB.Song().GetStreamInfo(r.Context(), id, q.User())
+	normalize, ok := h.normalizeMode(r.Context(), q)
+	if !ok {
+		return
+	}
+
+	h.stream(w, r, q, id, format, maxBitRate, timeOffset, normalize)
+}
+
+// stream serves the song or podcast episode with id in format. Raw files are served directly if no time offset
+// or normalization is requested. Transcodes without a time offset are cached.
+func (h *Handler) stream(w http.ResponseWriter, r *http.Request, q UrlQuery, id, format string, maxBitRate int, timeOffset time.Duration, normalize repos.NormalizeMode) {
+	info, err := h.getStreamInfo(r.Context(), id, q.User())
 	if err != nil {
 		respondErr(w, q.Format(), fmt.Errorf("stream: get info: %w", err))
 		return
@@ -66,7 +81,15 @@ func (h *Handler) handleStream(w http.ResponseWriter, r *http.Request) {
 
 	fileFormat, bitRate := h.Transcoder.SelectFormat(format, info.ChannelCount, maxBitRate)
 
-	if format == "raw" || (fileFormat.Mime == info.ContentType && (maxBitRate == 0 || maxBitRate >= info.BitRate)) {
+	var normalization *ffmpeg.Normalization
+	if t, ok := crossonic.GetIDType(id); format != "raw" && ok && t == crossonic.IDTypeSong {
+		normalization = newNormalization(info, normalize)
+	}
+	if normalization == nil {
+		normalize = repos.NormalizeModeOff
+	}
+
+	if format == "raw" || fileFormat.Name == "" || (normalization == nil && fileFormat.Mime == info.ContentType && (maxBitRate == 0 || maxBitRate >= info.BitRate)) {
 		format = "raw"
 		fileFormat.Mime = info.ContentType
 		fileFormat.Name = strings.TrimPrefix(filepath.Ext(info.Path), ".")
@@ -82,7 +105,7 @@ func (h *Handler) handleStream(w http.ResponseWriter, r *http.Request) {
 	if !ok {
 		return
 	}
-	if estimateContentLength {
+	if estimateContentLength && bitRate > 0 {
 		w.Header().Set("Content-Length", fmt.Sprint(int(float64(info.Duration.ToStd()-timeOffset)/float64(time.Second)*float64(bitRate)/8*1000)))
 	}
 
@@ -100,7 +123,7 @@ func (h *Handler) handleStream(w http.ResponseWriter, r *http.Request) {
 			})
 			log.Tracef("Streaming %s with offset (%s) (%s %dkbps) to %s (user: %s)...", id, timeOffset.String(), info.ContentType, info.BitRate, q.Client(), q.User())
 		} else {
-			bitRate, err = h.Transcoder.Transcode(info.Path, info.ChannelCount, fileFormat, bitRate, timeOffset, w, func(err error) {
+			bitRate, err = h.Transcoder.Transcode(info.Path, info.ChannelCount, fileFormat, bitRate, timeOffset, normalization, w, func(err error) {
 				close(done)
 			})
 			log.Tracef("Streaming %s with transcoded offset (%s) (%s %dkbps) to %s (user: %s)...", id, timeOffset.String(), fileFormat.Name, bitRate, q.Client(), q.User())
@@ -113,62 +136,303 @@ func (h *Handler) handleStream(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	cacheKey := fmt.Sprintf("%s-%s-%d", id, fileFormat.Name, bitRate)
+	cacheKey := transcodeCacheKey(id, fileFormat.Name, bitRate, normalize, normalization)
 
-	cacheObj, exists := h.TranscodeCache.GetObject(cacheKey)
-	if !exists {
-		cacheObj, err = h.TranscodeCache.CreateObject(cacheKey)
-		if err != nil {
-			respondErr(w, q.Format(), fmt.Errorf("stream: %w", err))
-			return
+	cacheObj, created, err := h.getOrCreateTranscode(cacheKey, func(w io.Writer, onDone func(err error)) error {
+		_, err := h.Transcoder.Transcode(info.Path, info.ChannelCount, fileFormat, bitRate, 0, normalization, w, onDone)
+		return err
+	})
+	if err != nil {
+		respondInternalErr(w, q.Format(), fmt.Errorf("stream: %w", err))
+		return
+	}
+	if created {
+		log.Tracef("Streaming %s transcoded (%s %dkbps) to %s (user: %s) (new transcode)...", id, fileFormat.Name, bitRate, q.Client(), q.User())
+	} else {
+		log.Tracef("Streaming %s transcoded (%s %dkbps) to %s (user: %s) (cached (complete: %t)) (range: %s)...", id, fileFormat.Name, bitRate, q.Client(), q.User(), cacheObj.IsComplete(), r.Header.Get("Range"))
+	}
+
+	h.serveTranscode(w, r, q, id, cacheObj)
+}
+
+// normalizeMode returns the value of the normalize parameter or the default of the user if it is not set.
+func (h *Handler) normalizeMode(ctx context.Context, q UrlQuery) (repos.NormalizeMode, bool) {
+	if q.Has("normalize") {
+		mode, ok := parseNormalizeMode(q.Str("normalize"))
+		if !ok {
+			q.invalidParameter("normalize")
 		}
-		bitRate, err = h.Transcoder.Transcode(info.Path, info.ChannelCount, fileFormat, bitRate, 0, cacheObj, func(err error) {
-			if err != nil {
-				err = h.TranscodeCache.DeleteObject(cacheKey)
-				if err != nil {
-					log.Errorf("stream: %s", err)
-				}
-				return
-			}
+		return mode, ok
+	}
+	user, err := h.DB.User().FindByName(ctx, q.User())
+	if err != nil {
+		respondInternalErr(q.responseWriter, q.Format(), fmt.Errorf("get normalize mode: %w", err))
+		return "", false
+	}
+	return user.Normalize, true
+}
 
-			err = cacheObj.SetComplete()
-			if err != nil {
-				log.Errorf("ffmpeg: transcode: %s", err)
+func parseNormalizeMode(value string) (repos.NormalizeMode, bool) {
+	switch mode := repos.NormalizeMode(value); mode {
+	case repos.NormalizeModeOff, repos.NormalizeModeTrack, repos.NormalizeModeAlbum:
+		return mode, true
+	default:
+		return "", false
+	}
+}
+
+// newNormalization returns the ReplayGain adjustment for info in mode or nil if mode is off.
+// Album mode falls back to the track gain. Songs without any gain use the library-wide fallback gain.
+func newNormalization(info *repos.SongStreamInfo, mode repos.NormalizeMode) *ffmpeg.Normalization {
+	var gain, peak *float64
+	switch mode {
+	case repos.NormalizeModeTrack:
+		gain, peak = info.ReplayGain, info.ReplayGainPeak
+	case repos.NormalizeModeAlbum:
+		gain, peak = info.AlbumReplayGain, info.AlbumReplayGainPeak
+		if gain == nil {
+			gain, peak = info.ReplayGain, info.ReplayGainPeak
+		}
+	default:
+		return nil
+	}
+	if gain == nil {
+		return &ffmpeg.Normalization{GainDB: repos.FallbackGain()}
+	}
+	n := &ffmpeg.Normalization{GainDB: *gain}
+	if peak != nil {
+		n.Peak = *peak
+	}
+	return n
+}
+
+// transcodeCacheKey returns the key of a transcode in the transcode cache.
+// The applied gain is part of the key, so that transcodes are not reused after the replay gain values changed.
+func transcodeCacheKey(id, format string, bitRate int, normalize repos.NormalizeMode, normalization *ffmpeg.Normalization) string {
+	cacheKey := fmt.Sprintf("%s-%s-%d", id, format, bitRate)
+	if normalization != nil {
+		cacheKey += fmt.Sprintf("-%s-%.2f-%.2f", normalize, normalization.GainDB, normalization.Peak)
+	}
+	return cacheKey
+}
+
+// getOrCreateTranscode returns the transcode cache object with cacheKey. If the object does not exist yet,
+// it is created and transcode is called to write the transcode into it.
+func (h *Handler) getOrCreateTranscode(cacheKey string, transcode func(w io.Writer, onDone func(err error)) error) (*cache.Object, bool, error) {
+	cacheObj, exists := h.TranscodeCache.GetObject(cacheKey)
+	if exists {
+		return cacheObj, false, nil
+	}
+	cacheObj, err := h.TranscodeCache.CreateObject(cacheKey)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			// another request started the same transcode in the meantime
+			if cacheObj, exists = h.TranscodeCache.GetObject(cacheKey); exists {
+				return cacheObj, false, nil
 			}
-		})
+		}
+		return nil, false, fmt.Errorf("get or create transcode: %w", err)
+	}
+	err = transcode(cacheObj, func(err error) {
 		if err != nil {
-			respondInternalErr(w, q.Format(), fmt.Errorf("stream: %w", err))
 			err = h.TranscodeCache.DeleteObject(cacheKey)
 			if err != nil {
-				log.Errorf("stream: %s", err)
+				log.Errorf("get or create transcode: %s", err)
 			}
 			return
 		}
-		log.Tracef("Streaming %s transcoded (%s %dkbps) to %s (user: %s) (new transcode)...", id, fileFormat.Name, bitRate, q.Client(), q.User())
-	} else {
-		log.Tracef("Streaming %s transcoded (%s %dkbps) to %s (user: %s) (cached (complete: %t)) (range: %s)...", id, fileFormat.Name, bitRate, q.Client(), q.User(), cacheObj.IsComplete(), r.Header.Get("Range"))
+
+		err = cacheObj.SetComplete()
+		if err != nil {
+			log.Errorf("ffmpeg: transcode: %s", err)
+		}
+	})
+	if err != nil {
+		deleteErr := h.TranscodeCache.DeleteObject(cacheKey)
+		if deleteErr != nil {
+			log.Errorf("get or create transcode: %s", deleteErr)
+		}
+		return nil, false, fmt.Errorf("get or create transcode: %w", err)
 	}
+	return cacheObj, true, nil
+}
 
+// serveTranscode writes the content of a transcode cache object to w. Range requests are only supported
+// once the transcode is complete.
+func (h *Handler) serveTranscode(w http.ResponseWriter, r *http.Request, q UrlQuery, name string, cacheObj *cache.Object) {
 	cacheReader, err := cacheObj.Reader(r.Context())
 	if err != nil {
-		respondInternalErr(w, q.Format(), fmt.Errorf("stream: %w", err))
+		respondInternalErr(w, q.Format(), fmt.Errorf("serve transcode: %w", err))
 		return
 	}
 	defer func() {
 		err = cacheReader.Close()
 		if err != nil {
-			log.Errorf("stream: %s", err)
+			log.Errorf("serve transcode: %s", err)
 		}
 	}()
 
 	if cacheObj.IsComplete() {
-		http.ServeContent(w, r, id, cacheObj.Modified(), cacheReader)
+		http.ServeContent(w, r, name, cacheObj.Modified(), cacheReader)
 	} else {
 		w.Header().Set("Accept-Ranges", "none")
 		_, _ = io.Copy(w, cacheReader)
 	}
 }
 
+const hlsSegmentDuration = 10 * time.Second
+
+// https://opensubsonic.netlify.app/docs/endpoints/hls/
+func (h *Handler) handleHLS(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	id, ok := q.IDReq("id")
+	if !ok {
+		return
+	}
+
+	bitRates, ok := parseHLSBitRates(q)
+	if !ok {
+		return
+	}
+
+	info, err := h.getStreamInfo(r.Context(), id, q.User())
+	if err != nil {
+		respondErr(w, q.Format(), fmt.Errorf("hls: get info: %w", err))
+		return
+	}
+	if info.Duration.ToStd() <= 0 {
+		respondGenericErr(w, q.Format(), "cannot stream files with unknown duration via hls")
+		return
+	}
+
+	playlist := new(strings.Builder)
+	playlist.WriteString("#EXTM3U\n#EXT-X-VERSION:3\n")
+	if len(bitRates) > 1 {
+		for i, b := range bitRates {
+			bitRates[i] = hlsBitRate(info, b)
+		}
+		slices.Sort(bitRates)
+		for _, b := range slices.Compact(bitRates) {
+			fmt.Fprintf(playlist, "#EXT-X-STREAM-INF:BANDWIDTH=%d,CODECS=\"mp4a.40.2\"\n", b*1000)
+			playlist.WriteString(hlsURI(q, "hls.m3u8", url.Values{
+				"id":      {id},
+				"bitRate": {strconv.Itoa(b)},
+			}) + "\n")
+		}
+	} else {
+		var bitRate int
+		if len(bitRates) == 1 {
+			bitRate = bitRates[0]
+		}
+		bitRate = hlsBitRate(info, bitRate)
+		fmt.Fprintf(playlist, "#EXT-X-PLAYLIST-TYPE:VOD\n#EXT-X-TARGETDURATION:%d\n#EXT-X-MEDIA-SEQUENCE:0\n", int(hlsSegmentDuration.Seconds()))
+		duration := info.Duration.ToStd()
+		for i := range hlsSegmentCount(duration) {
+			segmentDuration := min(hlsSegmentDuration, duration-time.Duration(i)*hlsSegmentDuration)
+			fmt.Fprintf(playlist, "#EXTINF:%.3f,\n", segmentDuration.Seconds())
+			playlist.WriteString(hlsURI(q, "hlsSegment.ts", url.Values{
+				"id":      {id},
+				"bitRate": {strconv.Itoa(bitRate)},
+				"index":   {strconv.Itoa(i)},
+			}) + "\n")
+		}
+		playlist.WriteString("#EXT-X-ENDLIST\n")
+	}
+
+	w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+	_, _ = io.WriteString(w, playlist.String())
+}
+
+// handleHLSSegment serves the media segments referenced by the playlists of handleHLS.
+func (h *Handler) handleHLSSegment(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	id, ok := q.IDReq("id")
+	if !ok {
+		return
+	}
+
+	bitRate, ok := q.IntPositiveDef("bitRate", 0)
+	if !ok {
+		return
+	}
+
+	info, err := h.getStreamInfo(r.Context(), id, q.User())
+	if err != nil {
+		respondErr(w, q.Format(), fmt.Errorf("hls segment: get info: %w", err))
+		return
+	}
+
+	index, ok := q.IntRangeReq("index", 0, hlsSegmentCount(info.Duration.ToStd())-1)
+	if !ok {
+		return
+	}
+
+	bitRate = hlsBitRate(info, bitRate)
+	timeOffset := time.Duration(index) * hlsSegmentDuration
+	duration := min(hlsSegmentDuration, info.Duration.ToStd()-timeOffset)
+
+	w.Header().Set("Content-Type", ffmpeg.HLSSegmentFormat.Mime)
+	if r.Method == http.MethodHead {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	cacheKey := fmt.Sprintf("%s-hls-%d-%d", id, bitRate, index)
+	cacheObj, created, err := h.getOrCreateTranscode(cacheKey, func(w io.Writer, onDone func(err error)) error {
+		_, err := h.Transcoder.TranscodeSegment(info.Path, info.ChannelCount, bitRate, timeOffset, duration, w, onDone)
+		return err
+	})
+	if err != nil {
+		respondInternalErr(w, q.Format(), fmt.Errorf("hls segment: %w", err))
+		return
+	}
+	log.Tracef("Streaming hls segment %d of %s (%dkbps) to %s (user: %s) (new transcode: %t)...", index, id, bitRate, q.Client(), q.User(), created)
+
+	h.serveTranscode(w, r, q, fmt.Sprintf("%s-%d.ts", id, index), cacheObj)
+}
+
+// parseHLSBitRates parses the bitRate parameters of the hls endpoint.
+// Video resolutions (e.g. 1000@480x360) are ignored.
+func parseHLSBitRates(q UrlQuery) ([]int, bool) {
+	strs := q.Strs("bitRate")
+	bitRates := make([]int, 0, len(strs))
+	for _, str := range strs {
+		str, _, _ = strings.Cut(str, "@")
+		bitRate, err := strconv.Atoi(str)
+		if err != nil || bitRate < 0 {
+			q.invalidParameter("bitRate")
+			return nil, false
+		}
+		bitRates = append(bitRates, bitRate)
+	}
+	return bitRates, true
+}
+
+// hlsBitRate limits bitRate to the bitrate of the source file and the bitrates supported by the segment format.
+func hlsBitRate(info *repos.SongStreamInfo, bitRate int) int {
+	if info.BitRate > 0 && bitRate > info.BitRate {
+		bitRate = info.BitRate
+	}
+	return ffmpeg.HLSSegmentFormat.ClampBitRate(info.ChannelCount, bitRate)
+}
+
+func hlsSegmentCount(duration time.Duration) int {
+	return int((duration + hlsSegmentDuration - 1) / hlsSegmentDuration)
+}
+
+// hlsURI returns a URI relative to the playlist. HLS clients request the URIs in playlists as is,
+// so the authentication parameters of the current request are included.
+func hlsURI(q UrlQuery, endpoint string, params url.Values) string {
+	for _, k := range []string{"u", "p", "t", "s", "apiKey", "v", "c"} {
+		if q.Has(k) {
+			params.Set(k, q.Str(k))
+		}
+	}
+	return endpoint + "?" + params.Encode()
+}
+
 func (h *Handler) handleDownload(w http.ResponseWriter, r *http.Request) {
 	q := getQuery(w, r)
 
@@ -177,7 +441,7 @@ func (h *Handler) handleDownload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	info, err := h.DB.Song().GetStreamInfo(r.Context(), id, q.User())
+	info, err := h.getStreamInfo(r.Context(), id, q.User())
 	if err != nil {
 		respondErr(w, q.Format(), fmt.Errorf("download: get info: %w", err))
 		return
@@ -542,6 +806,24 @@ func (h *Handler) validateUserAccessToID(ctx context.Context, user, id string) (
 			return false, fmt.Errorf("check user access to internet radio station id: %w", err)
 		}
 		return true, nil
+	case crossonic.IDTypePodcastChannel:
+		_, err := h.DB.Podcast().FindChannelByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, repos.ErrNotFound) {
+				return false, nil
+			}
+			return false, fmt.Errorf("check user access to podcast channel id: %w", err)
+		}
+		return true, nil
+	case crossonic.IDTypePodcastEpisode:
+		_, err := h.DB.Podcast().FindEpisodeByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, repos.ErrNotFound) {
+				return false, nil
+			}
+			return false, fmt.Errorf("check user access to podcast episode id: %w", err)
+		}
+		return true, nil
 	}
 	return false, nil
 }
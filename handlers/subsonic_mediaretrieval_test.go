@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/juho05/crossonic-server/ffmpeg"
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/juho05/crossonic-server/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewNormalization(t *testing.T) {
+	full := &repos.SongStreamInfo{
+		ReplayGain:          util.ToPtr(-7.0),
+		ReplayGainPeak:      util.ToPtr(0.9),
+		AlbumReplayGain:     util.ToPtr(-8.0),
+		AlbumReplayGainPeak: util.ToPtr(0.95),
+	}
+	trackOnly := &repos.SongStreamInfo{
+		ReplayGain: util.ToPtr(-5.0),
+	}
+
+	tests := []struct {
+		name string
+		info *repos.SongStreamInfo
+		mode repos.NormalizeMode
+		want *ffmpeg.Normalization
+	}{
+		{"off", full, repos.NormalizeModeOff, nil},
+		{"track", full, repos.NormalizeModeTrack, &ffmpeg.Normalization{GainDB: -7, Peak: 0.9}},
+		{"album", full, repos.NormalizeModeAlbum, &ffmpeg.Normalization{GainDB: -8, Peak: 0.95}},
+		{"album falls back to track", trackOnly, repos.NormalizeModeAlbum, &ffmpeg.Normalization{GainDB: -5}},
+		{"fallback gain", &repos.SongStreamInfo{}, repos.NormalizeModeTrack, &ffmpeg.Normalization{GainDB: repos.FallbackGain()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, newNormalization(tt.info, tt.mode))
+		})
+	}
+}
+
+func TestTranscodeCacheKey(t *testing.T) {
+	assert.Equal(t, "s_1-opus-128", transcodeCacheKey("s_1", "opus", 128, repos.NormalizeModeOff, nil))
+	assert.Equal(t, "s_1-opus-128-track--7.00-0.90", transcodeCacheKey("s_1", "opus", 128, repos.NormalizeModeTrack, &ffmpeg.Normalization{GainDB: -7, Peak: 0.9}))
+	assert.NotEqual(t,
+		transcodeCacheKey("s_1", "opus", 128, repos.NormalizeModeAlbum, &ffmpeg.Normalization{GainDB: -8}),
+		transcodeCacheKey("s_1", "opus", 128, repos.NormalizeModeAlbum, &ffmpeg.Normalization{GainDB: -8.5}),
+		"transcodes with different gains should not share a cache key")
+}
+
+func TestParseNormalizeMode(t *testing.T) {
+	for _, v := range []string{"off", "track", "album"} {
+		mode, ok := parseNormalizeMode(v)
+		assert.True(t, ok, v)
+		assert.Equal(t, repos.NormalizeMode(v), mode)
+	}
+	_, ok := parseNormalizeMode("loud")
+	assert.False(t, ok)
+	_, ok = parseNormalizeMode("")
+	assert.False(t, ok)
+}
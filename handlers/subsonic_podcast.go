@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/juho05/crossonic-server"
+	"github.com/juho05/crossonic-server/handlers/responses"
+	"github.com/juho05/crossonic-server/podcast"
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/juho05/log"
+)
+
+// https://opensubsonic.netlify.app/docs/endpoints/getpodcasts/
+func (h *Handler) handleGetPodcasts(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	includeEpisodes, ok := q.BoolDef("includeEpisodes", true)
+	if !ok {
+		return
+	}
+
+	var channels []*repos.PodcastChannel
+	if q.Has("id") {
+		id, ok := q.IDTypeReq("id", []crossonic.IDType{crossonic.IDTypePodcastChannel})
+		if !ok {
+			return
+		}
+		channel, err := h.DB.Podcast().FindChannelByID(r.Context(), id)
+		if err != nil {
+			respondErr(w, q.Format(), fmt.Errorf("get podcasts: %w", err))
+			return
+		}
+		channels = []*repos.PodcastChannel{channel}
+	} else {
+		var err error
+		channels, err = h.DB.Podcast().FindAllChannels(r.Context())
+		if err != nil {
+			respondErr(w, q.Format(), fmt.Errorf("get podcasts: %w", err))
+			return
+		}
+	}
+
+	resChannels := make([]*responses.PodcastChannel, 0, len(channels))
+	for _, c := range channels {
+		var episodes []*repos.PodcastEpisode
+		if includeEpisodes {
+			var err error
+			episodes, err = h.DB.Podcast().FindEpisodesByChannel(r.Context(), c.ID)
+			if err != nil {
+				respondErr(w, q.Format(), fmt.Errorf("get podcasts: %w", err))
+				return
+			}
+		}
+		resChannels = append(resChannels, responses.NewPodcastChannel(c, episodes, h.Config))
+	}
+
+	res := responses.New()
+	res.Podcasts = &responses.Podcasts{
+		Channels: resChannels,
+	}
+	res.EncodeOrLog(w, q.Format())
+}
+
+// https://opensubsonic.netlify.app/docs/endpoints/getnewestpodcasts/
+func (h *Handler) handleGetNewestPodcasts(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	count, ok := q.IntPositiveDef("count", 20)
+	if !ok {
+		return
+	}
+
+	episodes, err := h.DB.Podcast().FindNewestEpisodes(r.Context(), count)
+	if err != nil {
+		respondErr(w, q.Format(), fmt.Errorf("get newest podcasts: %w", err))
+		return
+	}
+
+	res := responses.New()
+	res.NewestPodcasts = &responses.NewestPodcasts{
+		Episodes: responses.NewPodcastEpisodes(episodes, h.Config),
+	}
+	res.EncodeOrLog(w, q.Format())
+}
+
+// https://opensubsonic.netlify.app/docs/endpoints/refreshpodcasts/
+func (h *Handler) handleRefreshPodcasts(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	go func() {
+		err := h.Podcasts.RefreshAll(context.Background())
+		if err != nil && !errors.Is(err, podcast.ErrRefreshInProgress) {
+			log.Errorf("refresh podcasts: %s", err)
+		}
+	}()
+
+	responses.New().EncodeOrLog(w, q.Format())
+}
+
+// https://opensubsonic.netlify.app/docs/endpoints/createpodcastchannel/
+func (h *Handler) handleCreatePodcastChannel(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	url, ok := q.StrReq("url")
+	if !ok {
+		return
+	}
+
+	_, err := h.Podcasts.CreateChannel(r.Context(), url)
+	if err != nil {
+		if errors.Is(err, repos.ErrInvalidParams) {
+			respondGenericErr(w, q.Format(), "invalid podcast url")
+			return
+		}
+		if errors.Is(err, repos.ErrExists) {
+			respondGenericErr(w, q.Format(), "podcast channel already exists")
+			return
+		}
+		respondErr(w, q.Format(), fmt.Errorf("create podcast channel: %w", err))
+		return
+	}
+
+	responses.New().EncodeOrLog(w, q.Format())
+}
+
+// https://opensubsonic.netlify.app/docs/endpoints/deletepodcastchannel/
+func (h *Handler) handleDeletePodcastChannel(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	id, ok := q.IDTypeReq("id", []crossonic.IDType{crossonic.IDTypePodcastChannel})
+	if !ok {
+		return
+	}
+
+	err := h.Podcasts.DeleteChannel(r.Context(), id)
+	if err != nil {
+		respondErr(w, q.Format(), fmt.Errorf("delete podcast channel: %w", err))
+		return
+	}
+
+	responses.New().EncodeOrLog(w, q.Format())
+}
+
+// https://opensubsonic.netlify.app/docs/endpoints/deletepodcastepisode/
+func (h *Handler) handleDeletePodcastEpisode(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	id, ok := q.IDTypeReq("id", []crossonic.IDType{crossonic.IDTypePodcastEpisode})
+	if !ok {
+		return
+	}
+
+	err := h.Podcasts.DeleteEpisode(r.Context(), id)
+	if err != nil {
+		respondErr(w, q.Format(), fmt.Errorf("delete podcast episode: %w", err))
+		return
+	}
+
+	responses.New().EncodeOrLog(w, q.Format())
+}
+
+// https://opensubsonic.netlify.app/docs/endpoints/downloadpodcastepisode/
+func (h *Handler) handleDownloadPodcastEpisode(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	id, ok := q.IDTypeReq("id", []crossonic.IDType{crossonic.IDTypePodcastEpisode})
+	if !ok {
+		return
+	}
+
+	err := h.Podcasts.Download(r.Context(), id)
+	if err != nil {
+		respondErr(w, q.Format(), fmt.Errorf("download podcast episode: %w", err))
+		return
+	}
+
+	responses.New().EncodeOrLog(w, q.Format())
+}
+
+// getStreamInfo returns the stream info of a song or a downloaded podcast episode.
+func (h *Handler) getStreamInfo(ctx context.Context, id, user string) (*repos.SongStreamInfo, error) {
+	if t, ok := crossonic.GetIDType(id); ok && t == crossonic.IDTypePodcastEpisode {
+		return h.DB.Podcast().GetEpisodeStreamInfo(ctx, id)
+	}
+	return h.DB.Song().GetStreamInfo(ctx, id, user)
+}
@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/juho05/crossonic-server"
+	"github.com/juho05/crossonic-server/handlers/responses"
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/juho05/crossonic-server/util"
+)
+
+var shareableIDTypes = []crossonic.IDType{crossonic.IDTypeSong, crossonic.IDTypeAlbum, crossonic.IDTypePlaylist}
+
+// https://opensubsonic.netlify.app/docs/endpoints/getshares/
+func (h *Handler) handleGetShares(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	shares, err := h.DB.Share().FindAll(r.Context(), q.User())
+	if err != nil {
+		respondErr(w, q.Format(), fmt.Errorf("get shares: %w", err))
+		return
+	}
+
+	resShares := make([]*responses.Share, 0, len(shares))
+	for _, s := range shares {
+		share, err := h.newShareResponse(r.Context(), s)
+		if err != nil {
+			respondErr(w, q.Format(), fmt.Errorf("get shares: %w", err))
+			return
+		}
+		resShares = append(resShares, share)
+	}
+
+	res := responses.New()
+	res.Shares = &responses.Shares{
+		Shares: resShares,
+	}
+	res.EncodeOrLog(w, q.Format())
+}
+
+// https://opensubsonic.netlify.app/docs/endpoints/createshare/
+func (h *Handler) handleCreateShare(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	ids, ok := q.IDsTypeReq("id", shareableIDTypes)
+	if !ok {
+		return
+	}
+
+	expires, ok := q.TimeUnixMillis("expires")
+	if !ok {
+		return
+	}
+	if expires != nil && expires.Before(time.Now()) {
+		respondGenericErr(w, q.Format(), "expires must be in the future")
+		return
+	}
+
+	for _, id := range ids {
+		hasAccess, err := h.validateUserAccessToID(r.Context(), q.User(), id)
+		if err != nil {
+			respondErr(w, q.Format(), fmt.Errorf("create share: %w", err))
+			return
+		}
+		if !hasAccess {
+			respondNotFoundErr(w, q.Format(), fmt.Sprintf("%s not found", id))
+			return
+		}
+	}
+
+	share, err := h.DB.Share().Create(r.Context(), q.User(), repos.CreateShareParams{
+		Description: util.NilIfEmpty(q.Str("description")),
+		Expires:     expires,
+		EntryIDs:    ids,
+	})
+	if err != nil {
+		respondErr(w, q.Format(), fmt.Errorf("create share: %w", err))
+		return
+	}
+
+	resShare, err := h.newShareResponse(r.Context(), share)
+	if err != nil {
+		respondErr(w, q.Format(), fmt.Errorf("create share: %w", err))
+		return
+	}
+
+	res := responses.New()
+	res.Shares = &responses.Shares{
+		Shares: []*responses.Share{resShare},
+	}
+	res.EncodeOrLog(w, q.Format())
+}
+
+// https://opensubsonic.netlify.app/docs/endpoints/updateshare/
+func (h *Handler) handleUpdateShare(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	id, ok := q.IDTypeReq("id", []crossonic.IDType{crossonic.IDTypeShare})
+	if !ok {
+		return
+	}
+
+	var description repos.Optional[*string]
+	if q.Has("description") {
+		description = repos.NewOptionalFull(util.NilIfEmpty(q.Str("description")))
+	}
+
+	var expires repos.Optional[*time.Time]
+	if q.Has("expires") {
+		e, ok := q.TimeUnixMillis("expires")
+		if !ok {
+			return
+		}
+		// expires=0 removes the expiry date
+		if e != nil && e.UnixMilli() == 0 {
+			e = nil
+		}
+		expires = repos.NewOptionalFull(e)
+	}
+
+	err := h.DB.Share().Update(r.Context(), q.User(), id, repos.UpdateShareParams{
+		Description: description,
+		Expires:     expires,
+	})
+	if err != nil {
+		respondErr(w, q.Format(), fmt.Errorf("update share: %w", err))
+		return
+	}
+
+	responses.New().EncodeOrLog(w, q.Format())
+}
+
+// https://opensubsonic.netlify.app/docs/endpoints/deleteshare/
+func (h *Handler) handleDeleteShare(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	id, ok := q.IDTypeReq("id", []crossonic.IDType{crossonic.IDTypeShare})
+	if !ok {
+		return
+	}
+
+	err := h.DB.Share().Delete(r.Context(), q.User(), id)
+	if err != nil {
+		respondErr(w, q.Format(), fmt.Errorf("delete share: %w", err))
+		return
+	}
+
+	responses.New().EncodeOrLog(w, q.Format())
+}
+
+func (h *Handler) newShareResponse(ctx context.Context, share *repos.Share) (*responses.Share, error) {
+	songs, err := h.getShareSongs(ctx, share)
+	if err != nil {
+		return nil, fmt.Errorf("new share response: %w", err)
+	}
+	return &responses.Share{
+		ID:          share.ID,
+		URL:         h.Config.BaseURL + "/share/" + share.ID,
+		Description: share.Description,
+		Username:    share.User,
+		Created:     share.Created,
+		Expires:     share.Expires,
+		LastVisited: share.LastVisited,
+		VisitCount:  share.VisitCount,
+		Entry:       responses.NewSongs(songs, h.Config),
+	}, nil
+}
+
+// getShareSongs resolves the entries of the share to songs the owner of the share has access to.
+// Entries that no longer exist are skipped.
+func (h *Handler) getShareSongs(ctx context.Context, share *repos.Share) ([]*repos.CompleteSong, error) {
+	entries, err := h.DB.Share().GetEntries(ctx, share.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get share songs: get entries: %w", err)
+	}
+
+	include := repos.IncludeSongInfo{
+		Album: true,
+		Lists: true,
+	}
+
+	songs := make([]*repos.CompleteSong, 0, len(entries))
+	for _, id := range entries {
+		t, _ := crossonic.GetIDType(id)
+		switch t {
+		case crossonic.IDTypeSong:
+			song, err := h.DB.Song().FindByID(ctx, id, share.User, include)
+			if err != nil {
+				if errors.Is(err, repos.ErrNotFound) {
+					continue
+				}
+				return nil, fmt.Errorf("get share songs: find song: %w", err)
+			}
+			songs = append(songs, song)
+		case crossonic.IDTypeAlbum:
+			hasAccess, err := h.validateUserAccessToID(ctx, share.User, id)
+			if err != nil {
+				return nil, fmt.Errorf("get share songs: %w", err)
+			}
+			if !hasAccess {
+				continue
+			}
+			tracks, err := h.DB.Album().GetTracks(ctx, id, include)
+			if err != nil {
+				return nil, fmt.Errorf("get share songs: get album tracks: %w", err)
+			}
+			songs = append(songs, tracks...)
+		case crossonic.IDTypePlaylist:
+			hasAccess, err := h.validateUserAccessToID(ctx, share.User, id)
+			if err != nil {
+				return nil, fmt.Errorf("get share songs: %w", err)
+			}
+			if !hasAccess {
+				continue
+			}
+			tracks, err := h.DB.Playlist().GetTracks(ctx, id, include)
+			if err != nil {
+				return nil, fmt.Errorf("get share songs: get playlist tracks: %w", err)
+			}
+			songs = append(songs, tracks...)
+		}
+	}
+	return songs, nil
+}
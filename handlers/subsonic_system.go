@@ -30,6 +30,8 @@ func (h *Handler) handleGetOpenSubsonicExtensions(w http.ResponseWriter, r *http
 		responses.OpenSubsonicExtension{Name: "transcodeOffset", Versions: []int{1}},
 		responses.OpenSubsonicExtension{Name: "songLyrics", Versions: []int{1}},
 		responses.OpenSubsonicExtension{Name: "apiKeyAuthentication", Versions: []int{1}},
+		responses.OpenSubsonicExtension{Name: "indexBasedQueue", Versions: []int{1}},
+		responses.OpenSubsonicExtension{Name: "transcoding", Versions: []int{1}},
 	}
 	res.EncodeOrLog(w, q.Format())
 }
@@ -0,0 +1,352 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juho05/crossonic-server"
+	"github.com/juho05/crossonic-server/ffmpeg"
+	"github.com/juho05/crossonic-server/handlers/responses"
+	"github.com/juho05/crossonic-server/repos"
+)
+
+const maxClientInfoSize = 1 << 20
+
+// transcodeClientInfo describes the playback capabilities of a client.
+// Bitrates are in bits per second.
+type transcodeClientInfo struct {
+	Name                       string                       `json:"name"`
+	Platform                   string                       `json:"platform"`
+	MaxAudioBitrate            int                          `json:"maxAudioBitrate"`
+	MaxTranscodingAudioBitrate int                          `json:"maxTranscodingAudioBitrate"`
+	DirectPlayProfiles         []transcodeDirectPlayProfile `json:"directPlayProfiles"`
+	TranscodingProfiles        []transcodeTargetProfile     `json:"transcodingProfiles"`
+	CodecProfiles              []transcodeCodecProfile      `json:"codecProfiles"`
+}
+
+// transcodeDirectPlayProfile lists combinations the client can play without transcoding.
+// Empty lists match every value.
+type transcodeDirectPlayProfile struct {
+	Containers       []string `json:"containers"`
+	AudioCodecs      []string `json:"audioCodecs"`
+	Protocols        []string `json:"protocols"`
+	MaxAudioChannels int      `json:"maxAudioChannels"`
+}
+
+// transcodeTargetProfile is a format the client accepts as a transcoding target, in order of preference.
+type transcodeTargetProfile struct {
+	Container        string `json:"container"`
+	AudioCodec       string `json:"audioCodec"`
+	Protocol         string `json:"protocol"`
+	MaxAudioChannels int    `json:"maxAudioChannels"`
+}
+
+// transcodeCodecProfile restricts the properties of a codec the client can play.
+type transcodeCodecProfile struct {
+	Type        string                     `json:"type"`
+	Name        string                     `json:"name"`
+	Limitations []transcodeCodecLimitation `json:"limitations"`
+}
+
+type transcodeCodecLimitation struct {
+	Name       string   `json:"name"`
+	Comparison string   `json:"comparison"`
+	Values     []string `json:"values"`
+	Required   bool     `json:"required"`
+}
+
+// reasons for not playing the source directly
+const (
+	transcodeReasonContainerNotSupported       = "ContainerNotSupported"
+	transcodeReasonAudioCodecNotSupported      = "AudioCodecNotSupported"
+	transcodeReasonProtocolNotSupported        = "ProtocolNotSupported"
+	transcodeReasonAudioChannelsNotSupported   = "AudioChannelsNotSupported"
+	transcodeReasonAudioBitrateNotSupported    = "AudioBitrateNotSupported"
+	transcodeReasonAudioSamplerateNotSupported = "AudioSamplerateNotSupported"
+)
+
+// https://opensubsonic.netlify.app/docs/endpoints/gettranscodedecision/
+func (h *Handler) handleGetTranscodeDecision(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	id, ok := h.parseTranscodeMediaID(q)
+	if !ok {
+		return
+	}
+
+	if r.Method != http.MethodPost || r.Body == nil {
+		respondGenericErr(w, q.Format(), "client info must be posted as JSON")
+		return
+	}
+	var client transcodeClientInfo
+	err := json.NewDecoder(io.LimitReader(r.Body, maxClientInfoSize)).Decode(&client)
+	if err != nil {
+		respondGenericErr(w, q.Format(), fmt.Sprintf("invalid client info: %s", err))
+		return
+	}
+
+	info, err := h.getStreamInfo(r.Context(), id, q.User())
+	if err != nil {
+		respondErr(w, q.Format(), fmt.Errorf("get transcode decision: get stream info: %w", err))
+		return
+	}
+
+	res := responses.New()
+	res.TranscodeDecision = decideTranscode(h.Transcoder, info, client)
+	res.EncodeOrLog(w, q.Format())
+}
+
+// https://opensubsonic.netlify.app/docs/endpoints/gettranscodestream/
+func (h *Handler) handleGetTranscodeStream(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	id, ok := h.parseTranscodeMediaID(q)
+	if !ok {
+		return
+	}
+
+	offset, ok := q.IntPositiveDef("offset", 0)
+	if !ok {
+		return
+	}
+
+	paramStr, ok := q.StrReq("transcodeParams")
+	if !ok {
+		return
+	}
+	params, err := url.ParseQuery(paramStr)
+	if err != nil {
+		q.invalidParameter("transcodeParams")
+		return
+	}
+	format := params.Get("format")
+	if _, ok := h.Transcoder.LookupFormat(format); !ok && format != "raw" {
+		q.invalidParameter("transcodeParams")
+		return
+	}
+	maxBitRate, err := strconv.Atoi(params.Get("maxBitRate"))
+	if err != nil || maxBitRate < 0 {
+		q.invalidParameter("transcodeParams")
+		return
+	}
+
+	normalize, ok := h.normalizeMode(r.Context(), q)
+	if !ok {
+		return
+	}
+
+	h.stream(w, r, q, id, format, maxBitRate, time.Duration(offset)*time.Second, normalize)
+}
+
+// parseTranscodeMediaID returns the mediaId parameter and verifies that it matches the optional mediaType parameter.
+func (h *Handler) parseTranscodeMediaID(q UrlQuery) (string, bool) {
+	idTypes := []crossonic.IDType{crossonic.IDTypeSong, crossonic.IDTypePodcastEpisode}
+	switch q.Str("mediaType") {
+	case "":
+	case "song":
+		idTypes = []crossonic.IDType{crossonic.IDTypeSong}
+	case "podcast":
+		idTypes = []crossonic.IDType{crossonic.IDTypePodcastEpisode}
+	default:
+		q.invalidParameter("mediaType")
+		return "", false
+	}
+	return q.IDTypeReq("mediaId", idTypes)
+}
+
+// decideTranscode decides whether the client can play the source directly or which transcoding profile should be used.
+func decideTranscode(transcoder *ffmpeg.Transcoder, info *repos.SongStreamInfo, client transcodeClientInfo) *responses.TranscodeDecision {
+	source := &responses.StreamDetails{
+		Protocol:        "http",
+		Container:       sourceContainer(info),
+		Codec:           sourceCodec(info),
+		AudioChannels:   info.ChannelCount,
+		AudioBitrate:    info.BitRate * 1000,
+		AudioSamplerate: info.SamplingRate,
+	}
+	decision := &responses.TranscodeDecision{
+		SourceStream: source,
+	}
+
+	reasons := directPlayReasons(source, client)
+	if len(reasons) == 0 {
+		decision.CanDirectPlay = true
+		decision.TranscodeParams = url.Values{
+			"format":     {"raw"},
+			"maxBitRate": {"0"},
+		}.Encode()
+		return decision
+	}
+	decision.TranscodeReason = reasons
+
+	maxBitRate := min(nonZero(client.MaxTranscodingAudioBitrate), nonZero(client.MaxAudioBitrate), nonZero(source.AudioBitrate))
+	if maxBitRate == math.MaxInt {
+		// use the default bitrate of the format
+		maxBitRate = 0
+	}
+	for _, p := range client.TranscodingProfiles {
+		if p.Protocol != "" && !strings.EqualFold(p.Protocol, "http") {
+			continue
+		}
+		if p.MaxAudioChannels > 0 && p.MaxAudioChannels < info.ChannelCount {
+			continue
+		}
+		name, ok := transcoder.FindFormat(p.AudioCodec, p.Container)
+		if !ok {
+			continue
+		}
+		format, _ := transcoder.LookupFormat(name)
+		bitRateK := format.ClampBitRate(info.ChannelCount, maxBitRate/1000)
+		decision.CanTranscode = true
+		decision.TranscodeParams = url.Values{
+			"format":     {name},
+			"maxBitRate": {strconv.Itoa(bitRateK)},
+		}.Encode()
+		decision.TranscodeStream = &responses.StreamDetails{
+			Protocol:      "http",
+			Container:     strings.ToLower(p.Container),
+			Codec:         strings.ToLower(p.AudioCodec),
+			AudioChannels: info.ChannelCount,
+			AudioBitrate:  bitRateK * 1000,
+		}
+		return decision
+	}
+	decision.ErrorReason = "no supported transcoding profile"
+	return decision
+}
+
+// directPlayReasons returns the reasons why the client cannot play source directly or nil if it can.
+func directPlayReasons(source *responses.StreamDetails, client transcodeClientInfo) []string {
+	if client.MaxAudioBitrate > 0 && source.AudioBitrate > client.MaxAudioBitrate {
+		return []string{transcodeReasonAudioBitrateNotSupported}
+	}
+	for _, p := range client.CodecProfiles {
+		if !strings.EqualFold(p.Name, source.Codec) {
+			continue
+		}
+		for _, l := range p.Limitations {
+			if reason, ok := checkCodecLimitation(source, l); !ok {
+				return []string{reason}
+			}
+		}
+	}
+
+	var reasons []string
+	for _, p := range client.DirectPlayProfiles {
+		var profileReasons []string
+		if !containsFoldOrEmpty(p.Containers, source.Container) {
+			profileReasons = append(profileReasons, transcodeReasonContainerNotSupported)
+		}
+		if !containsFoldOrEmpty(p.AudioCodecs, source.Codec) {
+			profileReasons = append(profileReasons, transcodeReasonAudioCodecNotSupported)
+		}
+		if !containsFoldOrEmpty(p.Protocols, source.Protocol) {
+			profileReasons = append(profileReasons, transcodeReasonProtocolNotSupported)
+		}
+		if p.MaxAudioChannels > 0 && source.AudioChannels > p.MaxAudioChannels {
+			profileReasons = append(profileReasons, transcodeReasonAudioChannelsNotSupported)
+		}
+		if len(profileReasons) == 0 {
+			return nil
+		}
+		for _, r := range profileReasons {
+			if !slices.Contains(reasons, r) {
+				reasons = append(reasons, r)
+			}
+		}
+	}
+	if len(client.DirectPlayProfiles) == 0 {
+		reasons = []string{transcodeReasonContainerNotSupported}
+	}
+	return reasons
+}
+
+// checkCodecLimitation reports whether source satisfies the limitation. Limitations of properties
+// that are unknown for the source are only violated if they are required.
+func checkCodecLimitation(source *responses.StreamDetails, l transcodeCodecLimitation) (string, bool) {
+	var value int
+	var reason string
+	switch l.Name {
+	case "audioChannels":
+		value, reason = source.AudioChannels, transcodeReasonAudioChannelsNotSupported
+	case "audioBitrate":
+		value, reason = source.AudioBitrate, transcodeReasonAudioBitrateNotSupported
+	case "audioSamplerate":
+		value, reason = source.AudioSamplerate, transcodeReasonAudioSamplerateNotSupported
+	default:
+		return transcodeReasonAudioCodecNotSupported, !l.Required
+	}
+	if value == 0 {
+		return reason, !l.Required
+	}
+	for _, v := range l.Values {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			continue
+		}
+		var ok bool
+		switch l.Comparison {
+		case "Equals":
+			ok = value == limit
+		case "NotEquals":
+			ok = value != limit
+		case "LessThanEqual":
+			ok = value <= limit
+		case "GreaterThanEqual":
+			ok = value >= limit
+		default:
+			ok = true
+		}
+		if l.Comparison == "NotEquals" && !ok {
+			return reason, false
+		}
+		if l.Comparison != "NotEquals" && ok {
+			return reason, true
+		}
+	}
+	return reason, l.Comparison == "NotEquals" || len(l.Values) == 0
+}
+
+func sourceContainer(info *repos.SongStreamInfo) string {
+	return strings.ToLower(strings.TrimPrefix(filepath.Ext(info.Path), "."))
+}
+
+// sourceCodec guesses the audio codec from the file extension.
+func sourceCodec(info *repos.SongStreamInfo) string {
+	switch ext := sourceContainer(info); ext {
+	case "ogg", "oga":
+		return "vorbis"
+	case "m4a", "mp4", "m4b":
+		return "aac"
+	case "wav":
+		return "pcm"
+	default:
+		return ext
+	}
+}
+
+func containsFoldOrEmpty(list []string, value string) bool {
+	if len(list) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(list, func(s string) bool {
+		return strings.EqualFold(s, value)
+	})
+}
+
+// nonZero maps 0 to the largest int so that it does not limit min.
+func nonZero(v int) int {
+	if v <= 0 {
+		return math.MaxInt
+	}
+	return v
+}
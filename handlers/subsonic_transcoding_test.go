@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/juho05/crossonic-server/ffmpeg"
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecideTranscode(t *testing.T) {
+	transcoder := &ffmpeg.Transcoder{} // constructor isn't used because decisions do not need ffmpeg
+
+	flac := &repos.SongStreamInfo{
+		Path:         "/music/song.flac",
+		BitRate:      900,
+		ContentType:  "audio/flac",
+		ChannelCount: 2,
+		SamplingRate: 96000,
+	}
+
+	mp3Only := transcodeClientInfo{
+		DirectPlayProfiles: []transcodeDirectPlayProfile{
+			{Containers: []string{"mp3"}, AudioCodecs: []string{"mp3"}, Protocols: []string{"http"}},
+		},
+		TranscodingProfiles: []transcodeTargetProfile{
+			{Container: "ogg", AudioCodec: "opus", Protocol: "http"},
+			{Container: "mp3", AudioCodec: "mp3", Protocol: "http"},
+		},
+	}
+
+	t.Run("direct play", func(t *testing.T) {
+		decision := decideTranscode(transcoder, flac, transcodeClientInfo{
+			DirectPlayProfiles: []transcodeDirectPlayProfile{
+				{Containers: []string{"flac"}, AudioCodecs: []string{"flac"}},
+			},
+		})
+		assert.True(t, decision.CanDirectPlay)
+		assert.False(t, decision.CanTranscode)
+		assert.Equal(t, "format=raw&maxBitRate=0", decision.TranscodeParams)
+		assert.Equal(t, "flac", decision.SourceStream.Codec)
+		assert.Equal(t, 900000, decision.SourceStream.AudioBitrate)
+		assert.Nil(t, decision.TranscodeStream)
+	})
+
+	t.Run("transcode to first supported profile", func(t *testing.T) {
+		decision := decideTranscode(transcoder, flac, mp3Only)
+		assert.False(t, decision.CanDirectPlay)
+		assert.True(t, decision.CanTranscode)
+		assert.ElementsMatch(t, []string{transcodeReasonContainerNotSupported, transcodeReasonAudioCodecNotSupported}, decision.TranscodeReason)
+		assert.Equal(t, "format=opus&maxBitRate=512", decision.TranscodeParams)
+		assert.Equal(t, "opus", decision.TranscodeStream.Codec)
+		assert.Equal(t, 512000, decision.TranscodeStream.AudioBitrate)
+	})
+
+	t.Run("bitrate limit", func(t *testing.T) {
+		client := mp3Only
+		client.DirectPlayProfiles = []transcodeDirectPlayProfile{{}}
+		client.MaxAudioBitrate = 320000
+		client.MaxTranscodingAudioBitrate = 128000
+		decision := decideTranscode(transcoder, flac, client)
+		assert.False(t, decision.CanDirectPlay)
+		assert.Equal(t, []string{transcodeReasonAudioBitrateNotSupported}, decision.TranscodeReason)
+		assert.Equal(t, "format=opus&maxBitRate=128", decision.TranscodeParams)
+	})
+
+	t.Run("codec limitation", func(t *testing.T) {
+		client := transcodeClientInfo{
+			DirectPlayProfiles: []transcodeDirectPlayProfile{{Containers: []string{"flac"}}},
+			CodecProfiles: []transcodeCodecProfile{
+				{Type: "AudioCodec", Name: "flac", Limitations: []transcodeCodecLimitation{
+					{Name: "audioSamplerate", Comparison: "LessThanEqual", Values: []string{"48000"}, Required: true},
+				}},
+			},
+			TranscodingProfiles: mp3Only.TranscodingProfiles[1:],
+		}
+		decision := decideTranscode(transcoder, flac, client)
+		assert.False(t, decision.CanDirectPlay)
+		assert.Equal(t, []string{transcodeReasonAudioSamplerateNotSupported}, decision.TranscodeReason)
+		assert.Equal(t, "format=mp3&maxBitRate=320", decision.TranscodeParams)
+	})
+
+	t.Run("lossless profile", func(t *testing.T) {
+		decision := decideTranscode(transcoder, flac, transcodeClientInfo{
+			TranscodingProfiles: []transcodeTargetProfile{{Container: "mp4", AudioCodec: "alac"}},
+		})
+		assert.True(t, decision.CanTranscode)
+		assert.Equal(t, "format=alac&maxBitRate=0", decision.TranscodeParams)
+	})
+
+	t.Run("no supported profile", func(t *testing.T) {
+		decision := decideTranscode(transcoder, flac, transcodeClientInfo{
+			TranscodingProfiles: []transcodeTargetProfile{{Container: "asf", AudioCodec: "wma"}},
+		})
+		assert.False(t, decision.CanDirectPlay)
+		assert.False(t, decision.CanTranscode)
+		assert.NotEmpty(t, decision.ErrorReason)
+		assert.Empty(t, decision.TranscodeParams)
+	})
+}
@@ -0,0 +1,339 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/juho05/crossonic-server/config"
+	"github.com/juho05/crossonic-server/handlers/responses"
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/juho05/crossonic-server/scanner"
+)
+
+// https://opensubsonic.netlify.app/docs/endpoints/getuser/
+func (h *Handler) handleGetUser(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	username, ok := q.StrReq("username")
+	if !ok {
+		return
+	}
+
+	if username != q.User() && !h.checkPermission(w, r, q, permissionAdmin) {
+		return
+	}
+
+	user, err := h.DB.User().FindByName(r.Context(), username)
+	if err != nil {
+		respondErr(w, q.Format(), fmt.Errorf("get user: %w", err))
+		return
+	}
+
+	resUser, err := h.newUserResponse(r.Context(), user)
+	if err != nil {
+		respondErr(w, q.Format(), fmt.Errorf("get user: %w", err))
+		return
+	}
+
+	res := responses.New()
+	res.User = resUser
+	res.EncodeOrLog(w, q.Format())
+}
+
+// https://opensubsonic.netlify.app/docs/endpoints/getusers/
+func (h *Handler) handleGetUsers(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	users, err := h.DB.User().FindAll(r.Context())
+	if err != nil {
+		respondErr(w, q.Format(), fmt.Errorf("get users: %w", err))
+		return
+	}
+
+	resUsers := make([]*responses.User, 0, len(users))
+	for _, u := range users {
+		resUser, err := h.newUserResponse(r.Context(), u)
+		if err != nil {
+			respondErr(w, q.Format(), fmt.Errorf("get users: %w", err))
+			return
+		}
+		resUsers = append(resUsers, resUser)
+	}
+
+	res := responses.New()
+	res.Users = &responses.Users{
+		Users: resUsers,
+	}
+	res.EncodeOrLog(w, q.Format())
+}
+
+// https://opensubsonic.netlify.app/docs/endpoints/createuser/
+func (h *Handler) handleCreateUser(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	username, ok := q.StrReq("username")
+	if !ok {
+		return
+	}
+
+	password, ok := q.StrReq("password")
+	if !ok {
+		return
+	}
+	password, err := decodePassword(password)
+	if err != nil {
+		q.invalidParameter("password")
+		return
+	}
+
+	// defaults as specified by the Subsonic API
+	params := repos.UpdateUserParams{
+		Admin:        repos.NewOptionalFull(false),
+		SettingsRole: repos.NewOptionalFull(true),
+		DownloadRole: repos.NewOptionalFull(false),
+		PlaylistRole: repos.NewOptionalFull(false),
+		ShareRole:    repos.NewOptionalFull(false),
+		JukeboxRole:  repos.NewOptionalFull(false),
+	}
+	if !parseUserRoleParams(q, &params) {
+		return
+	}
+
+	musicFolderIDs, ok := h.parseMusicFolderIDsParam(q)
+	if !ok {
+		return
+	}
+
+	err = h.DB.Transaction(r.Context(), func(tx repos.Tx) error {
+		err := tx.User().Create(r.Context(), username, password)
+		if err != nil {
+			return fmt.Errorf("create user: %w", err)
+		}
+		if musicFolderIDs != nil {
+			err = setUserMusicFolders(r.Context(), tx, username, musicFolderIDs)
+		} else {
+			err = scanner.CreateMusicFolderAssociationsForUser(r.Context(), tx, username, h.Config)
+		}
+		if err != nil {
+			return fmt.Errorf("create music folder associations: %w", err)
+		}
+		return tx.User().Update(r.Context(), username, params)
+	})
+	if err != nil {
+		if errors.Is(err, repos.ErrExists) {
+			respondGenericErr(w, q.Format(), "user already exists")
+			return
+		}
+		respondErr(w, q.Format(), fmt.Errorf("create user: %w", err))
+		return
+	}
+
+	responses.New().EncodeOrLog(w, q.Format())
+}
+
+// https://opensubsonic.netlify.app/docs/endpoints/updateuser/
+func (h *Handler) handleUpdateUser(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	username, ok := q.StrReq("username")
+	if !ok {
+		return
+	}
+
+	var params repos.UpdateUserParams
+	if q.Has("password") {
+		password, err := decodePassword(q.Str("password"))
+		if err != nil || password == "" {
+			q.invalidParameter("password")
+			return
+		}
+		params.Password = repos.NewOptionalFull(password)
+	}
+
+	if !parseUserRoleParams(q, &params) {
+		return
+	}
+
+	if username == q.User() && params.Admin.HasValue() && !params.Admin.Get().(bool) {
+		respondGenericErr(w, q.Format(), "cannot remove admin role from yourself")
+		return
+	}
+
+	musicFolderIDs, ok := h.parseMusicFolderIDsParam(q)
+	if !ok {
+		return
+	}
+
+	err := h.DB.Transaction(r.Context(), func(tx repos.Tx) error {
+		_, err := tx.User().FindByName(r.Context(), username)
+		if err != nil {
+			return fmt.Errorf("find user: %w", err)
+		}
+		if musicFolderIDs != nil {
+			err = setUserMusicFolders(r.Context(), tx, username, musicFolderIDs)
+			if err != nil {
+				return fmt.Errorf("set music folders: %w", err)
+			}
+		}
+		return tx.User().Update(r.Context(), username, params)
+	})
+	if err != nil {
+		respondErr(w, q.Format(), fmt.Errorf("update user: %w", err))
+		return
+	}
+
+	responses.New().EncodeOrLog(w, q.Format())
+}
+
+// https://opensubsonic.netlify.app/docs/endpoints/deleteuser/
+func (h *Handler) handleDeleteUser(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	username, ok := q.StrReq("username")
+	if !ok {
+		return
+	}
+
+	if username == q.User() {
+		respondGenericErr(w, q.Format(), "cannot delete yourself")
+		return
+	}
+
+	err := h.DB.User().DeleteByName(r.Context(), username)
+	if err != nil {
+		respondErr(w, q.Format(), fmt.Errorf("delete user: %w", err))
+		return
+	}
+
+	responses.New().EncodeOrLog(w, q.Format())
+}
+
+// https://opensubsonic.netlify.app/docs/endpoints/changepassword/
+func (h *Handler) handleChangePassword(w http.ResponseWriter, r *http.Request) {
+	q := getQuery(w, r)
+
+	username, ok := q.StrReq("username")
+	if !ok {
+		return
+	}
+
+	if username != q.User() && !h.checkPermission(w, r, q, permissionAdmin) {
+		return
+	}
+
+	password, ok := q.StrReq("password")
+	if !ok {
+		return
+	}
+	password, err := decodePassword(password)
+	if err != nil || password == "" {
+		q.invalidParameter("password")
+		return
+	}
+
+	err = h.DB.User().Update(r.Context(), username, repos.UpdateUserParams{
+		Password: repos.NewOptionalFull(password),
+	})
+	if err != nil {
+		respondErr(w, q.Format(), fmt.Errorf("change password: %w", err))
+		return
+	}
+
+	responses.New().EncodeOrLog(w, q.Format())
+}
+
+func (h *Handler) newUserResponse(ctx context.Context, user *repos.User) (*responses.User, error) {
+	folders, err := h.DB.MusicFolder().GetUserMusicFolderIDs(ctx, user.Name, nil)
+	if err != nil {
+		return nil, fmt.Errorf("new user response: get music folder ids: %w", err)
+	}
+	if folders == nil {
+		folders = []int{}
+	}
+	return &responses.User{
+		Username:          user.Name,
+		ScrobblingEnabled: user.ScrobbleRole,
+		AdminRole:         user.Admin,
+		SettingsRole:      user.SettingsRole,
+		DownloadRole:      user.DownloadRole,
+		PlaylistRole:      user.PlaylistRole,
+		StreamRole:        true,
+		ShareRole:         user.ShareRole,
+		JukeboxRole:       user.JukeboxRole,
+		Folder:            folders,
+	}, nil
+}
+
+// parseUserRoleParams sets the roles in params that are present in the query.
+func parseUserRoleParams(q UrlQuery, params *repos.UpdateUserParams) bool {
+	roles := []struct {
+		name   string
+		target *repos.Optional[bool]
+	}{
+		{"adminRole", &params.Admin},
+		{"settingsRole", &params.SettingsRole},
+		{"downloadRole", &params.DownloadRole},
+		{"playlistRole", &params.PlaylistRole},
+		{"shareRole", &params.ShareRole},
+		{"scrobblingEnabled", &params.ScrobbleRole},
+		{"jukeboxRole", &params.JukeboxRole},
+	}
+	for _, role := range roles {
+		if !q.Has(role.name) {
+			continue
+		}
+		value, ok := q.Bool(role.name)
+		if !ok {
+			return false
+		}
+		*role.target = repos.NewOptionalFull(value)
+	}
+	return true
+}
+
+// parseMusicFolderIDsParam returns the musicFolderId values of the query or nil if none were provided.
+func (h *Handler) parseMusicFolderIDsParam(q UrlQuery) ([]int, bool) {
+	if !q.Has("musicFolderId") {
+		return nil, true
+	}
+	ids, ok := q.Ints("musicFolderId")
+	if !ok {
+		return nil, false
+	}
+	musicDirs, err := h.Config.GetMusicDirs()
+	if err != nil {
+		respondInternalErr(q.responseWriter, q.Format(), fmt.Errorf("parse music folder ids: %w", err))
+		return nil, false
+	}
+	for _, id := range ids {
+		if !slices.ContainsFunc(musicDirs, func(dir config.MusicDir) bool {
+			return dir.ID == id
+		}) {
+			respondNotFoundErr(q.responseWriter, q.Format(), fmt.Sprintf("music folder %d not found", id))
+			return nil, false
+		}
+	}
+	slices.Sort(ids)
+	return slices.Compact(ids), true
+}
+
+// setUserMusicFolders replaces the music folder associations of the user and prevents them from
+// being reset to the music dir config.
+func setUserMusicFolders(ctx context.Context, tx repos.Tx, user string, musicFolderIDs []int) error {
+	err := tx.MusicFolder().DeleteUserAssociationsOfUser(ctx, user)
+	if err != nil {
+		return fmt.Errorf("delete user associations: %w", err)
+	}
+	for _, id := range musicFolderIDs {
+		err = tx.MusicFolder().CreateUserAssociations(ctx, id, []string{user})
+		if err != nil {
+			return fmt.Errorf("create user association for music folder %d: %w", id, err)
+		}
+	}
+	return tx.User().Update(ctx, user, repos.UpdateUserParams{
+		CustomMusicFolders: repos.NewOptionalFull(true),
+	})
+}
@@ -0,0 +1,269 @@
+package jukebox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/juho05/crossonic-server/ffmpeg"
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/juho05/log"
+)
+
+const defaultGain = 0.75
+
+var ErrIndexOutOfRange = errors.New("index out of range")
+
+// Status describes the playback state of the jukebox.
+type Status struct {
+	// CurrentIndex is the playlist index of the current song or -1 if the playlist is empty.
+	CurrentIndex int
+	Playing      bool
+	Gain         float64
+	Position     time.Duration
+}
+
+// Jukebox plays a server-side playlist through an audio output of the host.
+type Jukebox struct {
+	db     repos.DB
+	player *ffmpeg.Player
+	output Output
+
+	lock     sync.Mutex
+	playlist []string
+	index    int
+	gain     float64
+	// position of the current song at playbackStart or the paused position if playback is nil
+	position      time.Duration
+	playbackStart time.Time
+	playback      *ffmpeg.Playback
+}
+
+func New(db repos.DB, player *ffmpeg.Player, output Output) *Jukebox {
+	return &Jukebox{
+		db:       db,
+		player:   player,
+		output:   output,
+		playlist: []string{},
+		gain:     defaultGain,
+	}
+}
+
+// Status returns the current playback state.
+func (j *Jukebox) Status() Status {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	return j.status()
+}
+
+// Playlist returns the current playback state and the song ids of the playlist.
+func (j *Jukebox) Playlist() (Status, []string) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	return j.status(), slices.Clone(j.playlist)
+}
+
+// Start resumes playback at the current position.
+func (j *Jukebox) Start(ctx context.Context) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if j.playback != nil {
+		return nil
+	}
+	return j.play(ctx)
+}
+
+// Stop pauses playback and keeps the current position.
+func (j *Jukebox) Stop() {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	j.stop()
+}
+
+// Skip continues at offset of the song at index.
+// Returns ErrIndexOutOfRange if index is not part of the playlist.
+func (j *Jukebox) Skip(ctx context.Context, index int, offset time.Duration) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if index < 0 || index >= len(j.playlist) {
+		return ErrIndexOutOfRange
+	}
+	return j.seek(ctx, index, offset)
+}
+
+// Set replaces the playlist with songIDs and continues at the first song if the jukebox is playing.
+func (j *Jukebox) Set(ctx context.Context, songIDs []string) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	j.playlist = slices.Clone(songIDs)
+	return j.seek(ctx, 0, 0)
+}
+
+// Add appends songIDs to the playlist.
+func (j *Jukebox) Add(songIDs []string) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	j.playlist = append(j.playlist, songIDs...)
+}
+
+// Clear stops playback and removes all songs from the playlist.
+func (j *Jukebox) Clear() {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	j.stop()
+	j.playlist = []string{}
+	j.index = 0
+	j.position = 0
+}
+
+// Remove removes the song at index from the playlist. If it is the current song, the jukebox continues with the next one.
+// Returns ErrIndexOutOfRange if index is not part of the playlist.
+func (j *Jukebox) Remove(ctx context.Context, index int) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if index < 0 || index >= len(j.playlist) {
+		return ErrIndexOutOfRange
+	}
+	j.playlist = slices.Delete(j.playlist, index, index+1)
+	if index < j.index {
+		j.index--
+		return nil
+	}
+	if index == j.index {
+		return j.seek(ctx, j.index, 0)
+	}
+	return nil
+}
+
+// Shuffle randomizes the order of the playlist. The current song is moved to the front.
+func (j *Jukebox) Shuffle() {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if len(j.playlist) == 0 {
+		return
+	}
+	current := j.playlist[j.index]
+	rest := slices.Delete(slices.Clone(j.playlist), j.index, j.index+1)
+	rand.Shuffle(len(rest), func(a, b int) {
+		rest[a], rest[b] = rest[b], rest[a]
+	})
+	j.playlist = append([]string{current}, rest...)
+	j.index = 0
+}
+
+// SetGain sets the volume to gain, which must be in the range [0,1].
+func (j *Jukebox) SetGain(ctx context.Context, gain float64) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	j.gain = max(0, min(1, gain))
+	if j.playback == nil {
+		return nil
+	}
+	// ffmpeg cannot change the volume of a running process, so playback is restarted at the current position
+	return j.seek(ctx, j.index, j.currentPosition())
+}
+
+// Close stops playback.
+func (j *Jukebox) Close() {
+	j.Stop()
+}
+
+func (j *Jukebox) status() Status {
+	index := j.index
+	if len(j.playlist) == 0 {
+		index = -1
+	}
+	return Status{
+		CurrentIndex: index,
+		Playing:      j.playback != nil,
+		Gain:         j.gain,
+		Position:     j.currentPosition(),
+	}
+}
+
+func (j *Jukebox) currentPosition() time.Duration {
+	if j.playback == nil {
+		return j.position
+	}
+	return j.position + time.Since(j.playbackStart)
+}
+
+// seek moves to offset of the song at index and keeps playing if the jukebox was playing before.
+// An index past the end of the playlist stops playback and resets the jukebox to the first song.
+func (j *Jukebox) seek(ctx context.Context, index int, offset time.Duration) error {
+	playing := j.playback != nil
+	j.stop()
+	if index >= len(j.playlist) {
+		j.index = 0
+		j.position = 0
+		return nil
+	}
+	j.index = index
+	j.position = offset
+	if !playing {
+		return nil
+	}
+	return j.play(ctx)
+}
+
+// play starts the ffmpeg process for the current song. Songs that no longer exist are skipped.
+func (j *Jukebox) play(ctx context.Context) error {
+	for j.index < len(j.playlist) {
+		songs, err := j.db.Song().FindByIDs(ctx, []string{j.playlist[j.index]}, repos.IncludeSongInfoBare())
+		if err != nil {
+			return fmt.Errorf("jukebox: play: find song: %w", err)
+		}
+		if len(songs) == 0 {
+			log.Warnf("jukebox: song %s no longer exists, skipping...", j.playlist[j.index])
+			j.index++
+			j.position = 0
+			continue
+		}
+		playback, err := j.player.Play(songs[0].Path, j.position, j.gain, j.output.FFmpegArgs())
+		if err != nil {
+			return fmt.Errorf("jukebox: play: %w", err)
+		}
+		j.playback = playback
+		j.playbackStart = time.Now()
+		go j.advanceWhenDone(playback)
+		return nil
+	}
+	j.index = 0
+	j.position = 0
+	return nil
+}
+
+func (j *Jukebox) stop() {
+	if j.playback == nil {
+		return
+	}
+	j.position = j.currentPosition()
+	playback := j.playback
+	j.playback = nil
+	playback.Stop()
+}
+
+// advanceWhenDone continues with the next song once playback finished.
+func (j *Jukebox) advanceWhenDone(playback *ffmpeg.Playback) {
+	<-playback.Done()
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if j.playback != playback {
+		// stopped or replaced
+		return
+	}
+	j.playback = nil
+	if err := playback.Err(); err != nil {
+		log.Errorf("jukebox: %s", err)
+	}
+	j.index++
+	j.position = 0
+	err := j.play(context.Background())
+	if err != nil {
+		log.Errorf("jukebox: %s", err)
+	}
+}
@@ -0,0 +1,87 @@
+package jukebox
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJukeboxPlaylist(t *testing.T) {
+	ctx := context.Background()
+
+	j := New(nil, nil, NullOutput{})
+	status, playlist := j.Playlist()
+	assert.Equal(t, -1, status.CurrentIndex)
+	assert.False(t, status.Playing)
+	assert.Equal(t, defaultGain, status.Gain)
+	assert.Empty(t, playlist)
+
+	err := j.Set(ctx, []string{"a", "b", "c"})
+	require.NoError(t, err)
+	j.Add([]string{"d", "e"})
+	_, playlist = j.Playlist()
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, playlist)
+	assert.Equal(t, 0, j.Status().CurrentIndex)
+
+	t.Run("skip", func(t *testing.T) {
+		err := j.Skip(ctx, 2, 30*time.Second)
+		require.NoError(t, err)
+		status := j.Status()
+		assert.Equal(t, 2, status.CurrentIndex)
+		assert.Equal(t, 30*time.Second, status.Position)
+
+		assert.ErrorIs(t, j.Skip(ctx, 5, 0), ErrIndexOutOfRange)
+		assert.ErrorIs(t, j.Skip(ctx, -1, 0), ErrIndexOutOfRange)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		err := j.Remove(ctx, 0)
+		require.NoError(t, err)
+		_, playlist := j.Playlist()
+		assert.Equal(t, []string{"b", "c", "d", "e"}, playlist)
+		assert.Equal(t, 1, j.Status().CurrentIndex, "current song should stay the same")
+
+		err = j.Remove(ctx, 1)
+		require.NoError(t, err)
+		status, playlist := j.Playlist()
+		assert.Equal(t, []string{"b", "d", "e"}, playlist)
+		assert.Equal(t, 1, status.CurrentIndex, "removing the current song should continue with the next one")
+		assert.Equal(t, time.Duration(0), status.Position)
+
+		assert.ErrorIs(t, j.Remove(ctx, 3), ErrIndexOutOfRange)
+	})
+
+	t.Run("shuffle", func(t *testing.T) {
+		j.Shuffle()
+		status, playlist := j.Playlist()
+		assert.Equal(t, 0, status.CurrentIndex)
+		assert.Equal(t, "d", playlist[0], "current song should be moved to the front")
+		assert.ElementsMatch(t, []string{"b", "d", "e"}, playlist)
+	})
+
+	t.Run("gain", func(t *testing.T) {
+		require.NoError(t, j.SetGain(ctx, 0.3))
+		assert.Equal(t, 0.3, j.Status().Gain)
+		require.NoError(t, j.SetGain(ctx, 2))
+		assert.Equal(t, 1.0, j.Status().Gain)
+	})
+
+	t.Run("clear", func(t *testing.T) {
+		j.Clear()
+		status, playlist := j.Playlist()
+		assert.Empty(t, playlist)
+		assert.Equal(t, -1, status.CurrentIndex)
+		assert.False(t, status.Playing)
+	})
+}
+
+func TestOutputFFmpegArgs(t *testing.T) {
+	assert.Equal(t, []string{"-f", "alsa", "hw:1,0"}, ALSAOutput{Device: "hw:1,0"}.FFmpegArgs())
+	assert.Equal(t, []string{"-f", "pulse", "Crossonic Jukebox"}, PulseOutput{}.FFmpegArgs())
+	assert.Equal(t, []string{"-f", "pulse", "-device", "speaker", "Crossonic Jukebox"}, PulseOutput{Device: "speaker"}.FFmpegArgs())
+	assert.Equal(t, []string{"-y", "/tmp/out.wav"}, FileOutput{Path: "/tmp/out.wav"}.FFmpegArgs())
+	assert.Equal(t, []string{"-f", "null", "-"}, NullOutput{}.FFmpegArgs())
+}
@@ -0,0 +1,68 @@
+package jukebox
+
+import (
+	"fmt"
+
+	"github.com/juho05/crossonic-server/config"
+)
+
+// Output is an audio backend the jukebox plays through.
+type Output interface {
+	// FFmpegArgs returns the ffmpeg output options that write to the backend.
+	FFmpegArgs() []string
+}
+
+// ALSAOutput plays to an ALSA device, e.g. "default" or "hw:1,0".
+type ALSAOutput struct {
+	Device string
+}
+
+func (o ALSAOutput) FFmpegArgs() []string {
+	return []string{"-f", "alsa", o.Device}
+}
+
+// PulseOutput plays to a PulseAudio sink. An empty Device selects the default sink.
+type PulseOutput struct {
+	Device string
+}
+
+func (o PulseOutput) FFmpegArgs() []string {
+	args := []string{"-f", "pulse"}
+	if o.Device != "" {
+		args = append(args, "-device", o.Device)
+	}
+	return append(args, "Crossonic Jukebox")
+}
+
+// FileOutput writes the audio of the current song to Path.
+// The container is derived from the file extension and the file is overwritten for every song.
+type FileOutput struct {
+	Path string
+}
+
+func (o FileOutput) FFmpegArgs() []string {
+	return []string{"-y", o.Path}
+}
+
+// NullOutput decodes the audio in real time and discards it.
+type NullOutput struct{}
+
+func (o NullOutput) FFmpegArgs() []string {
+	return []string{"-f", "null", "-"}
+}
+
+// NewOutput returns the output configured in conf.
+func NewOutput(conf config.Config) (Output, error) {
+	switch conf.JukeboxOutput {
+	case config.JukeboxOutputALSA:
+		return ALSAOutput{Device: conf.JukeboxDevice}, nil
+	case config.JukeboxOutputPulse:
+		return PulseOutput{Device: conf.JukeboxDevice}, nil
+	case config.JukeboxOutputFile:
+		return FileOutput{Path: conf.JukeboxDevice}, nil
+	case config.JukeboxOutputNull:
+		return NullOutput{}, nil
+	default:
+		return nil, fmt.Errorf("new output: unsupported jukebox output: %q", conf.JukeboxOutput)
+	}
+}
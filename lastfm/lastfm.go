@@ -114,6 +114,108 @@ func (l *LastFm) GetAlbumInfo(ctx context.Context, name, artistName string, mbid
 	return res, nil
 }
 
+type SimilarArtist struct {
+	Name string
+	MBID *string
+	// Match is the similarity to the requested artist in the range [0,1].
+	Match float64
+}
+
+// GetSimilarArtists returns up to limit artists similar to the artist, most similar first.
+func (l *LastFm) GetSimilarArtists(ctx context.Context, name string, mbid *string, limit int) ([]SimilarArtist, error) {
+	type response struct {
+		Artists []struct {
+			Name  string `json:"name"`
+			MBID  string `json:"mbid"`
+			Match string `json:"match"`
+		} `json:"artist"`
+	}
+
+	params := artistParams(name, mbid)
+	params["limit"] = []string{strconv.Itoa(limit)}
+
+	log.Tracef("fetching similar artists for %s from last.fm...", name)
+	res, err := lastFMRequest[response](l, ctx, "artist.getsimilar", "similarartists", params)
+	if mbid != nil && errors.Is(err, ErrNotFound) {
+		log.Tracef("artist %s not found on last.fm with mbid, trying by name...", name)
+		params = artistParams(name, nil)
+		params["limit"] = []string{strconv.Itoa(limit)}
+		res, err = lastFMRequest[response](l, ctx, "artist.getsimilar", "similarartists", params)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get similar artists: %w", err)
+	}
+
+	artists := make([]SimilarArtist, 0, len(res.Artists))
+	for _, a := range res.Artists {
+		match, err := strconv.ParseFloat(a.Match, 64)
+		if err != nil {
+			log.Tracef("last.fm: invalid match value of similar artist %s: %s", a.Name, a.Match)
+			continue
+		}
+		artists = append(artists, SimilarArtist{
+			Name:  a.Name,
+			MBID:  util.NilIfEmpty(a.MBID),
+			Match: match,
+		})
+	}
+	return artists, nil
+}
+
+type TopTrack struct {
+	Name      string
+	MBID      *string
+	PlayCount int
+}
+
+// GetArtistTopTracks returns up to limit of the most played tracks of the artist, most played first.
+func (l *LastFm) GetArtistTopTracks(ctx context.Context, name string, mbid *string, limit int) ([]TopTrack, error) {
+	type response struct {
+		Tracks []struct {
+			Name      string `json:"name"`
+			MBID      string `json:"mbid"`
+			PlayCount string `json:"playcount"`
+		} `json:"track"`
+	}
+
+	params := artistParams(name, mbid)
+	params["limit"] = []string{strconv.Itoa(limit)}
+
+	log.Tracef("fetching top tracks of %s from last.fm...", name)
+	res, err := lastFMRequest[response](l, ctx, "artist.gettoptracks", "toptracks", params)
+	if mbid != nil && errors.Is(err, ErrNotFound) {
+		log.Tracef("artist %s not found on last.fm with mbid, trying by name...", name)
+		params = artistParams(name, nil)
+		params["limit"] = []string{strconv.Itoa(limit)}
+		res, err = lastFMRequest[response](l, ctx, "artist.gettoptracks", "toptracks", params)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get artist top tracks: %w", err)
+	}
+
+	tracks := make([]TopTrack, 0, len(res.Tracks))
+	for _, t := range res.Tracks {
+		playCount, _ := strconv.Atoi(t.PlayCount)
+		tracks = append(tracks, TopTrack{
+			Name:      t.Name,
+			MBID:      util.NilIfEmpty(t.MBID),
+			PlayCount: playCount,
+		})
+	}
+	return tracks, nil
+}
+
+func artistParams(name string, mbid *string) map[string][]string {
+	params := make(map[string][]string, 3)
+	if mbid != nil {
+		params["mbid"] = []string{*mbid}
+	} else {
+		params["artist"] = []string{name}
+	}
+	params["autocorrect"] = []string{"1"}
+	return params
+}
+
 var artistOpenGraphQuery = cascadia.MustCompile(`html > head > meta[property="og:image"]`)
 
 // GetArtistImageURL uses code from https://github.com/sentriz/gonic/blob/0e45f5e84cd650211351179edf3eed89a54c6c75/lastfm/client.go#L182
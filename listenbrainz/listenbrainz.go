@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -20,7 +21,6 @@ import (
 
 type ListenBrainz struct {
 	db     repos.DB
-	cancel context.CancelFunc
 	config config.Config
 
 	submittingMissingListens atomic.Bool
@@ -363,35 +363,11 @@ func (l *ListenBrainz) SubmitMissingListens(ctx context.Context) error {
 	return nil
 }
 
-func (l *ListenBrainz) StartPeriodicSync(period time.Duration) {
-	go func() {
-		ctx, cancel := context.WithCancel(context.Background())
-		l.cancel = cancel
-	loop:
-		for {
-			select {
-			case <-ctx.Done():
-				break loop
-			default:
-			}
-
-			err := l.SubmitMissingListens(ctx)
-			if err != nil {
-				log.Error(err)
-			}
-
-			err = l.SyncSongFeedback(ctx)
-			if err != nil {
-				log.Error(err)
-			}
-
-			select {
-			case <-ctx.Done():
-				break loop
-			case <-time.After(period):
-			}
-		}
-	}()
+// Sync submits listens that have not been submitted yet and synchronizes the song feedback of all connected users.
+func (l *ListenBrainz) Sync(ctx context.Context) error {
+	submitErr := l.SubmitMissingListens(ctx)
+	syncErr := l.SyncSongFeedback(ctx)
+	return errors.Join(submitErr, syncErr)
 }
 
 // https://github.com/metabrainz/listenbrainz-server/blob/2dafb0da41c327d2831e5130086243b4dc5035c9/listenbrainz/webserver/views/metadata_api.py#L25
@@ -761,8 +737,3 @@ func listenBrainzRequest[T any](ctx context.Context, listenBrainzURL, endpoint,
 	}
 	return obj, nil
 }
-
-func (l *ListenBrainz) Close() error {
-	l.cancel()
-	return nil
-}
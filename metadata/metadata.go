@@ -0,0 +1,176 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/juho05/crossonic-server/lastfm"
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/juho05/crossonic-server/util"
+	"github.com/juho05/log"
+)
+
+const (
+	// MaxInfoAge is the time after which the last.fm info of artists and albums is fetched again.
+	MaxInfoAge = 30 * 24 * time.Hour
+	// lastFMSimilarArtistCount is the number of similar artists stored per artist.
+	// Most of them are usually not part of the library, so this is much higher than the count of returned similar artists.
+	lastFMSimilarArtistCount = 100
+
+	// refreshLimit is the maximum number of artists and albums each that are refreshed per run.
+	refreshLimit = 250
+	// refreshRequestInterval keeps background refreshes well below the last.fm rate limit.
+	refreshRequestInterval = 500 * time.Millisecond
+)
+
+var ErrAlreadyRefreshing = errors.New("metadata refresh already in progress")
+
+// Refresher fetches the info of artists and albums from last.fm and stores it in the DB.
+type Refresher struct {
+	lock sync.Mutex
+
+	db     repos.DB
+	lastFM *lastfm.LastFm
+}
+
+// New creates a new Refresher. lastFM may be nil, in which case nothing is fetched.
+func New(db repos.DB, lastFM *lastfm.LastFm) *Refresher {
+	return &Refresher{
+		db:     db,
+		lastFM: lastFM,
+	}
+}
+
+// UpdateArtistInfo fetches the info and similar artists of the artist from last.fm and stores them.
+// Returns an error wrapping lastfm.ErrNotFound without changing the stored info if last.fm does not know the artist.
+func (r *Refresher) UpdateArtistInfo(ctx context.Context, id, name string, mbid *string) (repos.SetArtistInfo, error) {
+	if r.lastFM == nil {
+		return repos.SetArtistInfo{}, fmt.Errorf("update artist info: %w", lastfm.ErrNotFound)
+	}
+	lInfo, err := r.lastFM.GetArtistInfo(ctx, name, mbid)
+	if err != nil {
+		return repos.SetArtistInfo{}, fmt.Errorf("update artist info: fetch last.fm data: %w", err)
+	}
+
+	similar, err := r.lastFM.GetSimilarArtists(ctx, name, mbid, lastFMSimilarArtistCount)
+	if err != nil && !errors.Is(err, lastfm.ErrNotFound) {
+		return repos.SetArtistInfo{}, fmt.Errorf("update artist info: fetch last.fm similar artists: %w", err)
+	}
+
+	info := repos.SetArtistInfo{
+		Biography:  lInfo.Bio.Content,
+		LastFMURL:  lInfo.URL,
+		LastFMMBID: lInfo.MBID,
+		SimilarArtists: util.Map(similar, func(a lastfm.SimilarArtist) repos.SimilarArtistParams {
+			return repos.SimilarArtistParams{
+				Name:          a.Name,
+				MusicBrainzID: a.MBID,
+				Match:         a.Match,
+			}
+		}),
+	}
+	err = r.db.Artist().SetInfo(ctx, id, info)
+	if err != nil {
+		return repos.SetArtistInfo{}, fmt.Errorf("update artist info: save new last.fm data in DB: %w", err)
+	}
+	return info, nil
+}
+
+// UpdateAlbumInfo fetches the info of the album from last.fm and stores it.
+// Returns an error wrapping lastfm.ErrNotFound without changing the stored info if last.fm does not know the album.
+func (r *Refresher) UpdateAlbumInfo(ctx context.Context, id, name, artistName string, mbid *string) (repos.SetAlbumInfo, error) {
+	if r.lastFM == nil {
+		return repos.SetAlbumInfo{}, fmt.Errorf("update album info: %w", lastfm.ErrNotFound)
+	}
+	lInfo, err := r.lastFM.GetAlbumInfo(ctx, name, artistName, mbid)
+	if err != nil {
+		return repos.SetAlbumInfo{}, fmt.Errorf("update album info: fetch last.fm data: %w", err)
+	}
+
+	info := repos.SetAlbumInfo{
+		Description: lInfo.Wiki.Content,
+		LastFMURL:   lInfo.URL,
+		LastFMMBID:  lInfo.MBID,
+	}
+	err = r.db.Album().SetInfo(ctx, id, info)
+	if err != nil {
+		return repos.SetAlbumInfo{}, fmt.Errorf("update album info: save new last.fm data in DB: %w", err)
+	}
+	return info, nil
+}
+
+// Refresh fetches the info of artists and albums that is older than MaxInfoAge again.
+// Only info that has been requested before is refreshed, so that last.fm is not queried for the whole library.
+// Returns ErrAlreadyRefreshing if another refresh is in progress.
+func (r *Refresher) Refresh(ctx context.Context) error {
+	if r.lastFM == nil {
+		return nil
+	}
+	if !r.lock.TryLock() {
+		return ErrAlreadyRefreshing
+	}
+	defer r.lock.Unlock()
+
+	artists, err := r.db.Artist().FindOutdatedInfo(ctx, time.Now().Add(-MaxInfoAge), refreshLimit)
+	if err != nil {
+		return fmt.Errorf("refresh metadata: find artists: %w", err)
+	}
+	albums, err := r.db.Album().FindOutdatedInfo(ctx, time.Now().Add(-MaxInfoAge), refreshLimit)
+	if err != nil {
+		return fmt.Errorf("refresh metadata: find albums: %w", err)
+	}
+	if len(artists) == 0 && len(albums) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	log.Infof("Refreshing last.fm info of %d artists and %d albums...", len(artists), len(albums))
+
+	ticker := time.NewTicker(refreshRequestInterval)
+	defer ticker.Stop()
+	wait := func() error {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("refresh metadata: %w", ctx.Err())
+		case <-ticker.C:
+			return nil
+		}
+	}
+
+	for _, a := range artists {
+		if err = wait(); err != nil {
+			return err
+		}
+		_, err = r.UpdateArtistInfo(ctx, a.ID, a.Name, a.MusicBrainzID)
+		if errors.Is(err, lastfm.ErrNotFound) {
+			// store empty info so that the artist is not looked up again in every run
+			err = r.db.Artist().SetInfo(ctx, a.ID, repos.SetArtistInfo{})
+		}
+		if err != nil && !errors.Is(err, repos.ErrNotFound) {
+			log.Errorf("refresh metadata of artist %s: %s", a.ID, err)
+		}
+	}
+
+	for _, a := range albums {
+		if err = wait(); err != nil {
+			return err
+		}
+		if a.ArtistName != nil {
+			_, err = r.UpdateAlbumInfo(ctx, a.ID, a.Name, *a.ArtistName, a.MusicBrainzID)
+		} else {
+			err = lastfm.ErrNotFound
+		}
+		if errors.Is(err, lastfm.ErrNotFound) {
+			err = r.db.Album().SetInfo(ctx, a.ID, repos.SetAlbumInfo{})
+		}
+		if err != nil && !errors.Is(err, repos.ErrNotFound) {
+			log.Errorf("refresh metadata of album %s: %s", a.ID, err)
+		}
+	}
+
+	log.Infof("Refreshed last.fm info of %d artists and %d albums in %s.", len(artists), len(albums), time.Since(start).Round(time.Millisecond))
+	return nil
+}
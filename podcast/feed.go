@@ -0,0 +1,307 @@
+package podcast
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/itlightning/dateparse"
+	"golang.org/x/net/html/charset"
+)
+
+const itunesNamespace = "http://www.itunes.com/dtds/podcast-1.0.dtd"
+
+var ErrInvalidFeed = errors.New("invalid feed")
+
+type Feed struct {
+	Title       string
+	Description string
+	ImageURL    string
+	Episodes    []*FeedEpisode
+}
+
+type FeedEpisode struct {
+	GUID        string
+	Title       string
+	Description string
+	PublishDate *time.Time
+	URL         string
+	ContentType string
+	Size        int64
+	Duration    time.Duration
+}
+
+type rssFeed struct {
+	Channel struct {
+		Title       string `xml:"title"`
+		Description string `xml:"description"`
+		// matches both <image> and <itunes:image>
+		Images []struct {
+			XMLName xml.Name
+			URL     string `xml:"url"`
+			Href    string `xml:"href,attr"`
+		} `xml:"image"`
+		Items []struct {
+			Title       string `xml:"title"`
+			Description string `xml:"description"`
+			Summary     string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd summary"`
+			GUID        string `xml:"guid"`
+			PubDate     string `xml:"pubDate"`
+			Duration    string `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+			Enclosure   struct {
+				URL    string `xml:"url,attr"`
+				Type   string `xml:"type,attr"`
+				Length string `xml:"length,attr"`
+			} `xml:"enclosure"`
+		} `xml:"item"`
+	} `xml:"channel"`
+}
+
+type atomLink struct {
+	Rel    string `xml:"rel,attr"`
+	Href   string `xml:"href,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+type atomFeed struct {
+	Title    string `xml:"title"`
+	Subtitle string `xml:"subtitle"`
+	Logo     string `xml:"logo"`
+	Icon     string `xml:"icon"`
+	Entries  []struct {
+		ID        string     `xml:"id"`
+		Title     string     `xml:"title"`
+		Summary   string     `xml:"summary"`
+		Content   string     `xml:"content"`
+		Published string     `xml:"published"`
+		Updated   string     `xml:"updated"`
+		Links     []atomLink `xml:"link"`
+	} `xml:"entry"`
+}
+
+// ParseFeed parses an RSS 2.0 or Atom podcast feed.
+// Entries without an audio enclosure are skipped.
+func ParseFeed(r io.Reader) (*Feed, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("parse feed: read: %w", err)
+	}
+
+	root, err := rootElementName(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse feed: %w", err)
+	}
+
+	switch root {
+	case "rss":
+		return parseRSS(data)
+	case "feed":
+		return parseAtom(data)
+	default:
+		return nil, fmt.Errorf("parse feed: unsupported root element %s: %w", root, ErrInvalidFeed)
+	}
+}
+
+func rootElementName(data []byte) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	decoder.CharsetReader = charset.NewReaderLabel
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return "", ErrInvalidFeed
+			}
+			return "", fmt.Errorf("find root element: %w", err)
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+func parseRSS(data []byte) (*Feed, error) {
+	var rss rssFeed
+	err := unmarshal(data, &rss)
+	if err != nil {
+		return nil, fmt.Errorf("parse rss feed: %w", err)
+	}
+	feed := &Feed{
+		Title:       strings.TrimSpace(rss.Channel.Title),
+		Description: strings.TrimSpace(rss.Channel.Description),
+		Episodes:    make([]*FeedEpisode, 0, len(rss.Channel.Items)),
+	}
+	// prefer itunes:image because it usually has a higher resolution
+	for _, img := range rss.Channel.Images {
+		if img.XMLName.Space == itunesNamespace && strings.TrimSpace(img.Href) != "" {
+			feed.ImageURL = strings.TrimSpace(img.Href)
+			break
+		}
+		if feed.ImageURL == "" {
+			feed.ImageURL = strings.TrimSpace(img.URL)
+		}
+	}
+	for _, item := range rss.Channel.Items {
+		url := strings.TrimSpace(item.Enclosure.URL)
+		if url == "" {
+			continue
+		}
+		episode := &FeedEpisode{
+			GUID:        strings.TrimSpace(item.GUID),
+			Title:       strings.TrimSpace(item.Title),
+			Description: strings.TrimSpace(item.Description),
+			PublishDate: parseTime(item.PubDate),
+			URL:         url,
+			ContentType: strings.TrimSpace(item.Enclosure.Type),
+			Size:        parseSize(item.Enclosure.Length),
+			Duration:    parseDuration(item.Duration),
+		}
+		if episode.Description == "" {
+			episode.Description = strings.TrimSpace(item.Summary)
+		}
+		feed.Episodes = append(feed.Episodes, episode)
+	}
+	finalizeEpisodes(feed.Episodes)
+	return feed, nil
+}
+
+func parseAtom(data []byte) (*Feed, error) {
+	var atom atomFeed
+	err := unmarshal(data, &atom)
+	if err != nil {
+		return nil, fmt.Errorf("parse atom feed: %w", err)
+	}
+	feed := &Feed{
+		Title:       strings.TrimSpace(atom.Title),
+		Description: strings.TrimSpace(atom.Subtitle),
+		ImageURL:    strings.TrimSpace(atom.Logo),
+		Episodes:    make([]*FeedEpisode, 0, len(atom.Entries)),
+	}
+	if feed.ImageURL == "" {
+		feed.ImageURL = strings.TrimSpace(atom.Icon)
+	}
+	for _, entry := range atom.Entries {
+		var enclosure *atomLink
+		for _, l := range entry.Links {
+			if l.Rel == "enclosure" && l.Href != "" {
+				enclosure = &l
+				break
+			}
+		}
+		if enclosure == nil {
+			continue
+		}
+		published := entry.Published
+		if published == "" {
+			published = entry.Updated
+		}
+		episode := &FeedEpisode{
+			GUID:        strings.TrimSpace(entry.ID),
+			Title:       strings.TrimSpace(entry.Title),
+			Description: strings.TrimSpace(entry.Summary),
+			PublishDate: parseTime(published),
+			URL:         strings.TrimSpace(enclosure.Href),
+			ContentType: strings.TrimSpace(enclosure.Type),
+			Size:        parseSize(enclosure.Length),
+		}
+		if episode.Description == "" {
+			episode.Description = strings.TrimSpace(entry.Content)
+		}
+		feed.Episodes = append(feed.Episodes, episode)
+	}
+	finalizeEpisodes(feed.Episodes)
+	return feed, nil
+}
+
+func finalizeEpisodes(episodes []*FeedEpisode) {
+	for _, e := range episodes {
+		if e.GUID == "" {
+			e.GUID = e.URL
+		}
+		if e.Title == "" {
+			e.Title = e.URL
+		}
+	}
+}
+
+func unmarshal(data []byte, v any) error {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	decoder.CharsetReader = charset.NewReaderLabel
+	return decoder.Decode(v)
+}
+
+func parseTime(str string) *time.Time {
+	str = strings.TrimSpace(str)
+	if str == "" {
+		return nil
+	}
+	for _, layout := range []string{time.RFC1123Z, time.RFC1123, time.RFC3339} {
+		t, err := time.Parse(layout, str)
+		if err == nil {
+			return &t
+		}
+	}
+	t, err := dateparse.ParseAny(str)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+func parseSize(str string) int64 {
+	size, err := strconv.ParseInt(strings.TrimSpace(str), 10, 64)
+	if err != nil || size < 0 {
+		return 0
+	}
+	return size
+}
+
+// parseDuration parses itunes:duration values in the formats HH:MM:SS, MM:SS and SS.
+func parseDuration(str string) time.Duration {
+	str = strings.TrimSpace(str)
+	if str == "" {
+		return 0
+	}
+	parts := strings.Split(str, ":")
+	if len(parts) > 3 {
+		return 0
+	}
+	var seconds float64
+	for _, p := range parts {
+		v, err := strconv.ParseFloat(p, 64)
+		if err != nil || v < 0 {
+			return 0
+		}
+		seconds = seconds*60 + v
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+func fetchFeed(ctx context.Context, client *http.Client, url string) (*Feed, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetch feed: new request: %w", err)
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch feed: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch feed: unexpected status code: %d", res.StatusCode)
+	}
+	feed, err := ParseFeed(io.LimitReader(res.Body, maxFeedSize))
+	if err != nil {
+		return nil, fmt.Errorf("fetch feed: %w", err)
+	}
+	return feed, nil
+}
@@ -0,0 +1,165 @@
+package podcast
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRSSFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd">
+  <channel>
+    <title>Test Podcast</title>
+    <description>A podcast for testing.</description>
+    <image><url>https://example.com/image.png</url></image>
+    <itunes:image href="https://example.com/itunes.jpg"/>
+    <item>
+      <title>Episode 2</title>
+      <description>Second episode</description>
+      <guid>episode-2</guid>
+      <pubDate>Tue, 02 Jan 2024 10:00:00 +0000</pubDate>
+      <itunes:duration>01:02:03</itunes:duration>
+      <enclosure url="https://example.com/ep2.mp3" type="audio/mpeg" length="1234"/>
+    </item>
+    <item>
+      <title>Episode 1</title>
+      <itunes:summary>First episode</itunes:summary>
+      <pubDate>Mon, 01 Jan 2024 10:00:00 GMT</pubDate>
+      <itunes:duration>90</itunes:duration>
+      <enclosure url="https://example.com/ep1.mp3" type="audio/mpeg" length="invalid"/>
+    </item>
+    <item>
+      <title>Announcement without audio</title>
+      <guid>announcement</guid>
+    </item>
+  </channel>
+</rss>`
+
+const testAtomFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Atom Podcast</title>
+  <subtitle>An atom podcast</subtitle>
+  <icon>https://example.com/icon.png</icon>
+  <entry>
+    <id>urn:uuid:1</id>
+    <title>Atom Episode</title>
+    <content>Atom content</content>
+    <updated>2024-02-03T04:05:06Z</updated>
+    <link rel="alternate" href="https://example.com/episode"/>
+    <link rel="enclosure" href="https://example.com/atom.ogg" type="audio/ogg" length="42"/>
+  </entry>
+  <entry>
+    <id>urn:uuid:2</id>
+    <title>Entry without audio</title>
+    <link rel="alternate" href="https://example.com/other"/>
+  </entry>
+</feed>`
+
+func TestParseFeed(t *testing.T) {
+	t.Run("rss", func(t *testing.T) {
+		feed, err := ParseFeed(strings.NewReader(testRSSFeed))
+		require.NoError(t, err)
+		assert.Equal(t, "Test Podcast", feed.Title)
+		assert.Equal(t, "A podcast for testing.", feed.Description)
+		assert.Equal(t, "https://example.com/itunes.jpg", feed.ImageURL)
+		require.Len(t, feed.Episodes, 2)
+
+		ep := feed.Episodes[0]
+		assert.Equal(t, "episode-2", ep.GUID)
+		assert.Equal(t, "Episode 2", ep.Title)
+		assert.Equal(t, "Second episode", ep.Description)
+		require.NotNil(t, ep.PublishDate)
+		assert.True(t, time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC).Equal(*ep.PublishDate))
+		assert.Equal(t, time.Hour+2*time.Minute+3*time.Second, ep.Duration)
+		assert.Equal(t, "https://example.com/ep2.mp3", ep.URL)
+		assert.Equal(t, "audio/mpeg", ep.ContentType)
+		assert.Equal(t, int64(1234), ep.Size)
+
+		ep = feed.Episodes[1]
+		assert.Equal(t, "https://example.com/ep1.mp3", ep.GUID, "missing guid should fall back to the enclosure url")
+		assert.Equal(t, "First episode", ep.Description)
+		assert.Equal(t, 90*time.Second, ep.Duration)
+		assert.Equal(t, int64(0), ep.Size)
+	})
+
+	t.Run("atom", func(t *testing.T) {
+		feed, err := ParseFeed(strings.NewReader(testAtomFeed))
+		require.NoError(t, err)
+		assert.Equal(t, "Atom Podcast", feed.Title)
+		assert.Equal(t, "An atom podcast", feed.Description)
+		assert.Equal(t, "https://example.com/icon.png", feed.ImageURL)
+		require.Len(t, feed.Episodes, 1)
+
+		ep := feed.Episodes[0]
+		assert.Equal(t, "urn:uuid:1", ep.GUID)
+		assert.Equal(t, "Atom Episode", ep.Title)
+		assert.Equal(t, "Atom content", ep.Description)
+		require.NotNil(t, ep.PublishDate)
+		assert.True(t, time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC).Equal(*ep.PublishDate))
+		assert.Equal(t, "https://example.com/atom.ogg", ep.URL)
+		assert.Equal(t, "audio/ogg", ep.ContentType)
+		assert.Equal(t, int64(42), ep.Size)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := ParseFeed(strings.NewReader(`<html><body>not a feed</body></html>`))
+		assert.ErrorIs(t, err, ErrInvalidFeed)
+		_, err = ParseFeed(strings.NewReader(""))
+		assert.ErrorIs(t, err, ErrInvalidFeed)
+	})
+}
+
+func Test_fetchFeed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rss":
+			w.Header().Set("Content-Type", "application/rss+xml")
+			_, _ = w.Write([]byte(testRSSFeed))
+		case "/atom":
+			w.Header().Set("Content-Type", "application/atom+xml")
+			_, _ = w.Write([]byte(testAtomFeed))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	feed, err := fetchFeed(context.Background(), server.Client(), server.URL+"/rss")
+	require.NoError(t, err)
+	assert.Equal(t, "Test Podcast", feed.Title)
+	assert.Len(t, feed.Episodes, 2)
+
+	feed, err = fetchFeed(context.Background(), server.Client(), server.URL+"/atom")
+	require.NoError(t, err)
+	assert.Equal(t, "Atom Podcast", feed.Title)
+	assert.Len(t, feed.Episodes, 1)
+
+	_, err = fetchFeed(context.Background(), server.Client(), server.URL+"/missing")
+	assert.Error(t, err)
+}
+
+func Test_parseDuration(t *testing.T) {
+	tests := []struct {
+		str  string
+		want time.Duration
+	}{
+		{"", 0},
+		{"42", 42 * time.Second},
+		{"3:04", 3*time.Minute + 4*time.Second},
+		{"1:02:03", time.Hour + 2*time.Minute + 3*time.Second},
+		{"1:2:3:4", 0},
+		{"abc", 0},
+		{"-5", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.str, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseDuration(tt.str))
+		})
+	}
+}
@@ -0,0 +1,503 @@
+package podcast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/disintegration/imaging"
+	"github.com/juho05/crossonic-server/audiotags"
+	"github.com/juho05/crossonic-server/cache"
+	"github.com/juho05/crossonic-server/config"
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/juho05/crossonic-server/util"
+	"github.com/juho05/log"
+)
+
+const (
+	maxFeedSize     = 32e6
+	downloadWorkers = 2
+)
+
+var ErrRefreshInProgress = errors.New("refresh already in progress")
+
+type Podcasts struct {
+	db             repos.DB
+	conf           config.Config
+	client         *http.Client
+	transcodeCache *cache.Cache
+
+	downloads chan string
+	// episode ids currently in the download queue or being downloaded
+	queued     map[string]struct{}
+	queuedLock sync.Mutex
+
+	refreshing atomic.Bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func New(db repos.DB, conf config.Config, transcodeCache *cache.Cache) (*Podcasts, error) {
+	err := os.MkdirAll(conf.PodcastDir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("new podcasts: create podcast dir: %w", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Podcasts{
+		db:             db,
+		conf:           conf,
+		client:         &http.Client{Timeout: 30 * time.Minute},
+		transcodeCache: transcodeCache,
+		downloads:      make(chan string, 4096),
+		queued:         make(map[string]struct{}),
+		ctx:            ctx,
+		cancel:         cancel,
+	}
+	for range downloadWorkers {
+		p.wg.Add(1)
+		go p.runDownloadWorker()
+	}
+
+	// resume downloads that were interrupted by a restart
+	episodes, err := db.Podcast().FindEpisodesByStatus(ctx, repos.PodcastStatusDownloading)
+	if err != nil {
+		p.Close()
+		return nil, fmt.Errorf("new podcasts: find interrupted downloads: %w", err)
+	}
+	for _, e := range episodes {
+		p.enqueue(e.ID)
+	}
+	return p, nil
+}
+
+// CreateChannel adds the feed at feedURL and refreshes it in the background.
+func (p *Podcasts) CreateChannel(ctx context.Context, feedURL string) (*repos.PodcastChannel, error) {
+	u, err := url.Parse(feedURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("create channel: invalid url: %w", repos.ErrInvalidParams)
+	}
+	channel, err := p.db.Podcast().CreateChannel(ctx, feedURL)
+	if err != nil {
+		return nil, fmt.Errorf("create channel: %w", err)
+	}
+	go func() {
+		err := p.RefreshChannel(p.ctx, channel)
+		if err != nil {
+			log.Errorf("create podcast channel: %s", err)
+		}
+	}()
+	return channel, nil
+}
+
+// RefreshAll fetches the feeds of all channels and stores new episodes.
+// Returns ErrRefreshInProgress if another refresh is already running.
+func (p *Podcasts) RefreshAll(ctx context.Context) error {
+	if !p.refreshing.CompareAndSwap(false, true) {
+		return ErrRefreshInProgress
+	}
+	defer p.refreshing.Store(false)
+
+	channels, err := p.db.Podcast().FindAllChannels(ctx)
+	if err != nil {
+		return fmt.Errorf("refresh all podcasts: %w", err)
+	}
+	log.Tracef("Refreshing %d podcast channels...", len(channels))
+	for _, c := range channels {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		err = p.RefreshChannel(ctx, c)
+		if err != nil {
+			log.Errorf("refresh all podcasts: %s", err)
+		}
+	}
+	return nil
+}
+
+// RefreshChannel fetches the feed of the channel, updates the channel metadata and stores new episodes.
+// Failures are also recorded in the status of the channel.
+func (p *Podcasts) RefreshChannel(ctx context.Context, channel *repos.PodcastChannel) error {
+	feed, err := fetchFeed(ctx, p.client, channel.URL)
+	if err != nil {
+		updateErr := p.db.Podcast().UpdateChannel(ctx, channel.ID, repos.UpdatePodcastChannelParams{
+			Status:       repos.NewOptionalFull(repos.PodcastStatusError),
+			ErrorMessage: repos.NewOptionalFull(util.ToPtr(err.Error())),
+			LastRefresh:  repos.NewOptionalFull(util.ToPtr(time.Now())),
+		})
+		if updateErr != nil {
+			log.Errorf("refresh podcast channel: update status: %s", updateErr)
+		}
+		return fmt.Errorf("refresh podcast channel %s: %w", channel.ID, err)
+	}
+
+	episodes := make([]repos.CreatePodcastEpisodeParams, 0, len(feed.Episodes))
+	seen := make(map[string]struct{}, len(feed.Episodes))
+	for _, e := range feed.Episodes {
+		if _, ok := seen[e.GUID]; ok {
+			continue
+		}
+		seen[e.GUID] = struct{}{}
+		params := repos.CreatePodcastEpisodeParams{
+			GUID:        e.GUID,
+			Title:       e.Title,
+			Description: util.NilIfEmpty(e.Description),
+			PublishDate: e.PublishDate,
+			URL:         e.URL,
+			ContentType: util.NilIfEmpty(e.ContentType),
+		}
+		if e.Size > 0 {
+			params.Size = &e.Size
+		}
+		if e.Duration > 0 {
+			params.Duration = repos.NullDurationMS{
+				Duration: repos.DurationMS(e.Duration),
+				Valid:    true,
+			}
+		}
+		episodes = append(episodes, params)
+	}
+
+	err = p.db.Transaction(ctx, func(tx repos.Tx) error {
+		err := tx.Podcast().UpdateChannel(ctx, channel.ID, repos.UpdatePodcastChannelParams{
+			Title:        repos.NewOptionalFull(util.NilIfEmpty(feed.Title)),
+			Description:  repos.NewOptionalFull(util.NilIfEmpty(feed.Description)),
+			ImageURL:     repos.NewOptionalFull(util.NilIfEmpty(feed.ImageURL)),
+			Status:       repos.NewOptionalFull(repos.PodcastStatusCompleted),
+			ErrorMessage: repos.NewOptionalFull[*string](nil),
+			LastRefresh:  repos.NewOptionalFull(util.ToPtr(time.Now())),
+		})
+		if err != nil {
+			return fmt.Errorf("update channel: %w", err)
+		}
+		err = tx.Podcast().CreateOrUpdateEpisodes(ctx, channel.ID, episodes)
+		if err != nil {
+			return fmt.Errorf("create or update episodes: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("refresh podcast channel %s: %w", channel.ID, err)
+	}
+
+	if feed.ImageURL != "" && (channel.ImageURL == nil || *channel.ImageURL != feed.ImageURL || !p.hasCover(channel.ID)) {
+		err = p.downloadCover(ctx, channel.ID, feed.ImageURL)
+		if err != nil {
+			log.Errorf("refresh podcast channel %s: %s", channel.ID, err)
+		}
+	}
+
+	log.Tracef("Refreshed podcast channel %s (%d episodes)", channel.ID, len(episodes))
+	return nil
+}
+
+// DeleteChannel deletes the channel including all downloaded episodes and the cover.
+func (p *Podcasts) DeleteChannel(ctx context.Context, id string) error {
+	episodes, err := p.db.Podcast().FindEpisodesByChannel(ctx, id)
+	if err != nil {
+		return fmt.Errorf("delete channel: find episodes: %w", err)
+	}
+	err = p.db.Podcast().DeleteChannel(ctx, id)
+	if err != nil {
+		return fmt.Errorf("delete channel: %w", err)
+	}
+	for _, e := range episodes {
+		p.deleteTranscodes(e.ID)
+	}
+	err = os.RemoveAll(filepath.Join(p.conf.PodcastDir, id))
+	if err != nil {
+		log.Errorf("delete podcast channel: remove episode files: %s", err)
+	}
+	err = os.Remove(filepath.Join(p.conf.DataDir, "covers", id))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Errorf("delete podcast channel: remove cover: %s", err)
+	}
+	return nil
+}
+
+// DeleteEpisode removes the downloaded file of the episode and marks it as deleted.
+// Deleted episodes are not re-added by subsequent refreshes but can be downloaded again.
+func (p *Podcasts) DeleteEpisode(ctx context.Context, id string) error {
+	episode, err := p.db.Podcast().FindEpisodeByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("delete episode: %w", err)
+	}
+	err = p.db.Podcast().UpdateEpisode(ctx, id, repos.UpdatePodcastEpisodeParams{
+		Status:       repos.NewOptionalFull(repos.PodcastStatusDeleted),
+		ErrorMessage: repos.NewOptionalFull[*string](nil),
+		Path:         repos.NewOptionalFull[*string](nil),
+	})
+	if err != nil {
+		return fmt.Errorf("delete episode: %w", err)
+	}
+	p.deleteTranscodes(id)
+	if episode.Path != nil {
+		err = os.Remove(*episode.Path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Errorf("delete podcast episode: %s", err)
+		}
+	}
+	return nil
+}
+
+// Download marks the episode as downloading and adds it to the download queue.
+func (p *Podcasts) Download(ctx context.Context, id string) error {
+	episode, err := p.db.Podcast().FindEpisodeByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("download episode: %w", err)
+	}
+	if episode.Status == repos.PodcastStatusCompleted || episode.Status == repos.PodcastStatusDownloading {
+		return nil
+	}
+	err = p.db.Podcast().UpdateEpisode(ctx, id, repos.UpdatePodcastEpisodeParams{
+		Status:       repos.NewOptionalFull(repos.PodcastStatusDownloading),
+		ErrorMessage: repos.NewOptionalFull[*string](nil),
+	})
+	if err != nil {
+		return fmt.Errorf("download episode: %w", err)
+	}
+	p.enqueue(id)
+	return nil
+}
+
+func (p *Podcasts) StartPeriodicRefresh(period time.Duration) {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		for {
+			select {
+			case <-p.ctx.Done():
+				return
+			case <-time.After(period):
+			}
+			err := p.RefreshAll(p.ctx)
+			if err != nil && !errors.Is(err, ErrRefreshInProgress) && !errors.Is(err, context.Canceled) {
+				log.Error(err)
+			}
+		}
+	}()
+}
+
+func (p *Podcasts) Close() error {
+	p.cancel()
+	p.wg.Wait()
+	return nil
+}
+
+func (p *Podcasts) enqueue(id string) {
+	p.queuedLock.Lock()
+	defer p.queuedLock.Unlock()
+	if _, ok := p.queued[id]; ok {
+		return
+	}
+	select {
+	case p.downloads <- id:
+		p.queued[id] = struct{}{}
+	default:
+		log.Warnf("podcast download queue is full, episode %s will be downloaded after the next restart", id)
+	}
+}
+
+func (p *Podcasts) runDownloadWorker() {
+	defer p.wg.Done()
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case id := <-p.downloads:
+			err := p.downloadEpisode(p.ctx, id)
+			if err != nil && !errors.Is(err, context.Canceled) {
+				log.Errorf("podcast: %s", err)
+				// episodes deleted during the download stay deleted
+				updateErr := p.db.Podcast().UpdateEpisodeWithStatus(p.ctx, id, repos.PodcastStatusDownloading, repos.UpdatePodcastEpisodeParams{
+					Status:       repos.NewOptionalFull(repos.PodcastStatusError),
+					ErrorMessage: repos.NewOptionalFull(util.ToPtr(err.Error())),
+				})
+				if updateErr != nil && !errors.Is(updateErr, repos.ErrNotFound) {
+					log.Errorf("podcast: update episode status: %s", updateErr)
+				}
+			}
+			p.queuedLock.Lock()
+			delete(p.queued, id)
+			p.queuedLock.Unlock()
+		}
+	}
+}
+
+func (p *Podcasts) downloadEpisode(ctx context.Context, id string) error {
+	episode, err := p.db.Podcast().FindEpisodeByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repos.ErrNotFound) {
+			// channel was deleted in the meantime
+			return nil
+		}
+		return fmt.Errorf("download episode %s: %w", id, err)
+	}
+	if episode.Status != repos.PodcastStatusDownloading {
+		return nil
+	}
+
+	log.Tracef("Downloading podcast episode %s (%s)...", episode.ID, episode.URL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, episode.URL, nil)
+	if err != nil {
+		return fmt.Errorf("download episode %s: new request: %w", id, err)
+	}
+	res, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("download episode %s: %w", id, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("download episode %s: unexpected status code: %d", id, res.StatusCode)
+	}
+
+	contentType := episode.ContentType
+	if ct, _, err := mime.ParseMediaType(res.Header.Get("Content-Type")); err == nil && strings.HasPrefix(ct, "audio/") {
+		contentType = &ct
+	}
+
+	dir := filepath.Join(p.conf.PodcastDir, episode.ChannelID)
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return fmt.Errorf("download episode %s: create channel dir: %w", id, err)
+	}
+	filePath := filepath.Join(dir, episode.ID+episodeFileExtension(episode.URL, contentType))
+
+	file, err := os.CreateTemp(dir, episode.ID+"-*.part")
+	if err != nil {
+		return fmt.Errorf("download episode %s: create temp file: %w", id, err)
+	}
+	size, err := io.Copy(file, res.Body)
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return fmt.Errorf("download episode %s: write file: %w", id, err)
+	}
+	err = os.Rename(file.Name(), filePath)
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return fmt.Errorf("download episode %s: rename temp file: %w", id, err)
+	}
+
+	if contentType == nil {
+		contentType = util.NilIfEmpty(mime.TypeByExtension(filepath.Ext(filePath)))
+	}
+
+	params := repos.UpdatePodcastEpisodeParams{
+		Status:       repos.NewOptionalFull(repos.PodcastStatusCompleted),
+		ErrorMessage: repos.NewOptionalFull[*string](nil),
+		Path:         repos.NewOptionalFull(&filePath),
+		Size:         repos.NewOptionalFull(&size),
+		ContentType:  repos.NewOptionalFull(contentType),
+	}
+
+	_, props, _, err := audiotags.Read(filePath, false)
+	if err == nil && !props.IsEmpty() {
+		params.BitRate = repos.NewOptionalFull(&props.BitRate)
+		params.ChannelCount = repos.NewOptionalFull(&props.Channels)
+		params.Duration = repos.NewOptionalFull(repos.NullDurationMS{
+			Duration: repos.NewDurationMS(int64(props.LengthMs)),
+			Valid:    true,
+		})
+	} else if episode.Duration.Valid && episode.Duration.Duration.Seconds() > 0 {
+		params.BitRate = repos.NewOptionalFull(util.ToPtr(int(size * 8 / 1000 / int64(episode.Duration.Duration.Seconds()))))
+	}
+
+	err = p.db.Podcast().UpdateEpisodeWithStatus(ctx, id, repos.PodcastStatusDownloading, params)
+	if err != nil {
+		_ = os.Remove(filePath)
+		if errors.Is(err, repos.ErrNotFound) {
+			// deleted in the meantime
+			return nil
+		}
+		return fmt.Errorf("download episode %s: update episode: %w", id, err)
+	}
+	p.deleteTranscodes(id)
+	log.Tracef("Downloaded podcast episode %s", episode.ID)
+	return nil
+}
+
+func (p *Podcasts) downloadCover(ctx context.Context, channelID, imageURL string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return fmt.Errorf("download cover: new request: %w", err)
+	}
+	res, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("download cover: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("download cover: unexpected status code: %d", res.StatusCode)
+	}
+	img, err := imaging.Decode(res.Body, imaging.AutoOrientation(true))
+	if err != nil {
+		return fmt.Errorf("download cover: decode image: %w", err)
+	}
+	coverDir := filepath.Join(p.conf.DataDir, "covers")
+	err = os.MkdirAll(coverDir, 0755)
+	if err != nil {
+		return fmt.Errorf("download cover: create cover dir: %w", err)
+	}
+	file, err := os.Create(filepath.Join(coverDir, channelID))
+	if err != nil {
+		return fmt.Errorf("download cover: create file: %w", err)
+	}
+	defer file.Close()
+	err = imaging.Encode(file, img, imaging.JPEG)
+	if err != nil {
+		return fmt.Errorf("download cover: encode image: %w", err)
+	}
+	return nil
+}
+
+func (p *Podcasts) hasCover(channelID string) bool {
+	info, err := os.Stat(filepath.Join(p.conf.DataDir, "covers", channelID))
+	return err == nil && info.Size() > 0
+}
+
+func (p *Podcasts) deleteTranscodes(episodeID string) {
+	if p.transcodeCache == nil {
+		return
+	}
+	for _, k := range p.transcodeCache.Keys() {
+		if strings.HasPrefix(k, episodeID) {
+			err := p.transcodeCache.DeleteObject(k)
+			if err != nil {
+				log.Errorf("podcast: delete transcodes of %s: %s", episodeID, err)
+			}
+		}
+	}
+}
+
+func episodeFileExtension(episodeURL string, contentType *string) string {
+	if u, err := url.Parse(episodeURL); err == nil {
+		ext := strings.ToLower(path.Ext(u.Path))
+		if ext != "" && len(ext) <= 5 && strings.HasPrefix(mime.TypeByExtension(ext), "audio/") {
+			return ext
+		}
+	}
+	if contentType != nil {
+		exts, err := mime.ExtensionsByType(*contentType)
+		if err == nil && len(exts) > 0 {
+			return exts[0]
+		}
+	}
+	return ".mp3"
+}
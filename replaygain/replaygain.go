@@ -0,0 +1,219 @@
+package replaygain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juho05/crossonic-server/cache"
+	"github.com/juho05/crossonic-server/ffmpeg"
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/juho05/crossonic-server/util"
+	"github.com/juho05/log"
+)
+
+// referenceLoudness is the target loudness of ReplayGain 2.0 in LUFS.
+const referenceLoudness = -18
+
+var ErrAlreadyAnalyzing = errors.New("replay gain analysis already in progress")
+
+// ReplayGain computes ReplayGain values for songs and albums without ReplayGain tags.
+// Computed values are stored separately and never replace tag values.
+type ReplayGain struct {
+	lock sync.Mutex
+
+	db             repos.DB
+	analyzer       *ffmpeg.Analyzer
+	transcodeCache *cache.Cache
+}
+
+// New creates a new ReplayGain analyzer. transcodeCache may be nil.
+func New(db repos.DB, analyzer *ffmpeg.Analyzer, transcodeCache *cache.Cache) *ReplayGain {
+	return &ReplayGain{
+		db:             db,
+		analyzer:       analyzer,
+		transcodeCache: transcodeCache,
+	}
+}
+
+// Analyze measures the loudness of all songs without ReplayGain values and computes the gain of their albums.
+// Songs that cannot be analyzed are skipped and tried again in the next run.
+// Returns ErrAlreadyAnalyzing if another analysis is in progress.
+func (r *ReplayGain) Analyze(ctx context.Context) error {
+	if !r.lock.TryLock() {
+		return ErrAlreadyAnalyzing
+	}
+	defer r.lock.Unlock()
+
+	songs, err := r.db.Song().FindMissingReplayGain(ctx)
+	if err != nil {
+		return fmt.Errorf("analyze replay gain: find songs: %w", err)
+	}
+
+	albumIDs, err := r.db.Album().FindIDsMissingReplayGain(ctx)
+	if err != nil {
+		return fmt.Errorf("analyze replay gain: find albums: %w", err)
+	}
+	if len(songs) == 0 && len(albumIDs) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	log.Infof("Analyzing loudness of %d songs...", len(songs))
+	analyzed, err := r.analyzeSongs(ctx, songs)
+	if err != nil {
+		return fmt.Errorf("analyze replay gain: %w", err)
+	}
+
+	albums := make(map[string]struct{}, len(albumIDs))
+	for _, id := range albumIDs {
+		albums[id] = struct{}{}
+	}
+	for _, s := range analyzed {
+		if s.AlbumID != nil {
+			albums[*s.AlbumID] = struct{}{}
+		}
+	}
+	albumCount := 0
+	for id := range albums {
+		ok, err := r.updateAlbum(ctx, id)
+		if err != nil {
+			return fmt.Errorf("analyze replay gain: %w", err)
+		}
+		if ok {
+			albumCount++
+		}
+	}
+
+	if len(analyzed) > 0 {
+		fallbackGain, err := r.db.Song().GetMedianReplayGain(ctx)
+		if err != nil {
+			return fmt.Errorf("analyze replay gain: get median replay gain: %w", err)
+		}
+		if fallbackGain != 0 {
+			repos.SetFallbackGain(fallbackGain)
+		}
+	}
+
+	r.deleteTranscodes(analyzed)
+	log.Infof("Computed ReplayGain of %d/%d songs and %d albums in %s.", len(analyzed), len(songs), albumCount, time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// analyzeSongs measures the loudness of songs in the process pool of the analyzer
+// and returns the songs whose values were stored.
+func (r *ReplayGain) analyzeSongs(ctx context.Context, songs []*repos.SongAnalysisInfo) ([]*repos.SongAnalysisInfo, error) {
+	var analyzed []*repos.SongAnalysisInfo
+	var analyzedLock sync.Mutex
+	err := util.ForEachParallel(ctx, songs, ffmpeg.MaxAnalyzerProcesses, func(song *repos.SongAnalysisInfo) error {
+		loudness, err := r.analyzer.Loudness(ctx, song.Path)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Errorf("analyze replay gain of %s: %s", song.Path, err)
+			}
+			return nil
+		}
+		gain, peak := loudness.ReplayGain()
+		err = r.db.Song().SetComputedReplayGain(ctx, song.ID, gain, peak)
+		if err != nil {
+			if errors.Is(err, repos.ErrNotFound) {
+				// deleted in the meantime
+				return nil
+			}
+			return fmt.Errorf("set computed replay gain of %s: %w", song.ID, err)
+		}
+		analyzedLock.Lock()
+		analyzed = append(analyzed, song)
+		analyzedLock.Unlock()
+		return nil
+	})
+	return analyzed, err
+}
+
+// updateAlbum computes the album gain from the track gains and reports whether it was stored.
+// Albums with a ReplayGain tag or tracks without a gain are skipped.
+func (r *ReplayGain) updateAlbum(ctx context.Context, id string) (bool, error) {
+	tracks, err := r.db.Album().GetTracks(ctx, id, repos.IncludeSongInfo{Album: true})
+	if err != nil {
+		return false, fmt.Errorf("update album %s: get tracks: %w", id, err)
+	}
+	if len(tracks) == 0 || tracks[0].AlbumReplayGain != nil {
+		return false, nil
+	}
+	gain, peak, ok := albumReplayGain(tracks)
+	if !ok {
+		return false, nil
+	}
+	err = r.db.Album().SetComputedReplayGain(ctx, id, gain, peak)
+	if err != nil {
+		if errors.Is(err, repos.ErrNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("update album %s: %w", id, err)
+	}
+	return true, nil
+}
+
+// albumReplayGain combines the track gains to the gain of the whole album by averaging the loudness
+// of the tracks weighted by their duration. The album peak is the highest track peak.
+// ok is false if one of the tracks does not have a gain.
+func albumReplayGain(tracks []*repos.CompleteSong) (gain, peak float64, ok bool) {
+	var energy, totalDuration float64
+	for _, t := range tracks {
+		trackGain, trackPeak := t.TrackReplayGain()
+		if trackGain == nil {
+			return 0, 0, false
+		}
+		duration := max(t.Duration.ToStd().Seconds(), 1)
+		energy += duration * math.Pow(10, (referenceLoudness-*trackGain)/10)
+		totalDuration += duration
+		if trackPeak != nil {
+			peak = max(peak, *trackPeak)
+		}
+	}
+	if totalDuration == 0 {
+		return 0, 0, false
+	}
+	loudness := 10 * math.Log10(energy/totalDuration)
+	return referenceLoudness - loudness, peak, true
+}
+
+// deleteTranscodes removes cached transcodes of songs because normalized transcodes depend on the new values.
+func (r *ReplayGain) deleteTranscodes(songs []*repos.SongAnalysisInfo) {
+	if r.transcodeCache == nil || len(songs) == 0 {
+		return
+	}
+	ids := make(map[string]struct{}, len(songs))
+	for _, s := range songs {
+		ids[s.ID] = struct{}{}
+	}
+	for _, k := range r.transcodeCache.Keys() {
+		if !hasIDPrefix(k, ids) {
+			continue
+		}
+		err := r.transcodeCache.DeleteObject(k)
+		if err != nil {
+			log.Errorf("replay gain: delete transcode %s: %s", k, err)
+		}
+	}
+}
+
+// hasIDPrefix reports whether the cache key starts with one of ids followed by a dash.
+// IDs can contain dashes themselves, so every dash is a possible end of the ID.
+func hasIDPrefix(key string, ids map[string]struct{}) bool {
+	for i := strings.IndexByte(key, '-'); i >= 0; {
+		if _, ok := ids[key[:i]]; ok {
+			return true
+		}
+		next := strings.IndexByte(key[i+1:], '-')
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package replaygain
+
+import (
+	"testing"
+
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/juho05/crossonic-server/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func track(durationMS int64, gain, peak, computedGain, computedPeak *float64) *repos.CompleteSong {
+	return &repos.CompleteSong{
+		Song: repos.Song{
+			Duration:               repos.NewDurationMS(durationMS),
+			ReplayGain:             gain,
+			ReplayGainPeak:         peak,
+			ComputedReplayGain:     computedGain,
+			ComputedReplayGainPeak: computedPeak,
+		},
+	}
+}
+
+func TestAlbumReplayGain(t *testing.T) {
+	t.Run("equal tracks", func(t *testing.T) {
+		gain, peak, ok := albumReplayGain([]*repos.CompleteSong{
+			track(180000, util.ToPtr(-6.0), util.ToPtr(0.9), nil, nil),
+			track(180000, util.ToPtr(-6.0), util.ToPtr(0.8), nil, nil),
+		})
+		assert.True(t, ok)
+		assert.InDelta(t, -6.0, gain, 0.0001)
+		assert.InDelta(t, 0.9, peak, 0.0001)
+	})
+
+	t.Run("mixes tags and computed values", func(t *testing.T) {
+		gain, peak, ok := albumReplayGain([]*repos.CompleteSong{
+			track(180000, util.ToPtr(-6.0), util.ToPtr(0.9), util.ToPtr(-20.0), util.ToPtr(1.2)),
+			track(180000, nil, nil, util.ToPtr(-6.0), util.ToPtr(0.95)),
+		})
+		assert.True(t, ok)
+		assert.InDelta(t, -6.0, gain, 0.0001, "tag values should be preferred")
+		assert.InDelta(t, 0.95, peak, 0.0001)
+	})
+
+	t.Run("louder tracks dominate", func(t *testing.T) {
+		gain, _, ok := albumReplayGain([]*repos.CompleteSong{
+			track(180000, util.ToPtr(-10.0), nil, nil, nil),
+			track(180000, util.ToPtr(0.0), nil, nil, nil),
+		})
+		assert.True(t, ok)
+		assert.Less(t, gain, -5.0)
+		assert.Greater(t, gain, -10.0)
+	})
+
+	t.Run("longer tracks weigh more", func(t *testing.T) {
+		gain, _, ok := albumReplayGain([]*repos.CompleteSong{
+			track(600000, util.ToPtr(-4.0), nil, nil, nil),
+			track(60000, util.ToPtr(-8.0), nil, nil, nil),
+		})
+		assert.True(t, ok)
+		assert.Greater(t, gain, -5.0)
+	})
+
+	t.Run("missing track gain", func(t *testing.T) {
+		_, _, ok := albumReplayGain([]*repos.CompleteSong{
+			track(180000, util.ToPtr(-6.0), nil, nil, nil),
+			track(180000, nil, nil, nil, nil),
+		})
+		assert.False(t, ok)
+	})
+}
+
+func TestHasIDPrefix(t *testing.T) {
+	ids := map[string]struct{}{
+		"tr_abc-def~ghi":  {},
+		"tr_123456789012": {},
+	}
+	assert.True(t, hasIDPrefix("tr_abc-def~ghi-mp3-192", ids))
+	assert.True(t, hasIDPrefix("tr_123456789012-opus-128-album", ids))
+	assert.False(t, hasIDPrefix("tr_abc-mp3-192", ids))
+	assert.False(t, hasIDPrefix("tr_abc-def~ghi", ids))
+	assert.False(t, hasIDPrefix("tr_other-mp3-192", ids))
+}
@@ -65,6 +65,15 @@ type AlbumInfo struct {
 	ReleaseMBID   *string    `db:"release_mbid"`
 }
 
+// OutdatedAlbumInfo is an album whose last.fm info needs to be refreshed.
+type OutdatedAlbumInfo struct {
+	ID            string  `db:"id"`
+	Name          string  `db:"name"`
+	MusicBrainzID *string `db:"music_brainz_id"`
+	// ArtistName is the name of the first album artist.
+	ArtistName *string `db:"artist_name"`
+}
+
 type AlbumArtistConnection struct {
 	AlbumID    string `db:"album_id"`
 	ArtistID   string `db:"artist_id"`
@@ -147,16 +156,23 @@ const (
 	FindAlbumSortByReleaseDate
 	FindAlbumSortByFrequent
 	FindAlbumSortByRecent
+	FindAlbumSortByArtist
+	// FindAlbumSortByRecentAll orders albums by the last time they were played by any user
+	// and excludes albums that were never played.
+	FindAlbumSortByRecentAll
 )
 
 type FindAlbumParams struct {
-	SortBy         FindAlbumSortBy
-	FromYear       *int
-	ToYear         *int
-	Genres         []string
-	Paginate       Paginate
-	RandomSeed     *string
-	MusicFolderIDs []int
+	SortBy           FindAlbumSortBy
+	FromYear         *int
+	ToYear           *int
+	Genres           []string
+	RecordLabels     []string
+	ReleaseTypes     []string
+	OnlyCompilations bool
+	Paginate         Paginate
+	RandomSeed       *string
+	MusicFolderIDs   []int
 }
 
 type SetAlbumInfo struct {
@@ -190,6 +206,12 @@ type AlbumRepository interface {
 
 	GetTracks(ctx context.Context, id string, include IncludeSongInfo) ([]*CompleteSong, error)
 
+	// FindIDsMissingReplayGain returns the IDs of all albums that neither have a ReplayGain tag nor a computed ReplayGain value.
+	FindIDsMissingReplayGain(ctx context.Context) ([]string, error)
+	// SetComputedReplayGain stores the album gain that was computed from the track gains.
+	// ReplayGain tag values are not changed.
+	SetComputedReplayGain(ctx context.Context, id string, gain, peak float64) error
+
 	Star(ctx context.Context, user, albumID string) error
 	UnStar(ctx context.Context, user, albumID string) error
 
@@ -198,9 +220,16 @@ type AlbumRepository interface {
 
 	GetInfo(ctx context.Context, albumID, user string) (*AlbumInfo, error)
 	SetInfo(ctx context.Context, albumID string, params SetAlbumInfo) error
+	// FindOutdatedInfo returns up to limit albums whose info was last updated before updatedBefore, least recently updated first.
+	// Albums whose info has never been fetched are not included.
+	FindOutdatedInfo(ctx context.Context, updatedBefore time.Time, limit int) ([]*OutdatedAlbumInfo, error)
+	// FindSimilar returns up to limit albums in musicFolderIDs by the similar artists of the album artists
+	// (see ArtistRepository.FindSimilar), most similar first.
+	FindSimilar(ctx context.Context, albumID string, musicFolderIDs []int, limit int, include IncludeAlbumInfo) ([]*CompleteAlbum, error)
 
 	GetAllArtistConnections(ctx context.Context) ([]AlbumArtistConnection, error)
 	RemoveAllArtistConnections(ctx context.Context) error
+	DeleteArtistConnections(ctx context.Context, albumIDs []string) error
 	CreateArtistConnections(ctx context.Context, connections []AlbumArtistConnection) error
 	GetAlternateVersions(ctx context.Context, albumId string, musicFolderIDs []int, include IncludeAlbumInfo) ([]*CompleteAlbum, error)
 	MigrateAnnotations(ctx context.Context, oldId, newId string) error
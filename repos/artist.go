@@ -40,6 +40,13 @@ type ArtistInfo struct {
 	MusicBrainzID *string    `db:"music_brainz_id"`
 }
 
+// OutdatedArtistInfo is an artist whose last.fm info needs to be refreshed.
+type OutdatedArtistInfo struct {
+	ID            string  `db:"id"`
+	Name          string  `db:"name"`
+	MusicBrainzID *string `db:"music_brainz_id"`
+}
+
 // params
 
 type IncludeArtistInfo struct {
@@ -71,15 +78,26 @@ type UpdateArtistParams struct {
 }
 
 type SetArtistInfo struct {
-	Biography  *string
-	LastFMURL  *string
-	LastFMMBID *string
+	Biography      *string
+	LastFMURL      *string
+	LastFMMBID     *string
+	SimilarArtists []SimilarArtistParams
+}
+
+// SimilarArtistParams describes an artist that was reported as similar by last.fm.
+// The artist does not need to exist in the library.
+type SimilarArtistParams struct {
+	Name          string
+	MusicBrainzID *string
+	Match         float64
 }
 
 type FindArtistsParams struct {
 	OnlyAlbumArtists bool
 	UpdatedAfter     *time.Time
 	MusicFolderIDs   []int
+	// IDs restricts the results to the artists with these IDs if not nil
+	IDs []string
 }
 
 // results
@@ -110,6 +128,12 @@ type ArtistRepository interface {
 
 	GetInfo(ctx context.Context, artistID, user string) (*ArtistInfo, error)
 	SetInfo(ctx context.Context, artistID string, params SetArtistInfo) error
+	// FindOutdatedInfo returns up to limit artists whose info was last updated before updatedBefore, least recently updated first.
+	// Artists whose info has never been fetched are not included.
+	FindOutdatedInfo(ctx context.Context, updatedBefore time.Time, limit int) ([]*OutdatedArtistInfo, error)
+	// FindSimilar returns up to limit artists in musicFolderIDs that match the similar artists stored with SetInfo
+	// by MusicBrainz ID or normalized name, most similar first.
+	FindSimilar(ctx context.Context, artistID string, musicFolderIDs []int, limit int, include IncludeArtistInfo) ([]*CompleteArtist, error)
 
 	MigrateAnnotations(ctx context.Context, oldId, newId string) error
 	FindArtistIDsToMigrate(ctx context.Context, scanStartTime time.Time) ([]FindArtistIDsToMigrateResult, error)
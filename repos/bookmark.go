@@ -0,0 +1,37 @@
+package repos
+
+import (
+	"context"
+	"time"
+)
+
+// models
+
+type Bookmark struct {
+	User     string     `db:"user_name"`
+	SongID   string     `db:"song_id"`
+	Position DurationMS `db:"position_ms"`
+	Comment  *string    `db:"comment"`
+	Created  time.Time  `db:"created"`
+	Updated  time.Time  `db:"updated"`
+}
+
+// params
+
+type CreateBookmarkParams struct {
+	SongID   string
+	Position time.Duration
+	Comment  *string
+}
+
+type BookmarkRepository interface {
+	// FindAll returns all bookmarks of the user.
+	FindAll(ctx context.Context, user string) ([]*Bookmark, error)
+	// CreateOrUpdate creates a bookmark for the song or updates the existing one.
+	CreateOrUpdate(ctx context.Context, user string, params CreateBookmarkParams) error
+	// Delete removes the bookmark of the user for the song.
+	// If no bookmark exists for the song, ErrNotFound will be returned.
+	Delete(ctx context.Context, user, songID string) error
+	// DeleteMissingSongs removes all bookmarks of songs that no longer exist.
+	DeleteMissingSongs(ctx context.Context) error
+}
@@ -13,6 +13,13 @@ type Tx interface {
 	Playlist() PlaylistRepository
 	InternetRadioStation() InternetRadioStationRepository
 	MusicFolder() MusicFolderRepository
+	Share() ShareRepository
+	Podcast() PodcastRepository
+	PlayQueue() PlayQueueRepository
+	Bookmark() BookmarkRepository
+	Similarity() SimilarityRepository
+	Directory() DirectoryRepository
+	ScanProblem() ScanProblemRepository
 }
 
 type Transaction interface {
@@ -0,0 +1,51 @@
+package repos
+
+import (
+	"context"
+	"time"
+)
+
+// models
+
+// Directory is a directory of a music folder as found on the file system.
+type Directory struct {
+	ID            string    `db:"id"`
+	Path          string    `db:"path"`
+	Name          string    `db:"name"`
+	ParentID      *string   `db:"parent_id"`
+	MusicFolderID int       `db:"music_folder_id"`
+	Created       time.Time `db:"created"`
+	Updated       time.Time `db:"updated"`
+}
+
+// params
+
+type UpsertDirectoryParams struct {
+	// ID is ignored if a directory with the same path already exists.
+	ID            string
+	Path          string
+	Name          string
+	ParentID      *string
+	MusicFolderID int
+}
+
+// repo
+
+type DirectoryRepository interface {
+	// FindByID returns the directory if it is part of one of musicFolderIDs.
+	FindByID(ctx context.Context, id string, musicFolderIDs []int) (*Directory, error)
+	// FindRoots returns the root directories of musicFolderIDs.
+	FindRoots(ctx context.Context, musicFolderIDs []int) ([]*Directory, error)
+	// FindChildren returns the subdirectories of the directories in parentIDs that are part of one of musicFolderIDs ordered by name.
+	FindChildren(ctx context.Context, parentIDs []string, musicFolderIDs []int) ([]*Directory, error)
+	// FindAll returns all directories of all music folders.
+	FindAll(ctx context.Context) ([]*Directory, error)
+	// GetSongs returns the songs that are located directly in the directories in ids ordered by path.
+	GetSongs(ctx context.Context, ids []string, include IncludeSongInfo) ([]*CompleteSong, error)
+
+	// UpsertAll creates new directories or updates existing directories with the same path.
+	// Parents must come before their children in params.
+	UpsertAll(ctx context.Context, params []UpsertDirectoryParams) error
+	// DeleteByIDs deletes the directories and all of their subdirectories.
+	DeleteByIDs(ctx context.Context, ids []string) error
+}
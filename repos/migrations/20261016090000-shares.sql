@@ -0,0 +1,22 @@
+-- +migrate Up
+CREATE TABLE shares (
+  id text NOT NULL PRIMARY KEY,
+  user_name text NOT NULL REFERENCES users(name) ON UPDATE CASCADE ON DELETE CASCADE,
+  description text,
+  created timestamptz NOT NULL,
+  updated timestamptz NOT NULL,
+  expires timestamptz,
+  last_visited timestamptz,
+  visit_count int NOT NULL DEFAULT 0
+);
+
+CREATE TABLE share_entries (
+  share_id text NOT NULL REFERENCES shares(id) ON DELETE CASCADE,
+  entry_id text NOT NULL,
+  index int NOT NULL,
+  PRIMARY KEY (share_id, index)
+);
+
+-- +migrate Down
+DROP TABLE share_entries;
+DROP TABLE shares;
@@ -0,0 +1,40 @@
+-- +migrate Up
+CREATE TABLE podcast_channels (
+  id text NOT NULL PRIMARY KEY,
+  url text NOT NULL UNIQUE,
+  title text,
+  description text,
+  image_url text,
+  status text NOT NULL,
+  error_message text,
+  created timestamptz NOT NULL,
+  updated timestamptz NOT NULL,
+  last_refresh timestamptz
+);
+
+CREATE TABLE podcast_episodes (
+  id text NOT NULL PRIMARY KEY,
+  channel_id text NOT NULL REFERENCES podcast_channels(id) ON DELETE CASCADE,
+  guid text NOT NULL,
+  title text NOT NULL,
+  description text,
+  publish_date timestamptz,
+  url text NOT NULL,
+  status text NOT NULL,
+  error_message text,
+  path text,
+  size bigint,
+  content_type text,
+  bit_rate int,
+  duration_ms int,
+  channel_count int,
+  created timestamptz NOT NULL,
+  updated timestamptz NOT NULL,
+  UNIQUE (channel_id, guid)
+);
+
+CREATE INDEX podcast_episodes_publish_date_idx ON podcast_episodes(publish_date DESC NULLS LAST);
+
+-- +migrate Down
+DROP TABLE podcast_episodes;
+DROP TABLE podcast_channels;
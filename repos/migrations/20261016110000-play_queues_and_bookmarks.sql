@@ -0,0 +1,32 @@
+-- +migrate Up
+CREATE TABLE play_queues (
+  user_name text NOT NULL PRIMARY KEY REFERENCES users(name) ON UPDATE CASCADE ON DELETE CASCADE,
+  current_index int,
+  position_ms bigint NOT NULL DEFAULT 0,
+  changed timestamptz NOT NULL,
+  changed_by text NOT NULL
+);
+
+CREATE TABLE play_queue_songs (
+  user_name text NOT NULL REFERENCES play_queues(user_name) ON UPDATE CASCADE ON DELETE CASCADE,
+  index int NOT NULL,
+  song_id text NOT NULL,
+  PRIMARY KEY (user_name,index) DEFERRABLE INITIALLY IMMEDIATE
+);
+CREATE INDEX play_queue_songs_song_id_idx ON play_queue_songs(song_id);
+
+CREATE TABLE bookmarks (
+  user_name text NOT NULL REFERENCES users(name) ON UPDATE CASCADE ON DELETE CASCADE,
+  song_id text NOT NULL,
+  position_ms bigint NOT NULL,
+  comment text,
+  created timestamptz NOT NULL,
+  updated timestamptz NOT NULL,
+  PRIMARY KEY (user_name,song_id)
+);
+CREATE INDEX bookmarks_song_id_idx ON bookmarks(song_id);
+
+-- +migrate Down
+DROP TABLE bookmarks;
+DROP TABLE play_queue_songs;
+DROP TABLE play_queues;
@@ -0,0 +1,22 @@
+-- +migrate Up
+ALTER TABLE users
+ADD COLUMN admin boolean NOT NULL DEFAULT false,
+ADD COLUMN download_role boolean NOT NULL DEFAULT true,
+ADD COLUMN playlist_role boolean NOT NULL DEFAULT true,
+ADD COLUMN share_role boolean NOT NULL DEFAULT true,
+ADD COLUMN scrobble_role boolean NOT NULL DEFAULT true,
+ADD COLUMN settings_role boolean NOT NULL DEFAULT true,
+ADD COLUMN custom_music_folders boolean NOT NULL DEFAULT false;
+
+-- existing users had access to everything before roles were introduced
+UPDATE users SET admin = true;
+
+-- +migrate Down
+ALTER TABLE users
+DROP COLUMN admin,
+DROP COLUMN download_role,
+DROP COLUMN playlist_role,
+DROP COLUMN share_role,
+DROP COLUMN scrobble_role,
+DROP COLUMN settings_role,
+DROP COLUMN custom_music_folders;
@@ -0,0 +1,11 @@
+-- +migrate Up
+CREATE INDEX scrobbles_user_name_time_idx ON scrobbles(user_name, time);
+CREATE INDEX playlist_song_song_id_idx ON playlist_song(song_id);
+CREATE INDEX song_genre_genre_name_idx ON song_genre(genre_name);
+CREATE INDEX song_artist_artist_id_idx ON song_artist(artist_id);
+
+-- +migrate Down
+DROP INDEX song_artist_artist_id_idx;
+DROP INDEX song_genre_genre_name_idx;
+DROP INDEX playlist_song_song_id_idx;
+DROP INDEX scrobbles_user_name_time_idx;
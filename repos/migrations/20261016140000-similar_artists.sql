@@ -0,0 +1,18 @@
+-- +migrate Up
+CREATE TABLE artist_similar (
+    artist_id text NOT NULL REFERENCES artists(id) ON DELETE CASCADE,
+    index int NOT NULL,
+    name text NOT NULL,
+    search_text text NOT NULL,
+    music_brainz_id text,
+    match real NOT NULL,
+    PRIMARY KEY (artist_id, index)
+);
+CREATE INDEX artist_similar_search_text_idx ON artist_similar(search_text);
+CREATE INDEX artist_similar_music_brainz_id_idx ON artist_similar(music_brainz_id);
+
+-- refetch the last.fm info of all artists to populate artist_similar
+UPDATE artists SET info_updated = NULL;
+
+-- +migrate Down
+DROP TABLE artist_similar;
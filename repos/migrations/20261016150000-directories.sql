@@ -0,0 +1,19 @@
+-- +migrate Up
+CREATE TABLE directories (
+    id text PRIMARY KEY,
+    path text NOT NULL UNIQUE,
+    name text NOT NULL,
+    parent_id text REFERENCES directories(id) ON DELETE CASCADE,
+    music_folder_id int NOT NULL REFERENCES music_folders(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    created timestamptz NOT NULL,
+    updated timestamptz NOT NULL
+);
+CREATE INDEX directories_parent_id_idx ON directories(parent_id);
+
+ALTER TABLE songs ADD COLUMN directory_path text GENERATED ALWAYS AS (regexp_replace(path, '/[^/]*$', '')) STORED;
+CREATE INDEX songs_directory_path_idx ON songs(directory_path);
+
+-- +migrate Down
+DROP INDEX songs_directory_path_idx;
+ALTER TABLE songs DROP COLUMN directory_path;
+DROP TABLE directories;
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE users ADD COLUMN jukebox_role boolean NOT NULL DEFAULT false;
+
+-- +migrate Down
+ALTER TABLE users DROP COLUMN jukebox_role;
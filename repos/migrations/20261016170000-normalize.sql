@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE users ADD COLUMN normalize text NOT NULL DEFAULT 'off';
+
+-- +migrate Down
+ALTER TABLE users DROP COLUMN normalize;
@@ -0,0 +1,11 @@
+-- +migrate Up
+ALTER TABLE songs ADD COLUMN computed_replay_gain real;
+ALTER TABLE songs ADD COLUMN computed_replay_gain_peak real;
+ALTER TABLE albums ADD COLUMN computed_replay_gain real;
+ALTER TABLE albums ADD COLUMN computed_replay_gain_peak real;
+
+-- +migrate Down
+ALTER TABLE songs DROP COLUMN computed_replay_gain;
+ALTER TABLE songs DROP COLUMN computed_replay_gain_peak;
+ALTER TABLE albums DROP COLUMN computed_replay_gain;
+ALTER TABLE albums DROP COLUMN computed_replay_gain_peak;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE songs ADD COLUMN detected_bpm int;
+ALTER TABLE songs ADD COLUMN bpm_analyzed boolean NOT NULL DEFAULT false;
+
+-- +migrate Down
+ALTER TABLE songs DROP COLUMN detected_bpm;
+ALTER TABLE songs DROP COLUMN bpm_analyzed;
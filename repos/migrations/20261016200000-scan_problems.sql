@@ -0,0 +1,13 @@
+-- +migrate Up
+CREATE TABLE scan_problems (
+    path text NOT NULL,
+    type text NOT NULL,
+    message text NOT NULL,
+    music_folder_id int NOT NULL REFERENCES music_folders(id) ON DELETE CASCADE ON UPDATE CASCADE,
+    created timestamptz NOT NULL,
+    updated timestamptz NOT NULL,
+    PRIMARY KEY (path, type)
+);
+
+-- +migrate Down
+DROP TABLE scan_problems;
@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE songs ADD COLUMN fingerprint text;
+ALTER TABLE songs ADD COLUMN content_hash text;
+CREATE INDEX songs_fingerprint_idx ON songs(fingerprint);
+
+-- +migrate Down
+DROP INDEX songs_fingerprint_idx;
+ALTER TABLE songs DROP COLUMN content_hash;
+ALTER TABLE songs DROP COLUMN fingerprint;
@@ -23,6 +23,7 @@ type AlbumRepository struct {
 	RemoveRatingMock                  func(ctx context.Context, user, albumID string) error
 	GetInfoMock                       func(ctx context.Context, albumID, user string) (*repos.AlbumInfo, error)
 	SetInfoMock                       func(ctx context.Context, albumID string, params repos.SetAlbumInfo) error
+	FindSimilarMock                   func(ctx context.Context, albumID string, musicFolderIDs []int, limit int, include repos.IncludeAlbumInfo) ([]*repos.CompleteAlbum, error)
 	GetAllArtistConnectionsMock       func(ctx context.Context) ([]repos.AlbumArtistConnection, error)
 	RemoveAllArtistConnectionsMock    func(ctx context.Context) error
 	CreateArtistConnectionsMock       func(ctx context.Context, connections []repos.AlbumArtistConnection) error
@@ -30,6 +31,10 @@ type AlbumRepository struct {
 	MigrateAnnotationsMock            func(ctx context.Context, oldId, newId string) error
 	FindAlbumIDsToMigrateMock         func(ctx context.Context, scanStartTime time.Time) ([]repos.FindAlbumIDsToMigrateResult, error)
 	DeleteAllWithoutMusicFolderIDMock func(ctx context.Context) error
+	FindIDsMissingReplayGainMock      func(ctx context.Context) ([]string, error)
+	SetComputedReplayGainMock         func(ctx context.Context, id string, gain, peak float64) error
+	FindOutdatedInfoMock              func(ctx context.Context, updatedBefore time.Time, limit int) ([]*repos.OutdatedAlbumInfo, error)
+	DeleteArtistConnectionsMock       func(ctx context.Context, albumIDs []string) error
 }
 
 func (a AlbumRepository) Create(ctx context.Context, params repos.CreateAlbumParams) (string, error) {
@@ -137,6 +142,13 @@ func (a AlbumRepository) SetInfo(ctx context.Context, albumID string, params rep
 	panic("not implemented")
 }
 
+func (a AlbumRepository) FindSimilar(ctx context.Context, albumID string, musicFolderIDs []int, limit int, include repos.IncludeAlbumInfo) ([]*repos.CompleteAlbum, error) {
+	if a.FindSimilarMock != nil {
+		return a.FindSimilarMock(ctx, albumID, musicFolderIDs, limit, include)
+	}
+	panic("not implemented")
+}
+
 func (a AlbumRepository) GetAllArtistConnections(ctx context.Context) ([]repos.AlbumArtistConnection, error) {
 	if a.GetAllArtistConnectionsMock != nil {
 		return a.GetAllArtistConnectionsMock(ctx)
@@ -184,3 +196,31 @@ func (a AlbumRepository) DeleteAllWithoutMusicFolderID(ctx context.Context) erro
 	}
 	panic("not implemented")
 }
+
+func (a AlbumRepository) FindIDsMissingReplayGain(ctx context.Context) ([]string, error) {
+	if a.FindIDsMissingReplayGainMock != nil {
+		return a.FindIDsMissingReplayGainMock(ctx)
+	}
+	panic("not implemented")
+}
+
+func (a AlbumRepository) SetComputedReplayGain(ctx context.Context, id string, gain, peak float64) error {
+	if a.SetComputedReplayGainMock != nil {
+		return a.SetComputedReplayGainMock(ctx, id, gain, peak)
+	}
+	panic("not implemented")
+}
+
+func (a AlbumRepository) FindOutdatedInfo(ctx context.Context, updatedBefore time.Time, limit int) ([]*repos.OutdatedAlbumInfo, error) {
+	if a.FindOutdatedInfoMock != nil {
+		return a.FindOutdatedInfoMock(ctx, updatedBefore, limit)
+	}
+	panic("not implemented")
+}
+
+func (a AlbumRepository) DeleteArtistConnections(ctx context.Context, albumIDs []string) error {
+	if a.DeleteArtistConnectionsMock != nil {
+		return a.DeleteArtistConnectionsMock(ctx, albumIDs)
+	}
+	panic("not implemented")
+}
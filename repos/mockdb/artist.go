@@ -26,8 +26,10 @@ type ArtistRepository struct {
 	RemoveRatingMock               func(ctx context.Context, user, artistID string) error
 	GetInfoMock                    func(ctx context.Context, artistID, user string) (*repos.ArtistInfo, error)
 	SetInfoMock                    func(ctx context.Context, artistID string, params repos.SetArtistInfo) error
+	FindSimilarMock                func(ctx context.Context, artistID string, musicFolderIDs []int, limit int, include repos.IncludeArtistInfo) ([]*repos.CompleteArtist, error)
 	MigrateAnnotationsMock         func(ctx context.Context, oldId, newId string) error
 	FindArtistIDsToMigrateMock     func(ctx context.Context, scanStartTime time.Time) ([]repos.FindArtistIDsToMigrateResult, error)
+	FindOutdatedInfoMock           func(ctx context.Context, updatedBefore time.Time, limit int) ([]*repos.OutdatedArtistInfo, error)
 }
 
 func (a ArtistRepository) GetAppearsOnAlbums(ctx context.Context, id string, musicFolderIDs []int, include repos.IncludeAlbumInfo) ([]*repos.CompleteAlbum, error) {
@@ -156,6 +158,13 @@ func (a ArtistRepository) SetInfo(ctx context.Context, artistID string, params r
 	panic("not implemented")
 }
 
+func (a ArtistRepository) FindSimilar(ctx context.Context, artistID string, musicFolderIDs []int, limit int, include repos.IncludeArtistInfo) ([]*repos.CompleteArtist, error) {
+	if a.FindSimilarMock != nil {
+		return a.FindSimilarMock(ctx, artistID, musicFolderIDs, limit, include)
+	}
+	panic("not implemented")
+}
+
 func (a ArtistRepository) MigrateAnnotations(ctx context.Context, oldId, newId string) error {
 	if a.MigrateAnnotationsMock != nil {
 		return a.MigrateAnnotationsMock(ctx, oldId, newId)
@@ -169,3 +178,10 @@ func (a ArtistRepository) FindArtistIDsToMigrate(ctx context.Context, scanStartT
 	}
 	panic("not implemented")
 }
+
+func (a ArtistRepository) FindOutdatedInfo(ctx context.Context, updatedBefore time.Time, limit int) ([]*repos.OutdatedArtistInfo, error) {
+	if a.FindOutdatedInfoMock != nil {
+		return a.FindOutdatedInfoMock(ctx, updatedBefore, limit)
+	}
+	panic("not implemented")
+}
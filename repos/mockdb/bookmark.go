@@ -0,0 +1,42 @@
+package mockdb
+
+import (
+	"context"
+
+	"github.com/juho05/crossonic-server/repos"
+)
+
+type BookmarkRepository struct {
+	FindAllMock            func(ctx context.Context, user string) ([]*repos.Bookmark, error)
+	CreateOrUpdateMock     func(ctx context.Context, user string, params repos.CreateBookmarkParams) error
+	DeleteMock             func(ctx context.Context, user, songID string) error
+	DeleteMissingSongsMock func(ctx context.Context) error
+}
+
+func (b BookmarkRepository) FindAll(ctx context.Context, user string) ([]*repos.Bookmark, error) {
+	if b.FindAllMock != nil {
+		return b.FindAllMock(ctx, user)
+	}
+	panic("not implemented")
+}
+
+func (b BookmarkRepository) CreateOrUpdate(ctx context.Context, user string, params repos.CreateBookmarkParams) error {
+	if b.CreateOrUpdateMock != nil {
+		return b.CreateOrUpdateMock(ctx, user, params)
+	}
+	panic("not implemented")
+}
+
+func (b BookmarkRepository) Delete(ctx context.Context, user, songID string) error {
+	if b.DeleteMock != nil {
+		return b.DeleteMock(ctx, user, songID)
+	}
+	panic("not implemented")
+}
+
+func (b BookmarkRepository) DeleteMissingSongs(ctx context.Context) error {
+	if b.DeleteMissingSongsMock != nil {
+		return b.DeleteMissingSongsMock(ctx)
+	}
+	panic("not implemented")
+}
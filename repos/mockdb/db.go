@@ -17,6 +17,13 @@ type DB struct {
 	PlaylistRepository             PlaylistRepository
 	InternetRadioStationRepository InternetRadioStationRepository
 	MusicFolderRepository          MusicFolderRepository
+	ShareRepository                ShareRepository
+	PodcastRepository              PodcastRepository
+	PlayQueueRepository            PlayQueueRepository
+	BookmarkRepository             BookmarkRepository
+	SimilarityRepository           SimilarityRepository
+	DirectoryRepository            DirectoryRepository
+	ScanProblemRepository          ScanProblemRepository
 
 	TransactionMock    func(ctx context.Context, fn func(tx repos.Tx) error) error
 	NewTransactionMock func(ctx context.Context) (repos.Transaction, error)
@@ -65,6 +72,34 @@ func (d *DB) MusicFolder() repos.MusicFolderRepository {
 	return d.MusicFolderRepository
 }
 
+func (d *DB) Share() repos.ShareRepository {
+	return d.ShareRepository
+}
+
+func (d *DB) Podcast() repos.PodcastRepository {
+	return d.PodcastRepository
+}
+
+func (d *DB) PlayQueue() repos.PlayQueueRepository {
+	return d.PlayQueueRepository
+}
+
+func (d *DB) Bookmark() repos.BookmarkRepository {
+	return d.BookmarkRepository
+}
+
+func (d *DB) Similarity() repos.SimilarityRepository {
+	return d.SimilarityRepository
+}
+
+func (d *DB) Directory() repos.DirectoryRepository {
+	return d.DirectoryRepository
+}
+
+func (d *DB) ScanProblem() repos.ScanProblemRepository {
+	return d.ScanProblemRepository
+}
+
 func (d *DB) Transaction(ctx context.Context, fn func(tx repos.Tx) error) error {
 	if d.TransactionMock != nil {
 		return d.TransactionMock(ctx, fn)
@@ -0,0 +1,66 @@
+package mockdb
+
+import (
+	"context"
+
+	"github.com/juho05/crossonic-server/repos"
+)
+
+type DirectoryRepository struct {
+	FindByIDMock     func(ctx context.Context, id string, musicFolderIDs []int) (*repos.Directory, error)
+	FindRootsMock    func(ctx context.Context, musicFolderIDs []int) ([]*repos.Directory, error)
+	FindChildrenMock func(ctx context.Context, parentIDs []string, musicFolderIDs []int) ([]*repos.Directory, error)
+	FindAllMock      func(ctx context.Context) ([]*repos.Directory, error)
+	GetSongsMock     func(ctx context.Context, ids []string, include repos.IncludeSongInfo) ([]*repos.CompleteSong, error)
+	UpsertAllMock    func(ctx context.Context, params []repos.UpsertDirectoryParams) error
+	DeleteByIDsMock  func(ctx context.Context, ids []string) error
+}
+
+func (d DirectoryRepository) FindByID(ctx context.Context, id string, musicFolderIDs []int) (*repos.Directory, error) {
+	if d.FindByIDMock != nil {
+		return d.FindByIDMock(ctx, id, musicFolderIDs)
+	}
+	panic("not implemented")
+}
+
+func (d DirectoryRepository) FindRoots(ctx context.Context, musicFolderIDs []int) ([]*repos.Directory, error) {
+	if d.FindRootsMock != nil {
+		return d.FindRootsMock(ctx, musicFolderIDs)
+	}
+	panic("not implemented")
+}
+
+func (d DirectoryRepository) FindChildren(ctx context.Context, parentIDs []string, musicFolderIDs []int) ([]*repos.Directory, error) {
+	if d.FindChildrenMock != nil {
+		return d.FindChildrenMock(ctx, parentIDs, musicFolderIDs)
+	}
+	panic("not implemented")
+}
+
+func (d DirectoryRepository) FindAll(ctx context.Context) ([]*repos.Directory, error) {
+	if d.FindAllMock != nil {
+		return d.FindAllMock(ctx)
+	}
+	panic("not implemented")
+}
+
+func (d DirectoryRepository) GetSongs(ctx context.Context, ids []string, include repos.IncludeSongInfo) ([]*repos.CompleteSong, error) {
+	if d.GetSongsMock != nil {
+		return d.GetSongsMock(ctx, ids, include)
+	}
+	panic("not implemented")
+}
+
+func (d DirectoryRepository) UpsertAll(ctx context.Context, params []repos.UpsertDirectoryParams) error {
+	if d.UpsertAllMock != nil {
+		return d.UpsertAllMock(ctx, params)
+	}
+	panic("not implemented")
+}
+
+func (d DirectoryRepository) DeleteByIDs(ctx context.Context, ids []string) error {
+	if d.DeleteByIDsMock != nil {
+		return d.DeleteByIDsMock(ctx, ids)
+	}
+	panic("not implemented")
+}
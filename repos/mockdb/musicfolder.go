@@ -11,12 +11,15 @@ type MusicFolderRepository struct {
 	CreateOrUpdateMock                       func(ctx context.Context, folders []repos.CreateMusicFolderParams) error
 	DeleteMusicFoldersNotInMock              func(ctx context.Context, keepIDs []int) error
 	DeleteAllUserAssociationsMock            func(ctx context.Context) error
+	DeleteDefaultUserAssociationsMock        func(ctx context.Context) error
+	DeleteUserAssociationsOfUserMock         func(ctx context.Context, user string) error
 	CreateUserAssociationsMock               func(ctx context.Context, folderId int, users []string) error
 	GetAllArtistAsssociationsMock            func(ctx context.Context) ([]repos.ArtistMusicFolderAssociation, error)
 	DeleteAllArtistAssociationsMock          func(ctx context.Context) error
 	CreateArtistAssociationsMock             func(ctx context.Context, associations []repos.ArtistMusicFolderAssociation) error
 	DeleteArtistAssociationsWithoutSongsMock func(ctx context.Context) error
 	GetUserMusicFolderIDsMock                func(ctx context.Context, user string, requestedIDs []int) ([]int, error)
+	DeleteArtistAssociationsMock             func(ctx context.Context, artistIDs []string) error
 }
 
 func (m MusicFolderRepository) FindAll(ctx context.Context, user string) ([]repos.MusicFolder, error) {
@@ -47,6 +50,20 @@ func (m MusicFolderRepository) DeleteAllUserAssociations(ctx context.Context) er
 	panic("not implemented")
 }
 
+func (m MusicFolderRepository) DeleteDefaultUserAssociations(ctx context.Context) error {
+	if m.DeleteDefaultUserAssociationsMock != nil {
+		return m.DeleteDefaultUserAssociationsMock(ctx)
+	}
+	panic("not implemented")
+}
+
+func (m MusicFolderRepository) DeleteUserAssociationsOfUser(ctx context.Context, user string) error {
+	if m.DeleteUserAssociationsOfUserMock != nil {
+		return m.DeleteUserAssociationsOfUserMock(ctx, user)
+	}
+	panic("not implemented")
+}
+
 func (m MusicFolderRepository) CreateUserAssociations(ctx context.Context, folderId int, users []string) error {
 	if m.CreateUserAssociationsMock != nil {
 		return m.CreateUserAssociationsMock(ctx, folderId, users)
@@ -88,3 +105,10 @@ func (m MusicFolderRepository) GetUserMusicFolderIDs(ctx context.Context, user s
 	}
 	panic("not implemented")
 }
+
+func (m MusicFolderRepository) DeleteArtistAssociations(ctx context.Context, artistIDs []string) error {
+	if m.DeleteArtistAssociationsMock != nil {
+		return m.DeleteArtistAssociationsMock(ctx, artistIDs)
+	}
+	panic("not implemented")
+}
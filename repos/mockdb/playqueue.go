@@ -0,0 +1,50 @@
+package mockdb
+
+import (
+	"context"
+
+	"github.com/juho05/crossonic-server/repos"
+)
+
+type PlayQueueRepository struct {
+	GetMock                func(ctx context.Context, user string) (*repos.PlayQueue, error)
+	GetSongIDsMock         func(ctx context.Context, user string) ([]string, error)
+	SaveMock               func(ctx context.Context, user string, params repos.SavePlayQueueParams) error
+	DeleteMock             func(ctx context.Context, user string) error
+	DeleteMissingSongsMock func(ctx context.Context) error
+}
+
+func (p PlayQueueRepository) Get(ctx context.Context, user string) (*repos.PlayQueue, error) {
+	if p.GetMock != nil {
+		return p.GetMock(ctx, user)
+	}
+	panic("not implemented")
+}
+
+func (p PlayQueueRepository) GetSongIDs(ctx context.Context, user string) ([]string, error) {
+	if p.GetSongIDsMock != nil {
+		return p.GetSongIDsMock(ctx, user)
+	}
+	panic("not implemented")
+}
+
+func (p PlayQueueRepository) Save(ctx context.Context, user string, params repos.SavePlayQueueParams) error {
+	if p.SaveMock != nil {
+		return p.SaveMock(ctx, user, params)
+	}
+	panic("not implemented")
+}
+
+func (p PlayQueueRepository) Delete(ctx context.Context, user string) error {
+	if p.DeleteMock != nil {
+		return p.DeleteMock(ctx, user)
+	}
+	panic("not implemented")
+}
+
+func (p PlayQueueRepository) DeleteMissingSongs(ctx context.Context) error {
+	if p.DeleteMissingSongsMock != nil {
+		return p.DeleteMissingSongsMock(ctx)
+	}
+	panic("not implemented")
+}
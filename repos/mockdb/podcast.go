@@ -0,0 +1,114 @@
+package mockdb
+
+import (
+	"context"
+
+	"github.com/juho05/crossonic-server/repos"
+)
+
+type PodcastRepository struct {
+	CreateChannelMock           func(ctx context.Context, url string) (*repos.PodcastChannel, error)
+	UpdateChannelMock           func(ctx context.Context, id string, params repos.UpdatePodcastChannelParams) error
+	FindAllChannelsMock         func(ctx context.Context) ([]*repos.PodcastChannel, error)
+	FindChannelByIDMock         func(ctx context.Context, id string) (*repos.PodcastChannel, error)
+	DeleteChannelMock           func(ctx context.Context, id string) error
+	CreateOrUpdateEpisodesMock  func(ctx context.Context, channelID string, params []repos.CreatePodcastEpisodeParams) error
+	FindEpisodesByChannelMock   func(ctx context.Context, channelID string) ([]*repos.PodcastEpisode, error)
+	FindNewestEpisodesMock      func(ctx context.Context, count int) ([]*repos.PodcastEpisode, error)
+	FindEpisodesByStatusMock    func(ctx context.Context, status repos.PodcastStatus) ([]*repos.PodcastEpisode, error)
+	FindEpisodeByIDMock         func(ctx context.Context, id string) (*repos.PodcastEpisode, error)
+	UpdateEpisodeMock           func(ctx context.Context, id string, params repos.UpdatePodcastEpisodeParams) error
+	GetEpisodeStreamInfoMock    func(ctx context.Context, id string) (*repos.SongStreamInfo, error)
+	UpdateEpisodeWithStatusMock func(ctx context.Context, id string, status repos.PodcastStatus, params repos.UpdatePodcastEpisodeParams) error
+}
+
+func (p PodcastRepository) CreateChannel(ctx context.Context, url string) (*repos.PodcastChannel, error) {
+	if p.CreateChannelMock != nil {
+		return p.CreateChannelMock(ctx, url)
+	}
+	panic("not implemented")
+}
+
+func (p PodcastRepository) UpdateChannel(ctx context.Context, id string, params repos.UpdatePodcastChannelParams) error {
+	if p.UpdateChannelMock != nil {
+		return p.UpdateChannelMock(ctx, id, params)
+	}
+	panic("not implemented")
+}
+
+func (p PodcastRepository) FindAllChannels(ctx context.Context) ([]*repos.PodcastChannel, error) {
+	if p.FindAllChannelsMock != nil {
+		return p.FindAllChannelsMock(ctx)
+	}
+	panic("not implemented")
+}
+
+func (p PodcastRepository) FindChannelByID(ctx context.Context, id string) (*repos.PodcastChannel, error) {
+	if p.FindChannelByIDMock != nil {
+		return p.FindChannelByIDMock(ctx, id)
+	}
+	panic("not implemented")
+}
+
+func (p PodcastRepository) DeleteChannel(ctx context.Context, id string) error {
+	if p.DeleteChannelMock != nil {
+		return p.DeleteChannelMock(ctx, id)
+	}
+	panic("not implemented")
+}
+
+func (p PodcastRepository) CreateOrUpdateEpisodes(ctx context.Context, channelID string, params []repos.CreatePodcastEpisodeParams) error {
+	if p.CreateOrUpdateEpisodesMock != nil {
+		return p.CreateOrUpdateEpisodesMock(ctx, channelID, params)
+	}
+	panic("not implemented")
+}
+
+func (p PodcastRepository) FindEpisodesByChannel(ctx context.Context, channelID string) ([]*repos.PodcastEpisode, error) {
+	if p.FindEpisodesByChannelMock != nil {
+		return p.FindEpisodesByChannelMock(ctx, channelID)
+	}
+	panic("not implemented")
+}
+
+func (p PodcastRepository) FindNewestEpisodes(ctx context.Context, count int) ([]*repos.PodcastEpisode, error) {
+	if p.FindNewestEpisodesMock != nil {
+		return p.FindNewestEpisodesMock(ctx, count)
+	}
+	panic("not implemented")
+}
+
+func (p PodcastRepository) FindEpisodesByStatus(ctx context.Context, status repos.PodcastStatus) ([]*repos.PodcastEpisode, error) {
+	if p.FindEpisodesByStatusMock != nil {
+		return p.FindEpisodesByStatusMock(ctx, status)
+	}
+	panic("not implemented")
+}
+
+func (p PodcastRepository) FindEpisodeByID(ctx context.Context, id string) (*repos.PodcastEpisode, error) {
+	if p.FindEpisodeByIDMock != nil {
+		return p.FindEpisodeByIDMock(ctx, id)
+	}
+	panic("not implemented")
+}
+
+func (p PodcastRepository) UpdateEpisode(ctx context.Context, id string, params repos.UpdatePodcastEpisodeParams) error {
+	if p.UpdateEpisodeMock != nil {
+		return p.UpdateEpisodeMock(ctx, id, params)
+	}
+	panic("not implemented")
+}
+
+func (p PodcastRepository) GetEpisodeStreamInfo(ctx context.Context, id string) (*repos.SongStreamInfo, error) {
+	if p.GetEpisodeStreamInfoMock != nil {
+		return p.GetEpisodeStreamInfoMock(ctx, id)
+	}
+	panic("not implemented")
+}
+
+func (p PodcastRepository) UpdateEpisodeWithStatus(ctx context.Context, id string, status repos.PodcastStatus, params repos.UpdatePodcastEpisodeParams) error {
+	if p.UpdateEpisodeWithStatusMock != nil {
+		return p.UpdateEpisodeWithStatusMock(ctx, id, status, params)
+	}
+	panic("not implemented")
+}
@@ -0,0 +1,50 @@
+package mockdb
+
+import (
+	"context"
+
+	"github.com/juho05/crossonic-server/repos"
+)
+
+type ScanProblemRepository struct {
+	FindAllMock       func(ctx context.Context, params repos.FindScanProblemsParams) ([]*repos.ScanProblem, error)
+	CountMock         func(ctx context.Context, params repos.FindScanProblemsParams) (int, error)
+	FindPathsMock     func(ctx context.Context, dirs []string) ([]string, error)
+	CreateAllMock     func(ctx context.Context, params []repos.CreateScanProblemParams) error
+	DeleteByPathsMock func(ctx context.Context, paths []string, types []repos.ScanProblemType) error
+}
+
+func (s ScanProblemRepository) FindAll(ctx context.Context, params repos.FindScanProblemsParams) ([]*repos.ScanProblem, error) {
+	if s.FindAllMock != nil {
+		return s.FindAllMock(ctx, params)
+	}
+	panic("not implemented")
+}
+
+func (s ScanProblemRepository) Count(ctx context.Context, params repos.FindScanProblemsParams) (int, error) {
+	if s.CountMock != nil {
+		return s.CountMock(ctx, params)
+	}
+	panic("not implemented")
+}
+
+func (s ScanProblemRepository) FindPaths(ctx context.Context, dirs []string) ([]string, error) {
+	if s.FindPathsMock != nil {
+		return s.FindPathsMock(ctx, dirs)
+	}
+	panic("not implemented")
+}
+
+func (s ScanProblemRepository) CreateAll(ctx context.Context, params []repos.CreateScanProblemParams) error {
+	if s.CreateAllMock != nil {
+		return s.CreateAllMock(ctx, params)
+	}
+	panic("not implemented")
+}
+
+func (s ScanProblemRepository) DeleteByPaths(ctx context.Context, paths []string, types []repos.ScanProblemType) error {
+	if s.DeleteByPathsMock != nil {
+		return s.DeleteByPathsMock(ctx, paths, types)
+	}
+	panic("not implemented")
+}
@@ -0,0 +1,74 @@
+package mockdb
+
+import (
+	"context"
+
+	"github.com/juho05/crossonic-server/repos"
+)
+
+type ShareRepository struct {
+	CreateMock         func(ctx context.Context, user string, params repos.CreateShareParams) (*repos.Share, error)
+	FindAllMock        func(ctx context.Context, user string) ([]*repos.Share, error)
+	FindByIDMock       func(ctx context.Context, user, id string) (*repos.Share, error)
+	FindPublicByIDMock func(ctx context.Context, id string) (*repos.Share, error)
+	GetEntriesMock     func(ctx context.Context, id string) ([]string, error)
+	UpdateMock         func(ctx context.Context, user, id string, params repos.UpdateShareParams) error
+	RegisterVisitMock  func(ctx context.Context, id string) error
+	DeleteMock         func(ctx context.Context, user, id string) error
+}
+
+func (s ShareRepository) Create(ctx context.Context, user string, params repos.CreateShareParams) (*repos.Share, error) {
+	if s.CreateMock != nil {
+		return s.CreateMock(ctx, user, params)
+	}
+	panic("not implemented")
+}
+
+func (s ShareRepository) FindAll(ctx context.Context, user string) ([]*repos.Share, error) {
+	if s.FindAllMock != nil {
+		return s.FindAllMock(ctx, user)
+	}
+	panic("not implemented")
+}
+
+func (s ShareRepository) FindByID(ctx context.Context, user, id string) (*repos.Share, error) {
+	if s.FindByIDMock != nil {
+		return s.FindByIDMock(ctx, user, id)
+	}
+	panic("not implemented")
+}
+
+func (s ShareRepository) FindPublicByID(ctx context.Context, id string) (*repos.Share, error) {
+	if s.FindPublicByIDMock != nil {
+		return s.FindPublicByIDMock(ctx, id)
+	}
+	panic("not implemented")
+}
+
+func (s ShareRepository) GetEntries(ctx context.Context, id string) ([]string, error) {
+	if s.GetEntriesMock != nil {
+		return s.GetEntriesMock(ctx, id)
+	}
+	panic("not implemented")
+}
+
+func (s ShareRepository) Update(ctx context.Context, user, id string, params repos.UpdateShareParams) error {
+	if s.UpdateMock != nil {
+		return s.UpdateMock(ctx, user, id, params)
+	}
+	panic("not implemented")
+}
+
+func (s ShareRepository) RegisterVisit(ctx context.Context, id string) error {
+	if s.RegisterVisitMock != nil {
+		return s.RegisterVisitMock(ctx, id)
+	}
+	panic("not implemented")
+}
+
+func (s ShareRepository) Delete(ctx context.Context, user, id string) error {
+	if s.DeleteMock != nil {
+		return s.DeleteMock(ctx, user, id)
+	}
+	panic("not implemented")
+}
@@ -0,0 +1,67 @@
+package mockdb
+
+import (
+	"context"
+	"time"
+
+	"github.com/juho05/crossonic-server/repos"
+)
+
+type SimilarityRepository struct {
+	FindSongCandidatesMock     func(ctx context.Context, genres []string, artistIDs []string, musicFolderIDs []int, limit int) ([]string, error)
+	GetSongCoOccurrencesMock   func(ctx context.Context, songIDs []string, window time.Duration, musicFolderIDs []int, limit int) ([]*repos.SimilarityCount, error)
+	GetArtistFeaturesMock      func(ctx context.Context, artistIDs []string, musicFolderIDs []int) ([]*repos.ArtistFeatures, error)
+	FindArtistCandidatesMock   func(ctx context.Context, genres []string, musicFolderIDs []int, limit int) ([]string, error)
+	GetArtistRelationsMock     func(ctx context.Context, artistID string, musicFolderIDs []int) ([]*repos.SimilarityCount, error)
+	GetArtistCoOccurrencesMock func(ctx context.Context, artistID string, window time.Duration, musicFolderIDs []int, limit int) ([]*repos.SimilarityCount, error)
+	GetArtistTopSongsMock      func(ctx context.Context, artistID string, musicFolderIDs []int, limit int) ([]*repos.SimilarityCount, error)
+}
+
+func (s SimilarityRepository) FindSongCandidates(ctx context.Context, genres []string, artistIDs []string, musicFolderIDs []int, limit int) ([]string, error) {
+	if s.FindSongCandidatesMock != nil {
+		return s.FindSongCandidatesMock(ctx, genres, artistIDs, musicFolderIDs, limit)
+	}
+	panic("not implemented")
+}
+
+func (s SimilarityRepository) GetSongCoOccurrences(ctx context.Context, songIDs []string, window time.Duration, musicFolderIDs []int, limit int) ([]*repos.SimilarityCount, error) {
+	if s.GetSongCoOccurrencesMock != nil {
+		return s.GetSongCoOccurrencesMock(ctx, songIDs, window, musicFolderIDs, limit)
+	}
+	panic("not implemented")
+}
+
+func (s SimilarityRepository) GetArtistFeatures(ctx context.Context, artistIDs []string, musicFolderIDs []int) ([]*repos.ArtistFeatures, error) {
+	if s.GetArtistFeaturesMock != nil {
+		return s.GetArtistFeaturesMock(ctx, artistIDs, musicFolderIDs)
+	}
+	panic("not implemented")
+}
+
+func (s SimilarityRepository) FindArtistCandidates(ctx context.Context, genres []string, musicFolderIDs []int, limit int) ([]string, error) {
+	if s.FindArtistCandidatesMock != nil {
+		return s.FindArtistCandidatesMock(ctx, genres, musicFolderIDs, limit)
+	}
+	panic("not implemented")
+}
+
+func (s SimilarityRepository) GetArtistRelations(ctx context.Context, artistID string, musicFolderIDs []int) ([]*repos.SimilarityCount, error) {
+	if s.GetArtistRelationsMock != nil {
+		return s.GetArtistRelationsMock(ctx, artistID, musicFolderIDs)
+	}
+	panic("not implemented")
+}
+
+func (s SimilarityRepository) GetArtistCoOccurrences(ctx context.Context, artistID string, window time.Duration, musicFolderIDs []int, limit int) ([]*repos.SimilarityCount, error) {
+	if s.GetArtistCoOccurrencesMock != nil {
+		return s.GetArtistCoOccurrencesMock(ctx, artistID, window, musicFolderIDs, limit)
+	}
+	panic("not implemented")
+}
+
+func (s SimilarityRepository) GetArtistTopSongs(ctx context.Context, artistID string, musicFolderIDs []int, limit int) ([]*repos.SimilarityCount, error) {
+	if s.GetArtistTopSongsMock != nil {
+		return s.GetArtistTopSongsMock(ctx, artistID, musicFolderIDs, limit)
+	}
+	panic("not implemented")
+}
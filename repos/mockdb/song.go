@@ -16,12 +16,12 @@ type SongRepository struct {
 	FindByTitleMock                                     func(ctx context.Context, title, user string, include repos.IncludeSongInfo) ([]*repos.CompleteSong, error)
 	FindAllByPathOrMBIDMock                             func(ctx context.Context, paths []string, mbids []string, include repos.IncludeSongInfo) ([]*repos.CompleteSong, error)
 	FindNonExistentIDsMock                              func(ctx context.Context, ids []string) ([]string, error)
-	FindPathsMock                                       func(ctx context.Context, updatedBefore time.Time, paginate repos.Paginate) ([]string, error)
+	FindPathsMock                                       func(ctx context.Context, updatedBefore time.Time, dirs []string, paginate repos.Paginate) ([]string, error)
 	DeleteByPathsMock                                   func(ctx context.Context, paths []string) error
 	GetStreamInfoMock                                   func(ctx context.Context, id, user string) (*repos.SongStreamInfo, error)
 	CreateAllMock                                       func(ctx context.Context, params []repos.CreateSongParams) error
 	TryUpdateAllMock                                    func(ctx context.Context, params []repos.UpdateSongAllParams) (int, error)
-	DeleteLastUpdatedBeforeMock                         func(ctx context.Context, before time.Time) error
+	DeleteLastUpdatedBeforeMock                         func(ctx context.Context, before time.Time, dirs []string) error
 	DeleteArtistConnectionsMock                         func(ctx context.Context, songIDs []string) error
 	CreateArtistConnectionsMock                         func(ctx context.Context, connections []repos.SongArtistConnection) error
 	DeleteGenreConnectionsMock                          func(ctx context.Context, songIDs []string) error
@@ -39,6 +39,17 @@ type SongRepository struct {
 	CountMock                                           func(ctx context.Context) (int, error)
 	GetMedianReplayGainMock                             func(ctx context.Context) (float64, error)
 	DeleteAllWithoutMusicFolderIDMock                   func(ctx context.Context) error
+	FindMissingReplayGainMock                           func(ctx context.Context) ([]*repos.SongAnalysisInfo, error)
+	SetComputedReplayGainMock                           func(ctx context.Context, id string, gain, peak float64) error
+	FindMissingBPMMock                                  func(ctx context.Context) ([]*repos.SongAnalysisInfo, error)
+	SetDetectedBPMMock                                  func(ctx context.Context, id string, bpm *int) error
+	FindPathsUpdatedSinceMock                           func(ctx context.Context, since time.Time) ([]string, error)
+	MarkUpdatedByPathsMock                              func(ctx context.Context, paths []string) error
+	FindFingerprintsMock                                func(ctx context.Context, dirs []string) ([]*repos.SongFingerprint, error)
+	FindByFingerprintsMock                              func(ctx context.Context, fingerprints []string) ([]*repos.SongFingerprint, error)
+	SetFingerprintsMock                                 func(ctx context.Context, params []repos.SetSongFingerprintParams) error
+	ResetComputedReplayGainMock                         func(ctx context.Context, songIDs []string) error
+	ResetDetectedBPMMock                                func(ctx context.Context, songIDs []string) error
 }
 
 func (s SongRepository) FindByID(ctx context.Context, id, user string, include repos.IncludeSongInfo) (*repos.CompleteSong, error) {
@@ -97,9 +108,9 @@ func (s SongRepository) FindNonExistentIDs(ctx context.Context, ids []string) ([
 	panic("not implemented")
 }
 
-func (s SongRepository) FindPaths(ctx context.Context, updatedBefore time.Time, paginate repos.Paginate) ([]string, error) {
+func (s SongRepository) FindPaths(ctx context.Context, updatedBefore time.Time, dirs []string, paginate repos.Paginate) ([]string, error) {
 	if s.FindByPathMock != nil {
-		return s.FindPathsMock(ctx, updatedBefore, paginate)
+		return s.FindPathsMock(ctx, updatedBefore, dirs, paginate)
 	}
 	panic("not implemented")
 }
@@ -132,9 +143,9 @@ func (s SongRepository) TryUpdateAll(ctx context.Context, params []repos.UpdateS
 	panic("not implemented")
 }
 
-func (s SongRepository) DeleteLastUpdatedBefore(ctx context.Context, before time.Time) error {
+func (s SongRepository) DeleteLastUpdatedBefore(ctx context.Context, before time.Time, dirs []string) error {
 	if s.DeleteLastUpdatedBeforeMock != nil {
-		return s.DeleteLastUpdatedBeforeMock(ctx, before)
+		return s.DeleteLastUpdatedBeforeMock(ctx, before, dirs)
 	}
 	panic("not implemented")
 }
@@ -257,3 +268,80 @@ func (s SongRepository) DeleteAllWithoutMusicFolderID(ctx context.Context) error
 	}
 	panic("not implemented")
 }
+
+func (s SongRepository) FindMissingReplayGain(ctx context.Context) ([]*repos.SongAnalysisInfo, error) {
+	if s.FindMissingReplayGainMock != nil {
+		return s.FindMissingReplayGainMock(ctx)
+	}
+	panic("not implemented")
+}
+
+func (s SongRepository) SetComputedReplayGain(ctx context.Context, id string, gain, peak float64) error {
+	if s.SetComputedReplayGainMock != nil {
+		return s.SetComputedReplayGainMock(ctx, id, gain, peak)
+	}
+	panic("not implemented")
+}
+
+func (s SongRepository) FindMissingBPM(ctx context.Context) ([]*repos.SongAnalysisInfo, error) {
+	if s.FindMissingBPMMock != nil {
+		return s.FindMissingBPMMock(ctx)
+	}
+	panic("not implemented")
+}
+
+func (s SongRepository) SetDetectedBPM(ctx context.Context, id string, bpm *int) error {
+	if s.SetDetectedBPMMock != nil {
+		return s.SetDetectedBPMMock(ctx, id, bpm)
+	}
+	panic("not implemented")
+}
+
+func (s SongRepository) FindPathsUpdatedSince(ctx context.Context, since time.Time) ([]string, error) {
+	if s.FindPathsUpdatedSinceMock != nil {
+		return s.FindPathsUpdatedSinceMock(ctx, since)
+	}
+	panic("not implemented")
+}
+
+func (s SongRepository) MarkUpdatedByPaths(ctx context.Context, paths []string) error {
+	if s.MarkUpdatedByPathsMock != nil {
+		return s.MarkUpdatedByPathsMock(ctx, paths)
+	}
+	panic("not implemented")
+}
+
+func (s SongRepository) FindFingerprints(ctx context.Context, dirs []string) ([]*repos.SongFingerprint, error) {
+	if s.FindFingerprintsMock != nil {
+		return s.FindFingerprintsMock(ctx, dirs)
+	}
+	panic("not implemented")
+}
+
+func (s SongRepository) FindByFingerprints(ctx context.Context, fingerprints []string) ([]*repos.SongFingerprint, error) {
+	if s.FindByFingerprintsMock != nil {
+		return s.FindByFingerprintsMock(ctx, fingerprints)
+	}
+	panic("not implemented")
+}
+
+func (s SongRepository) SetFingerprints(ctx context.Context, params []repos.SetSongFingerprintParams) error {
+	if s.SetFingerprintsMock != nil {
+		return s.SetFingerprintsMock(ctx, params)
+	}
+	panic("not implemented")
+}
+
+func (s SongRepository) ResetComputedReplayGain(ctx context.Context, songIDs []string) error {
+	if s.ResetComputedReplayGainMock != nil {
+		return s.ResetComputedReplayGainMock(ctx, songIDs)
+	}
+	panic("not implemented")
+}
+
+func (s SongRepository) ResetDetectedBPM(ctx context.Context, songIDs []string) error {
+	if s.ResetDetectedBPMMock != nil {
+		return s.ResetDetectedBPMMock(ctx, songIDs)
+	}
+	panic("not implemented")
+}
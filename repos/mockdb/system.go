@@ -3,16 +3,21 @@ package mockdb
 import (
 	"context"
 	"time"
+
+	"github.com/juho05/crossonic-server/repos"
 )
 
 type SystemRepository struct {
-	InstanceIDMock         func(ctx context.Context) (string, error)
-	LastScanMock           func(ctx context.Context) (time.Time, error)
-	SetLastScanMock        func(ctx context.Context, t time.Time) error
-	NeedsFullScanMock      func(ctx context.Context) (bool, error)
-	ResetNeedsFullScanMock func(ctx context.Context) error
-	SetMusicDirConfigMock  func(ctx context.Context, config string) error
-	MusicDirConfigMock     func(ctx context.Context) (string, error)
+	InstanceIDMock           func(ctx context.Context) (string, error)
+	LastScanMock             func(ctx context.Context) (time.Time, error)
+	SetLastScanMock          func(ctx context.Context, t time.Time) error
+	NeedsFullScanMock        func(ctx context.Context) (bool, error)
+	ResetNeedsFullScanMock   func(ctx context.Context) error
+	SetMusicDirConfigMock    func(ctx context.Context, config string) error
+	MusicDirConfigMock       func(ctx context.Context) (string, error)
+	ScanCheckpointMock       func(ctx context.Context) (*repos.ScanCheckpoint, error)
+	SetScanCheckpointMock    func(ctx context.Context, checkpoint repos.ScanCheckpoint) error
+	DeleteScanCheckpointMock func(ctx context.Context) error
 }
 
 func (s SystemRepository) SetMusicDirConfig(ctx context.Context, config string) error {
@@ -63,3 +68,24 @@ func (s SystemRepository) ResetNeedsFullScan(ctx context.Context) error {
 	}
 	panic("not implemented")
 }
+
+func (s SystemRepository) ScanCheckpoint(ctx context.Context) (*repos.ScanCheckpoint, error) {
+	if s.ScanCheckpointMock != nil {
+		return s.ScanCheckpointMock(ctx)
+	}
+	panic("not implemented")
+}
+
+func (s SystemRepository) SetScanCheckpoint(ctx context.Context, checkpoint repos.ScanCheckpoint) error {
+	if s.SetScanCheckpointMock != nil {
+		return s.SetScanCheckpointMock(ctx, checkpoint)
+	}
+	panic("not implemented")
+}
+
+func (s SystemRepository) DeleteScanCheckpoint(ctx context.Context) error {
+	if s.DeleteScanCheckpointMock != nil {
+		return s.DeleteScanCheckpointMock(ctx)
+	}
+	panic("not implemented")
+}
@@ -30,10 +30,15 @@ type MusicFolderRepository interface {
 	DeleteMusicFoldersNotIn(ctx context.Context, keepIDs []int) error
 
 	DeleteAllUserAssociations(ctx context.Context) error
+	// DeleteDefaultUserAssociations removes the music folder associations of all users without custom music folders.
+	DeleteDefaultUserAssociations(ctx context.Context) error
+	// DeleteUserAssociationsOfUser removes all music folder associations of the user.
+	DeleteUserAssociationsOfUser(ctx context.Context, user string) error
 	CreateUserAssociations(ctx context.Context, folderId int, users []string) error
 
 	GetAllArtistAsssociations(ctx context.Context) ([]ArtistMusicFolderAssociation, error)
 	DeleteAllArtistAssociations(ctx context.Context) error
+	DeleteArtistAssociations(ctx context.Context, artistIDs []string) error
 	CreateArtistAssociations(ctx context.Context, associations []ArtistMusicFolderAssociation) error
 	DeleteArtistAssociationsWithoutSongs(ctx context.Context) error
 
@@ -0,0 +1,41 @@
+package repos
+
+import (
+	"context"
+	"time"
+)
+
+// models
+
+type PlayQueue struct {
+	User string `db:"user_name"`
+	// CurrentIndex is the index of the currently playing song in the queue.
+	CurrentIndex *int       `db:"current_index"`
+	Position     DurationMS `db:"position_ms"`
+	Changed      time.Time  `db:"changed"`
+	ChangedBy    string     `db:"changed_by"`
+}
+
+// params
+
+type SavePlayQueueParams struct {
+	SongIDs      []string
+	CurrentIndex *int
+	Position     time.Duration
+	ChangedBy    string
+}
+
+type PlayQueueRepository interface {
+	// Get returns the play queue of the user.
+	// If the user has not saved a play queue, ErrNotFound will be returned.
+	Get(ctx context.Context, user string) (*PlayQueue, error)
+	// GetSongIDs returns the song IDs of the play queue of the user in queue order.
+	GetSongIDs(ctx context.Context, user string) ([]string, error)
+	// Save replaces the play queue of the user.
+	Save(ctx context.Context, user string, params SavePlayQueueParams) error
+	// Delete removes the play queue of the user.
+	Delete(ctx context.Context, user string) error
+	// DeleteMissingSongs removes all queue entries of songs that no longer exist
+	// and moves the current index of affected queues accordingly.
+	DeleteMissingSongs(ctx context.Context) error
+}
@@ -0,0 +1,118 @@
+package repos
+
+import (
+	"context"
+	"time"
+)
+
+// models
+
+type PodcastStatus string
+
+const (
+	PodcastStatusNew         PodcastStatus = "new"
+	PodcastStatusDownloading PodcastStatus = "downloading"
+	PodcastStatusCompleted   PodcastStatus = "completed"
+	PodcastStatusError       PodcastStatus = "error"
+	PodcastStatusDeleted     PodcastStatus = "deleted"
+	PodcastStatusSkipped     PodcastStatus = "skipped"
+)
+
+type PodcastChannel struct {
+	ID           string        `db:"id"`
+	URL          string        `db:"url"`
+	Title        *string       `db:"title"`
+	Description  *string       `db:"description"`
+	ImageURL     *string       `db:"image_url"`
+	Status       PodcastStatus `db:"status"`
+	ErrorMessage *string       `db:"error_message"`
+	Created      time.Time     `db:"created"`
+	Updated      time.Time     `db:"updated"`
+	LastRefresh  *time.Time    `db:"last_refresh"`
+}
+
+type PodcastEpisode struct {
+	ID           string         `db:"id"`
+	ChannelID    string         `db:"channel_id"`
+	GUID         string         `db:"guid"`
+	Title        string         `db:"title"`
+	Description  *string        `db:"description"`
+	PublishDate  *time.Time     `db:"publish_date"`
+	URL          string         `db:"url"`
+	Status       PodcastStatus  `db:"status"`
+	ErrorMessage *string        `db:"error_message"`
+	Path         *string        `db:"path"`
+	Size         *int64         `db:"size"`
+	ContentType  *string        `db:"content_type"`
+	BitRate      *int           `db:"bit_rate"`
+	Duration     NullDurationMS `db:"duration_ms"`
+	ChannelCount *int           `db:"channel_count"`
+	Created      time.Time      `db:"created"`
+	Updated      time.Time      `db:"updated"`
+}
+
+// params
+
+type UpdatePodcastChannelParams struct {
+	Title        Optional[*string]
+	Description  Optional[*string]
+	ImageURL     Optional[*string]
+	Status       Optional[PodcastStatus]
+	ErrorMessage Optional[*string]
+	LastRefresh  Optional[*time.Time]
+}
+
+type CreatePodcastEpisodeParams struct {
+	GUID        string
+	Title       string
+	Description *string
+	PublishDate *time.Time
+	URL         string
+	Size        *int64
+	ContentType *string
+	Duration    NullDurationMS
+}
+
+type UpdatePodcastEpisodeParams struct {
+	Status       Optional[PodcastStatus]
+	ErrorMessage Optional[*string]
+	Path         Optional[*string]
+	Size         Optional[*int64]
+	ContentType  Optional[*string]
+	BitRate      Optional[*int]
+	Duration     Optional[NullDurationMS]
+	ChannelCount Optional[*int]
+}
+
+type PodcastRepository interface {
+	// CreateChannel creates a new podcast channel with status new for the feed at url.
+	// If a channel with the same url already exists, ErrExists will be returned.
+	CreateChannel(ctx context.Context, url string) (*PodcastChannel, error)
+	// UpdateChannel updates an existing podcast channel.
+	// If no channel with the provided id is found, ErrNotFound will be returned.
+	UpdateChannel(ctx context.Context, id string, params UpdatePodcastChannelParams) error
+	FindAllChannels(ctx context.Context) ([]*PodcastChannel, error)
+	FindChannelByID(ctx context.Context, id string) (*PodcastChannel, error)
+	// DeleteChannel removes the channel and all of its episodes.
+	// If no channel with the provided id is found, ErrNotFound will be returned.
+	DeleteChannel(ctx context.Context, id string) error
+
+	// CreateOrUpdateEpisodes inserts new episodes of the channel and updates the feed metadata of
+	// already known episodes (identified by their GUID). The download state of existing episodes is preserved.
+	CreateOrUpdateEpisodes(ctx context.Context, channelID string, params []CreatePodcastEpisodeParams) error
+	// FindEpisodesByChannel returns all episodes of the channel ordered by publish date (newest first).
+	FindEpisodesByChannel(ctx context.Context, channelID string) ([]*PodcastEpisode, error)
+	// FindNewestEpisodes returns the newest episodes of all channels that are not deleted.
+	FindNewestEpisodes(ctx context.Context, count int) ([]*PodcastEpisode, error)
+	FindEpisodesByStatus(ctx context.Context, status PodcastStatus) ([]*PodcastEpisode, error)
+	FindEpisodeByID(ctx context.Context, id string) (*PodcastEpisode, error)
+	// UpdateEpisode updates an existing podcast episode.
+	// If no episode with the provided id is found, ErrNotFound will be returned.
+	UpdateEpisode(ctx context.Context, id string, params UpdatePodcastEpisodeParams) error
+	// UpdateEpisodeWithStatus updates an existing podcast episode if its status is still status.
+	// If no episode with the provided id and status is found, ErrNotFound will be returned.
+	UpdateEpisodeWithStatus(ctx context.Context, id string, status PodcastStatus, params UpdatePodcastEpisodeParams) error
+	// GetEpisodeStreamInfo returns the stream info of a downloaded episode.
+	// If the episode does not exist or is not downloaded, ErrNotFound will be returned.
+	GetEpisodeStreamInfo(ctx context.Context, id string) (*SongStreamInfo, error)
+}
@@ -101,10 +101,20 @@ func (a albumRepository) FindAll(ctx context.Context, params repos.FindAlbumPara
 				WHERE songs.album_id = albums.id AND lower(song_genre.genre_name) IN (?)
 			))`, genres)
 	}
+	if len(params.RecordLabels) > 0 {
+		where.And("?", genStringListContainsCondition("albums.record_labels", params.RecordLabels))
+	}
+	if len(params.ReleaseTypes) > 0 {
+		where.And("?", genStringListContainsCondition("albums.release_types", params.ReleaseTypes))
+	}
+	if params.OnlyCompilations {
+		where.And("(albums.is_compilation = true)")
+	}
 	if params.MusicFolderIDs != nil {
 		where.And("?", genOneOfMusicFoldersCondition("albums", params.MusicFolderIDs))
 	}
 
+	joins := bqb.Optional("")
 	orderBy := bqb.Optional("ORDER BY")
 	switch params.SortBy {
 	case repos.FindAlbumSortByName:
@@ -152,10 +162,21 @@ func (a albumRepository) FindAll(ctx context.Context, params repos.FindAlbumPara
 			return nil, repos.NewError("find all albums ordered by last played requires include.PlayInfo and include.User to be set", repos.ErrInvalidParams, nil)
 		}
 		orderBy.Comma("plays.last_played DESC NULLS LAST, lower(albums.name)")
+	case repos.FindAlbumSortByArtist:
+		orderBy.Comma(`(
+			SELECT lower(artists.name) FROM album_artist
+			JOIN artists ON artists.id = album_artist.artist_id
+			WHERE album_artist.album_id = albums.id ORDER BY album_artist.index LIMIT 1
+		) NULLS LAST, albums.original_date, lower(albums.name)`)
+	case repos.FindAlbumSortByRecentAll:
+		joins.Space(`JOIN (
+			SELECT album_id, MAX(time) as last_played FROM scrobbles WHERE album_id IS NOT NULL AND now_playing = false AND (duration_ms IS NULL OR duration_ms >= 240000 OR duration_ms >= song_duration_ms*0.5) GROUP BY album_id
+		) all_plays ON all_plays.album_id = albums.id`)
+		orderBy.Comma("all_plays.last_played DESC, lower(albums.name)")
 	}
 	orderBy.Comma("albums.id")
 
-	q = bqb.New("? ? ?", q, where, orderBy)
+	q = bqb.New("? ? ? ?", q, joins, where, orderBy)
 	params.Paginate.Apply(q)
 	return execAlbumSelectMany(ctx, a.db, q, include)
 }
@@ -196,6 +217,16 @@ func (a albumRepository) GetTracks(ctx context.Context, albumID string, include
 	return execSongSelectMany(ctx, a.db, q, include)
 }
 
+func (a albumRepository) FindIDsMissingReplayGain(ctx context.Context) ([]string, error) {
+	q := bqb.New("SELECT albums.id FROM albums WHERE albums.replay_gain IS NULL AND albums.computed_replay_gain IS NULL")
+	return selectQuery[string](ctx, a.db, q)
+}
+
+func (a albumRepository) SetComputedReplayGain(ctx context.Context, id string, gain, peak float64) error {
+	q := bqb.New("UPDATE albums SET computed_replay_gain = ?, computed_replay_gain_peak = ? WHERE id = ?", gain, peak, id)
+	return executeQueryExpectAffectedRows(ctx, a.db, q)
+}
+
 func (a albumRepository) Star(ctx context.Context, user, albumID string) error {
 	q := bqb.New("INSERT INTO album_stars (album_id, user_name, created) VALUES (?, ?, NOW()) ON CONFLICT(album_id,user_name) DO NOTHING", albumID, user)
 	return executeQuery(ctx, a.db, q)
@@ -226,6 +257,26 @@ func (a albumRepository) SetInfo(ctx context.Context, albumID string, params rep
 	return executeQueryExpectAffectedRows(ctx, a.db, q)
 }
 
+func (a albumRepository) FindOutdatedInfo(ctx context.Context, updatedBefore time.Time, limit int) ([]*repos.OutdatedAlbumInfo, error) {
+	q := bqb.New(`SELECT albums.id, albums.name, albums.music_brainz_id, (
+		SELECT artists.name FROM album_artist INNER JOIN artists ON artists.id = album_artist.artist_id
+		WHERE album_artist.album_id = albums.id ORDER BY album_artist.index LIMIT 1
+	) AS artist_name FROM albums WHERE albums.info_updated < ? ORDER BY albums.info_updated LIMIT ?`, updatedBefore, limit)
+	return selectQuery[*repos.OutdatedAlbumInfo](ctx, a.db, q)
+}
+
+func (a albumRepository) FindSimilar(ctx context.Context, albumID string, musicFolderIDs []int, limit int, include repos.IncludeAlbumInfo) ([]*repos.CompleteAlbum, error) {
+	q := bqb.New("SELECT ? FROM albums ?", genAlbumSelectList(include), genAlbumJoins(include))
+	q.Space(`INNER JOIN (
+		SELECT album_artist.album_id, MIN(similar.index) AS index FROM album_artist
+		INNER JOIN (?) similar ON similar.id = album_artist.artist_id
+		GROUP BY album_artist.album_id
+	) similar_albums ON similar_albums.album_id = albums.id`, genSimilarArtistsQuery(bqb.New("SELECT artist_id FROM album_artist WHERE album_id = ?", albumID)))
+	q.Space("WHERE albums.id != ? AND ?", albumID, genOneOfMusicFoldersCondition("albums", musicFolderIDs))
+	q.Space("ORDER BY similar_albums.index, albums.original_date DESC, albums.release_date DESC, albums.id LIMIT ?", limit)
+	return execAlbumSelectMany(ctx, a.db, q, include)
+}
+
 func (a albumRepository) GetAllArtistConnections(ctx context.Context) ([]repos.AlbumArtistConnection, error) {
 	q := bqb.New("SELECT album_artist.album_id, album_artist.artist_id, album_artist.index, artists.name as artist_name FROM album_artist INNER JOIN artists ON artists.id = album_artist.artist_id ORDER BY album_artist.index")
 	return selectQuery[repos.AlbumArtistConnection](ctx, a.db, q)
@@ -236,6 +287,15 @@ func (a albumRepository) RemoveAllArtistConnections(ctx context.Context) error {
 	return executeQuery(ctx, a.db, q)
 }
 
+func (a albumRepository) DeleteArtistConnections(ctx context.Context, albumIDs []string) error {
+	return a.tx(ctx, func(a albumRepository) error {
+		return execBatch(albumIDs, func(albumIDs []string) error {
+			q := bqb.New("DELETE FROM album_artist WHERE album_artist.album_id IN (?)", albumIDs)
+			return executeQuery(ctx, a.db, q)
+		})
+	})
+}
+
 func (a albumRepository) CreateArtistConnections(ctx context.Context, connections []repos.AlbumArtistConnection) error {
 	return a.tx(ctx, func(a albumRepository) error {
 		return execBatch(connections, func(connections []repos.AlbumArtistConnection) error {
@@ -264,6 +264,94 @@ func TestAlbumRepository(t *testing.T) {
 			assert.NotContains(t, ids, albumWithoutGenre)
 		})
 
+		t.Run("filter by record label, release type and compilation", func(t *testing.T) {
+			folderID := thCreateMusicFolder(t, db, user)
+			plain := thCreateAlbum(t, db, folderID)
+			labeled, err := repo.Create(ctx, repos.CreateAlbumParams{
+				Name:          "Labeled Album",
+				RecordLabels:  repos.StringList{"Some Label", "Warp"},
+				ReleaseTypes:  repos.StringList{"ep", "live"},
+				MusicFolderID: folderID,
+			})
+			require.NoError(t, err)
+			compilation, err := repo.Create(ctx, repos.CreateAlbumParams{
+				Name:          "Compilation",
+				ReleaseTypes:  repos.StringList{"album", "compilation"},
+				IsCompilation: util.ToPtr(true),
+				MusicFolderID: folderID,
+			})
+			require.NoError(t, err)
+
+			find := func(params repos.FindAlbumParams) []string {
+				params.MusicFolderIDs = []int{folderID}
+				results, err := repo.FindAll(ctx, params, repos.IncludeAlbumInfoBare())
+				require.NoErrorf(t, err, "find all: %v", err)
+				return util.Map(results, func(a *repos.CompleteAlbum) string { return a.ID })
+			}
+
+			assert.Equal(t, []string{labeled}, find(repos.FindAlbumParams{RecordLabels: []string{"warp"}}))
+			assert.Equal(t, []string{labeled}, find(repos.FindAlbumParams{ReleaseTypes: []string{"EP"}}))
+			assert.ElementsMatch(t, []string{labeled, compilation}, find(repos.FindAlbumParams{ReleaseTypes: []string{"live", "compilation"}}))
+			assert.Equal(t, []string{compilation}, find(repos.FindAlbumParams{OnlyCompilations: true}))
+			assert.Contains(t, find(repos.FindAlbumParams{}), plain)
+		})
+
+		t.Run("sort by artist", func(t *testing.T) {
+			folderID := thCreateMusicFolder(t, db, user)
+			byB := thCreateAlbum(t, db, folderID)
+			byA := thCreateAlbum(t, db, folderID)
+			withoutArtist := thCreateAlbum(t, db, folderID)
+			artistA, err := db.Artist().Create(ctx, repos.CreateArtistParams{Name: "a artist"})
+			require.NoError(t, err)
+			artistB, err := db.Artist().Create(ctx, repos.CreateArtistParams{Name: "B artist"})
+			require.NoError(t, err)
+			_, err = db.db.ExecContext(ctx, "INSERT INTO album_artist (album_id, artist_id, index) VALUES ($1, $2, 0), ($3, $4, 0)", byA, artistA, byB, artistB)
+			require.NoError(t, err)
+
+			results, err := repo.FindAll(ctx, repos.FindAlbumParams{
+				SortBy:         repos.FindAlbumSortByArtist,
+				MusicFolderIDs: []int{folderID},
+			}, repos.IncludeAlbumInfoBare())
+			require.NoErrorf(t, err, "find all: %v", err)
+			assert.Equal(t, []string{byA, byB, withoutArtist}, util.Map(results, func(a *repos.CompleteAlbum) string { return a.ID }))
+		})
+
+		t.Run("sort by recently played by anyone", func(t *testing.T) {
+			folderID := thCreateMusicFolder(t, db, user)
+			playedEarlier := thCreateAlbum(t, db, folderID)
+			playedLater := thCreateAlbum(t, db, folderID)
+			notPlayed := thCreateAlbum(t, db, folderID)
+			songEarlier := thCreateSong(t, db, &playedEarlier, folderID)
+			songLater := thCreateSong(t, db, &playedLater, folderID)
+			require.NoError(t, db.Scrobble().CreateMultiple(ctx, []repos.CreateScrobbleParams{
+				{
+					User:         user,
+					SongID:       songEarlier,
+					AlbumID:      &playedEarlier,
+					Time:         time.Now().Add(-2 * time.Hour),
+					SongDuration: repos.NewDurationMS(300000),
+					Duration:     repos.NullDurationMS{Duration: repos.NewDurationMS(300000), Valid: true},
+				},
+				{
+					User:         user2,
+					SongID:       songLater,
+					AlbumID:      &playedLater,
+					Time:         time.Now().Add(-time.Hour),
+					SongDuration: repos.NewDurationMS(300000),
+					Duration:     repos.NullDurationMS{Duration: repos.NewDurationMS(300000), Valid: true},
+				},
+			}))
+
+			results, err := repo.FindAll(ctx, repos.FindAlbumParams{
+				SortBy:         repos.FindAlbumSortByRecentAll,
+				MusicFolderIDs: []int{folderID},
+			}, repos.IncludeAlbumInfoBare())
+			require.NoErrorf(t, err, "find all: %v", err)
+			ids := util.Map(results, func(a *repos.CompleteAlbum) string { return a.ID })
+			assert.Equal(t, []string{playedLater, playedEarlier}, ids)
+			assert.NotContains(t, ids, notPlayed)
+		})
+
 		t.Run("sort by rating requires annotations and user", func(t *testing.T) {
 			_, err := repo.FindAll(ctx, repos.FindAlbumParams{SortBy: repos.FindAlbumSortByRating}, repos.IncludeAlbumInfoBare())
 			assert.ErrorIs(t, err, repos.ErrInvalidParams)
@@ -786,6 +874,29 @@ func TestAlbumRepository(t *testing.T) {
 			_, err := repo.GetInfo(ctx, isolatedAlbum, user)
 			assert.ErrorIs(t, err, repos.ErrNotFound)
 		})
+
+		t.Run("find outdated info", func(t *testing.T) {
+			withoutInfo := thCreateAlbum(t, db, folderID)
+			artistID := thCreateArtist(t, db)
+			require.NoError(t, repo.CreateArtistConnections(ctx, []repos.AlbumArtistConnection{
+				{AlbumID: albumID, ArtistID: artistID, Index: 0},
+			}))
+
+			outdated, err := repo.FindOutdatedInfo(ctx, time.Now().Add(time.Hour), 1000)
+			require.NoErrorf(t, err, "find outdated info: %v", err)
+			ids := util.Map(outdated, func(a *repos.OutdatedAlbumInfo) string { return a.ID })
+			assert.Contains(t, ids, albumID)
+			assert.NotContains(t, ids, withoutInfo)
+			for _, a := range outdated {
+				if a.ID == albumID {
+					assert.NotNil(t, a.ArtistName)
+				}
+			}
+
+			outdated, err = repo.FindOutdatedInfo(ctx, time.Now().Add(-time.Hour), 1000)
+			require.NoErrorf(t, err, "find outdated info: %v", err)
+			assert.NotContains(t, util.Map(outdated, func(a *repos.OutdatedAlbumInfo) string { return a.ID }), albumID)
+		})
 	})
 
 	t.Run("GetAllArtistConnections", func(t *testing.T) {
@@ -828,6 +939,22 @@ func TestAlbumRepository(t *testing.T) {
 		assert.False(t, thExists(t, db, "album_artist", map[string]any{"album_id": albumID, "artist_id": artistID}))
 	})
 
+	t.Run("DeleteArtistConnections", func(t *testing.T) {
+		folderID := thCreateMusicFolder(t, db, user)
+		album1 := thCreateAlbum(t, db, folderID)
+		album2 := thCreateAlbum(t, db, folderID)
+		artistID := thCreateArtist(t, db)
+		require.NoError(t, repo.CreateArtistConnections(ctx, []repos.AlbumArtistConnection{
+			{AlbumID: album1, ArtistID: artistID, Index: 0},
+			{AlbumID: album2, ArtistID: artistID, Index: 0},
+		}))
+
+		err := repo.DeleteArtistConnections(ctx, []string{album1})
+		require.NoErrorf(t, err, "delete artist connections: %v", err)
+		assert.False(t, thExists(t, db, "album_artist", map[string]any{"album_id": album1, "artist_id": artistID}))
+		assert.True(t, thExists(t, db, "album_artist", map[string]any{"album_id": album2, "artist_id": artistID}))
+	})
+
 	t.Run("GetAlternateVersions", func(t *testing.T) {
 		t.Run("finds alternate by matching music brainz id", func(t *testing.T) {
 			folderID := thCreateMusicFolder(t, db, user)
@@ -63,6 +63,9 @@ func (a artistRepository) FindByNames(ctx context.Context, names []string, inclu
 }
 
 func (a artistRepository) FindAll(ctx context.Context, params repos.FindArtistsParams, include repos.IncludeArtistInfo) ([]*repos.CompleteArtist, error) {
+	if params.IDs != nil && len(params.IDs) == 0 {
+		return []*repos.CompleteArtist{}, nil
+	}
 	q := bqb.New("SELECT ? FROM artists ?", genArtistSelectList(include), genArtistJoins(include))
 	where := bqb.Optional("WHERE")
 	if params.OnlyAlbumArtists {
@@ -77,6 +80,9 @@ func (a artistRepository) FindAll(ctx context.Context, params repos.FindArtistsP
 	if params.MusicFolderIDs != nil {
 		where.And("?", genArtistInMusicFolderCondition("artists", params.MusicFolderIDs))
 	}
+	if params.IDs != nil {
+		where.And("artists.id IN (?)", params.IDs)
+	}
 	q = bqb.New("? ? ORDER BY lower(artists.name), artists.id", q, where)
 	return selectQuery[*repos.CompleteArtist](ctx, a.db, q)
 }
@@ -169,8 +175,52 @@ func (a artistRepository) GetInfo(ctx context.Context, artistID, user string) (*
 }
 
 func (a artistRepository) SetInfo(ctx context.Context, artistID string, params repos.SetArtistInfo) error {
-	q := bqb.New("UPDATE artists SET info_updated=NOW(), biography=?, lastfm_url=?, lastfm_mbid=? WHERE id = ?", params.Biography, params.LastFMURL, params.LastFMMBID, artistID)
-	return executeQueryExpectAffectedRows(ctx, a.db, q)
+	return a.tx(ctx, func(a artistRepository) error {
+		q := bqb.New("UPDATE artists SET info_updated=NOW(), biography=?, lastfm_url=?, lastfm_mbid=? WHERE id = ?", params.Biography, params.LastFMURL, params.LastFMMBID, artistID)
+		err := executeQueryExpectAffectedRows(ctx, a.db, q)
+		if err != nil {
+			return fmt.Errorf("update info: %w", err)
+		}
+
+		err = executeQuery(ctx, a.db, bqb.New("DELETE FROM artist_similar WHERE artist_id = ?", artistID))
+		if err != nil {
+			return fmt.Errorf("delete old similar artists: %w", err)
+		}
+		if len(params.SimilarArtists) == 0 {
+			return nil
+		}
+		valueList := bqb.Optional("")
+		for i, s := range params.SimilarArtists {
+			valueList.Comma("(?,?,?,?,?,?)", artistID, i, s.Name, " "+util.NormalizeText(s.Name)+" ", s.MusicBrainzID, s.Match)
+		}
+		q = bqb.New("INSERT INTO artist_similar (artist_id,index,name,search_text,music_brainz_id,match) VALUES ?", valueList)
+		err = executeQuery(ctx, a.db, q)
+		if err != nil {
+			return fmt.Errorf("insert similar artists: %w", err)
+		}
+		return nil
+	})
+}
+
+func (a artistRepository) FindOutdatedInfo(ctx context.Context, updatedBefore time.Time, limit int) ([]*repos.OutdatedArtistInfo, error) {
+	q := bqb.New("SELECT artists.id, artists.name, artists.music_brainz_id FROM artists WHERE artists.info_updated < ? ORDER BY artists.info_updated LIMIT ?", updatedBefore, limit)
+	return selectQuery[*repos.OutdatedArtistInfo](ctx, a.db, q)
+}
+
+func (a artistRepository) FindSimilar(ctx context.Context, artistID string, musicFolderIDs []int, limit int, include repos.IncludeArtistInfo) ([]*repos.CompleteArtist, error) {
+	q := bqb.New("SELECT ? FROM artists ?", genArtistSelectList(include), genArtistJoins(include))
+	q.Space("INNER JOIN (?) similar ON similar.id = artists.id", genSimilarArtistsQuery(bqb.New("?", artistID)))
+	q.Space("WHERE ? ORDER BY similar.index LIMIT ?", genArtistInMusicFolderCondition("artists", musicFolderIDs), limit)
+	return selectQuery[*repos.CompleteArtist](ctx, a.db, q)
+}
+
+// genSimilarArtistsQuery selects the ids of all local artists matching one of the similar artists of the artists selected by artistIDs
+// together with the index of the best match.
+func genSimilarArtistsQuery(artistIDs *bqb.Query) *bqb.Query {
+	return bqb.New(`SELECT artists.id, MIN(artist_similar.index) AS index FROM artist_similar
+		INNER JOIN artists ON artists.music_brainz_id = artist_similar.music_brainz_id OR artists.search_text = artist_similar.search_text
+		WHERE artist_similar.artist_id IN (?) AND artists.id NOT IN (?)
+		GROUP BY artists.id`, artistIDs, artistIDs)
 }
 
 func (a artistRepository) MigrateAnnotations(ctx context.Context, oldId, newId string) error {
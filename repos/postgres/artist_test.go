@@ -220,6 +220,31 @@ func TestArtistRepository(t *testing.T) {
 			assert.NotContains(t, util.Map(results, func(a *repos.CompleteArtist) string { return a.ID }), a)
 		})
 
+		t.Run("ids filters correctly", func(t *testing.T) {
+			folderID := thCreateMusicFolder(t, db, user)
+			otherFolderID := thCreateMusicFolder(t, db, user)
+			a1 := thCreateArtist(t, db)
+			a2 := thCreateArtist(t, db)
+			a3 := thCreateArtist(t, db)
+			thAssociateMusicFolderArtist(t, db, a1, folderID)
+			thAssociateMusicFolderArtist(t, db, a2, folderID)
+			thAssociateMusicFolderArtist(t, db, a3, otherFolderID)
+
+			results, err := repo.FindAll(ctx, repos.FindArtistsParams{
+				IDs:            []string{a1, a3},
+				MusicFolderIDs: []int{folderID},
+			}, repos.IncludeArtistInfoBare())
+			require.NoErrorf(t, err, "find all: %v", err)
+			assert.Equal(t, []string{a1}, util.Map(results, func(a *repos.CompleteArtist) string { return a.ID }))
+
+			results, err = repo.FindAll(ctx, repos.FindArtistsParams{
+				IDs:            []string{},
+				MusicFolderIDs: []int{folderID},
+			}, repos.IncludeArtistInfoBare())
+			require.NoErrorf(t, err, "find all: %v", err)
+			assert.Empty(t, results)
+		})
+
 		t.Run("onlyAlbumArtists filters artists without albums", func(t *testing.T) {
 			folderID := thCreateMusicFolder(t, db, user)
 			albumArtist := thCreateArtist(t, db)
@@ -410,6 +435,72 @@ func TestArtistRepository(t *testing.T) {
 			_, err := repo.GetInfo(ctx, isolatedArtist, user)
 			assert.ErrorIs(t, err, repos.ErrNotFound)
 		})
+
+		t.Run("find outdated info", func(t *testing.T) {
+			withoutInfo := thCreateArtist(t, db)
+
+			outdated, err := repo.FindOutdatedInfo(ctx, time.Now().Add(time.Hour), 1000)
+			require.NoErrorf(t, err, "find outdated info: %v", err)
+			ids := util.Map(outdated, func(a *repos.OutdatedArtistInfo) string { return a.ID })
+			assert.Contains(t, ids, artistID)
+			assert.NotContains(t, ids, withoutInfo)
+
+			outdated, err = repo.FindOutdatedInfo(ctx, time.Now().Add(-time.Hour), 1000)
+			require.NoErrorf(t, err, "find outdated info: %v", err)
+			assert.NotContains(t, util.Map(outdated, func(a *repos.OutdatedArtistInfo) string { return a.ID }), artistID)
+		})
+	})
+
+	t.Run("FindSimilar", func(t *testing.T) {
+		artistID, folderID := thCreateArtistInMusicFolder(t, db, user)
+
+		byName, err := repo.Create(ctx, repos.CreateArtistParams{Name: "Similär Artist " + t.Name()})
+		require.NoError(t, err)
+		thAssociateMusicFolderArtist(t, db, byName, folderID)
+
+		mbid := "similar-mbid-" + t.Name()
+		byMBID, err := repo.Create(ctx, repos.CreateArtistParams{Name: "Other Name " + t.Name(), MusicBrainzID: &mbid})
+		require.NoError(t, err)
+		thAssociateMusicFolderArtist(t, db, byMBID, folderID)
+
+		otherFolder := thCreateMusicFolder(t, db, user)
+		inOtherFolder, err := repo.Create(ctx, repos.CreateArtistParams{Name: "Folder Artist " + t.Name()})
+		require.NoError(t, err)
+		thAssociateMusicFolderArtist(t, db, inOtherFolder, otherFolder)
+
+		err = repo.SetInfo(ctx, artistID, repos.SetArtistInfo{
+			SimilarArtists: []repos.SimilarArtistParams{
+				{Name: "Not In Library", Match: 1},
+				{Name: "Unknown", MusicBrainzID: &mbid, Match: 0.9},
+				{Name: "similar artist " + t.Name(), Match: 0.8},
+				{Name: "Folder Artist " + t.Name(), Match: 0.7},
+			},
+		})
+		require.NoErrorf(t, err, "set info: %v", err)
+
+		t.Run("matches by mbid and normalized name in order", func(t *testing.T) {
+			similar, err := repo.FindSimilar(ctx, artistID, []int{folderID}, 10, repos.IncludeArtistInfoBare())
+			require.NoErrorf(t, err, "find similar: %v", err)
+			assert.Equal(t, []string{byMBID, byName}, util.Map(similar, func(a *repos.CompleteArtist) string { return a.ID }))
+		})
+
+		t.Run("respects music folders and limit", func(t *testing.T) {
+			similar, err := repo.FindSimilar(ctx, artistID, []int{folderID, otherFolder}, 2, repos.IncludeArtistInfoBare())
+			require.NoErrorf(t, err, "find similar: %v", err)
+			assert.Equal(t, []string{byMBID, byName}, util.Map(similar, func(a *repos.CompleteArtist) string { return a.ID }))
+
+			similar, err = repo.FindSimilar(ctx, artistID, []int{otherFolder}, 10, repos.IncludeArtistInfoBare())
+			require.NoErrorf(t, err, "find similar: %v", err)
+			assert.Equal(t, []string{inOtherFolder}, util.Map(similar, func(a *repos.CompleteArtist) string { return a.ID }))
+		})
+
+		t.Run("replaces previous similar artists", func(t *testing.T) {
+			err := repo.SetInfo(ctx, artistID, repos.SetArtistInfo{})
+			require.NoErrorf(t, err, "set info: %v", err)
+			similar, err := repo.FindSimilar(ctx, artistID, []int{folderID, otherFolder}, 10, repos.IncludeArtistInfoBare())
+			require.NoErrorf(t, err, "find similar: %v", err)
+			assert.Empty(t, similar)
+		})
 	})
 
 	t.Run("MigrateAnnotations", func(t *testing.T) {
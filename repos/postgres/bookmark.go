@@ -0,0 +1,35 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/nullism/bqb"
+)
+
+type bookmarkRepository struct {
+	db executer
+	tx func(ctx context.Context, fn func(b bookmarkRepository) error) error
+}
+
+func (b bookmarkRepository) FindAll(ctx context.Context, user string) ([]*repos.Bookmark, error) {
+	q := bqb.New("SELECT * FROM bookmarks WHERE user_name = ? ORDER BY bookmarks.updated DESC", user)
+	return selectQuery[*repos.Bookmark](ctx, b.db, q)
+}
+
+func (b bookmarkRepository) CreateOrUpdate(ctx context.Context, user string, params repos.CreateBookmarkParams) error {
+	q := bqb.New(`INSERT INTO bookmarks (user_name,song_id,position_ms,comment,created,updated) VALUES (?,?,?,?,NOW(),NOW())
+		ON CONFLICT (user_name,song_id) DO UPDATE SET position_ms = excluded.position_ms, comment = excluded.comment, updated = NOW()`,
+		user, params.SongID, params.Position.Milliseconds(), params.Comment)
+	return executeQuery(ctx, b.db, q)
+}
+
+func (b bookmarkRepository) Delete(ctx context.Context, user, songID string) error {
+	q := bqb.New("DELETE FROM bookmarks WHERE user_name = ? AND song_id = ?", user, songID)
+	return executeQueryExpectAffectedRows(ctx, b.db, q)
+}
+
+func (b bookmarkRepository) DeleteMissingSongs(ctx context.Context) error {
+	q := bqb.New("DELETE FROM bookmarks WHERE NOT EXISTS (SELECT 1 FROM songs WHERE songs.id = bookmarks.song_id)")
+	return executeQuery(ctx, b.db, q)
+}
@@ -205,6 +205,111 @@ func (d *DB) MusicFolder() repos.MusicFolderRepository {
 	}
 }
 
+func (d *DB) Share() repos.ShareRepository {
+	exec := executer(d.db)
+	if d.tx != nil {
+		exec = d.tx
+	}
+	return shareRepository{
+		db: exec,
+		tx: newTransactionFn(d, func(tx executer) shareRepository {
+			return shareRepository{
+				db: tx,
+			}
+		}),
+	}
+}
+
+func (d *DB) Podcast() repos.PodcastRepository {
+	exec := executer(d.db)
+	if d.tx != nil {
+		exec = d.tx
+	}
+	return podcastRepository{
+		db: exec,
+		tx: newTransactionFn(d, func(tx executer) podcastRepository {
+			return podcastRepository{
+				db: tx,
+			}
+		}),
+	}
+}
+
+func (d *DB) PlayQueue() repos.PlayQueueRepository {
+	exec := executer(d.db)
+	if d.tx != nil {
+		exec = d.tx
+	}
+	return playQueueRepository{
+		db: exec,
+		tx: newTransactionFn(d, func(tx executer) playQueueRepository {
+			return playQueueRepository{
+				db: tx,
+			}
+		}),
+	}
+}
+
+func (d *DB) Bookmark() repos.BookmarkRepository {
+	exec := executer(d.db)
+	if d.tx != nil {
+		exec = d.tx
+	}
+	return bookmarkRepository{
+		db: exec,
+		tx: newTransactionFn(d, func(tx executer) bookmarkRepository {
+			return bookmarkRepository{
+				db: tx,
+			}
+		}),
+	}
+}
+
+func (d *DB) Similarity() repos.SimilarityRepository {
+	exec := executer(d.db)
+	if d.tx != nil {
+		exec = d.tx
+	}
+	return similarityRepository{
+		db: exec,
+		tx: newTransactionFn(d, func(tx executer) similarityRepository {
+			return similarityRepository{
+				db: tx,
+			}
+		}),
+	}
+}
+
+func (d *DB) Directory() repos.DirectoryRepository {
+	exec := executer(d.db)
+	if d.tx != nil {
+		exec = d.tx
+	}
+	return directoryRepository{
+		db: exec,
+		tx: newTransactionFn(d, func(tx executer) directoryRepository {
+			return directoryRepository{
+				db: tx,
+			}
+		}),
+	}
+}
+
+func (d *DB) ScanProblem() repos.ScanProblemRepository {
+	exec := executer(d.db)
+	if d.tx != nil {
+		exec = d.tx
+	}
+	return scanProblemRepository{
+		db: exec,
+		tx: newTransactionFn(d, func(tx executer) scanProblemRepository {
+			return scanProblemRepository{
+				db: tx,
+			}
+		}),
+	}
+}
+
 func (d *DB) Transaction(ctx context.Context, fn func(tx repos.Tx) error) error {
 	if d.db == nil {
 		return repos.NewError("create transaction", repos.ErrNestedTransaction, nil)
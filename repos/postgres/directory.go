@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/nullism/bqb"
+)
+
+type directoryRepository struct {
+	db executer
+	tx func(ctx context.Context, fn func(d directoryRepository) error) error
+}
+
+func (d directoryRepository) FindByID(ctx context.Context, id string, musicFolderIDs []int) (*repos.Directory, error) {
+	q := bqb.New("SELECT * FROM directories WHERE directories.id = ? AND ?", id, genOneOfMusicFoldersCondition("directories", musicFolderIDs))
+	return getQuery[*repos.Directory](ctx, d.db, q)
+}
+
+func (d directoryRepository) FindRoots(ctx context.Context, musicFolderIDs []int) ([]*repos.Directory, error) {
+	q := bqb.New("SELECT * FROM directories WHERE directories.parent_id IS NULL AND ? ORDER BY directories.music_folder_id", genOneOfMusicFoldersCondition("directories", musicFolderIDs))
+	return selectQuery[*repos.Directory](ctx, d.db, q)
+}
+
+func (d directoryRepository) FindChildren(ctx context.Context, parentIDs []string, musicFolderIDs []int) ([]*repos.Directory, error) {
+	if len(parentIDs) == 0 {
+		return []*repos.Directory{}, nil
+	}
+	q := bqb.New("SELECT * FROM directories WHERE directories.parent_id IN (?) AND ? ORDER BY lower(directories.name), directories.id", parentIDs, genOneOfMusicFoldersCondition("directories", musicFolderIDs))
+	return selectQuery[*repos.Directory](ctx, d.db, q)
+}
+
+func (d directoryRepository) FindAll(ctx context.Context) ([]*repos.Directory, error) {
+	return selectQuery[*repos.Directory](ctx, d.db, bqb.New("SELECT * FROM directories"))
+}
+
+func (d directoryRepository) GetSongs(ctx context.Context, ids []string, include repos.IncludeSongInfo) ([]*repos.CompleteSong, error) {
+	if len(ids) == 0 {
+		return []*repos.CompleteSong{}, nil
+	}
+	q := bqb.New("SELECT ? FROM songs ?", genSongSelectList(include), genSongJoins(include))
+	q.Space("INNER JOIN directories ON directories.path = songs.directory_path AND directories.music_folder_id = songs.music_folder_id")
+	q.Space("WHERE directories.id IN (?) ORDER BY songs.path", ids)
+	return execSongSelectMany(ctx, d.db, q, include)
+}
+
+func (d directoryRepository) UpsertAll(ctx context.Context, params []repos.UpsertDirectoryParams) error {
+	return d.tx(ctx, func(d directoryRepository) error {
+		return execBatch(params, func(params []repos.UpsertDirectoryParams) error {
+			valueList := bqb.Optional("")
+			for _, p := range params {
+				valueList.Comma("(?,?,?,?,?,NOW(),NOW())", p.ID, p.Path, p.Name, p.ParentID, p.MusicFolderID)
+			}
+			q := bqb.New(`INSERT INTO directories (id,path,name,parent_id,music_folder_id,created,updated) VALUES ?
+				ON CONFLICT (path) DO UPDATE SET name = EXCLUDED.name, parent_id = EXCLUDED.parent_id, music_folder_id = EXCLUDED.music_folder_id, updated = NOW()
+				WHERE directories.name IS DISTINCT FROM EXCLUDED.name OR directories.parent_id IS DISTINCT FROM EXCLUDED.parent_id OR directories.music_folder_id <> EXCLUDED.music_folder_id`, valueList)
+			return executeQuery(ctx, d.db, q)
+		})
+	})
+}
+
+func (d directoryRepository) DeleteByIDs(ctx context.Context, ids []string) error {
+	return d.tx(ctx, func(d directoryRepository) error {
+		return execBatch(ids, func(ids []string) error {
+			return executeQuery(ctx, d.db, bqb.New("DELETE FROM directories WHERE id IN (?)", ids))
+		})
+	})
+}
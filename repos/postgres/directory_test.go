@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/juho05/crossonic-server"
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/juho05/crossonic-server/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirectoryRepository(t *testing.T) {
+	db, _ := thSetupDatabase(t)
+
+	repo := db.Directory()
+
+	ctx := context.Background()
+
+	assert.Equalf(t, 0, thCount(t, db, "directories"),
+		"there should be no directories at beginning of test")
+
+	user := thCreateUser(t, db)
+	musicFolderID := thCreateMusicFolder(t, db, user)
+	otherMusicFolderID := thCreateMusicFolder(t, db, user)
+
+	rootPath := "/music-" + uuid.NewString()
+	root := repos.UpsertDirectoryParams{
+		ID:            crossonic.GenIDDirectory(),
+		Path:          rootPath,
+		Name:          "music",
+		MusicFolderID: musicFolderID,
+	}
+	b := repos.UpsertDirectoryParams{
+		ID:            crossonic.GenIDDirectory(),
+		Path:          rootPath + "/b",
+		Name:          "b",
+		ParentID:      &root.ID,
+		MusicFolderID: musicFolderID,
+	}
+	a := repos.UpsertDirectoryParams{
+		ID:            crossonic.GenIDDirectory(),
+		Path:          rootPath + "/A",
+		Name:          "A",
+		ParentID:      &root.ID,
+		MusicFolderID: musicFolderID,
+	}
+	err := repo.UpsertAll(ctx, []repos.UpsertDirectoryParams{root, b, a})
+	require.NoErrorf(t, err, "upsert directories: %v", err)
+
+	songID := crossonic.GenIDSong()
+	err = db.Song().CreateAll(ctx, []repos.CreateSongParams{
+		{
+			ID:            &songID,
+			Path:          rootPath + "/A/song.mp3",
+			Title:         "Test Song",
+			Size:          1000,
+			ContentType:   "audio/mpeg",
+			Duration:      repos.NewDurationMS(180000),
+			BitRate:       320,
+			SamplingRate:  44100,
+			ChannelCount:  2,
+			MusicFolderID: musicFolderID,
+		},
+	})
+	require.NoErrorf(t, err, "create song: %v", err)
+
+	t.Run("UpsertAll", func(t *testing.T) {
+		assert.Equal(t, 3, thCount(t, db, "directories"))
+
+		renamed := a
+		renamed.ID = crossonic.GenIDDirectory()
+		renamed.Name = "A2"
+		err := repo.UpsertAll(ctx, []repos.UpsertDirectoryParams{renamed})
+		require.NoErrorf(t, err, "upsert directory: %v", err)
+		assert.Equal(t, 3, thCount(t, db, "directories"))
+		assert.True(t, thExists(t, db, "directories", map[string]any{
+			"id":   a.ID,
+			"name": "A2",
+		}), "existing directory should keep its id")
+
+		err = repo.UpsertAll(ctx, []repos.UpsertDirectoryParams{a})
+		require.NoErrorf(t, err, "upsert directory: %v", err)
+	})
+
+	t.Run("FindByID", func(t *testing.T) {
+		dir, err := repo.FindByID(ctx, a.ID, []int{musicFolderID})
+		require.NoErrorf(t, err, "find by id: %v", err)
+		assert.Equal(t, a.ID, dir.ID)
+		assert.Equal(t, a.Path, dir.Path)
+		assert.Equal(t, "A", dir.Name)
+		assert.Equal(t, util.ToPtr(root.ID), dir.ParentID)
+		assert.Equal(t, musicFolderID, dir.MusicFolderID)
+
+		_, err = repo.FindByID(ctx, a.ID, []int{otherMusicFolderID})
+		assert.Truef(t, errors.Is(err, repos.ErrNotFound), "expected not found error, got: %v", err)
+	})
+
+	t.Run("FindRoots", func(t *testing.T) {
+		roots, err := repo.FindRoots(ctx, []int{musicFolderID, otherMusicFolderID})
+		require.NoErrorf(t, err, "find roots: %v", err)
+		require.Len(t, roots, 1)
+		assert.Equal(t, root.ID, roots[0].ID)
+
+		roots, err = repo.FindRoots(ctx, []int{otherMusicFolderID})
+		require.NoErrorf(t, err, "find roots: %v", err)
+		assert.Empty(t, roots)
+	})
+
+	t.Run("FindChildren", func(t *testing.T) {
+		children, err := repo.FindChildren(ctx, []string{root.ID}, []int{musicFolderID})
+		require.NoErrorf(t, err, "find children: %v", err)
+		assert.Equal(t, []string{a.ID, b.ID}, util.Map(children, func(d *repos.Directory) string {
+			return d.ID
+		}), "children should be sorted by name")
+
+		children, err = repo.FindChildren(ctx, []string{a.ID}, []int{musicFolderID})
+		require.NoErrorf(t, err, "find children: %v", err)
+		assert.Empty(t, children)
+	})
+
+	t.Run("GetSongs", func(t *testing.T) {
+		songs, err := repo.GetSongs(ctx, []string{a.ID}, repos.IncludeSongInfoBare())
+		require.NoErrorf(t, err, "get songs: %v", err)
+		require.Len(t, songs, 1)
+		assert.Equal(t, songID, songs[0].ID)
+
+		songs, err = repo.GetSongs(ctx, []string{root.ID, b.ID}, repos.IncludeSongInfoBare())
+		require.NoErrorf(t, err, "get songs: %v", err)
+		assert.Empty(t, songs)
+	})
+
+	t.Run("DeleteByIDs", func(t *testing.T) {
+		err := repo.DeleteByIDs(ctx, []string{root.ID})
+		require.NoErrorf(t, err, "delete directories: %v", err)
+		assert.Equal(t, 0, thCount(t, db, "directories"), "subdirectories should be deleted with their parent")
+		assert.True(t, thExists(t, db, "songs", map[string]any{"id": songID}), "songs should not be deleted")
+	})
+}
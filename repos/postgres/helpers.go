@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/juho05/crossonic-server/repos"
@@ -187,9 +188,31 @@ func genMusicFolderUserJoin(tableName, user string) *bqb.Query {
 	return bqb.New(fmt.Sprintf("JOIN music_folder_users mfu ON mfu.music_folder_id = %s.music_folder_id AND mfu.user_name = ?", tableName), user)
 }
 
+// genStringListContainsCondition checks whether the repos.StringList column contains at least one of values (case-insensitive).
+func genStringListContainsCondition(column string, values []string) *bqb.Query {
+	values = util.Map(values, strings.ToLower)
+	return bqb.New(fmt.Sprintf("(EXISTS (SELECT 1 FROM unnest(string_to_array(%s, chr(3))) AS v WHERE lower(v) IN (?)))", column), values)
+}
+
 func genOneOfMusicFoldersCondition(tableName string, musicFolderIDs []int) *bqb.Query {
 	if len(musicFolderIDs) == 0 {
 		return bqb.New("false")
 	}
 	return bqb.New(fmt.Sprintf("(%s.music_folder_id IN (?))", tableName), musicFolderIDs)
 }
+
+// genPathInDirsCondition checks whether the path column of the table is one of dirs or inside of one of them.
+func genPathInDirsCondition(tableName string, dirs []string) *bqb.Query {
+	if len(dirs) == 0 {
+		return bqb.New("false")
+	}
+	conditions := bqb.Optional("")
+	for _, dir := range dirs {
+		prefix := dir
+		if !strings.HasSuffix(prefix, string(filepath.Separator)) {
+			prefix += string(filepath.Separator)
+		}
+		conditions.Or(fmt.Sprintf("%s.path = ? OR starts_with(%s.path, ?)", tableName, tableName), dir, prefix)
+	}
+	return bqb.New("(?)", conditions)
+}
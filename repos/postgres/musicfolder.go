@@ -53,6 +53,16 @@ func (m musicFolderRepository) DeleteAllUserAssociations(ctx context.Context) er
 	return executeQuery(ctx, m.db, q)
 }
 
+func (m musicFolderRepository) DeleteDefaultUserAssociations(ctx context.Context) error {
+	q := bqb.New("DELETE FROM music_folder_users mfu USING users u WHERE mfu.user_name = u.name AND u.custom_music_folders = false")
+	return executeQuery(ctx, m.db, q)
+}
+
+func (m musicFolderRepository) DeleteUserAssociationsOfUser(ctx context.Context, user string) error {
+	q := bqb.New("DELETE FROM music_folder_users WHERE user_name = ?", user)
+	return executeQuery(ctx, m.db, q)
+}
+
 func (m musicFolderRepository) CreateUserAssociations(ctx context.Context, folderId int, users []string) error {
 	if len(users) == 0 {
 		return nil
@@ -78,6 +88,15 @@ func (m musicFolderRepository) DeleteAllArtistAssociations(ctx context.Context)
 	return executeQuery(ctx, m.db, q)
 }
 
+func (m musicFolderRepository) DeleteArtistAssociations(ctx context.Context, artistIDs []string) error {
+	return m.tx(ctx, func(m musicFolderRepository) error {
+		return execBatch(artistIDs, func(artistIDs []string) error {
+			q := bqb.New("DELETE FROM music_folder_artists WHERE artist_id IN (?)", artistIDs)
+			return executeQuery(ctx, m.db, q)
+		})
+	})
+}
+
 func (m musicFolderRepository) CreateArtistAssociations(ctx context.Context, associations []repos.ArtistMusicFolderAssociation) error {
 	if len(associations) == 0 {
 		return nil
@@ -88,7 +107,7 @@ func (m musicFolderRepository) CreateArtistAssociations(ctx context.Context, ass
 			for _, a := range associations {
 				valueList.Comma("(?,?)", a.MusicFolderID, a.ArtistID)
 			}
-			q := bqb.New("INSERT INTO music_folder_artists (music_folder_id, artist_id) VALUES ?", valueList)
+			q := bqb.New("INSERT INTO music_folder_artists (music_folder_id, artist_id) VALUES ? ON CONFLICT (music_folder_id, artist_id) DO NOTHING", valueList)
 			return executeQuery(ctx, m.db, q)
 		})
 	})
@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/nullism/bqb"
+)
+
+type playQueueRepository struct {
+	db executer
+	tx func(ctx context.Context, fn func(p playQueueRepository) error) error
+}
+
+func (p playQueueRepository) Get(ctx context.Context, user string) (*repos.PlayQueue, error) {
+	q := bqb.New("SELECT * FROM play_queues WHERE user_name = ?", user)
+	return getQuery[*repos.PlayQueue](ctx, p.db, q)
+}
+
+func (p playQueueRepository) GetSongIDs(ctx context.Context, user string) ([]string, error) {
+	q := bqb.New("SELECT song_id FROM play_queue_songs WHERE user_name = ? ORDER BY play_queue_songs.index", user)
+	return selectQuery[string](ctx, p.db, q)
+}
+
+func (p playQueueRepository) Save(ctx context.Context, user string, params repos.SavePlayQueueParams) error {
+	return p.tx(ctx, func(p playQueueRepository) error {
+		err := executeQuery(ctx, p.db, bqb.New("DELETE FROM play_queues WHERE user_name = ?", user))
+		if err != nil {
+			return fmt.Errorf("delete old play queue: %w", err)
+		}
+		if len(params.SongIDs) == 0 {
+			return nil
+		}
+		q := bqb.New("INSERT INTO play_queues (user_name,current_index,position_ms,changed,changed_by) VALUES (?,?,?,NOW(),?)",
+			user, params.CurrentIndex, params.Position.Milliseconds(), params.ChangedBy)
+		err = executeQuery(ctx, p.db, q)
+		if err != nil {
+			return fmt.Errorf("insert play queue: %w", err)
+		}
+		index := 0
+		err = execBatch(params.SongIDs, func(songIDs []string) error {
+			valueList := bqb.Optional("")
+			for _, id := range songIDs {
+				valueList.Comma("(?,?,?)", user, index, id)
+				index++
+			}
+			return executeQuery(ctx, p.db, bqb.New("INSERT INTO play_queue_songs (user_name,index,song_id) VALUES ?", valueList))
+		})
+		if err != nil {
+			return fmt.Errorf("insert play queue songs: %w", err)
+		}
+		return nil
+	})
+}
+
+func (p playQueueRepository) Delete(ctx context.Context, user string) error {
+	q := bqb.New("DELETE FROM play_queues WHERE user_name = ?", user)
+	return executeQuery(ctx, p.db, q)
+}
+
+func (p playQueueRepository) DeleteMissingSongs(ctx context.Context) error {
+	return p.tx(ctx, func(p playQueueRepository) error {
+		// move the current index back by the number of removed songs in front of it
+		q := bqb.New(`UPDATE play_queues SET current_index = play_queues.current_index - m.removed FROM (
+				SELECT pqs.user_name, COUNT(*) AS removed FROM play_queue_songs pqs
+				JOIN play_queues pq ON pq.user_name = pqs.user_name
+				WHERE pqs.index < pq.current_index AND NOT EXISTS (SELECT 1 FROM songs WHERE songs.id = pqs.song_id)
+				GROUP BY pqs.user_name
+			) m WHERE play_queues.user_name = m.user_name`)
+		err := executeQuery(ctx, p.db, q)
+		if err != nil {
+			return fmt.Errorf("update current index: %w", err)
+		}
+
+		q = bqb.New("DELETE FROM play_queue_songs WHERE NOT EXISTS (SELECT 1 FROM songs WHERE songs.id = play_queue_songs.song_id)")
+		err = executeQuery(ctx, p.db, q)
+		if err != nil {
+			return fmt.Errorf("delete missing songs: %w", err)
+		}
+
+		q = bqb.New(`UPDATE play_queue_songs SET index = t.new_index FROM
+				(SELECT user_name,index,(row_number() OVER (PARTITION BY user_name ORDER BY index))-1 AS new_index FROM play_queue_songs) AS t
+			WHERE play_queue_songs.user_name = t.user_name AND play_queue_songs.index = t.index AND play_queue_songs.index != t.new_index`)
+		err = executeQuery(ctx, p.db, q)
+		if err != nil {
+			return fmt.Errorf("fix indices: %w", err)
+		}
+
+		q = bqb.New("DELETE FROM play_queues WHERE NOT EXISTS (SELECT 1 FROM play_queue_songs WHERE play_queue_songs.user_name = play_queues.user_name)")
+		err = executeQuery(ctx, p.db, q)
+		if err != nil {
+			return fmt.Errorf("delete empty play queues: %w", err)
+		}
+
+		q = bqb.New(`UPDATE play_queues SET current_index = m.max_index FROM (
+				SELECT user_name, MAX(index) AS max_index FROM play_queue_songs GROUP BY user_name
+			) m WHERE play_queues.user_name = m.user_name AND play_queues.current_index > m.max_index`)
+		err = executeQuery(ctx, p.db, q)
+		if err != nil {
+			return fmt.Errorf("clamp current index: %w", err)
+		}
+		return nil
+	})
+}
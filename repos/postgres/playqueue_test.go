@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/juho05/crossonic-server/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlayQueueRepository(t *testing.T) {
+	db, _ := thSetupDatabase(t)
+
+	repo := db.PlayQueue()
+
+	ctx := context.Background()
+
+	user := thCreateUser(t, db)
+	musicFolderID := thCreateMusicFolder(t, db, user)
+
+	t.Run("Save", func(t *testing.T) {
+		thDeleteAll(t, db, "play_queues")
+		song1 := thCreateSong(t, db, nil, musicFolderID)
+		song2 := thCreateSong(t, db, nil, musicFolderID)
+
+		err := repo.Save(ctx, user, repos.SavePlayQueueParams{
+			SongIDs:      []string{song1, song2, song1},
+			CurrentIndex: util.ToPtr(2),
+			Position:     90 * time.Second,
+			ChangedBy:    "test-client",
+		})
+		require.NoErrorf(t, err, "save play queue: %v", err)
+
+		queue, err := repo.Get(ctx, user)
+		require.NoErrorf(t, err, "get play queue: %v", err)
+		assert.Equal(t, user, queue.User)
+		assert.Equal(t, util.ToPtr(2), queue.CurrentIndex)
+		assert.Equal(t, 90*time.Second, queue.Position.ToStd())
+		assert.Equal(t, "test-client", queue.ChangedBy)
+
+		ids, err := repo.GetSongIDs(ctx, user)
+		require.NoErrorf(t, err, "get song ids: %v", err)
+		assert.Equal(t, []string{song1, song2, song1}, ids)
+
+		err = repo.Save(ctx, user, repos.SavePlayQueueParams{
+			SongIDs:   []string{song2},
+			ChangedBy: "test-client",
+		})
+		require.NoErrorf(t, err, "replace play queue: %v", err)
+		ids, err = repo.GetSongIDs(ctx, user)
+		require.NoErrorf(t, err, "get song ids: %v", err)
+		assert.Equal(t, []string{song2}, ids)
+
+		err = repo.Save(ctx, user, repos.SavePlayQueueParams{
+			ChangedBy: "test-client",
+		})
+		require.NoErrorf(t, err, "clear play queue: %v", err)
+		_, err = repo.Get(ctx, user)
+		assert.ErrorIs(t, err, repos.ErrNotFound)
+		assert.Equal(t, 0, thCount(t, db, "play_queue_songs"))
+	})
+
+	t.Run("DeleteMissingSongs", func(t *testing.T) {
+		thDeleteAll(t, db, "play_queues")
+		song1 := thCreateSong(t, db, nil, musicFolderID)
+		song2 := thCreateSong(t, db, nil, musicFolderID)
+		song3 := thCreateSong(t, db, nil, musicFolderID)
+		song4 := thCreateSong(t, db, nil, musicFolderID)
+
+		err := repo.Save(ctx, user, repos.SavePlayQueueParams{
+			SongIDs:      []string{song1, song2, song3, song4},
+			CurrentIndex: util.ToPtr(2),
+			ChangedBy:    "test-client",
+		})
+		require.NoErrorf(t, err, "save play queue: %v", err)
+
+		_, err = db.db.ExecContext(ctx, "DELETE FROM songs WHERE id = $1 OR id = $2", song1, song4)
+		require.NoErrorf(t, err, "delete songs: %v", err)
+
+		err = repo.DeleteMissingSongs(ctx)
+		require.NoErrorf(t, err, "delete missing songs: %v", err)
+
+		ids, err := repo.GetSongIDs(ctx, user)
+		require.NoErrorf(t, err, "get song ids: %v", err)
+		assert.Equal(t, []string{song2, song3}, ids)
+
+		queue, err := repo.Get(ctx, user)
+		require.NoErrorf(t, err, "get play queue: %v", err)
+		assert.Equal(t, util.ToPtr(1), queue.CurrentIndex, "current index should still point to song3")
+
+		_, err = db.db.ExecContext(ctx, "DELETE FROM songs WHERE id = $1 OR id = $2", song2, song3)
+		require.NoErrorf(t, err, "delete songs: %v", err)
+
+		err = repo.DeleteMissingSongs(ctx)
+		require.NoErrorf(t, err, "delete missing songs: %v", err)
+
+		_, err = repo.Get(ctx, user)
+		assert.ErrorIs(t, err, repos.ErrNotFound, "empty play queue should be deleted")
+	})
+}
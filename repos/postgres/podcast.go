@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/juho05/crossonic-server"
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/nullism/bqb"
+)
+
+type podcastRepository struct {
+	db executer
+	tx func(ctx context.Context, fn func(p podcastRepository) error) error
+}
+
+func (p podcastRepository) CreateChannel(ctx context.Context, url string) (*repos.PodcastChannel, error) {
+	q := bqb.New("INSERT INTO podcast_channels (id,url,status,created,updated) VALUES (?,?,?,NOW(),NOW()) RETURNING *",
+		crossonic.GenIDPodcastChannel(), url, repos.PodcastStatusNew)
+	return getQuery[*repos.PodcastChannel](ctx, p.db, q)
+}
+
+func (p podcastRepository) UpdateChannel(ctx context.Context, id string, params repos.UpdatePodcastChannelParams) error {
+	updateList, empty := genUpdateList(map[string]repos.OptionalGetter{
+		"title":         params.Title,
+		"description":   params.Description,
+		"image_url":     params.ImageURL,
+		"status":        params.Status,
+		"error_message": params.ErrorMessage,
+		"last_refresh":  params.LastRefresh,
+	}, true)
+	if empty {
+		return nil
+	}
+	q := bqb.New("UPDATE podcast_channels SET ? WHERE id = ?", updateList, id)
+	return executeQueryExpectAffectedRows(ctx, p.db, q)
+}
+
+func (p podcastRepository) FindAllChannels(ctx context.Context) ([]*repos.PodcastChannel, error) {
+	q := bqb.New("SELECT * FROM podcast_channels ORDER BY lower(COALESCE(podcast_channels.title, podcast_channels.url)), podcast_channels.created")
+	return selectQuery[*repos.PodcastChannel](ctx, p.db, q)
+}
+
+func (p podcastRepository) FindChannelByID(ctx context.Context, id string) (*repos.PodcastChannel, error) {
+	q := bqb.New("SELECT * FROM podcast_channels WHERE id = ?", id)
+	return getQuery[*repos.PodcastChannel](ctx, p.db, q)
+}
+
+func (p podcastRepository) DeleteChannel(ctx context.Context, id string) error {
+	q := bqb.New("DELETE FROM podcast_channels WHERE id = ?", id)
+	return executeQueryExpectAffectedRows(ctx, p.db, q)
+}
+
+func (p podcastRepository) CreateOrUpdateEpisodes(ctx context.Context, channelID string, params []repos.CreatePodcastEpisodeParams) error {
+	if len(params) == 0 {
+		return nil
+	}
+	return p.tx(ctx, func(p podcastRepository) error {
+		return execBatch(params, func(params []repos.CreatePodcastEpisodeParams) error {
+			valueList := bqb.Optional("")
+			for _, e := range params {
+				valueList.Comma("(?,?,?,?,?,?,?,?,?,?,?,NOW(),NOW())", crossonic.GenIDPodcastEpisode(), channelID, e.GUID, e.Title,
+					e.Description, e.PublishDate, e.URL, repos.PodcastStatusNew, e.Size, e.ContentType, e.Duration)
+			}
+			q := bqb.New(`INSERT INTO podcast_episodes (id,channel_id,guid,title,description,publish_date,url,status,size,content_type,duration_ms,created,updated)
+			VALUES ? ON CONFLICT (channel_id, guid) DO UPDATE SET title = excluded.title, description = excluded.description,
+			publish_date = excluded.publish_date, url = excluded.url,
+			size = CASE WHEN podcast_episodes.path IS NULL THEN excluded.size ELSE podcast_episodes.size END,
+			content_type = CASE WHEN podcast_episodes.path IS NULL THEN excluded.content_type ELSE podcast_episodes.content_type END,
+			duration_ms = CASE WHEN podcast_episodes.path IS NULL THEN excluded.duration_ms ELSE podcast_episodes.duration_ms END,
+			updated = NOW()`, valueList)
+			return executeQuery(ctx, p.db, q)
+		})
+	})
+}
+
+func (p podcastRepository) FindEpisodesByChannel(ctx context.Context, channelID string) ([]*repos.PodcastEpisode, error) {
+	q := bqb.New("SELECT * FROM podcast_episodes WHERE channel_id = ? ORDER BY podcast_episodes.publish_date DESC NULLS LAST, podcast_episodes.created DESC", channelID)
+	return selectQuery[*repos.PodcastEpisode](ctx, p.db, q)
+}
+
+func (p podcastRepository) FindNewestEpisodes(ctx context.Context, count int) ([]*repos.PodcastEpisode, error) {
+	q := bqb.New("SELECT * FROM podcast_episodes WHERE status != ? ORDER BY podcast_episodes.publish_date DESC NULLS LAST, podcast_episodes.created DESC LIMIT ?",
+		repos.PodcastStatusDeleted, count)
+	return selectQuery[*repos.PodcastEpisode](ctx, p.db, q)
+}
+
+func (p podcastRepository) FindEpisodesByStatus(ctx context.Context, status repos.PodcastStatus) ([]*repos.PodcastEpisode, error) {
+	q := bqb.New("SELECT * FROM podcast_episodes WHERE status = ? ORDER BY podcast_episodes.created", status)
+	return selectQuery[*repos.PodcastEpisode](ctx, p.db, q)
+}
+
+func (p podcastRepository) FindEpisodeByID(ctx context.Context, id string) (*repos.PodcastEpisode, error) {
+	q := bqb.New("SELECT * FROM podcast_episodes WHERE id = ?", id)
+	return getQuery[*repos.PodcastEpisode](ctx, p.db, q)
+}
+
+func (p podcastRepository) UpdateEpisode(ctx context.Context, id string, params repos.UpdatePodcastEpisodeParams) error {
+	return p.updateEpisode(ctx, id, nil, params)
+}
+
+func (p podcastRepository) UpdateEpisodeWithStatus(ctx context.Context, id string, status repos.PodcastStatus, params repos.UpdatePodcastEpisodeParams) error {
+	return p.updateEpisode(ctx, id, &status, params)
+}
+
+func (p podcastRepository) updateEpisode(ctx context.Context, id string, status *repos.PodcastStatus, params repos.UpdatePodcastEpisodeParams) error {
+	updateList, empty := genUpdateList(map[string]repos.OptionalGetter{
+		"status":        params.Status,
+		"error_message": params.ErrorMessage,
+		"path":          params.Path,
+		"size":          params.Size,
+		"content_type":  params.ContentType,
+		"bit_rate":      params.BitRate,
+		"duration_ms":   params.Duration,
+		"channel_count": params.ChannelCount,
+	}, true)
+	if empty {
+		return nil
+	}
+	q := bqb.New("UPDATE podcast_episodes SET ? WHERE id = ?", updateList, id)
+	if status != nil {
+		q.And("status = ?", *status)
+	}
+	return executeQueryExpectAffectedRows(ctx, p.db, q)
+}
+
+func (p podcastRepository) GetEpisodeStreamInfo(ctx context.Context, id string) (*repos.SongStreamInfo, error) {
+	q := bqb.New(`SELECT podcast_episodes.path, COALESCE(podcast_episodes.bit_rate, 0) AS bit_rate,
+		COALESCE(podcast_episodes.content_type, 'application/octet-stream') AS content_type,
+		COALESCE(podcast_episodes.duration_ms, 0) AS duration_ms, COALESCE(podcast_episodes.channel_count, 2) AS channel_count, 0 AS sampling_rate,
+		CAST(NULL AS real) AS replay_gain, CAST(NULL AS real) AS replay_gain_peak, CAST(NULL AS real) AS album_replay_gain, CAST(NULL AS real) AS album_replay_gain_peak
+		FROM podcast_episodes WHERE podcast_episodes.id = ? AND podcast_episodes.status = ? AND podcast_episodes.path IS NOT NULL`,
+		id, repos.PodcastStatusCompleted)
+	return getQuery[*repos.SongStreamInfo](ctx, p.db, q)
+}
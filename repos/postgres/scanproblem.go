@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/juho05/crossonic-server/util"
+	"github.com/nullism/bqb"
+)
+
+type scanProblemRepository struct {
+	db executer
+	tx func(ctx context.Context, fn func(s scanProblemRepository) error) error
+}
+
+func (s scanProblemRepository) FindAll(ctx context.Context, params repos.FindScanProblemsParams) ([]*repos.ScanProblem, error) {
+	q := bqb.New("SELECT * FROM scan_problems ? ORDER BY path, type", genScanProblemsWhere(params))
+	params.Paginate.Apply(q)
+	return selectQuery[*repos.ScanProblem](ctx, s.db, q)
+}
+
+func (s scanProblemRepository) Count(ctx context.Context, params repos.FindScanProblemsParams) (int, error) {
+	return getQuery[int](ctx, s.db, bqb.New("SELECT COUNT(*) FROM scan_problems ?", genScanProblemsWhere(params)))
+}
+
+func (s scanProblemRepository) FindPaths(ctx context.Context, dirs []string) ([]string, error) {
+	q := bqb.New("SELECT DISTINCT path FROM scan_problems")
+	if dirs != nil {
+		q.Space("WHERE ?", genPathInDirsCondition("scan_problems", dirs))
+	}
+	return selectQuery[string](ctx, s.db, q)
+}
+
+func (s scanProblemRepository) CreateAll(ctx context.Context, params []repos.CreateScanProblemParams) error {
+	return s.tx(ctx, func(s scanProblemRepository) error {
+		return execBatch(params, func(params []repos.CreateScanProblemParams) error {
+			valueList := bqb.Optional("")
+			for _, p := range params {
+				valueList.Comma("(?,?,?,?,NOW(),NOW())", p.Path, string(p.Type), p.Message, p.MusicFolderID)
+			}
+			q := bqb.New(`INSERT INTO scan_problems (path,type,message,music_folder_id,created,updated) VALUES ?
+				ON CONFLICT (path, type) DO UPDATE SET message = EXCLUDED.message, music_folder_id = EXCLUDED.music_folder_id, updated = NOW()`, valueList)
+			return executeQuery(ctx, s.db, q)
+		})
+	})
+}
+
+func (s scanProblemRepository) DeleteByPaths(ctx context.Context, paths []string, types []repos.ScanProblemType) error {
+	return s.tx(ctx, func(s scanProblemRepository) error {
+		return execBatch(paths, func(paths []string) error {
+			q := bqb.New("DELETE FROM scan_problems WHERE path IN (?)", paths)
+			if len(types) > 0 {
+				q.And("type IN (?)", scanProblemTypesToStrings(types))
+			}
+			return executeQuery(ctx, s.db, q)
+		})
+	})
+}
+
+func genScanProblemsWhere(params repos.FindScanProblemsParams) *bqb.Query {
+	where := bqb.Optional("WHERE")
+	if len(params.Types) > 0 {
+		where.And("scan_problems.type IN (?)", scanProblemTypesToStrings(params.Types))
+	}
+	if params.MusicFolderIDs != nil {
+		where.And("?", genOneOfMusicFoldersCondition("scan_problems", params.MusicFolderIDs))
+	}
+	return where
+}
+
+func scanProblemTypesToStrings(types []repos.ScanProblemType) []string {
+	return util.Map(types, func(t repos.ScanProblemType) string {
+		return string(t)
+	})
+}
@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/juho05/crossonic-server/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanProblemRepository(t *testing.T) {
+	db, _ := thSetupDatabase(t)
+
+	repo := db.ScanProblem()
+
+	ctx := context.Background()
+
+	assert.Equalf(t, 0, thCount(t, db, "scan_problems"),
+		"there should be no scan problems at beginning of test")
+
+	user := thCreateUser(t, db)
+	musicFolderID := thCreateMusicFolder(t, db, user)
+	otherMusicFolderID := thCreateMusicFolder(t, db, user)
+
+	rootPath := "/music-" + uuid.NewString()
+	songPath := rootPath + "/a/song.mp3"
+	coverPath := rootPath + "/a/cover.jpg"
+	otherPath := rootPath + "/b/song.flac"
+
+	err := repo.CreateAll(ctx, []repos.CreateScanProblemParams{
+		{Path: songPath, Type: repos.ScanProblemMissingTitle, Message: "missing title", MusicFolderID: musicFolderID},
+		{Path: songPath, Type: repos.ScanProblemInvalidDate, Message: "invalid date", MusicFolderID: musicFolderID},
+		{Path: coverPath, Type: repos.ScanProblemUnsupportedCover, Message: "unsupported cover", MusicFolderID: musicFolderID},
+		{Path: otherPath, Type: repos.ScanProblemUnreadableTags, Message: "unreadable tags", MusicFolderID: otherMusicFolderID},
+	})
+	require.NoErrorf(t, err, "create scan problems: %v", err)
+
+	t.Run("CreateAll", func(t *testing.T) {
+		assert.Equal(t, 4, thCount(t, db, "scan_problems"))
+
+		err := repo.CreateAll(ctx, []repos.CreateScanProblemParams{
+			{Path: songPath, Type: repos.ScanProblemInvalidDate, Message: "still invalid", MusicFolderID: musicFolderID},
+		})
+		require.NoErrorf(t, err, "create existing scan problem: %v", err)
+		assert.Equal(t, 4, thCount(t, db, "scan_problems"))
+		assert.True(t, thExists(t, db, "scan_problems", map[string]any{
+			"path":    songPath,
+			"type":    string(repos.ScanProblemInvalidDate),
+			"message": "still invalid",
+		}), "message of existing problem should be updated")
+	})
+
+	t.Run("FindAll", func(t *testing.T) {
+		problems, err := repo.FindAll(ctx, repos.FindScanProblemsParams{})
+		require.NoErrorf(t, err, "find all: %v", err)
+		assert.Equal(t, []string{coverPath, songPath, songPath, otherPath}, util.Map(problems, func(p *repos.ScanProblem) string {
+			return p.Path
+		}), "problems should be sorted by path")
+
+		problems, err = repo.FindAll(ctx, repos.FindScanProblemsParams{
+			Types: []repos.ScanProblemType{repos.ScanProblemUnreadableTags, repos.ScanProblemMissingTitle},
+		})
+		require.NoErrorf(t, err, "find all by types: %v", err)
+		assert.Len(t, problems, 2)
+
+		problems, err = repo.FindAll(ctx, repos.FindScanProblemsParams{
+			MusicFolderIDs: []int{otherMusicFolderID},
+		})
+		require.NoErrorf(t, err, "find all by music folder: %v", err)
+		require.Len(t, problems, 1)
+		assert.Equal(t, otherPath, problems[0].Path)
+		assert.Equal(t, repos.ScanProblemUnreadableTags, problems[0].Type)
+		assert.Equal(t, "unreadable tags", problems[0].Message)
+
+		limit := 1
+		problems, err = repo.FindAll(ctx, repos.FindScanProblemsParams{
+			Paginate: repos.Paginate{Offset: 1, Limit: &limit},
+		})
+		require.NoErrorf(t, err, "find all paginated: %v", err)
+		require.Len(t, problems, 1)
+		assert.Equal(t, songPath, problems[0].Path)
+	})
+
+	t.Run("Count", func(t *testing.T) {
+		count, err := repo.Count(ctx, repos.FindScanProblemsParams{MusicFolderIDs: []int{musicFolderID}})
+		require.NoErrorf(t, err, "count: %v", err)
+		assert.Equal(t, 3, count)
+	})
+
+	t.Run("FindPaths", func(t *testing.T) {
+		paths, err := repo.FindPaths(ctx, []string{rootPath + "/a"})
+		require.NoErrorf(t, err, "find paths: %v", err)
+		assert.ElementsMatch(t, []string{songPath, coverPath}, paths)
+	})
+
+	t.Run("DeleteByPaths", func(t *testing.T) {
+		err := repo.DeleteByPaths(ctx, []string{songPath, coverPath}, []repos.ScanProblemType{repos.ScanProblemMissingTitle, repos.ScanProblemInvalidDate})
+		require.NoErrorf(t, err, "delete by paths and types: %v", err)
+		assert.Equal(t, 2, thCount(t, db, "scan_problems"))
+		assert.True(t, thExists(t, db, "scan_problems", map[string]any{"path": coverPath}), "problems of other types should be kept")
+
+		err = repo.DeleteByPaths(ctx, []string{coverPath, otherPath}, nil)
+		require.NoErrorf(t, err, "delete by paths: %v", err)
+		assert.Equal(t, 0, thCount(t, db, "scan_problems"))
+	})
+}
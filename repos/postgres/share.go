@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/juho05/crossonic-server"
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/nullism/bqb"
+)
+
+type shareRepository struct {
+	db executer
+	tx func(ctx context.Context, fn func(s shareRepository) error) error
+}
+
+func (s shareRepository) Create(ctx context.Context, user string, params repos.CreateShareParams) (*repos.Share, error) {
+	var share *repos.Share
+	err := s.tx(ctx, func(s shareRepository) error {
+		var err error
+		q := bqb.New(`INSERT INTO shares (id,user_name,description,created,updated,expires,visit_count)
+		VALUES (?,?,?,NOW(),NOW(),?,0) RETURNING *`, crossonic.GenIDShare(), user, params.Description, params.Expires)
+		share, err = getQuery[*repos.Share](ctx, s.db, q)
+		if err != nil {
+			return fmt.Errorf("insert share: %w", err)
+		}
+		index := 0
+		err = execBatch(params.EntryIDs, func(entryIDs []string) error {
+			valueList := bqb.Optional("")
+			for _, e := range entryIDs {
+				valueList.Comma("(?,?,?)", share.ID, e, index)
+				index++
+			}
+			return executeQuery(ctx, s.db, bqb.New("INSERT INTO share_entries (share_id,entry_id,index) VALUES ?", valueList))
+		})
+		if err != nil {
+			return fmt.Errorf("insert share entries: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return share, nil
+}
+
+func (s shareRepository) FindAll(ctx context.Context, user string) ([]*repos.Share, error) {
+	q := bqb.New("SELECT * FROM shares WHERE user_name = ? ORDER BY shares.created DESC", user)
+	return selectQuery[*repos.Share](ctx, s.db, q)
+}
+
+func (s shareRepository) FindByID(ctx context.Context, user, id string) (*repos.Share, error) {
+	q := bqb.New("SELECT * FROM shares WHERE user_name = ? AND id = ?", user, id)
+	return getQuery[*repos.Share](ctx, s.db, q)
+}
+
+func (s shareRepository) FindPublicByID(ctx context.Context, id string) (*repos.Share, error) {
+	q := bqb.New("SELECT * FROM shares WHERE id = ? AND (expires IS NULL OR expires > NOW())", id)
+	return getQuery[*repos.Share](ctx, s.db, q)
+}
+
+func (s shareRepository) GetEntries(ctx context.Context, id string) ([]string, error) {
+	q := bqb.New("SELECT entry_id FROM share_entries WHERE share_id = ? ORDER BY share_entries.index", id)
+	return selectQuery[string](ctx, s.db, q)
+}
+
+func (s shareRepository) Update(ctx context.Context, user, id string, params repos.UpdateShareParams) error {
+	updateList, empty := genUpdateList(map[string]repos.OptionalGetter{
+		"description": params.Description,
+		"expires":     params.Expires,
+	}, true)
+	if empty {
+		return nil
+	}
+	q := bqb.New("UPDATE shares SET ? WHERE user_name = ? AND id = ?", updateList, user, id)
+	return executeQueryExpectAffectedRows(ctx, s.db, q)
+}
+
+func (s shareRepository) RegisterVisit(ctx context.Context, id string) error {
+	q := bqb.New("UPDATE shares SET visit_count = visit_count + 1, last_visited = NOW() WHERE id = ?", id)
+	return executeQueryExpectAffectedRows(ctx, s.db, q)
+}
+
+func (s shareRepository) Delete(ctx context.Context, user, id string) error {
+	q := bqb.New("DELETE FROM shares WHERE user_name = ? AND id = ?", user, id)
+	return executeQueryExpectAffectedRows(ctx, s.db, q)
+}
@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/juho05/crossonic-server"
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/juho05/crossonic-server/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShareRepository(t *testing.T) {
+	db, _ := thSetupDatabase(t)
+
+	repo := db.Share()
+
+	ctx := context.Background()
+
+	assert.Equalf(t, 0, thCount(t, db, "shares"),
+		"there should be no shares at beginning of test")
+
+	createShare := func(user string, expires *time.Time, entryIDs ...string) string {
+		share, err := repo.Create(ctx, user, repos.CreateShareParams{
+			Description: util.ToPtr("Test Share"),
+			Expires:     expires,
+			EntryIDs:    entryIDs,
+		})
+		require.NoErrorf(t, err, "create share: %v", err)
+		return share.ID
+	}
+
+	user := thCreateUser(t, db)
+	user2 := thCreateUser(t, db)
+
+	t.Run("Create", func(t *testing.T) {
+		thDeleteAll(t, db, "shares")
+		share, err := repo.Create(ctx, user, repos.CreateShareParams{
+			Description: util.ToPtr("Test Share"),
+			EntryIDs:    []string{"tr_abcdefghijkl", "al_abcdefghijkl"},
+		})
+		require.NoErrorf(t, err, "create share")
+		require.NotNil(t, share)
+		assert.Truef(t, crossonic.IsIDType(share.ID, crossonic.IDTypeShare),
+			"expected valid ID, got: %s", share.ID)
+		assert.Equal(t, user, share.User)
+		assert.Equal(t, util.ToPtr("Test Share"), share.Description)
+		assert.Nil(t, share.Expires)
+		assert.Nil(t, share.LastVisited)
+		assert.Equal(t, 0, share.VisitCount)
+
+		assert.True(t, thExists(t, db, "shares", map[string]any{
+			"id":          share.ID,
+			"user_name":   user,
+			"description": "Test Share",
+		}), "created share should exist in db")
+		assert.Equal(t, 2, thCountWhere(t, db, "share_entries", "share_id = '"+share.ID+"'"))
+	})
+
+	t.Run("GetEntries", func(t *testing.T) {
+		thDeleteAll(t, db, "shares")
+		id := createShare(user, nil, "pl_abcdefghijkl", "tr_abcdefghijkl", "al_abcdefghijkl")
+		entries, err := repo.GetEntries(ctx, id)
+		require.NoErrorf(t, err, "get entries: %v", err)
+		assert.Equal(t, []string{"pl_abcdefghijkl", "tr_abcdefghijkl", "al_abcdefghijkl"}, entries)
+	})
+
+	t.Run("FindAll", func(t *testing.T) {
+		thDeleteAll(t, db, "shares")
+		share1 := createShare(user, nil)
+		share2 := createShare(user, nil)
+		createShare(user2, nil)
+
+		shares, err := repo.FindAll(ctx, user)
+		require.NoErrorf(t, err, "find all shares: %v", err)
+		ids := util.Map(shares, func(s *repos.Share) string {
+			return s.ID
+		})
+		assert.ElementsMatch(t, []string{share1, share2}, ids)
+	})
+
+	t.Run("FindByID", func(t *testing.T) {
+		thDeleteAll(t, db, "shares")
+		id := createShare(user, nil)
+
+		share, err := repo.FindByID(ctx, user, id)
+		require.NoErrorf(t, err, "find share by id: %v", err)
+		assert.Equal(t, id, share.ID)
+
+		_, err = repo.FindByID(ctx, user2, id)
+		assert.Truef(t, errors.Is(err, repos.ErrNotFound), "expected ErrNotFound, got: %v", err)
+	})
+
+	t.Run("FindPublicByID", func(t *testing.T) {
+		thDeleteAll(t, db, "shares")
+		valid := createShare(user, util.ToPtr(time.Now().Add(time.Hour)))
+		noExpiry := createShare(user, nil)
+		expired := createShare(user, util.ToPtr(time.Now().Add(-time.Hour)))
+
+		share, err := repo.FindPublicByID(ctx, valid)
+		require.NoErrorf(t, err, "find public share: %v", err)
+		assert.Equal(t, valid, share.ID)
+
+		share, err = repo.FindPublicByID(ctx, noExpiry)
+		require.NoErrorf(t, err, "find public share: %v", err)
+		assert.Equal(t, noExpiry, share.ID)
+
+		_, err = repo.FindPublicByID(ctx, expired)
+		assert.Truef(t, errors.Is(err, repos.ErrNotFound), "expected ErrNotFound, got: %v", err)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		thDeleteAll(t, db, "shares")
+		id := createShare(user, nil)
+
+		expires := time.Now().Add(time.Hour).Truncate(time.Second)
+		err := repo.Update(ctx, user, id, repos.UpdateShareParams{
+			Description: repos.NewOptionalFull[*string](nil),
+			Expires:     repos.NewOptionalFull(&expires),
+		})
+		require.NoErrorf(t, err, "update share: %v", err)
+
+		share, err := repo.FindByID(ctx, user, id)
+		require.NoErrorf(t, err, "find share by id: %v", err)
+		assert.Nil(t, share.Description)
+		require.NotNil(t, share.Expires)
+		assert.True(t, expires.Equal(*share.Expires))
+
+		err = repo.Update(ctx, user2, id, repos.UpdateShareParams{
+			Description: repos.NewOptionalFull(util.ToPtr("other")),
+		})
+		assert.Truef(t, errors.Is(err, repos.ErrNotFound), "expected ErrNotFound, got: %v", err)
+	})
+
+	t.Run("RegisterVisit", func(t *testing.T) {
+		thDeleteAll(t, db, "shares")
+		id := createShare(user, nil)
+
+		require.NoError(t, repo.RegisterVisit(ctx, id))
+		require.NoError(t, repo.RegisterVisit(ctx, id))
+
+		share, err := repo.FindByID(ctx, user, id)
+		require.NoErrorf(t, err, "find share by id: %v", err)
+		assert.Equal(t, 2, share.VisitCount)
+		assert.NotNil(t, share.LastVisited)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		thDeleteAll(t, db, "shares")
+		id := createShare(user, nil, "tr_abcdefghijkl")
+
+		err := repo.Delete(ctx, user2, id)
+		assert.Truef(t, errors.Is(err, repos.ErrNotFound), "expected ErrNotFound, got: %v", err)
+
+		err = repo.Delete(ctx, user, id)
+		require.NoErrorf(t, err, "delete share: %v", err)
+		assert.Equal(t, 0, thCount(t, db, "shares"))
+		assert.Equal(t, 0, thCount(t, db, "share_entries"))
+	})
+}
@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/nullism/bqb"
+)
+
+type similarityRepository struct {
+	db executer
+	tx func(ctx context.Context, fn func(s similarityRepository) error) error
+}
+
+func (s similarityRepository) FindSongCandidates(ctx context.Context, genres []string, artistIDs []string, musicFolderIDs []int, limit int) ([]string, error) {
+	matches := bqb.Optional("")
+	if len(genres) > 0 {
+		matches.Join(" UNION ALL ", "SELECT song_id, 1 AS weight FROM song_genre WHERE genre_name IN (?)", genres)
+	}
+	if len(artistIDs) > 0 {
+		matches.Join(" UNION ALL ", `SELECT a.song_id, 1 AS weight FROM (
+			SELECT song_id FROM song_artist WHERE artist_id IN (?)
+			UNION SELECT songs.id AS song_id FROM songs JOIN album_artist ON album_artist.album_id = songs.album_id WHERE album_artist.artist_id IN (?)
+		) a`, artistIDs, artistIDs)
+	}
+	if matches.Empty() {
+		return []string{}, nil
+	}
+	q := bqb.New(`SELECT songs.id FROM songs
+		JOIN (SELECT m.song_id, SUM(m.weight) AS weight FROM (?) m GROUP BY m.song_id) matches ON matches.song_id = songs.id
+		WHERE ? ORDER BY matches.weight DESC, random() LIMIT ?`, matches, genOneOfMusicFoldersCondition("songs", musicFolderIDs), limit)
+	return selectQuery[string](ctx, s.db, q)
+}
+
+func (s similarityRepository) GetSongCoOccurrences(ctx context.Context, songIDs []string, window time.Duration, musicFolderIDs []int, limit int) ([]*repos.SimilarityCount, error) {
+	if len(songIDs) == 0 {
+		return []*repos.SimilarityCount{}, nil
+	}
+	q := bqb.New(`SELECT c.song_id AS id, CAST(SUM(c.count) AS int) AS count FROM (
+			SELECT other.song_id, COUNT(DISTINCT other.playlist_id) AS count FROM playlist_song seed
+			JOIN playlist_song other ON other.playlist_id = seed.playlist_id
+			WHERE seed.song_id IN (?) GROUP BY other.song_id
+			UNION ALL
+			SELECT other.song_id, COUNT(*) AS count FROM scrobbles seed
+			JOIN scrobbles other ON other.user_name = seed.user_name AND other.now_playing = false
+				AND other.time BETWEEN seed.time - make_interval(secs => ?) AND seed.time + make_interval(secs => ?)
+			WHERE seed.song_id IN (?) AND seed.now_playing = false GROUP BY other.song_id
+		) c JOIN songs ON songs.id = c.song_id
+		WHERE c.song_id NOT IN (?) AND ?
+		GROUP BY c.song_id ORDER BY count DESC LIMIT ?`,
+		songIDs, window.Seconds(), window.Seconds(), songIDs, songIDs, genOneOfMusicFoldersCondition("songs", musicFolderIDs), limit)
+	return selectQuery[*repos.SimilarityCount](ctx, s.db, q)
+}
+
+func (s similarityRepository) GetArtistFeatures(ctx context.Context, artistIDs []string, musicFolderIDs []int) ([]*repos.ArtistFeatures, error) {
+	if len(artistIDs) == 0 {
+		return []*repos.ArtistFeatures{}, nil
+	}
+	q := bqb.New(`SELECT artists.id, artists.name, artists.music_brainz_id, CAST(COUNT(songs.id) AS int) AS song_count,
+			CAST(AVG(songs.bpm) AS double precision) AS avg_bpm,
+			CAST(AVG(CAST(NULLIF(SPLIT_PART(COALESCE(songs.original_date, songs.release_date), '-', 1), '') AS int)) AS double precision) AS avg_year
+		FROM artists
+		LEFT JOIN song_artist ON song_artist.artist_id = artists.id
+		LEFT JOIN songs ON songs.id = song_artist.song_id AND ?
+		WHERE artists.id IN (?) AND ? GROUP BY artists.id, artists.name, artists.music_brainz_id`,
+		genOneOfMusicFoldersCondition("songs", musicFolderIDs), artistIDs, genArtistInMusicFolderCondition("artists", musicFolderIDs))
+	features, err := selectQuery[*repos.ArtistFeatures](ctx, s.db, q)
+	if err != nil {
+		return nil, fmt.Errorf("select features: %w", err)
+	}
+
+	type artistGenre struct {
+		ArtistID string `db:"artist_id"`
+		Genre    string `db:"genre_name"`
+		Count    int    `db:"count"`
+	}
+	genres, err := selectQuery[artistGenre](ctx, s.db, bqb.New(`SELECT song_artist.artist_id, song_genre.genre_name, CAST(COUNT(*) AS int) AS count
+		FROM song_artist JOIN song_genre ON song_genre.song_id = song_artist.song_id
+		JOIN songs ON songs.id = song_artist.song_id
+		WHERE song_artist.artist_id IN (?) AND ? GROUP BY song_artist.artist_id, song_genre.genre_name`,
+		artistIDs, genOneOfMusicFoldersCondition("songs", musicFolderIDs)))
+	if err != nil {
+		return nil, fmt.Errorf("select genres: %w", err)
+	}
+	genreMap := make(map[string]map[string]int, len(features))
+	for _, g := range genres {
+		if genreMap[g.ArtistID] == nil {
+			genreMap[g.ArtistID] = make(map[string]int)
+		}
+		genreMap[g.ArtistID][g.Genre] = g.Count
+	}
+	for _, f := range features {
+		f.Genres = genreMap[f.ID]
+		if f.Genres == nil {
+			f.Genres = map[string]int{}
+		}
+	}
+	return features, nil
+}
+
+func (s similarityRepository) FindArtistCandidates(ctx context.Context, genres []string, musicFolderIDs []int, limit int) ([]string, error) {
+	if len(genres) == 0 {
+		return []string{}, nil
+	}
+	q := bqb.New(`SELECT artists.id FROM artists
+		JOIN (
+			SELECT song_artist.artist_id, COUNT(*) AS count FROM song_artist
+			JOIN song_genre ON song_genre.song_id = song_artist.song_id
+			WHERE song_genre.genre_name IN (?) GROUP BY song_artist.artist_id
+		) g ON g.artist_id = artists.id
+		WHERE ? ORDER BY g.count DESC LIMIT ?`, genres, genArtistInMusicFolderCondition("artists", musicFolderIDs), limit)
+	return selectQuery[string](ctx, s.db, q)
+}
+
+func (s similarityRepository) GetArtistRelations(ctx context.Context, artistID string, musicFolderIDs []int) ([]*repos.SimilarityCount, error) {
+	q := bqb.New(`SELECT artists.id, CAST(COUNT(*) AS int) AS count FROM (
+			SELECT other.artist_id FROM song_artist seed
+			JOIN song_artist other ON other.song_id = seed.song_id
+			WHERE seed.artist_id = ?
+			UNION ALL
+			SELECT other.artist_id FROM album_artist seed
+			JOIN album_artist other ON other.album_id = seed.album_id
+			WHERE seed.artist_id = ?
+			UNION ALL
+			SELECT song_artist.artist_id FROM album_artist
+			JOIN songs ON songs.album_id = album_artist.album_id
+			JOIN song_artist ON song_artist.song_id = songs.id
+			WHERE album_artist.artist_id = ?
+			UNION ALL
+			SELECT album_artist.artist_id FROM song_artist
+			JOIN songs ON songs.id = song_artist.song_id
+			JOIN album_artist ON album_artist.album_id = songs.album_id
+			WHERE song_artist.artist_id = ?
+		) r JOIN artists ON artists.id = r.artist_id
+		WHERE artists.id <> ? AND ?
+		GROUP BY artists.id`, artistID, artistID, artistID, artistID, artistID, genArtistInMusicFolderCondition("artists", musicFolderIDs))
+	return selectQuery[*repos.SimilarityCount](ctx, s.db, q)
+}
+
+func (s similarityRepository) GetArtistCoOccurrences(ctx context.Context, artistID string, window time.Duration, musicFolderIDs []int, limit int) ([]*repos.SimilarityCount, error) {
+	q := bqb.New(`SELECT artists.id, CAST(SUM(c.count) AS int) AS count FROM (
+			SELECT other_artist.artist_id, COUNT(DISTINCT other.playlist_id) AS count FROM song_artist seed_artist
+			JOIN playlist_song seed ON seed.song_id = seed_artist.song_id
+			JOIN playlist_song other ON other.playlist_id = seed.playlist_id
+			JOIN song_artist other_artist ON other_artist.song_id = other.song_id
+			WHERE seed_artist.artist_id = ? GROUP BY other_artist.artist_id
+			UNION ALL
+			SELECT other_artist.artist_id, COUNT(*) AS count FROM song_artist seed_artist
+			JOIN scrobbles seed ON seed.song_id = seed_artist.song_id AND seed.now_playing = false
+			JOIN scrobbles other ON other.user_name = seed.user_name AND other.now_playing = false
+				AND other.time BETWEEN seed.time - make_interval(secs => ?) AND seed.time + make_interval(secs => ?)
+			JOIN song_artist other_artist ON other_artist.song_id = other.song_id
+			WHERE seed_artist.artist_id = ? GROUP BY other_artist.artist_id
+		) c JOIN artists ON artists.id = c.artist_id
+		WHERE artists.id <> ? AND ?
+		GROUP BY artists.id ORDER BY count DESC LIMIT ?`,
+		artistID, window.Seconds(), window.Seconds(), artistID, artistID, genArtistInMusicFolderCondition("artists", musicFolderIDs), limit)
+	return selectQuery[*repos.SimilarityCount](ctx, s.db, q)
+}
+
+func (s similarityRepository) GetArtistTopSongs(ctx context.Context, artistID string, musicFolderIDs []int, limit int) ([]*repos.SimilarityCount, error) {
+	q := bqb.New(`SELECT songs.id, CAST(COUNT(scrobbles.song_id) AS int) AS count FROM songs
+		JOIN song_artist ON song_artist.song_id = songs.id
+		LEFT JOIN scrobbles ON scrobbles.song_id = songs.id AND scrobbles.now_playing = false
+		WHERE song_artist.artist_id = ? AND ?
+		GROUP BY songs.id ORDER BY count DESC, random() LIMIT ?`, artistID, genOneOfMusicFoldersCondition("songs", musicFolderIDs), limit)
+	return selectQuery[*repos.SimilarityCount](ctx, s.db, q)
+}
@@ -81,11 +81,15 @@ func (s songRepository) FindAllFiltered(ctx context.Context, filter repos.SongFi
 		where.And("(song_stars.created IS NOT NULL)")
 	}
 
+	bpmColumn := "songs.bpm"
+	if filter.UseDetectedBPM {
+		bpmColumn = "COALESCE(songs.bpm, songs.detected_bpm)"
+	}
 	if filter.MinBPM != nil {
-		where.And("(songs.bpm IS NOT NULL AND songs.bpm >= ?)", *filter.MinBPM)
+		where.And(fmt.Sprintf("(%s IS NOT NULL AND %s >= ?)", bpmColumn, bpmColumn), *filter.MinBPM)
 	}
 	if filter.MaxBPM != nil {
-		where.And("(songs.bpm IS NOT NULL AND songs.bpm <= ?)", *filter.MaxBPM)
+		where.And(fmt.Sprintf("(%s IS NOT NULL AND %s <= ?)", bpmColumn, bpmColumn), *filter.MaxBPM)
 	}
 
 	if filter.FromYear != nil {
@@ -135,7 +139,7 @@ func (s songRepository) FindAllFiltered(ctx context.Context, filter repos.SongFi
 			}
 			orderBy.Comma("song_stars.created")
 		case repos.SongOrderBPM:
-			orderBy.Comma("songs.bpm")
+			orderBy.Comma(bpmColumn)
 		}
 
 		if *filter.Order != repos.SongOrderRandom {
@@ -205,8 +209,12 @@ func (s songRepository) FindNonExistentIDs(ctx context.Context, ids []string) ([
 	return result, err
 }
 
-func (s songRepository) FindPaths(ctx context.Context, updatedBefore time.Time, paginate repos.Paginate) ([]string, error) {
-	q := bqb.New("SELECT songs.path FROM songs WHERE songs.updated <= ? ORDER BY songs.id", updatedBefore)
+func (s songRepository) FindPaths(ctx context.Context, updatedBefore time.Time, dirs []string, paginate repos.Paginate) ([]string, error) {
+	q := bqb.New("SELECT songs.path FROM songs WHERE songs.updated <= ?", updatedBefore)
+	if dirs != nil {
+		q.And("?", genPathInDirsCondition("songs", dirs))
+	}
+	q.Space("ORDER BY songs.id")
 	paginate.Apply(q)
 	return selectQuery[string](ctx, s.db, q)
 }
@@ -220,8 +228,18 @@ func (s songRepository) DeleteByPaths(ctx context.Context, paths []string) error
 	})
 }
 
+func (s songRepository) FindPathsUpdatedSince(ctx context.Context, since time.Time) ([]string, error) {
+	q := bqb.New("SELECT songs.path FROM songs WHERE songs.updated >= ?", since)
+	return selectQuery[string](ctx, s.db, q)
+}
+
 func (s songRepository) GetStreamInfo(ctx context.Context, id, user string) (*repos.SongStreamInfo, error) {
-	q := bqb.New("SELECT songs.path, songs.bit_rate, songs.content_type, songs.duration_ms, songs.channel_count FROM songs ? WHERE songs.id = ?", genMusicFolderUserJoin("songs", user), id)
+	q := bqb.New(`SELECT songs.path, songs.bit_rate, songs.content_type, songs.duration_ms, songs.channel_count, songs.sampling_rate,
+		COALESCE(songs.replay_gain, songs.computed_replay_gain) AS replay_gain,
+		CASE WHEN songs.replay_gain IS NULL THEN songs.computed_replay_gain_peak ELSE songs.replay_gain_peak END AS replay_gain_peak,
+		COALESCE(albums.replay_gain, albums.computed_replay_gain) AS album_replay_gain,
+		CASE WHEN albums.replay_gain IS NULL THEN albums.computed_replay_gain_peak ELSE albums.replay_gain_peak END AS album_replay_gain_peak
+		FROM songs ? LEFT JOIN albums ON albums.id = songs.album_id WHERE songs.id = ?`, genMusicFolderUserJoin("songs", user), id)
 	return getQuery[*repos.SongStreamInfo](ctx, s.db, q)
 }
 
@@ -313,8 +331,21 @@ func (s songRepository) TryUpdateAll(ctx context.Context, params []repos.UpdateS
 	return count, err
 }
 
-func (s songRepository) DeleteLastUpdatedBefore(ctx context.Context, before time.Time) error {
-	return executeQuery(ctx, s.db, bqb.New("DELETE FROM songs WHERE updated < ?", before))
+func (s songRepository) DeleteLastUpdatedBefore(ctx context.Context, before time.Time, dirs []string) error {
+	q := bqb.New("DELETE FROM songs WHERE updated < ?", before)
+	if dirs != nil {
+		q.And("?", genPathInDirsCondition("songs", dirs))
+	}
+	return executeQuery(ctx, s.db, q)
+}
+
+func (s songRepository) MarkUpdatedByPaths(ctx context.Context, paths []string) error {
+	return s.tx(ctx, func(s songRepository) error {
+		return execBatch(paths, func(paths []string) error {
+			q := bqb.New("UPDATE songs SET updated = NOW() WHERE ?", genPathInDirsCondition("songs", paths))
+			return executeQuery(ctx, s.db, q)
+		})
+	})
 }
 
 func (s songRepository) DeleteArtistConnections(ctx context.Context, songIDs []string) error {
@@ -508,7 +539,87 @@ func (s songRepository) Count(ctx context.Context) (int, error) {
 }
 
 func (s songRepository) GetMedianReplayGain(ctx context.Context) (float64, error) {
-	return getQuery[float64](ctx, s.db, bqb.New("SELECT COALESCE(PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY songs.replay_gain), 0) FROM songs"))
+	return getQuery[float64](ctx, s.db, bqb.New("SELECT COALESCE(PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY COALESCE(songs.replay_gain, songs.computed_replay_gain)), 0) FROM songs"))
+}
+
+func (s songRepository) FindMissingBPM(ctx context.Context) ([]*repos.SongAnalysisInfo, error) {
+	q := bqb.New(`SELECT songs.id, songs.path, songs.album_id, songs.duration_ms FROM songs
+		WHERE songs.bpm IS NULL AND NOT songs.bpm_analyzed ORDER BY songs.path`)
+	return selectQuery[*repos.SongAnalysisInfo](ctx, s.db, q)
+}
+
+func (s songRepository) SetDetectedBPM(ctx context.Context, id string, bpm *int) error {
+	q := bqb.New("UPDATE songs SET detected_bpm = ?, bpm_analyzed = true WHERE id = ?", bpm, id)
+	return executeQueryExpectAffectedRows(ctx, s.db, q)
+}
+
+func (s songRepository) ResetDetectedBPM(ctx context.Context, songIDs []string) error {
+	if len(songIDs) == 0 {
+		return nil
+	}
+	q := bqb.New("UPDATE songs SET detected_bpm = NULL, bpm_analyzed = false WHERE songs.id IN (?)", songIDs)
+	return executeQuery(ctx, s.db, q)
+}
+
+func (s songRepository) FindMissingReplayGain(ctx context.Context) ([]*repos.SongAnalysisInfo, error) {
+	q := bqb.New(`SELECT songs.id, songs.path, songs.album_id, songs.duration_ms FROM songs
+		WHERE songs.replay_gain IS NULL AND songs.computed_replay_gain IS NULL ORDER BY songs.path`)
+	return selectQuery[*repos.SongAnalysisInfo](ctx, s.db, q)
+}
+
+func (s songRepository) SetComputedReplayGain(ctx context.Context, id string, gain, peak float64) error {
+	q := bqb.New("UPDATE songs SET computed_replay_gain = ?, computed_replay_gain_peak = ? WHERE id = ?", gain, peak, id)
+	return executeQueryExpectAffectedRows(ctx, s.db, q)
+}
+
+func (s songRepository) ResetComputedReplayGain(ctx context.Context, songIDs []string) error {
+	if len(songIDs) == 0 {
+		return nil
+	}
+	return s.tx(ctx, func(s songRepository) error {
+		q := bqb.New(`UPDATE albums SET computed_replay_gain = NULL, computed_replay_gain_peak = NULL
+			WHERE albums.id IN (SELECT songs.album_id FROM songs WHERE songs.id IN (?))`, songIDs)
+		err := executeQuery(ctx, s.db, q)
+		if err != nil {
+			return fmt.Errorf("reset albums: %w", err)
+		}
+		q = bqb.New("UPDATE songs SET computed_replay_gain = NULL, computed_replay_gain_peak = NULL WHERE songs.id IN (?)", songIDs)
+		err = executeQuery(ctx, s.db, q)
+		if err != nil {
+			return fmt.Errorf("reset songs: %w", err)
+		}
+		return nil
+	})
+}
+
+func (s songRepository) FindFingerprints(ctx context.Context, dirs []string) ([]*repos.SongFingerprint, error) {
+	q := bqb.New("SELECT songs.id, songs.path, songs.fingerprint, songs.content_hash FROM songs WHERE songs.fingerprint IS NOT NULL")
+	if dirs != nil {
+		q.And("?", genPathInDirsCondition("songs", dirs))
+	}
+	return selectQuery[*repos.SongFingerprint](ctx, s.db, q)
+}
+
+func (s songRepository) FindByFingerprints(ctx context.Context, fingerprints []string) ([]*repos.SongFingerprint, error) {
+	if len(fingerprints) == 0 {
+		return []*repos.SongFingerprint{}, nil
+	}
+	q := bqb.New("SELECT songs.id, songs.path, songs.fingerprint, songs.content_hash FROM songs WHERE songs.fingerprint IN (?)", fingerprints)
+	return selectQuery[*repos.SongFingerprint](ctx, s.db, q)
+}
+
+func (s songRepository) SetFingerprints(ctx context.Context, params []repos.SetSongFingerprintParams) error {
+	return s.tx(ctx, func(s songRepository) error {
+		return execBatch(params, func(params []repos.SetSongFingerprintParams) error {
+			valueList := bqb.Optional("")
+			for _, p := range params {
+				valueList.Comma("(?,?,?)", p.ID, p.Fingerprint, p.ContentHash)
+			}
+			q := bqb.New(`UPDATE songs SET fingerprint = v.fingerprint, content_hash = v.content_hash
+				FROM (VALUES ?) AS v(id, fingerprint, content_hash) WHERE songs.id = v.id`, valueList)
+			return executeQuery(ctx, s.db, q)
+		})
+	})
 }
 
 func (s songRepository) DeleteAllWithoutMusicFolderID(ctx context.Context) error {
@@ -520,11 +631,13 @@ func (s songRepository) DeleteAllWithoutMusicFolderID(ctx context.Context) error
 func genSongSelectList(include repos.IncludeSongInfo) *bqb.Query {
 	q := bqb.New(`songs.id, songs.path, songs.album_id, songs.title, songs.track, songs.original_date, songs.release_date, songs.size, songs.content_type,
 		songs.duration_ms, songs.bit_rate, songs.sampling_rate, songs.channel_count, songs.disc_number, songs.created, songs.updated,
-		songs.bpm, songs.music_brainz_id, songs.replay_gain, songs.replay_gain_peak, songs.lyrics, songs.music_folder_id`)
+		songs.bpm, songs.music_brainz_id, songs.replay_gain, songs.replay_gain_peak, songs.lyrics, songs.music_folder_id,
+		songs.computed_replay_gain, songs.computed_replay_gain_peak, songs.detected_bpm`)
 
 	if include.Album {
 		q.Comma(`albums.name as album_name, albums.replay_gain as album_replay_gain, albums.replay_gain_peak as album_replay_gain_peak,
-		albums.music_brainz_id as album_music_brainz_id, albums.release_mbid as album_release_mbid`)
+		albums.music_brainz_id as album_music_brainz_id, albums.release_mbid as album_release_mbid,
+		albums.computed_replay_gain as album_computed_replay_gain, albums.computed_replay_gain_peak as album_computed_replay_gain_peak`)
 	}
 
 	if include.Annotations {
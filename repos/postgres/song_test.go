@@ -707,9 +707,77 @@ func TestSongRepository(t *testing.T) {
 			{ID: &id, Path: path, Title: "Test", Size: 1, ContentType: "audio/mpeg", Duration: repos.NewDurationMS(1000), BitRate: 128, SamplingRate: 44100, ChannelCount: 2, MusicFolderID: folderID},
 		}))
 
-		paths, err := repo.FindPaths(ctx, time.Now().Add(time.Hour), repos.Paginate{})
+		paths, err := repo.FindPaths(ctx, time.Now().Add(time.Hour), nil, repos.Paginate{})
 		require.NoErrorf(t, err, "find paths: %v", err)
 		assert.Contains(t, paths, path)
+
+		t.Run("in dirs", func(t *testing.T) {
+			paths, err := repo.FindPaths(ctx, time.Now().Add(time.Hour), []string{"/test"}, repos.Paginate{})
+			require.NoErrorf(t, err, "find paths: %v", err)
+			assert.Contains(t, paths, path)
+
+			paths, err = repo.FindPaths(ctx, time.Now().Add(time.Hour), []string{path}, repos.Paginate{})
+			require.NoErrorf(t, err, "find paths: %v", err)
+			assert.Equal(t, []string{path}, paths)
+
+			paths, err = repo.FindPaths(ctx, time.Now().Add(time.Hour), []string{"/tes", "/other"}, repos.Paginate{})
+			require.NoErrorf(t, err, "find paths: %v", err)
+			assert.NotContains(t, paths, path)
+		})
+	})
+
+	t.Run("FindPathsUpdatedSince", func(t *testing.T) {
+		folderID := thCreateMusicFolder(t, db, user)
+		since := time.Now().Add(-time.Minute)
+		id := crossonic.GenIDSong()
+		path := "/test/updatedsince-" + id + ".mp3"
+		require.NoError(t, repo.CreateAll(ctx, []repos.CreateSongParams{
+			{ID: &id, Path: path, Title: "Test", Size: 1, ContentType: "audio/mpeg", Duration: repos.NewDurationMS(1000), BitRate: 128, SamplingRate: 44100, ChannelCount: 2, MusicFolderID: folderID},
+		}))
+
+		paths, err := repo.FindPathsUpdatedSince(ctx, since)
+		require.NoErrorf(t, err, "find paths updated since: %v", err)
+		assert.Contains(t, paths, path)
+
+		paths, err = repo.FindPathsUpdatedSince(ctx, time.Now().Add(time.Hour))
+		require.NoErrorf(t, err, "find paths updated since: %v", err)
+		assert.NotContains(t, paths, path)
+	})
+
+	t.Run("Fingerprints", func(t *testing.T) {
+		folderID := thCreateMusicFolder(t, db, user)
+		id1 := crossonic.GenIDSong()
+		id2 := crossonic.GenIDSong()
+		path1 := "/test/fingerprint-" + id1 + ".mp3"
+		path2 := "/test/fingerprint-" + id2 + ".mp3"
+		require.NoError(t, repo.CreateAll(ctx, []repos.CreateSongParams{
+			{ID: &id1, Path: path1, Title: "FP1", Size: 1, ContentType: "audio/mpeg", Duration: repos.NewDurationMS(1000), BitRate: 128, SamplingRate: 44100, ChannelCount: 2, MusicFolderID: folderID},
+			{ID: &id2, Path: path2, Title: "FP2", Size: 1, ContentType: "audio/mpeg", Duration: repos.NewDurationMS(1000), BitRate: 128, SamplingRate: 44100, ChannelCount: 2, MusicFolderID: folderID},
+		}))
+
+		err := repo.SetFingerprints(ctx, []repos.SetSongFingerprintParams{
+			{ID: id1, Fingerprint: "audio-" + id1, ContentHash: "content-" + id1},
+		})
+		require.NoErrorf(t, err, "set fingerprints: %v", err)
+
+		t.Run("FindFingerprints returns songs with fingerprint", func(t *testing.T) {
+			fingerprints, err := repo.FindFingerprints(ctx, []string{path1, path2})
+			require.NoErrorf(t, err, "find fingerprints: %v", err)
+			assert.Equal(t, []*repos.SongFingerprint{
+				{ID: id1, Path: path1, Fingerprint: "audio-" + id1, ContentHash: "content-" + id1},
+			}, fingerprints)
+		})
+
+		t.Run("FindByFingerprints", func(t *testing.T) {
+			fingerprints, err := repo.FindByFingerprints(ctx, []string{"audio-" + id1, "audio-" + id2})
+			require.NoErrorf(t, err, "find by fingerprints: %v", err)
+			require.Len(t, fingerprints, 1)
+			assert.Equal(t, id1, fingerprints[0].ID)
+
+			fingerprints, err = repo.FindByFingerprints(ctx, nil)
+			require.NoErrorf(t, err, "find by fingerprints: %v", err)
+			assert.Empty(t, fingerprints)
+		})
 	})
 
 	t.Run("DeleteByPaths", func(t *testing.T) {
@@ -872,24 +940,66 @@ func TestSongRepository(t *testing.T) {
 		songID := thCreateSong(t, db, nil, folderID)
 
 		t.Run("keeps recently updated songs", func(t *testing.T) {
-			err := repo.DeleteLastUpdatedBefore(ctx, time.Now().Add(-time.Hour))
+			err := repo.DeleteLastUpdatedBefore(ctx, time.Now().Add(-time.Hour), nil)
+			require.NoErrorf(t, err, "delete: %v", err)
+			assert.True(t, thExists(t, db, "songs", map[string]any{"id": songID}))
+		})
+
+		t.Run("keeps songs outside of dirs", func(t *testing.T) {
+			err := repo.DeleteLastUpdatedBefore(ctx, time.Now().Add(time.Hour), []string{"/does-not-exist"})
 			require.NoErrorf(t, err, "delete: %v", err)
 			assert.True(t, thExists(t, db, "songs", map[string]any{"id": songID}))
 		})
 
 		t.Run("deletes old songs", func(t *testing.T) {
-			err := repo.DeleteLastUpdatedBefore(ctx, time.Now().Add(time.Hour))
+			err := repo.DeleteLastUpdatedBefore(ctx, time.Now().Add(time.Hour), nil)
 			require.NoErrorf(t, err, "delete: %v", err)
 			assert.False(t, thExists(t, db, "songs", map[string]any{"id": songID}))
 		})
 	})
 
+	t.Run("MarkUpdatedByPaths", func(t *testing.T) {
+		folderID := thCreateMusicFolder(t, db, user)
+		id := crossonic.GenIDSong()
+		path := "/test/markupdated-" + id + ".mp3"
+		require.NoError(t, repo.CreateAll(ctx, []repos.CreateSongParams{
+			{ID: &id, Path: path, Title: "Test", Size: 1, ContentType: "audio/mpeg", Duration: repos.NewDurationMS(1000), BitRate: 128, SamplingRate: 44100, ChannelCount: 2, MusicFolderID: folderID},
+		}))
+		_, err := db.db.ExecContext(ctx, "UPDATE songs SET updated = $1 WHERE id = $2", time.Now().Add(-time.Hour), id)
+		require.NoError(t, err)
+
+		err = repo.MarkUpdatedByPaths(ctx, []string{path})
+		require.NoErrorf(t, err, "mark updated by paths: %v", err)
+
+		err = repo.DeleteLastUpdatedBefore(ctx, time.Now().Add(-time.Minute), []string{path})
+		require.NoErrorf(t, err, "delete: %v", err)
+		assert.True(t, thExists(t, db, "songs", map[string]any{"id": id}))
+
+		t.Run("songs inside of directories", func(t *testing.T) {
+			id := crossonic.GenIDSong()
+			dir := "/test/markupdated-dir-" + id
+			require.NoError(t, repo.CreateAll(ctx, []repos.CreateSongParams{
+				{ID: &id, Path: dir + "/song.mp3", Title: "Test", Size: 1, ContentType: "audio/mpeg", Duration: repos.NewDurationMS(1000), BitRate: 128, SamplingRate: 44100, ChannelCount: 2, MusicFolderID: folderID},
+			}))
+			_, err := db.db.ExecContext(ctx, "UPDATE songs SET updated = $1 WHERE id = $2", time.Now().Add(-time.Hour), id)
+			require.NoError(t, err)
+
+			err = repo.MarkUpdatedByPaths(ctx, []string{dir})
+			require.NoErrorf(t, err, "mark updated by paths: %v", err)
+
+			err = repo.DeleteLastUpdatedBefore(ctx, time.Now().Add(-time.Minute), []string{dir})
+			require.NoErrorf(t, err, "delete: %v", err)
+			assert.True(t, thExists(t, db, "songs", map[string]any{"id": id}))
+		})
+	})
+
 	t.Run("GetStreamInfo", func(t *testing.T) {
 		folderID := thCreateMusicFolder(t, db, user)
 		id := crossonic.GenIDSong()
 		path := "/test/stream-" + id + ".mp3"
 		require.NoError(t, repo.CreateAll(ctx, []repos.CreateSongParams{
-			{ID: &id, Path: path, Title: "Stream Test", Size: 1, ContentType: "audio/mpeg", Duration: repos.NewDurationMS(180000), BitRate: 320, SamplingRate: 44100, ChannelCount: 2, MusicFolderID: folderID},
+			{ID: &id, Path: path, Title: "Stream Test", Size: 1, ContentType: "audio/mpeg", Duration: repos.NewDurationMS(180000), BitRate: 320, SamplingRate: 44100, ChannelCount: 2, MusicFolderID: folderID,
+				ReplayGain: util.ToPtr(-6.5), ReplayGainPeak: util.ToPtr(0.9)},
 		}))
 
 		t.Run("returns stream info for authorized user", func(t *testing.T) {
@@ -898,6 +1008,11 @@ func TestSongRepository(t *testing.T) {
 			assert.Equal(t, path, info.Path)
 			assert.Equal(t, "audio/mpeg", info.ContentType)
 			assert.Equal(t, 320, info.BitRate)
+			require.NotNil(t, info.ReplayGain)
+			assert.InDelta(t, -6.5, *info.ReplayGain, 0.001)
+			require.NotNil(t, info.ReplayGainPeak)
+			assert.InDelta(t, 0.9, *info.ReplayGainPeak, 0.001)
+			assert.Nil(t, info.AlbumReplayGain)
 		})
 
 		t.Run("returns not found for unauthorized user", func(t *testing.T) {
@@ -908,6 +1023,123 @@ func TestSongRepository(t *testing.T) {
 		})
 	})
 
+	t.Run("computed replay gain", func(t *testing.T) {
+		folderID := thCreateMusicFolder(t, db, user)
+		albumID := thCreateAlbum(t, db, folderID)
+		untagged := thCreateSong(t, db, &albumID, folderID)
+		tagged := crossonic.GenIDSong()
+		require.NoError(t, repo.CreateAll(ctx, []repos.CreateSongParams{
+			{ID: &tagged, Path: "/test/tagged-" + tagged + ".mp3", Title: "Tagged", ContentType: "audio/mpeg", MusicFolderID: folderID,
+				ReplayGain: util.ToPtr(-4.0), ReplayGainPeak: util.ToPtr(0.8)},
+		}))
+
+		missing, err := repo.FindMissingReplayGain(ctx)
+		require.NoErrorf(t, err, "find missing replay gain: %v", err)
+		ids := util.Map(missing, func(s *repos.SongAnalysisInfo) string { return s.ID })
+		assert.Contains(t, ids, untagged)
+		assert.NotContains(t, ids, tagged)
+
+		require.NoError(t, repo.SetComputedReplayGain(ctx, untagged, -7, 1.1))
+		require.NoError(t, repo.SetComputedReplayGain(ctx, tagged, -9, 0.5))
+		assert.ErrorIs(t, repo.SetComputedReplayGain(ctx, "tr_doesnotexist", -7, 1.1), repos.ErrNotFound)
+
+		missing, err = repo.FindMissingReplayGain(ctx)
+		require.NoErrorf(t, err, "find missing replay gain: %v", err)
+		assert.NotContains(t, util.Map(missing, func(s *repos.SongAnalysisInfo) string { return s.ID }), untagged)
+
+		song, err := repo.FindByID(ctx, untagged, user, repos.IncludeSongInfoBare())
+		require.NoError(t, err)
+		assert.Nil(t, song.ReplayGain, "computed values must not replace tag values")
+		gain, peak := song.TrackReplayGain()
+		require.NotNil(t, gain)
+		assert.InDelta(t, -7, *gain, 0.001)
+		assert.InDelta(t, 1.1, *peak, 0.001)
+
+		song, err = repo.FindByID(ctx, tagged, user, repos.IncludeSongInfoBare())
+		require.NoError(t, err)
+		gain, peak = song.TrackReplayGain()
+		assert.InDelta(t, -4, *gain, 0.001, "tag values should be preferred")
+		assert.InDelta(t, 0.8, *peak, 0.001)
+
+		albums, err := db.Album().FindIDsMissingReplayGain(ctx)
+		require.NoError(t, err)
+		assert.Contains(t, albums, albumID)
+		require.NoError(t, db.Album().SetComputedReplayGain(ctx, albumID, -6, 1.1))
+		albums, err = db.Album().FindIDsMissingReplayGain(ctx)
+		require.NoError(t, err)
+		assert.NotContains(t, albums, albumID)
+
+		info, err := repo.GetStreamInfo(ctx, untagged, user)
+		require.NoError(t, err)
+		assert.InDelta(t, -7, *info.ReplayGain, 0.001)
+		assert.InDelta(t, -6, *info.AlbumReplayGain, 0.001)
+
+		require.NoError(t, repo.ResetComputedReplayGain(ctx, []string{untagged}))
+		missing, err = repo.FindMissingReplayGain(ctx)
+		require.NoErrorf(t, err, "find missing replay gain: %v", err)
+		assert.Contains(t, util.Map(missing, func(s *repos.SongAnalysisInfo) string { return s.ID }), untagged)
+		albums, err = db.Album().FindIDsMissingReplayGain(ctx)
+		require.NoError(t, err)
+		assert.Contains(t, albums, albumID, "album gain depends on the track gains")
+	})
+
+	t.Run("detected bpm", func(t *testing.T) {
+		folderID := thCreateMusicFolder(t, db, user)
+		bpmTag := 90
+		untagged, tagged := crossonic.GenIDSong(), crossonic.GenIDSong()
+		require.NoError(t, repo.CreateAll(ctx, []repos.CreateSongParams{
+			{ID: &untagged, Path: "/test/detectedbpm-" + untagged + ".mp3", Title: "Untagged", ContentType: "audio/mpeg", MusicFolderID: folderID},
+			{ID: &tagged, Path: "/test/detectedbpm-" + tagged + ".mp3", Title: "Tagged", BPM: &bpmTag, ContentType: "audio/mpeg", MusicFolderID: folderID},
+		}))
+
+		missing, err := repo.FindMissingBPM(ctx)
+		require.NoErrorf(t, err, "find missing bpm: %v", err)
+		ids := util.Map(missing, func(s *repos.SongAnalysisInfo) string { return s.ID })
+		assert.Contains(t, ids, untagged)
+		assert.NotContains(t, ids, tagged)
+
+		require.NoError(t, repo.SetDetectedBPM(ctx, untagged, util.ToPtr(150)))
+		assert.ErrorIs(t, repo.SetDetectedBPM(ctx, "tr_doesnotexist", nil), repos.ErrNotFound)
+
+		missing, err = repo.FindMissingBPM(ctx)
+		require.NoErrorf(t, err, "find missing bpm: %v", err)
+		assert.NotContains(t, util.Map(missing, func(s *repos.SongAnalysisInfo) string { return s.ID }), untagged)
+
+		t.Run("ResetDetectedBPM", func(t *testing.T) {
+			other := thCreateSong(t, db, nil, folderID)
+			require.NoError(t, repo.SetDetectedBPM(ctx, other, util.ToPtr(100)))
+			require.NoError(t, repo.ResetDetectedBPM(ctx, []string{other}))
+
+			missing, err := repo.FindMissingBPM(ctx)
+			require.NoErrorf(t, err, "find missing bpm: %v", err)
+			assert.Contains(t, util.Map(missing, func(s *repos.SongAnalysisInfo) string { return s.ID }), other)
+			song, err := repo.FindByID(ctx, other, user, repos.IncludeSongInfoBare())
+			require.NoError(t, err)
+			assert.Nil(t, song.DetectedBPM)
+		})
+
+		song, err := repo.FindByID(ctx, untagged, user, repos.IncludeSongInfoBare())
+		require.NoError(t, err)
+		assert.Nil(t, song.BPM, "detected values must not replace tag values")
+		assert.Equal(t, util.ToPtr(150), song.DetectedBPM)
+
+		minBPM := 120
+		results, err := repo.FindAllFiltered(ctx, repos.SongFindAllFilter{
+			MinBPM:         &minBPM,
+			MusicFolderIDs: []int{folderID},
+		}, repos.IncludeSongInfoBare())
+		require.NoErrorf(t, err, "find all filtered: %v", err)
+		assert.Empty(t, results, "detected values should only be used if requested")
+
+		results, err = repo.FindAllFiltered(ctx, repos.SongFindAllFilter{
+			MinBPM:         &minBPM,
+			UseDetectedBPM: true,
+			MusicFolderIDs: []int{folderID},
+		}, repos.IncludeSongInfoBare())
+		require.NoErrorf(t, err, "find all filtered: %v", err)
+		assert.Equal(t, []string{untagged}, util.Map(results, func(s *repos.CompleteSong) string { return s.ID }))
+	})
+
 	t.Run("FindByMusicBrainzID", func(t *testing.T) {
 		folderID := thCreateMusicFolder(t, db, user)
 		mbid := "song-mbid-" + crossonic.GenIDSong()
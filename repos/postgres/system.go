@@ -2,10 +2,12 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/jaevor/go-nanoid"
+	"github.com/juho05/crossonic-server/repos"
 	"github.com/nullism/bqb"
 )
 
@@ -63,6 +65,31 @@ func (s systemRepository) MusicDirConfig(ctx context.Context) (string, error) {
 	return s.get(ctx, "music-dir-config")
 }
 
+func (s systemRepository) ScanCheckpoint(ctx context.Context) (*repos.ScanCheckpoint, error) {
+	value, err := s.get(ctx, "scan-checkpoint")
+	if err != nil {
+		return nil, fmt.Errorf("get system value: %w", err)
+	}
+	var checkpoint repos.ScanCheckpoint
+	err = json.Unmarshal([]byte(value), &checkpoint)
+	if err != nil {
+		return nil, fmt.Errorf("decode scan checkpoint: %w", err)
+	}
+	return &checkpoint, nil
+}
+
+func (s systemRepository) SetScanCheckpoint(ctx context.Context, checkpoint repos.ScanCheckpoint) error {
+	value, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("encode scan checkpoint: %w", err)
+	}
+	return s.set(ctx, "scan-checkpoint", string(value))
+}
+
+func (s systemRepository) DeleteScanCheckpoint(ctx context.Context) error {
+	return executeQuery(ctx, s.db, bqb.New("DELETE FROM system WHERE key = ?", "scan-checkpoint"))
+}
+
 func (s systemRepository) get(ctx context.Context, key string) (string, error) {
 	q := bqb.New("SELECT value FROM system WHERE key = ?", key)
 	return getQuery[string](ctx, s.db, q)
@@ -83,4 +83,29 @@ func TestSystemRepository(t *testing.T) {
 		require.NoErrorf(t, err, "get music dir config: %v", err)
 		assert.Equalf(t, config, musicDirConfig, "music dir config from repo should match the provided config")
 	})
+
+	t.Run("(Set/Delete)ScanCheckpoint", func(t *testing.T) {
+		thDeleteAll(t, db, "system")
+
+		_, err := repo.ScanCheckpoint(ctx)
+		assert.True(t, errors.Is(err, repos.ErrNotFound), "first call to ScanCheckpoint should return ErrNotFound")
+
+		checkpoint := repos.ScanCheckpoint{
+			Start:    time.Date(2222, 1, 2, 3, 4, 5, 0, time.UTC),
+			FullScan: true,
+			Paths:    []string{"/music/artist"},
+		}
+		err = repo.SetScanCheckpoint(ctx, checkpoint)
+		require.NoErrorf(t, err, "set scan checkpoint: %v", err)
+
+		stored, err := repo.ScanCheckpoint(ctx)
+		require.NoErrorf(t, err, "get scan checkpoint: %v", err)
+		assert.Equal(t, checkpoint, *stored)
+
+		err = repo.DeleteScanCheckpoint(ctx)
+		require.NoErrorf(t, err, "delete scan checkpoint: %v", err)
+
+		_, err = repo.ScanCheckpoint(ctx)
+		assert.True(t, errors.Is(err, repos.ErrNotFound), "ScanCheckpoint should return ErrNotFound after deleting the checkpoint")
+	})
 }
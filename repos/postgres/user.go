@@ -69,7 +69,7 @@ func (u userRepository) UpdateListenBrainzSettings(ctx context.Context, user str
 }
 
 func (u userRepository) FindAll(ctx context.Context) ([]*repos.User, error) {
-	return selectQuery[*repos.User](ctx, u.db, bqb.New("SELECT users.* FROM users"))
+	return selectQuery[*repos.User](ctx, u.db, bqb.New("SELECT users.* FROM users ORDER BY users.name"))
 }
 
 func (u userRepository) FindByName(ctx context.Context, name string) (*repos.User, error) {
@@ -95,9 +95,18 @@ func (u userRepository) Update(ctx context.Context, name string, params repos.Up
 	}
 
 	updateList, empty := genUpdateList(map[string]repos.OptionalGetter{
-		"name":               params.Name,
-		"encrypted_password": encryptedPassword,
-		"hashed_password":    hashedPassword,
+		"name":                 params.Name,
+		"encrypted_password":   encryptedPassword,
+		"hashed_password":      hashedPassword,
+		"admin":                params.Admin,
+		"download_role":        params.DownloadRole,
+		"playlist_role":        params.PlaylistRole,
+		"share_role":           params.ShareRole,
+		"scrobble_role":        params.ScrobbleRole,
+		"settings_role":        params.SettingsRole,
+		"jukebox_role":         params.JukeboxRole,
+		"normalize":            params.Normalize,
+		"custom_music_folders": params.CustomMusicFolders,
 	}, false)
 	if empty {
 		return nil
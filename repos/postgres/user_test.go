@@ -299,6 +299,62 @@ func TestUserRepository(t *testing.T) {
 
 			// TODO verify password hash
 		})
+
+		t.Run("update roles", func(t *testing.T) {
+			thDeleteAll(t, db, "users")
+			user1 := thCreateUser(t, db)
+			user2 := thCreateUser(t, db)
+
+			u, err := db.User().FindByName(ctx, user1)
+			require.NoErrorf(t, err, "find user: %v", err)
+			assert.False(t, u.Admin)
+			assert.True(t, u.DownloadRole)
+			assert.True(t, u.PlaylistRole)
+			assert.True(t, u.ShareRole)
+			assert.True(t, u.ScrobbleRole)
+			assert.True(t, u.SettingsRole)
+			assert.False(t, u.JukeboxRole)
+			assert.False(t, u.CustomMusicFolders)
+
+			err = repo.Update(ctx, user1, repos.UpdateUserParams{
+				Admin:              repos.NewOptionalFull(true),
+				DownloadRole:       repos.NewOptionalFull(false),
+				ShareRole:          repos.NewOptionalFull(false),
+				JukeboxRole:        repos.NewOptionalFull(true),
+				CustomMusicFolders: repos.NewOptionalFull(true),
+			})
+			require.NoErrorf(t, err, "update user: %v", err)
+
+			u, err = db.User().FindByName(ctx, user1)
+			require.NoErrorf(t, err, "find user: %v", err)
+			assert.True(t, u.Admin)
+			assert.False(t, u.DownloadRole)
+			assert.True(t, u.PlaylistRole)
+			assert.False(t, u.ShareRole)
+			assert.True(t, u.ScrobbleRole)
+			assert.True(t, u.SettingsRole)
+			assert.True(t, u.JukeboxRole)
+			assert.True(t, u.CustomMusicFolders)
+
+			assert.True(t, thExists(t, db, "users", map[string]any{"name": user2, "admin": false, "download_role": true}))
+		})
+
+		t.Run("update normalize", func(t *testing.T) {
+			user := thCreateUser(t, db)
+
+			u, err := db.User().FindByName(ctx, user)
+			require.NoErrorf(t, err, "find user: %v", err)
+			assert.Equal(t, repos.NormalizeModeOff, u.Normalize)
+
+			err = repo.Update(ctx, user, repos.UpdateUserParams{
+				Normalize: repos.NewOptionalFull(repos.NormalizeModeAlbum),
+			})
+			require.NoErrorf(t, err, "update user: %v", err)
+
+			u, err = db.User().FindByName(ctx, user)
+			require.NoErrorf(t, err, "find user: %v", err)
+			assert.Equal(t, repos.NormalizeModeAlbum, u.Normalize)
+		})
 	})
 
 	t.Run("DeleteByName", func(t *testing.T) {
@@ -0,0 +1,76 @@
+package repos
+
+import (
+	"context"
+	"time"
+)
+
+// models
+
+// ScanProblemType is the kind of problem the scanner found in a file.
+type ScanProblemType string
+
+const (
+	// ScanProblemUnreadableTags is a media file whose tags or audio properties could not be read.
+	ScanProblemUnreadableTags ScanProblemType = "unreadableTags"
+	// ScanProblemMissingTitle is a media file without a title tag. The file name is used as the title.
+	ScanProblemMissingTitle ScanProblemType = "missingTitle"
+	// ScanProblemZeroDuration is a media file with a duration of zero.
+	ScanProblemZeroDuration ScanProblemType = "zeroDuration"
+	// ScanProblemInvalidDate is a media file with a date tag that could not be parsed.
+	ScanProblemInvalidDate ScanProblemType = "invalidDate"
+	// ScanProblemUnsupportedCover is a cover image file or a media file with an embedded cover that could not be decoded.
+	ScanProblemUnsupportedCover ScanProblemType = "unsupportedCover"
+)
+
+// ScanProblemTypes are all types of scan problems.
+var ScanProblemTypes = []ScanProblemType{
+	ScanProblemUnreadableTags,
+	ScanProblemMissingTitle,
+	ScanProblemZeroDuration,
+	ScanProblemInvalidDate,
+	ScanProblemUnsupportedCover,
+}
+
+// ScanProblem is a problem with a file that is kept until the file is fixed or deleted.
+type ScanProblem struct {
+	Path          string          `db:"path"`
+	Type          ScanProblemType `db:"type"`
+	Message       string          `db:"message"`
+	MusicFolderID int             `db:"music_folder_id"`
+	Created       time.Time       `db:"created"`
+	Updated       time.Time       `db:"updated"`
+}
+
+// params
+
+type CreateScanProblemParams struct {
+	Path          string
+	Type          ScanProblemType
+	Message       string
+	MusicFolderID int
+}
+
+type FindScanProblemsParams struct {
+	// Types limits the result to problems of these types. All types are returned if empty.
+	Types []ScanProblemType
+	// MusicFolderIDs limits the result to problems of files in these music folders. All music folders are included if nil.
+	MusicFolderIDs []int
+	Paginate       Paginate
+}
+
+// repo
+
+type ScanProblemRepository interface {
+	// FindAll returns the matching problems ordered by path and type.
+	FindAll(ctx context.Context, params FindScanProblemsParams) ([]*ScanProblem, error)
+	// Count returns the number of matching problems. params.Paginate is ignored.
+	Count(ctx context.Context, params FindScanProblemsParams) (int, error)
+	// FindPaths returns the paths of all files with problems in dirs or in all music folders if dirs is nil.
+	FindPaths(ctx context.Context, dirs []string) ([]string, error)
+
+	// CreateAll creates the problems or updates the message of existing problems with the same path and type.
+	CreateAll(ctx context.Context, params []CreateScanProblemParams) error
+	// DeleteByPaths deletes the problems of the files at paths. If types is not empty, only problems of these types are deleted.
+	DeleteByPaths(ctx context.Context, paths []string, types []ScanProblemType) error
+}
@@ -0,0 +1,55 @@
+package repos
+
+import (
+	"context"
+	"time"
+)
+
+// models
+
+type Share struct {
+	ID          string     `db:"id"`
+	User        string     `db:"user_name"`
+	Description *string    `db:"description"`
+	Created     time.Time  `db:"created"`
+	Updated     time.Time  `db:"updated"`
+	Expires     *time.Time `db:"expires"`
+	LastVisited *time.Time `db:"last_visited"`
+	VisitCount  int        `db:"visit_count"`
+}
+
+// params
+
+type CreateShareParams struct {
+	Description *string
+	Expires     *time.Time
+	// EntryIDs contains the IDs of the shared songs, albums and playlists.
+	EntryIDs []string
+}
+
+type UpdateShareParams struct {
+	Description Optional[*string]
+	Expires     Optional[*time.Time]
+}
+
+type ShareRepository interface {
+	// Create creates a new share for the user.
+	Create(ctx context.Context, user string, params CreateShareParams) (*Share, error)
+	// FindAll returns all shares created by the user.
+	FindAll(ctx context.Context, user string) ([]*Share, error)
+	// FindByID returns the share with the given id if it was created by the user.
+	FindByID(ctx context.Context, user, id string) (*Share, error)
+	// FindPublicByID returns the share with the given id if it has not expired yet.
+	// It does not check the owner and must only be used to resolve public share links.
+	FindPublicByID(ctx context.Context, id string) (*Share, error)
+	// GetEntries returns the IDs of the songs, albums and playlists of the share in the order they were added.
+	GetEntries(ctx context.Context, id string) ([]string, error)
+	// Update updates an existing share of the user.
+	// If no share with the provided id is found for the user, ErrNotFound will be returned.
+	Update(ctx context.Context, user, id string, params UpdateShareParams) error
+	// RegisterVisit increments the visit count of the share and sets last visited to the current time.
+	RegisterVisit(ctx context.Context, id string) error
+	// Delete removes an existing share of the user.
+	// If no share with the provided id is found for the user, ErrNotFound will be returned.
+	Delete(ctx context.Context, user, id string) error
+}
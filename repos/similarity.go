@@ -0,0 +1,49 @@
+package repos
+
+import (
+	"context"
+	"time"
+)
+
+// models
+
+// ArtistFeatures aggregates the properties of all songs of an artist.
+type ArtistFeatures struct {
+	ID            string         `db:"id"`
+	Name          string         `db:"name"`
+	MusicBrainzID *string        `db:"music_brainz_id"`
+	SongCount     int            `db:"song_count"`
+	AvgBPM        *float64       `db:"avg_bpm"`
+	AvgYear       *float64       `db:"avg_year"`
+	Genres        map[string]int `db:"-"`
+}
+
+// SimilarityCount is the number of times an entity is related to the entities it was compared with.
+type SimilarityCount struct {
+	ID    string `db:"id"`
+	Count int    `db:"count"`
+}
+
+// repo
+
+type SimilarityRepository interface {
+	// FindSongCandidates returns the ids of up to limit songs in musicFolderIDs that have one of the genres
+	// or were released by one of the artists. Songs sharing the most genres and artists are returned first.
+	FindSongCandidates(ctx context.Context, genres []string, artistIDs []string, musicFolderIDs []int, limit int) ([]string, error)
+	// GetSongCoOccurrences counts for all songs in musicFolderIDs how often they were part of the same playlist as one of songIDs
+	// or were scrobbled by the same user within window of a scrobble of one of songIDs.
+	GetSongCoOccurrences(ctx context.Context, songIDs []string, window time.Duration, musicFolderIDs []int, limit int) ([]*SimilarityCount, error)
+
+	// GetArtistFeatures returns the features of all artists in artistIDs that have content in musicFolderIDs.
+	GetArtistFeatures(ctx context.Context, artistIDs []string, musicFolderIDs []int) ([]*ArtistFeatures, error)
+	// FindArtistCandidates returns the ids of up to limit artists in musicFolderIDs that released songs with one of the genres.
+	// Artists with the most songs of those genres are returned first.
+	FindArtistCandidates(ctx context.Context, genres []string, musicFolderIDs []int, limit int) ([]string, error)
+	// GetArtistRelations counts the songs and albums the artist shares with other artists in musicFolderIDs.
+	GetArtistRelations(ctx context.Context, artistID string, musicFolderIDs []int) ([]*SimilarityCount, error)
+	// GetArtistCoOccurrences counts for all other artists in musicFolderIDs how often their songs were part of the same playlist
+	// as a song of the artist or were scrobbled by the same user within window of a scrobble of a song of the artist.
+	GetArtistCoOccurrences(ctx context.Context, artistID string, window time.Duration, musicFolderIDs []int, limit int) ([]*SimilarityCount, error)
+	// GetArtistTopSongs returns the songs of the artist in musicFolderIDs with their play count by all users, most played first.
+	GetArtistTopSongs(ctx context.Context, artistID string, musicFolderIDs []int, limit int) ([]*SimilarityCount, error)
+}
@@ -31,6 +31,18 @@ type Song struct {
 	ReplayGainPeak *float64   `db:"replay_gain_peak"`
 	Lyrics         *string    `db:"lyrics"`
 	MusicFolderID  *int       `db:"music_folder_id"`
+
+	// ComputedReplayGain and ComputedReplayGainPeak were measured by analyzing the audio
+	// of songs without ReplayGain tags.
+	ComputedReplayGain     *float64 `db:"computed_replay_gain"`
+	ComputedReplayGainPeak *float64 `db:"computed_replay_gain_peak"`
+	// DetectedBPM was estimated by analyzing the audio of songs without a BPM tag.
+	DetectedBPM *int `db:"detected_bpm"`
+}
+
+// TrackReplayGain returns the ReplayGain tag values or the computed values if the song does not have a ReplayGain tag.
+func (s Song) TrackReplayGain() (gain, peak *float64) {
+	return effectiveReplayGain(s.ReplayGain, s.ReplayGainPeak, s.ComputedReplayGain, s.ComputedReplayGainPeak)
 }
 
 type SongAlbumInfo struct {
@@ -39,6 +51,22 @@ type SongAlbumInfo struct {
 	AlbumReplayGainPeak *float64 `db:"album_replay_gain_peak"`
 	AlbumMusicBrainzID  *string  `db:"album_music_brainz_id"`
 	AlbumReleaseMBID    *string  `db:"album_release_mbid"`
+
+	AlbumComputedReplayGain     *float64 `db:"album_computed_replay_gain"`
+	AlbumComputedReplayGainPeak *float64 `db:"album_computed_replay_gain_peak"`
+}
+
+// AlbumGain returns the album ReplayGain tag values or the computed values if the album does not have a ReplayGain tag.
+func (s SongAlbumInfo) AlbumGain() (gain, peak *float64) {
+	return effectiveReplayGain(s.AlbumReplayGain, s.AlbumReplayGainPeak, s.AlbumComputedReplayGain, s.AlbumComputedReplayGainPeak)
+}
+
+// effectiveReplayGain prefers tag values over computed values. The peak always belongs to the returned gain.
+func effectiveReplayGain(gain, peak, computedGain, computedPeak *float64) (*float64, *float64) {
+	if gain != nil {
+		return gain, peak
+	}
+	return computedGain, computedPeak
 }
 
 type SongAnnotations struct {
@@ -78,6 +106,31 @@ type SongStreamInfo struct {
 	ContentType  string     `db:"content_type"`
 	Duration     DurationMS `db:"duration_ms"`
 	ChannelCount int        `db:"channel_count"`
+	SamplingRate int        `db:"sampling_rate"`
+
+	// ReplayGain values already prefer tags over computed values.
+	ReplayGain          *float64 `db:"replay_gain"`
+	ReplayGainPeak      *float64 `db:"replay_gain_peak"`
+	AlbumReplayGain     *float64 `db:"album_replay_gain"`
+	AlbumReplayGainPeak *float64 `db:"album_replay_gain_peak"`
+}
+
+// SongAnalysisInfo references a song file whose audio needs to be analyzed.
+type SongAnalysisInfo struct {
+	ID       string     `db:"id"`
+	Path     string     `db:"path"`
+	AlbumID  *string    `db:"album_id"`
+	Duration DurationMS `db:"duration_ms"`
+}
+
+// SongFingerprint identifies the file of a song in a read-only library.
+type SongFingerprint struct {
+	ID   string `db:"id"`
+	Path string `db:"path"`
+	// Fingerprint is the hash of the audio payload of the file without tags.
+	Fingerprint string `db:"fingerprint"`
+	// ContentHash is the hash of the whole file.
+	ContentHash string `db:"content_hash"`
 }
 
 type SongArtistConnection struct {
@@ -202,6 +255,8 @@ type SongFindAllFilter struct {
 
 	MinBPM *int
 	MaxBPM *int
+	// UseDetectedBPM makes the BPM filters and order fall back to the detected BPM of songs without a BPM tag.
+	UseDetectedBPM bool
 
 	FromYear *int
 	ToYear   *int
@@ -226,6 +281,12 @@ type SongSetLBFeedbackUploadedParams struct {
 	Uploaded   bool
 }
 
+type SetSongFingerprintParams struct {
+	ID          string
+	Fingerprint string
+	ContentHash string
+}
+
 // repo
 
 type SongRepository interface {
@@ -238,15 +299,23 @@ type SongRepository interface {
 	FindAllByPathOrMBID(ctx context.Context, paths []string, mbids []string, include IncludeSongInfo) ([]*CompleteSong, error)
 	FindNonExistentIDs(ctx context.Context, ids []string) ([]string, error)
 
-	FindPaths(ctx context.Context, updatedBefore time.Time, paginate Paginate) ([]string, error)
+	// FindPaths returns the paths of all songs last updated before updatedBefore.
+	// If dirs is not nil, only songs at or inside one of the paths in dirs are included.
+	FindPaths(ctx context.Context, updatedBefore time.Time, dirs []string, paginate Paginate) ([]string, error)
 	DeleteByPaths(ctx context.Context, paths []string) error
+	// FindPathsUpdatedSince returns the paths of all songs updated at or after since.
+	FindPathsUpdatedSince(ctx context.Context, since time.Time) ([]string, error)
 
 	GetStreamInfo(ctx context.Context, id, user string) (*SongStreamInfo, error)
 
 	CreateAll(ctx context.Context, params []CreateSongParams) error
 	TryUpdateAll(ctx context.Context, params []UpdateSongAllParams) (int, error)
 
-	DeleteLastUpdatedBefore(ctx context.Context, before time.Time) error
+	// DeleteLastUpdatedBefore deletes all songs last updated before before.
+	// If dirs is not nil, only songs at or inside one of the paths in dirs are deleted.
+	DeleteLastUpdatedBefore(ctx context.Context, before time.Time, dirs []string) error
+	// MarkUpdatedByPaths sets the updated time of the songs at or inside of paths to the current time without changing anything else.
+	MarkUpdatedByPaths(ctx context.Context, paths []string) error
 
 	DeleteArtistConnections(ctx context.Context, songIDs []string) error
 	CreateArtistConnections(ctx context.Context, connections []SongArtistConnection) error
@@ -268,7 +337,33 @@ type SongRepository interface {
 	SetLBFeedbackUploadedForAllMatchingStarredSongs(ctx context.Context, user string, lbLovedMBIDs []string) error
 
 	Count(ctx context.Context) (int, error)
+	// GetMedianReplayGain returns the median track gain of all songs. Computed values are used for songs without ReplayGain tags.
 	GetMedianReplayGain(ctx context.Context) (float64, error)
 
+	// FindMissingBPM returns all songs without a BPM tag whose BPM has not been detected yet.
+	FindMissingBPM(ctx context.Context) ([]*SongAnalysisInfo, error)
+	// SetDetectedBPM stores the BPM estimated by analyzing the audio of the song. A nil bpm marks the song
+	// as analyzed without a detectable tempo. BPM tag values are not changed.
+	SetDetectedBPM(ctx context.Context, id string, bpm *int) error
+	// ResetDetectedBPM removes the detected BPM of the songs, so that they are analyzed again.
+	ResetDetectedBPM(ctx context.Context, songIDs []string) error
+
+	// FindMissingReplayGain returns all songs that neither have a ReplayGain tag nor a computed ReplayGain value.
+	FindMissingReplayGain(ctx context.Context) ([]*SongAnalysisInfo, error)
+	// SetComputedReplayGain stores the ReplayGain values measured by analyzing the audio of the song.
+	// ReplayGain tag values are not changed.
+	SetComputedReplayGain(ctx context.Context, id string, gain, peak float64) error
+	// ResetComputedReplayGain removes the computed ReplayGain values of the songs and their albums,
+	// so that they are analyzed again.
+	ResetComputedReplayGain(ctx context.Context, songIDs []string) error
+
+	// FindFingerprints returns the fingerprints of all songs that have one.
+	// If dirs is not nil, only songs at or inside one of the paths in dirs are included.
+	FindFingerprints(ctx context.Context, dirs []string) ([]*SongFingerprint, error)
+	// FindByFingerprints returns the songs with one of the audio fingerprints.
+	FindByFingerprints(ctx context.Context, fingerprints []string) ([]*SongFingerprint, error)
+	// SetFingerprints stores the audio fingerprints and content hashes of the song files.
+	SetFingerprints(ctx context.Context, params []SetSongFingerprintParams) error
+
 	DeleteAllWithoutMusicFolderID(ctx context.Context) error
 }
@@ -5,6 +5,15 @@ import (
 	"time"
 )
 
+// ScanCheckpoint describes a scan that committed some of its changes but did not finish.
+type ScanCheckpoint struct {
+	// Start is the start time of the scan. Songs updated after it have already been saved by the scan.
+	Start    time.Time `json:"start"`
+	FullScan bool      `json:"fullScan"`
+	// Paths are the paths the scan was restricted to or nil if all music dirs were scanned.
+	Paths []string `json:"paths,omitempty"`
+}
+
 type SystemRepository interface {
 	// InstanceID returns an ID that uniquely identifies this instance of crossonic-server
 	// and is preserved between application restarts.
@@ -22,4 +31,10 @@ type SystemRepository interface {
 	SetMusicDirConfig(ctx context.Context, config string) error
 	// MusicDirConfig returns the last known music dir config. Should be a string of <id>:<abs-path>;<id>:<abs-path>;...
 	MusicDirConfig(ctx context.Context) (string, error)
+	// ScanCheckpoint returns the checkpoint of an interrupted scan or ErrNotFound if the last scan finished.
+	ScanCheckpoint(ctx context.Context) (*ScanCheckpoint, error)
+	// SetScanCheckpoint stores the checkpoint of the running scan so that it can be resumed if it is interrupted.
+	SetScanCheckpoint(ctx context.Context, checkpoint ScanCheckpoint) error
+	// DeleteScanCheckpoint removes the checkpoint after the scan has finished.
+	DeleteScanCheckpoint(ctx context.Context) error
 }
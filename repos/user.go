@@ -5,6 +5,15 @@ import (
 	"time"
 )
 
+// NormalizeMode selects the ReplayGain values that are applied to transcoded streams.
+type NormalizeMode string
+
+const (
+	NormalizeModeOff   NormalizeMode = "off"
+	NormalizeModeTrack NormalizeMode = "track"
+	NormalizeModeAlbum NormalizeMode = "album"
+)
+
 type User struct {
 	Name              string  `db:"name"`
 	EncryptedPassword []byte  `db:"encrypted_password"`
@@ -14,6 +23,21 @@ type User struct {
 	EncryptedListenBrainzToken []byte  `db:"encrypted_listenbrainz_token"`
 	ListenBrainzScrobble       bool    `db:"listenbrainz_scrobble"`
 	ListenBrainzSyncFeedback   bool    `db:"listenbrainz_sync_feedback"`
+
+	Admin        bool `db:"admin"`
+	DownloadRole bool `db:"download_role"`
+	PlaylistRole bool `db:"playlist_role"`
+	ShareRole    bool `db:"share_role"`
+	ScrobbleRole bool `db:"scrobble_role"`
+	SettingsRole bool `db:"settings_role"`
+	JukeboxRole  bool `db:"jukebox_role"`
+
+	// Normalize is the default normalization of streams requested without a normalize parameter.
+	Normalize NormalizeMode `db:"normalize"`
+
+	// CustomMusicFolders is true if the music folder associations of the user were set explicitly
+	// instead of being derived from the music dir config.
+	CustomMusicFolders bool `db:"custom_music_folders"`
 }
 
 type APIKey struct {
@@ -31,6 +55,18 @@ type UpdateListenBrainzSettingsParams struct {
 type UpdateUserParams struct {
 	Name     Optional[string]
 	Password Optional[string]
+
+	Admin        Optional[bool]
+	DownloadRole Optional[bool]
+	PlaylistRole Optional[bool]
+	ShareRole    Optional[bool]
+	ScrobbleRole Optional[bool]
+	SettingsRole Optional[bool]
+	JukeboxRole  Optional[bool]
+
+	Normalize Optional[NormalizeMode]
+
+	CustomMusicFolders Optional[bool]
 }
 
 // UserRepository is an interface to manipulate user data in a database.
@@ -32,6 +32,9 @@ type album struct {
 
 type albumMap struct {
 	albums map[string][]*album
+
+	// albums found since the last checkpoint
+	dirty []*album
 }
 
 type findOrCreateAlbumParamsArtist struct {
@@ -269,6 +272,7 @@ func (a *albumMap) findOrCreate(ctx context.Context, s *Scanner, name string, pa
 				}
 			}
 			found.updated = true
+			a.dirty = append(a.dirty, found)
 
 			if changed || s.fullScan {
 				err := a.updateAlbum(ctx, s, name, found)
@@ -278,10 +282,12 @@ func (a *albumMap) findOrCreate(ctx context.Context, s *Scanner, name string, pa
 			}
 
 			if !s.setAlbumCoverClosed {
+				s.pendingCovers.Add(1)
 				s.setAlbumCover <- albumCover{
-					id:       found.id,
-					cover:    params.cover,
-					songPath: params.songPath,
+					id:            found.id,
+					cover:         params.cover,
+					songPath:      params.songPath,
+					musicFolderID: params.musicFolderId,
 				}
 			}
 		}
@@ -312,6 +318,7 @@ func (a *albumMap) findOrCreate(ctx context.Context, s *Scanner, name string, pa
 		musicFolderID:  params.musicFolderId,
 	}
 	a.albums[name] = append(a.albums[name], alb)
+	a.dirty = append(a.dirty, alb)
 
 	// sets alb.id
 	err := a.createAlbum(ctx, s, name, alb)
@@ -320,10 +327,12 @@ func (a *albumMap) findOrCreate(ctx context.Context, s *Scanner, name string, pa
 	}
 
 	if !s.setAlbumCoverClosed {
+		s.pendingCovers.Add(1)
 		s.setAlbumCover <- albumCover{
-			id:       alb.id,
-			cover:    params.cover,
-			songPath: params.songPath,
+			id:            alb.id,
+			cover:         params.cover,
+			songPath:      params.songPath,
+			musicFolderID: params.musicFolderId,
 		}
 	}
 
@@ -408,29 +417,57 @@ func (a *albumMap) updateDiscTitle(ctx context.Context, s *Scanner, album *album
 	return nil
 }
 
+// updateArtists stores the album artists of all albums or, in targeted scans, only of the albums found during the scan.
 func (a *albumMap) updateArtists(ctx context.Context, s *Scanner) error {
-	err := s.tx.Album().RemoveAllArtistConnections(ctx)
-	if err != nil {
-		return fmt.Errorf("remove all artist connections: %w", err)
-	}
-
-	connections := make([]repos.AlbumArtistConnection, 0, len(a.albums))
+	albums := make([]*album, 0, len(a.albums))
 	for _, albs := range a.albums {
 		for _, alb := range albs {
-			for artistID, i := range alb.artistIDs {
-				connections = append(connections, repos.AlbumArtistConnection{
-					AlbumID:  alb.id,
-					ArtistID: artistID,
-					Index:    i,
-				})
+			if s.targets == nil || alb.updated {
+				albums = append(albums, alb)
 			}
 		}
 	}
 
-	err = s.tx.Album().CreateArtistConnections(ctx, connections)
+	if s.targets != nil {
+		return a.saveArtistConnections(ctx, s, albums)
+	}
+
+	err := s.tx.Album().RemoveAllArtistConnections(ctx)
+	if err != nil {
+		return fmt.Errorf("remove all artist connections: %w", err)
+	}
+	err = s.tx.Album().CreateArtistConnections(ctx, albumArtistConnections(albums))
 	if err != nil {
 		return fmt.Errorf("create new artist connections: %w", err)
 	}
+	return nil
+}
 
+// saveArtistConnections replaces the stored album artists of albums.
+func (a *albumMap) saveArtistConnections(ctx context.Context, s *Scanner, albums []*album) error {
+	err := s.tx.Album().DeleteArtistConnections(ctx, util.Map(albums, func(alb *album) string {
+		return alb.id
+	}))
+	if err != nil {
+		return fmt.Errorf("delete artist connections: %w", err)
+	}
+	err = s.tx.Album().CreateArtistConnections(ctx, albumArtistConnections(albums))
+	if err != nil {
+		return fmt.Errorf("create artist connections: %w", err)
+	}
 	return nil
 }
+
+func albumArtistConnections(albums []*album) []repos.AlbumArtistConnection {
+	connections := make([]repos.AlbumArtistConnection, 0, len(albums))
+	for _, alb := range albums {
+		for artistID, i := range alb.artistIDs {
+			connections = append(connections, repos.AlbumArtistConnection{
+				AlbumID:  alb.id,
+				ArtistID: artistID,
+				Index:    i,
+			})
+		}
+	}
+	return connections
+}
@@ -2,6 +2,7 @@ package scanner
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
@@ -22,6 +23,7 @@ type artistImageScanner struct {
 	images   map[string]string
 	conf     config.Config
 	fullScan bool
+	ignore   *ignoreMatcher
 }
 
 func (s *Scanner) findArtistImages(ctx context.Context) (map[string]string, error) {
@@ -30,10 +32,17 @@ func (s *Scanner) findArtistImages(ctx context.Context) (map[string]string, erro
 		images:   make(map[string]string),
 		conf:     s.conf,
 		fullScan: s.fullScan,
+		ignore:   s.ignore,
 	}
 
-	for _, dir := range s.musicDirs {
-		err := scanner.scanDir(ctx, dir.Path)
+	for _, root := range s.scanRoots() {
+		if _, err := os.Stat(root.path); errors.Is(err, os.ErrNotExist) && s.targets != nil {
+			continue
+		}
+		if s.ignore.ignored(root.path, true) {
+			continue
+		}
+		err := scanner.scanDir(ctx, root.path)
 		if err != nil {
 			return nil, fmt.Errorf("scan dir: %w", err)
 		}
@@ -54,6 +63,13 @@ func (a *artistImageScanner) scanDir(ctx context.Context, mediaDir string) error
 		}
 
 		if d.IsDir() {
+			if path != mediaDir && a.ignore.ignoredInDir(path, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if a.ignore.ignoredInDir(path, false) {
 			return nil
 		}
 
@@ -113,26 +129,17 @@ func (s *Scanner) saveArtistImages(ctx context.Context, foundImages map[string]s
 
 	var waitGroup sync.WaitGroup
 
-	var saveImageErr error
 	for range saveArtistCoversWorkerCount {
 		waitGroup.Add(1)
 		go func() {
 			defer waitGroup.Done()
 			for image := range imageChannel {
-				if saveImageErr != nil {
-					return
-				}
-				select {
-				case <-ctx.Done():
-					return
-				default:
+				if ctx.Err() != nil {
+					continue
 				}
-				err = s.saveArtistImage(image.path, image.artistID)
+				err := s.saveArtistImage(image.path, image.artistID)
 				if err != nil {
-					if saveImageErr == nil {
-						saveImageErr = fmt.Errorf("save artist image: %w", err)
-					}
-					return
+					s.logFileError(image.path, "save artist image: %s", err)
 				}
 			}
 		}()
@@ -147,11 +154,7 @@ func (s *Scanner) saveArtistImages(ctx context.Context, foundImages map[string]s
 	close(imageChannel)
 	waitGroup.Wait()
 
-	if saveImageErr != nil {
-		return saveImageErr
-	}
-
-	return nil
+	return ctx.Err()
 }
 
 func (s *Scanner) saveArtistImage(path, artistID string) error {
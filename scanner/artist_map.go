@@ -22,6 +22,9 @@ type artistMap struct {
 
 	// artist name -> image path
 	artistImages map[string]string
+
+	// artists with new music folders since the last checkpoint
+	dirty []*artist
 }
 
 func newArtistMapFromDB(ctx context.Context, s *Scanner) (*artistMap, error) {
@@ -46,7 +49,8 @@ func newArtistMapFromDB(ctx context.Context, s *Scanner) (*artistMap, error) {
 		artistIDMap[art.id] = art
 	}
 
-	if !s.fullScan {
+	// full scans of the whole library recreate all associations, unless they were interrupted and skip already saved songs
+	if !s.fullScan || s.targets != nil || s.savedPaths != nil {
 		artistMusicFolderAssociations, err := s.tx.MusicFolder().GetAllArtistAsssociations(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("get all artist music folder associations: %w", err)
@@ -94,6 +98,7 @@ func (a *artistMap) findOrCreate(ctx context.Context, s *Scanner, name string, m
 		}
 		if _, ok := found.musicFolderIDs[musicFolderID]; !ok {
 			found.musicFolderIDs[musicFolderID] = struct{}{}
+			a.dirty = append(a.dirty, found)
 		}
 		return found.id, nil
 	}
@@ -106,6 +111,7 @@ func (a *artistMap) findOrCreate(ctx context.Context, s *Scanner, name string, m
 		},
 	}
 	a.artists[name] = append(a.artists[name], art)
+	a.dirty = append(a.dirty, art)
 
 	err := a.createArtist(ctx, s, name, art)
 	if err != nil {
@@ -144,27 +150,34 @@ func (a *artistMap) createArtist(ctx context.Context, s *Scanner, name string, a
 	return nil
 }
 
+// updateMusicFolderAssociations stores the music folders of all artists or, in targeted scans, only of the artists found during the scan.
 func (a *artistMap) updateMusicFolderAssociations(ctx context.Context, s *Scanner) error {
-	err := s.tx.MusicFolder().DeleteAllArtistAssociations(ctx)
-	if err != nil {
-		return fmt.Errorf("delete all artist associations: %w", err)
+	artists := make([]*artist, 0, len(a.artists))
+	for _, arts := range a.artists {
+		for _, artist := range arts {
+			if s.targets == nil || artist.updated {
+				artists = append(artists, artist)
+			}
+		}
 	}
 
-	associations := make([]repos.ArtistMusicFolderAssociation, 0, len(a.artists))
-	for _, artists := range a.artists {
-		for _, artist := range artists {
-			for musicFolderID := range artist.musicFolderIDs {
-				associations = append(associations, repos.ArtistMusicFolderAssociation{
-					MusicFolderID: musicFolderID,
-					ArtistID:      artist.id,
-				})
-			}
+	if s.targets == nil {
+		err := s.tx.MusicFolder().DeleteAllArtistAssociations(ctx)
+		if err != nil {
+			return fmt.Errorf("delete all artist associations: %w", err)
+		}
+	} else {
+		err := s.tx.MusicFolder().DeleteArtistAssociations(ctx, util.Map(artists, func(a *artist) string {
+			return a.id
+		}))
+		if err != nil {
+			return fmt.Errorf("delete artist associations: %w", err)
 		}
 	}
 
-	err = s.tx.MusicFolder().CreateArtistAssociations(ctx, associations)
+	err := a.saveMusicFolderAssociations(ctx, s, artists)
 	if err != nil {
-		return fmt.Errorf("create artist associations: %w", err)
+		return err
 	}
 
 	err = s.tx.MusicFolder().DeleteArtistAssociationsWithoutSongs(ctx)
@@ -174,3 +187,22 @@ func (a *artistMap) updateMusicFolderAssociations(ctx context.Context, s *Scanne
 
 	return nil
 }
+
+// saveMusicFolderAssociations adds the music folders of artists to the stored associations.
+func (a *artistMap) saveMusicFolderAssociations(ctx context.Context, s *Scanner, artists []*artist) error {
+	associations := make([]repos.ArtistMusicFolderAssociation, 0, len(artists))
+	for _, artist := range artists {
+		for musicFolderID := range artist.musicFolderIDs {
+			associations = append(associations, repos.ArtistMusicFolderAssociation{
+				MusicFolderID: musicFolderID,
+				ArtistID:      artist.id,
+			})
+		}
+	}
+
+	err := s.tx.MusicFolder().CreateArtistAssociations(ctx, associations)
+	if err != nil {
+		return fmt.Errorf("create artist associations: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+
+	"github.com/djherbis/times"
+	"github.com/juho05/log"
+)
+
+// checkpointInterval is the number of saved songs after which the changes of a scan are committed.
+const checkpointInterval = 1000
+
+// checkpoint commits all changes of the scan so far and continues the scan in a new transaction.
+// If the scan is interrupted after a checkpoint, the next scan resumes it and skips all songs saved before the checkpoint.
+// Must only be called by the save songs loop.
+func (s *Scanner) checkpoint(ctx context.Context) error {
+	// covers of albums found before the checkpoint are not queued again when the scan is resumed
+	s.pendingCovers.Wait()
+
+	err := s.saveProblems(ctx)
+	if err != nil {
+		return fmt.Errorf("save problems: %w", err)
+	}
+
+	err = s.albums.saveArtistConnections(ctx, s, s.albums.dirty)
+	if err != nil {
+		return fmt.Errorf("save album artists: %w", err)
+	}
+	s.albums.dirty = s.albums.dirty[:0]
+
+	err = s.artists.saveMusicFolderAssociations(ctx, s, s.artists.dirty)
+	if err != nil {
+		return fmt.Errorf("save artist music folder associations: %w", err)
+	}
+	s.artists.dirty = s.artists.dirty[:0]
+
+	err = s.tx.Commit()
+	if err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	tx, err := s.db.NewTransaction(ctx)
+	if err != nil {
+		return fmt.Errorf("new transaction: %w", err)
+	}
+	s.tx = tx
+	log.Tracef("scan checkpoint: committed %d processed files", s.processed.Load())
+	return nil
+}
+
+// savedBeforeInterruption reports whether the file at path was saved before the resumed scan was interrupted
+// and has not been modified since the start of the scan.
+func (s *Scanner) savedBeforeInterruption(path string, info fs.FileInfo) bool {
+	// mtimes of read-only libraries are not reliable, unchanged files are skipped by their content hash instead
+	if _, ok := s.savedPaths[path]; !ok || s.conf.ReadOnlyLibrary {
+		return false
+	}
+	if info.ModTime().After(s.scanStart) {
+		return false
+	}
+	stat, err := times.Stat(path)
+	if err != nil {
+		return false
+	}
+	return stat.HasChangeTime() && !stat.ChangeTime().After(s.scanStart)
+}
+
+// loadSavedPaths loads the paths of the songs that were saved by the resumed scan before it was interrupted.
+func (s *Scanner) loadSavedPaths(ctx context.Context) error {
+	paths, err := s.tx.Song().FindPathsUpdatedSince(ctx, s.scanStart)
+	if err != nil {
+		return fmt.Errorf("find paths updated since scan start: %w", err)
+	}
+	s.savedPaths = make(map[string]struct{}, len(paths))
+	for _, p := range paths {
+		s.savedPaths[p] = struct{}{}
+	}
+	return nil
+}
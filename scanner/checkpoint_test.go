@@ -0,0 +1,28 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanner_savedBeforeInterruption(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "song.flac")
+	require.NoError(t, os.WriteFile(path, nil, 0644))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+
+	s := &Scanner{
+		savedPaths: map[string]struct{}{path: {}},
+		scanStart:  time.Now().Add(time.Hour),
+	}
+	assert.True(t, s.savedBeforeInterruption(path, info), "saved and not modified since the scan start")
+	assert.False(t, s.savedBeforeInterruption(filepath.Join(filepath.Dir(path), "other.flac"), info), "not saved")
+
+	s.scanStart = time.Now().Add(-time.Hour)
+	assert.False(t, s.savedBeforeInterruption(path, info), "modified after the scan start")
+}
@@ -19,7 +19,12 @@ func (s *Scanner) deleteOrphaned(ctx context.Context) error {
 	}
 
 	if s.fullScan {
-		err := s.tx.Song().DeleteLastUpdatedBefore(ctx, s.scanStart)
+		// songs of files and directories that could not be read are kept, they are removed by the scan after the file is deleted
+		err := s.tx.Song().MarkUpdatedByPaths(ctx, s.failedFilePaths())
+		if err != nil {
+			return fmt.Errorf("keep songs of failed files: %w", err)
+		}
+		err = s.tx.Song().DeleteLastUpdatedBefore(ctx, s.scanStart, s.targetPaths())
 		if err != nil {
 			return fmt.Errorf("delete orphaned songs (by last updated): %w", err)
 		}
@@ -30,6 +35,21 @@ func (s *Scanner) deleteOrphaned(ctx context.Context) error {
 		}
 	}
 
+	err = s.deleteProblemsOfMissingFiles(ctx)
+	if err != nil {
+		return fmt.Errorf("delete problems of missing files: %w", err)
+	}
+
+	err = s.tx.PlayQueue().DeleteMissingSongs(ctx)
+	if err != nil {
+		return fmt.Errorf("delete play queue entries of deleted songs: %w", err)
+	}
+
+	err = s.tx.Bookmark().DeleteMissingSongs(ctx)
+	if err != nil {
+		return fmt.Errorf("delete bookmarks of deleted songs: %w", err)
+	}
+
 	err = s.tx.Genre().DeleteIfNoSongs(ctx)
 	if err != nil {
 		return fmt.Errorf("delete orphaned genres: %w", err)
@@ -72,7 +92,7 @@ func (s *Scanner) cleanAlbums(ctx context.Context) error {
 		log.Tracef("migrating album annotations from %s to %s", id.OldID, id.NewID)
 		err := s.tx.Album().MigrateAnnotations(ctx, id.OldID, id.NewID)
 		if err != nil {
-			log.Errorf("failed to migrate album annotations from %s to %s: %v", id.OldID, id.NewID, err)
+			s.logError("failed to migrate album annotations from %s to %s: %v", id.OldID, id.NewID, err)
 		}
 	}
 
@@ -93,7 +113,7 @@ func (s *Scanner) cleanArtists(ctx context.Context) error {
 		log.Tracef("migrating artist annotations from %s to %s", id.OldID, id.NewID)
 		err := s.tx.Artist().MigrateAnnotations(ctx, id.OldID, id.NewID)
 		if err != nil {
-			log.Errorf("failed to migrate artist annotations from %s to %s: %v", id.OldID, id.NewID, err)
+			s.logError("failed to migrate artist annotations from %s to %s: %v", id.OldID, id.NewID, err)
 		}
 	}
 
@@ -111,7 +131,7 @@ func (s *Scanner) deleteOrphanedSongsByPath(ctx context.Context) error {
 	removePaths := make([]string, 0, deleteOrphanedSongsByPathBatchSize)
 	var foundCount atomic.Int32
 	for i := 0; ; i += deleteOrphanedSongsByPathBatchSize {
-		paths, err := s.tx.Song().FindPaths(ctx, s.scanStart, repos.Paginate{
+		paths, err := s.tx.Song().FindPaths(ctx, s.scanStart, s.targetPaths(), repos.Paginate{
 			Offset: int(foundCount.Load()),
 			Limit:  &limit,
 		})
@@ -133,7 +153,7 @@ func (s *Scanner) deleteOrphanedSongsByPath(ctx context.Context) error {
 				defer waitGroup.Done()
 				for path := range checkPathsChan {
 					_, err := os.Stat(path)
-					if errors.Is(err, os.ErrNotExist) {
+					if errors.Is(err, os.ErrNotExist) || (err == nil && s.ignore.ignored(path, false)) {
 						removePathsChan <- path
 					} else {
 						foundCount.Add(1)
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"image"
 	"image/jpeg"
+	_ "image/png"
 	"io"
 	"os"
 	"path/filepath"
@@ -15,13 +17,13 @@ import (
 
 	"github.com/juho05/crossonic-server/audiotags"
 	"github.com/juho05/crossonic-server/config"
-	"github.com/juho05/log"
 )
 
 type albumCover struct {
-	id       string
-	cover    *string
-	songPath string
+	id            string
+	cover         *string
+	songPath      string
+	musicFolderID int
 }
 
 func (s *Scanner) runSetAlbumCoverLoop(ctx context.Context) error {
@@ -30,7 +32,6 @@ func (s *Scanner) runSetAlbumCoverLoop(ctx context.Context) error {
 		return fmt.Errorf("create cover dir: %w", err)
 	}
 	var waitGroup sync.WaitGroup
-	var setCoverErr error
 
 	embeddedEnabled := slices.Contains(s.conf.CoverArtPriority, config.CoverArtPriorityEmbedded)
 
@@ -39,67 +40,75 @@ func (s *Scanner) runSetAlbumCoverLoop(ctx context.Context) error {
 		go func() {
 			defer waitGroup.Done()
 
+			// keep receiving after the scan was canceled so that the save songs loop is never blocked
 			for album := range s.setAlbumCover {
-				if setCoverErr != nil {
-					return
-				}
-				select {
-				case <-ctx.Done():
-					return
-				default:
-				}
-				if album.cover != nil {
-					err := s.saveCoverFromPath(*album.cover, album.id)
-					if err != nil {
-						if setCoverErr == nil {
-							setCoverErr = fmt.Errorf("save cover from path: %w", err)
-						}
-						return
-					}
-					continue
-				}
-
-				if !embeddedEnabled {
-					err = s.removeCover(album.id)
-					if err != nil {
-						if setCoverErr == nil {
-							setCoverErr = fmt.Errorf("remove cover: %w", err)
-						}
-						return
-					}
-					continue
-				}
-
-				stat, err := os.Stat(album.songPath)
-				if err != nil {
-					if setCoverErr == nil {
-						setCoverErr = fmt.Errorf("stat song file: %w", err)
-					}
-					return
-				}
-
-				err = s.saveCoverFromEmbeddedCover(stat.ModTime(), album.songPath, album.id)
-				if errors.Is(err, errNoEmbeddedCover) {
-					err = s.removeCover(album.id)
-					if err != nil {
-						if setCoverErr == nil {
-							setCoverErr = fmt.Errorf("remove cover: %w", err)
-						}
-						return
-					}
-					continue
-				}
-				if err != nil {
-					if setCoverErr == nil {
-						setCoverErr = fmt.Errorf("save cover from path: %w", err)
-					}
-					return
+				if ctx.Err() == nil {
+					s.setAlbumCoverOfAlbum(album, embeddedEnabled)
 				}
+				s.pendingCovers.Done()
 			}
 		}()
 	}
 	waitGroup.Wait()
-	return setCoverErr
+	return nil
+}
+
+// setAlbumCoverOfAlbum saves or removes the cover of the album. Errors are added to the error list of the scan.
+func (s *Scanner) setAlbumCoverOfAlbum(album albumCover, embeddedEnabled bool) {
+	if album.cover != nil {
+		err := s.saveCoverFromPath(*album.cover, album.id)
+		if errors.Is(err, errUnsupportedCover) {
+			s.reportCover(*album.cover, album.musicFolderID, err)
+			s.removeUnsupportedCover(album.id)
+			return
+		}
+		if err != nil {
+			s.logFileError(*album.cover, "save cover from path: %s", err)
+			return
+		}
+		s.reportCover(*album.cover, album.musicFolderID, nil)
+		return
+	}
+
+	if !embeddedEnabled {
+		err := s.removeCover(album.id)
+		if err != nil {
+			s.logError("remove cover of album %s: %s", album.id, err)
+		}
+		return
+	}
+
+	stat, err := os.Stat(album.songPath)
+	if err != nil {
+		s.logFileError(album.songPath, "stat song file: %s", err)
+		return
+	}
+
+	err = s.saveCoverFromEmbeddedCover(stat.ModTime(), album.songPath, album.id)
+	if errors.Is(err, errUnsupportedCover) {
+		s.reportCover(album.songPath, album.musicFolderID, err)
+		s.removeUnsupportedCover(album.id)
+		return
+	}
+	s.reportCover(album.songPath, album.musicFolderID, nil)
+	if errors.Is(err, errNoEmbeddedCover) {
+		err = s.removeCover(album.id)
+		if err != nil {
+			s.logError("remove cover of album %s: %s", album.id, err)
+		}
+		return
+	}
+	if err != nil {
+		s.logFileError(album.songPath, "save embedded cover: %s", err)
+	}
+}
+
+// removeUnsupportedCover removes the cover of the album so that the unsupported image is checked again by the next scan.
+func (s *Scanner) removeUnsupportedCover(albumID string) {
+	err := s.removeCover(albumID)
+	if err != nil {
+		s.logError("remove cover of album %s: %s", albumID, err)
+	}
 }
 
 func (s *Scanner) createCoverDir() error {
@@ -133,6 +142,15 @@ func (s *Scanner) saveCoverFromPath(originalPath, id string) error {
 	}
 	defer old.Close()
 
+	_, _, err = image.DecodeConfig(old)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errUnsupportedCover, err)
+	}
+	_, err = old.Seek(0, io.SeekStart)
+	if err != nil {
+		return fmt.Errorf("seek original path: %w", err)
+	}
+
 	newFile, err := os.Create(s.idToCoverPath(id))
 	if err != nil {
 		return fmt.Errorf("create cover file: %w", err)
@@ -150,6 +168,7 @@ func (s *Scanner) saveCoverFromPath(originalPath, id string) error {
 }
 
 var errNoEmbeddedCover = errors.New("no embedded cover")
+var errUnsupportedCover = errors.New("unsupported cover image")
 
 func (s *Scanner) saveCoverFromEmbeddedCover(lastModified time.Time, songPath, id string) error {
 	if !s.fullScan {
@@ -163,19 +182,19 @@ func (s *Scanner) saveCoverFromEmbeddedCover(lastModified time.Time, songPath, i
 		}
 	}
 
-	newFile, err := os.Create(s.idToCoverPath(id))
+	img, err := audiotags.ReadImage(songPath)
 	if err != nil {
-		return fmt.Errorf("create cover file: %w", err)
+		return fmt.Errorf("%w: %w", errUnsupportedCover, err)
 	}
-	defer newFile.Close()
-
-	img, err := audiotags.ReadImage(songPath)
 	if img == nil {
 		return errNoEmbeddedCover
 	}
+
+	newFile, err := os.Create(s.idToCoverPath(id))
 	if err != nil {
-		return fmt.Errorf("read embedded song cover: %w", err)
+		return fmt.Errorf("create cover file: %w", err)
 	}
+	defer newFile.Close()
 
 	err = jpeg.Encode(newFile, img, nil)
 	if err != nil {
@@ -201,7 +220,7 @@ func (s *Scanner) invalidateCoverCache(id string) {
 		if strings.HasPrefix(key, id) {
 			err := s.coverCache.DeleteObject(key)
 			if err != nil {
-				log.Errorf("failed to invalidate cover cache for %s: %s", id, err)
+				s.logError("failed to invalidate cover cache for %s: %s", id, err)
 			}
 		}
 	}
@@ -0,0 +1,88 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/juho05/crossonic-server"
+	"github.com/juho05/crossonic-server/repos"
+)
+
+type directory struct {
+	path          string
+	parentPath    *string
+	musicFolderID int
+}
+
+// addDirectory remembers a directory found by walkDir to be saved by updateDirectories at the end of the scan.
+func (s *Scanner) addDirectory(path string, musicDirPath string, musicFolderID int) {
+	path = filepath.Clean(path)
+	dir := directory{
+		path:          path,
+		musicFolderID: musicFolderID,
+	}
+	if path != filepath.Clean(musicDirPath) {
+		parent := filepath.Dir(path)
+		dir.parentPath = &parent
+	}
+	s.directories = append(s.directories, dir)
+}
+
+// updateDirectories replaces the directory tree in the database with the directories found during the scan
+// while keeping the ids of directories that still exist. Directories outside the trees of a targeted scan are kept.
+func (s *Scanner) updateDirectories(ctx context.Context) error {
+	existing, err := s.tx.Directory().FindAll(ctx)
+	if err != nil {
+		return fmt.Errorf("find existing directories: %w", err)
+	}
+	ids := make(map[string]string, len(existing))
+	for _, d := range existing {
+		ids[d.Path] = d.ID
+	}
+
+	seen := make(map[string]struct{}, len(s.directories))
+	params := make([]repos.UpsertDirectoryParams, 0, len(s.directories))
+	for _, d := range s.directories {
+		if _, ok := seen[d.path]; ok {
+			continue
+		}
+		seen[d.path] = struct{}{}
+
+		id, ok := ids[d.path]
+		if !ok {
+			id = crossonic.GenIDDirectory()
+			ids[d.path] = id
+		}
+		var parentID *string
+		if d.parentPath != nil {
+			if p, ok := ids[*d.parentPath]; ok {
+				parentID = &p
+			}
+		}
+		params = append(params, repos.UpsertDirectoryParams{
+			ID:            id,
+			Path:          d.path,
+			Name:          filepath.Base(d.path),
+			ParentID:      parentID,
+			MusicFolderID: d.musicFolderID,
+		})
+	}
+
+	err = s.tx.Directory().UpsertAll(ctx, params)
+	if err != nil {
+		return fmt.Errorf("upsert directories: %w", err)
+	}
+
+	deleteIDs := make([]string, 0)
+	for _, d := range existing {
+		if _, ok := seen[d.Path]; !ok && s.inScanRoots(d.Path) {
+			deleteIDs = append(deleteIDs, d.ID)
+		}
+	}
+	err = s.tx.Directory().DeleteByIDs(ctx, deleteIDs)
+	if err != nil {
+		return fmt.Errorf("delete removed directories: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/juho05/crossonic-server/repos"
+)
+
+// Read-only libraries cannot store the song ID in a tag of the file and their mtimes cannot be trusted.
+// Instead, files are checked for changes by the hash of their content and moved files are re-associated
+// with their songs by the fingerprint of their audio payload, which does not change when the tags are edited.
+
+// loadContentHashes loads the content hashes of the songs in the targets of the current scan.
+func (s *Scanner) loadContentHashes(ctx context.Context) error {
+	fingerprints, err := s.tx.Song().FindFingerprints(ctx, s.targetPaths())
+	if err != nil {
+		return fmt.Errorf("find fingerprints: %w", err)
+	}
+	s.contentHashes = make(map[string]string, len(fingerprints))
+	for _, f := range fingerprints {
+		s.contentHashes[f.Path] = f.ContentHash
+	}
+	return nil
+}
+
+// findFingerprintMatches returns the songs with the audio fingerprints of songs by fingerprint.
+func (s *Scanner) findFingerprintMatches(ctx context.Context, songs []*song) (map[string][]*repos.SongFingerprint, error) {
+	if !s.conf.ReadOnlyLibrary {
+		return nil, nil
+	}
+	fingerprints := make([]string, 0, len(songs))
+	for _, song := range songs {
+		if song.fingerprint != "" {
+			fingerprints = append(fingerprints, song.fingerprint)
+		}
+	}
+	found, err := s.tx.Song().FindByFingerprints(ctx, fingerprints)
+	if err != nil {
+		return nil, fmt.Errorf("find by fingerprints: %w", err)
+	}
+	matches := make(map[string][]*repos.SongFingerprint, len(found))
+	for _, f := range found {
+		matches[f.Fingerprint] = append(matches[f.Fingerprint], f)
+	}
+	return matches, nil
+}
+
+// movedFrom reports whether the file of song could have been moved to newPath.
+// Files that still exist are copies, so their songs are kept.
+func (s *Scanner) movedFrom(song *repos.SongFingerprint, newPath string) bool {
+	if song.Path == newPath {
+		return false
+	}
+	_, err := os.Stat(song.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return true
+	}
+	if err != nil {
+		s.logFileError(song.Path, "stat previous path of moved file: %s", err)
+		return false
+	}
+	return s.ignore.ignored(song.Path, false)
+}
+
+// saveFingerprints stores the fingerprints of songs, which must already have been saved.
+func (s *Scanner) saveFingerprints(ctx context.Context, songs []*song) error {
+	params := make([]repos.SetSongFingerprintParams, 0, len(songs))
+	for _, song := range songs {
+		if song.fingerprint == "" {
+			continue
+		}
+		params = append(params, repos.SetSongFingerprintParams{
+			ID:          *song.id,
+			Fingerprint: song.fingerprint,
+			ContentHash: song.contentHash,
+		})
+	}
+	err := s.tx.Song().SetFingerprints(ctx, params)
+	if err != nil {
+		return fmt.Errorf("set fingerprints: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/juho05/crossonic-server/config"
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanner_movedFrom(t *testing.T) {
+	root := t.TempDir()
+	existing := filepath.Join(root, "existing.flac")
+	excluded := filepath.Join(root, "excluded.flac")
+	require.NoError(t, os.WriteFile(existing, nil, 0644))
+	require.NoError(t, os.WriteFile(excluded, nil, 0644))
+
+	s := &Scanner{}
+	s.ignore = newIgnoreMatcher([]config.MusicDir{{Path: root, Exclude: []string{"excluded.flac"}}}, func(path string, format string, args ...any) {
+		t.Errorf("unexpected error for %s: "+format, append([]any{path}, args...)...)
+	})
+
+	newPath := filepath.Join(root, "new.flac")
+	assert.True(t, s.movedFrom(&repos.SongFingerprint{Path: filepath.Join(root, "deleted.flac")}, newPath), "deleted file")
+	assert.True(t, s.movedFrom(&repos.SongFingerprint{Path: excluded}, newPath), "excluded file")
+	assert.False(t, s.movedFrom(&repos.SongFingerprint{Path: existing}, newPath), "copy of existing file")
+	assert.False(t, s.movedFrom(&repos.SongFingerprint{Path: newPath}, newPath), "same path")
+}
@@ -0,0 +1,235 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/djherbis/times"
+	"github.com/juho05/crossonic-server/config"
+)
+
+// ignoreFileName is the name of the files that exclude files and directories from scans with gitignore-style patterns.
+// The patterns apply to the directory of the ignore file and all of its subdirectories.
+const ignoreFileName = ".crossonicignore"
+
+// ignoreRule is a single gitignore-style pattern.
+type ignoreRule struct {
+	// base is the directory the pattern is relative to
+	base     string
+	segments []string
+	// anchored patterns are matched against the path relative to base, other patterns against the name of the file at any depth
+	anchored bool
+	negate   bool
+	dirOnly  bool
+}
+
+// parseIgnorePatterns parses gitignore-style patterns relative to base. Invalid patterns are skipped and returned as an error.
+func parseIgnorePatterns(base string, patterns []string) ([]ignoreRule, error) {
+	rules := make([]ignoreRule, 0, len(patterns))
+	var errs []error
+	for _, pattern := range patterns {
+		p := strings.TrimRight(pattern, " \t\r")
+		if p == "" || strings.HasPrefix(p, "#") {
+			continue
+		}
+		rule := ignoreRule{base: base}
+		if strings.HasPrefix(p, "!") {
+			rule.negate = true
+			p = p[1:]
+		} else if strings.HasPrefix(p, `\!`) || strings.HasPrefix(p, `\#`) {
+			p = p[1:]
+		}
+		if strings.HasSuffix(p, "/") {
+			rule.dirOnly = true
+			p = strings.TrimRight(p, "/")
+		}
+		if strings.Contains(p, "/") {
+			rule.anchored = true
+			p = strings.TrimPrefix(p, "/")
+		}
+		if p == "" {
+			continue
+		}
+		rule.segments = strings.Split(p, "/")
+		valid := true
+		for _, s := range rule.segments {
+			if _, err := path.Match(s, ""); err != nil {
+				errs = append(errs, fmt.Errorf("invalid pattern %q: %w", pattern, err))
+				valid = false
+				break
+			}
+		}
+		if valid {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, errors.Join(errs...)
+}
+
+// matches reports whether the rule matches the file or directory at p.
+func (r ignoreRule) matches(p string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+	rel, err := filepath.Rel(r.base, p)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false
+	}
+	segments := strings.Split(filepath.ToSlash(rel), "/")
+	if !r.anchored {
+		match, _ := path.Match(r.segments[0], segments[len(segments)-1])
+		return match
+	}
+	return matchIgnoreSegments(r.segments, segments)
+}
+
+// matchIgnoreSegments matches the path segments against the pattern segments. ** matches any number of segments.
+func matchIgnoreSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchIgnoreSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	match, _ := path.Match(pattern[0], segments[0])
+	return match && matchIgnoreSegments(pattern[1:], segments[1:])
+}
+
+// ignoreMatcher decides which files and directories of the music dirs are excluded from a scan
+// by the exclude patterns of the music dir config and the ignore files found in the music dirs.
+// Later rules take precedence, rules of ignore files in subdirectories come after the rules of their parents.
+type ignoreMatcher struct {
+	lock sync.Mutex
+	// excludes contains the rules of the exclude patterns of every music dir by its path
+	excludes map[string][]ignoreRule
+	// dirRules caches all rules that apply to the entries of a directory
+	dirRules map[string][]ignoreRule
+	// ignoredDirs caches whether a directory or one of its parents is excluded
+	ignoredDirs map[string]bool
+	onError     func(path string, format string, args ...any)
+}
+
+func newIgnoreMatcher(musicDirs []config.MusicDir, onError func(path string, format string, args ...any)) *ignoreMatcher {
+	m := &ignoreMatcher{
+		excludes:    make(map[string][]ignoreRule, len(musicDirs)),
+		dirRules:    make(map[string][]ignoreRule),
+		ignoredDirs: make(map[string]bool),
+		onError:     onError,
+	}
+	for _, dir := range musicDirs {
+		root := filepath.Clean(dir.Path)
+		rules, err := parseIgnorePatterns(root, dir.Exclude)
+		if err != nil {
+			onError(root, "exclude patterns: %s", err)
+		}
+		m.excludes[root] = rules
+	}
+	return m
+}
+
+// ignoredInDir reports whether the file or directory at p is excluded by the rules of its parent directory.
+// It does not check whether one of the parent directories is excluded.
+func (m *ignoreMatcher) ignoredInDir(p string, isDir bool) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	p = filepath.Clean(p)
+	if !m.inMusicDir(p) {
+		return false
+	}
+	return m.match(p, isDir)
+}
+
+// ignored reports whether the file or directory at p or one of its parent directories is excluded.
+func (m *ignoreMatcher) ignored(p string, isDir bool) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.ignoredLocked(filepath.Clean(p), isDir)
+}
+
+func (m *ignoreMatcher) ignoredLocked(p string, isDir bool) bool {
+	if !m.inMusicDir(p) {
+		return false
+	}
+	parent := filepath.Dir(p)
+	parentIgnored, ok := m.ignoredDirs[parent]
+	if !ok {
+		parentIgnored = m.ignoredLocked(parent, true)
+		m.ignoredDirs[parent] = parentIgnored
+	}
+	return parentIgnored || m.match(p, isDir)
+}
+
+// inMusicDir reports whether p is inside of a music dir. The music dirs themselves cannot be excluded.
+func (m *ignoreMatcher) inMusicDir(p string) bool {
+	for root := range m.excludes {
+		if p != root && isSubPath(root, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// match applies the rules of the parent directory of p. p must be inside of a music dir.
+func (m *ignoreMatcher) match(p string, isDir bool) bool {
+	ignored := false
+	for _, r := range m.rulesOf(filepath.Dir(p)) {
+		if r.matches(p, isDir) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+// rulesOf returns all rules that apply to the entries of dir. dir must be a music dir or inside of one.
+func (m *ignoreMatcher) rulesOf(dir string) []ignoreRule {
+	if rules, ok := m.dirRules[dir]; ok {
+		return rules
+	}
+	parentRules, ok := m.excludes[dir]
+	if !ok {
+		parentRules = m.rulesOf(filepath.Dir(dir))
+	}
+	rules := slices.Concat(parentRules, m.readIgnoreFile(dir))
+	m.dirRules[dir] = rules
+	return rules
+}
+
+func (m *ignoreMatcher) readIgnoreFile(dir string) []ignoreRule {
+	filePath := filepath.Join(dir, ignoreFileName)
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			m.onError(filePath, "read ignore file: %s", err)
+		}
+		return nil
+	}
+	rules, err := parseIgnorePatterns(dir, strings.Split(string(data), "\n"))
+	if err != nil {
+		m.onError(filePath, "parse ignore file: %s", err)
+	}
+	return rules
+}
+
+// ignoreFileChanged reports whether the ignore file of dir was created or modified since the last scan.
+// Deleting the ignore file modifies dir itself.
+func (s *Scanner) ignoreFileChanged(dir string) bool {
+	stat, err := times.Stat(filepath.Join(dir, ignoreFileName))
+	if err != nil {
+		return false
+	}
+	return stat.ModTime().After(s.lastScan) || !stat.HasChangeTime() || stat.ChangeTime().After(s.lastScan)
+}
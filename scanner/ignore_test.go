@@ -0,0 +1,76 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/juho05/crossonic-server/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ignoreRule_matches(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		path    string
+		isDir   bool
+		want    bool
+	}{
+		{"name at any depth", "*.wav", "/music/a/b/song.wav", false, true},
+		{"name no match", "*.wav", "/music/a/song.flac", false, false},
+		{"dir only matches dir", "stems/", "/music/a/stems", true, true},
+		{"dir only does not match file", "stems/", "/music/a/stems", false, false},
+		{"anchored", "/samples", "/music/samples", true, true},
+		{"anchored not nested", "/samples", "/music/a/samples", true, false},
+		{"anchored with slash", "a/stems", "/music/a/stems", true, true},
+		{"double star prefix", "**/stems", "/music/a/b/stems", true, true},
+		{"double star middle", "a/**/*.wav", "/music/a/b/c/song.wav", false, true},
+		{"double star middle zero dirs", "a/**/*.wav", "/music/a/song.wav", false, true},
+		{"outside of base", "*.wav", "/other/song.wav", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := parseIgnorePatterns("/music", []string{tt.pattern})
+			require.NoError(t, err)
+			require.Len(t, rules, 1)
+			assert.Equal(t, tt.want, rules[0].matches(tt.path, tt.isDir))
+		})
+	}
+}
+
+func Test_parseIgnorePatterns(t *testing.T) {
+	rules, err := parseIgnorePatterns("/music", []string{"# comment", "", "  ", "!keep.wav", `\#hash`, "[", "*.wav  "})
+	assert.Error(t, err, "invalid pattern should be reported")
+	require.Len(t, rules, 3)
+	assert.True(t, rules[0].negate)
+	assert.Equal(t, []string{"keep.wav"}, rules[0].segments)
+	assert.Equal(t, []string{"#hash"}, rules[1].segments)
+	assert.Equal(t, []string{"*.wav"}, rules[2].segments)
+}
+
+func Test_ignoreMatcher(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "a", "stems"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "audiobooks"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ignoreFileName), []byte("*.wav\nstems/\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a", ignoreFileName), []byte("!keep.wav\n"), 0644))
+
+	var errs []string
+	m := newIgnoreMatcher([]config.MusicDir{{Path: root, Exclude: []string{"/audiobooks"}}}, func(path string, format string, args ...any) {
+		errs = append(errs, path)
+	})
+
+	assert.False(t, m.ignored(root, true), "music dir should never be excluded")
+	assert.True(t, m.ignored(filepath.Join(root, "audiobooks"), true), "exclude pattern of the config")
+	assert.True(t, m.ignored(filepath.Join(root, "audiobooks", "book.mp3"), false), "files in excluded dirs should be excluded")
+	assert.False(t, m.ignoredInDir(filepath.Join(root, "audiobooks", "book.mp3"), false), "parents are not checked")
+	assert.True(t, m.ignored(filepath.Join(root, "song.wav"), false))
+	assert.True(t, m.ignored(filepath.Join(root, "a", "song.wav"), false), "rules apply to subdirectories")
+	assert.False(t, m.ignored(filepath.Join(root, "a", "keep.wav"), false), "rules of subdirectories take precedence")
+	assert.True(t, m.ignored(filepath.Join(root, "a", "stems", "keep.wav"), false))
+	assert.False(t, m.ignored(filepath.Join(root, "a", "song.flac"), false))
+	assert.False(t, m.ignored("/other/song.wav", false), "paths outside of music dirs are never excluded")
+	assert.Empty(t, errs)
+}
@@ -0,0 +1,137 @@
+//go:build linux
+
+package scanner
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/juho05/log"
+	"golang.org/x/sys/unix"
+)
+
+const inotifyMask = unix.IN_CREATE | unix.IN_CLOSE_WRITE | unix.IN_DELETE | unix.IN_MOVED_FROM | unix.IN_MOVED_TO | unix.IN_ONLYDIR
+
+// inotifyWatcher watches directories with the inotify API of the Linux kernel.
+type inotifyWatcher struct {
+	fd   int
+	file *os.File
+
+	lock    sync.Mutex
+	watches map[int32]string
+	paths   map[string]int32
+
+	eventsChan chan fsEvent
+}
+
+func newFSWatcher() (fsWatcher, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("init inotify: %w", err)
+	}
+	w := &inotifyWatcher{
+		fd: fd,
+		// the file is non-blocking, so reads are handled by the runtime poller and unblocked by Close
+		file:       os.NewFile(uintptr(fd), "inotify"),
+		watches:    make(map[int32]string),
+		paths:      make(map[string]int32),
+		eventsChan: make(chan fsEvent, 64),
+	}
+	go w.readEvents()
+	return w, nil
+}
+
+func (w *inotifyWatcher) add(path string) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	wd, err := unix.InotifyAddWatch(w.fd, path, inotifyMask)
+	if err != nil {
+		if errors.Is(err, unix.ENOSPC) {
+			return fmt.Errorf("inotify watch limit reached, consider increasing fs.inotify.max_user_watches: %w", err)
+		}
+		return fmt.Errorf("add inotify watch: %w", err)
+	}
+	w.watches[int32(wd)] = path
+	w.paths[path] = int32(wd)
+	return nil
+}
+
+func (w *inotifyWatcher) remove(path string) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	for p, wd := range w.paths {
+		if !isSubPath(path, p) {
+			continue
+		}
+		// fails if the kernel already removed the watch because the directory was deleted
+		_, _ = unix.InotifyRmWatch(w.fd, uint32(wd))
+		delete(w.paths, p)
+		delete(w.watches, wd)
+	}
+}
+
+func (w *inotifyWatcher) events() <-chan fsEvent {
+	return w.eventsChan
+}
+
+func (w *inotifyWatcher) close() error {
+	return w.file.Close()
+}
+
+func (w *inotifyWatcher) readEvents() {
+	defer close(w.eventsChan)
+	buf := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+	for {
+		n, err := w.file.Read(buf)
+		if err != nil {
+			if !errors.Is(err, os.ErrClosed) {
+				log.Errorf("read inotify events: %s", err)
+			}
+			return
+		}
+		for offset := 0; offset+unix.SizeofInotifyEvent <= n; {
+			wd := int32(binary.NativeEndian.Uint32(buf[offset:]))
+			mask := binary.NativeEndian.Uint32(buf[offset+4:])
+			nameLen := int(binary.NativeEndian.Uint32(buf[offset+12:]))
+			offset += unix.SizeofInotifyEvent
+			name := strings.TrimRight(string(buf[offset:min(offset+nameLen, n)]), "\x00")
+			offset += nameLen
+
+			if event, ok := w.toEvent(wd, mask, name); ok {
+				w.eventsChan <- event
+			}
+		}
+	}
+}
+
+func (w *inotifyWatcher) toEvent(wd int32, mask uint32, name string) (fsEvent, bool) {
+	if mask&unix.IN_Q_OVERFLOW != 0 {
+		return fsEvent{}, true
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	dir, ok := w.watches[wd]
+	if !ok {
+		return fsEvent{}, false
+	}
+	if mask&unix.IN_IGNORED != 0 {
+		delete(w.watches, wd)
+		if w.paths[dir] == wd {
+			delete(w.paths, dir)
+		}
+		return fsEvent{}, false
+	}
+	if name == "" {
+		return fsEvent{}, false
+	}
+	return fsEvent{
+		path:    filepath.Join(dir, name),
+		isDir:   mask&unix.IN_ISDIR != 0,
+		removed: mask&(unix.IN_DELETE|unix.IN_MOVED_FROM) != 0,
+	}, true
+}
@@ -0,0 +1,51 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInotifyWatcher(t *testing.T) {
+	dir := t.TempDir()
+	album := filepath.Join(dir, "album")
+	require.NoError(t, os.Mkdir(album, 0755))
+
+	w, err := newFSWatcher()
+	require.NoError(t, err)
+	defer w.close()
+	require.NoError(t, w.add(dir))
+	require.NoError(t, w.add(album))
+
+	next := func() fsEvent {
+		select {
+		case e := <-w.events():
+			return e
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "timeout waiting for event")
+			return fsEvent{}
+		}
+	}
+
+	song := filepath.Join(album, "song.mp3")
+	require.NoError(t, os.WriteFile(song, []byte("test"), 0644))
+	assert.Equal(t, fsEvent{path: song}, next(), "create")
+	assert.Equal(t, fsEvent{path: song}, next(), "close write")
+
+	require.NoError(t, os.Remove(song))
+	assert.Equal(t, fsEvent{path: song, removed: true}, next())
+
+	moved := filepath.Join(dir, "moved")
+	require.NoError(t, os.Rename(album, moved))
+	assert.Equal(t, fsEvent{path: album, isDir: true, removed: true}, next())
+	assert.Equal(t, fsEvent{path: moved, isDir: true}, next())
+
+	w.remove(album)
+	require.NoError(t, os.WriteFile(filepath.Join(moved, "song.mp3"), []byte("test"), 0644))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "new"), 0755))
+	assert.Equal(t, fsEvent{path: filepath.Join(dir, "new"), isDir: true}, next(), "removed watches should not report events")
+}
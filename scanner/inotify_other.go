@@ -0,0 +1,9 @@
+//go:build !linux
+
+package scanner
+
+import "errors"
+
+func newFSWatcher() (fsWatcher, error) {
+	return nil, errors.New("watching music dirs is only supported on Linux")
+}
@@ -48,7 +48,7 @@ func (s *Scanner) LoadMusicDirs(tx repos.Transaction) (changed bool, err error)
 		return false, fmt.Errorf("set system music dir config: %w", err)
 	}
 
-	err = tx.MusicFolder().DeleteAllUserAssociations(ctx)
+	err = tx.MusicFolder().DeleteDefaultUserAssociations(ctx)
 	if err != nil {
 		return false, fmt.Errorf("delete user associations: %w", err)
 	}
@@ -71,8 +71,15 @@ func (s *Scanner) LoadMusicDirs(tx repos.Transaction) (changed bool, err error)
 	userNames := util.Map(users, func(u *repos.User) string {
 		return u.Name
 	})
+	// users with custom music folders keep their associations
+	defaultUserNames := make([]string, 0, len(users))
+	for _, u := range users {
+		if !u.CustomMusicFolders {
+			defaultUserNames = append(defaultUserNames, u.Name)
+		}
+	}
 	for _, dir := range musicDirs {
-		names := userNames
+		names := defaultUserNames
 
 		if dir.Users != nil {
 			names = make([]string, 0, len(dir.Users))
@@ -80,7 +87,9 @@ func (s *Scanner) LoadMusicDirs(tx repos.Transaction) (changed bool, err error)
 				if !slices.Contains(userNames, u) {
 					return false, fmt.Errorf("unknown user for music dir %d: %s", dir.ID, u)
 				}
-				names = append(names, u)
+				if slices.Contains(defaultUserNames, u) {
+					names = append(names, u)
+				}
 			}
 		}
 
@@ -99,3 +108,37 @@ func (s *Scanner) LoadMusicDirs(tx repos.Transaction) (changed bool, err error)
 
 	return changed, nil
 }
+
+// CreateMusicFolderAssociationsForUser gives a new user access to all music folders configured for them in the music dir config.
+func CreateMusicFolderAssociationsForUser(ctx context.Context, tx repos.Tx, user string, conf config.Config) error {
+	lastMusicDirConfig, err := tx.System().MusicDirConfig(ctx)
+	if errors.Is(err, repos.ErrNotFound) {
+		// user associations don't exist yet, nothing to do
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get last music folder: %w", err)
+	}
+
+	musicDirs, err := conf.GetMusicDirs()
+	if err != nil {
+		return fmt.Errorf("get music dirs: %w", err)
+	}
+	currentMusicDirConfig := config.GenerateMusicDirConfigString(musicDirs)
+	if lastMusicDirConfig != currentMusicDirConfig {
+		log.Warnf("The music dir configuration has changed since the last scan. Please execute a scan or restart crossonic-server " +
+			"before using this user.")
+		return nil
+	}
+
+	for _, dir := range musicDirs {
+		if dir.Users == nil || slices.Contains(dir.Users, user) {
+			err = tx.MusicFolder().CreateUserAssociations(ctx, dir.ID, []string{user})
+			if err != nil {
+				return fmt.Errorf("create user association for music folder %d: %w", dir.ID, err)
+			}
+		}
+	}
+
+	return nil
+}
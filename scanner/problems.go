@@ -0,0 +1,104 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/juho05/crossonic-server/repos"
+)
+
+// fileProblemTypes are the problems that are checked whenever the tags of a media file are read.
+var fileProblemTypes = []repos.ScanProblemType{
+	repos.ScanProblemUnreadableTags,
+	repos.ScanProblemMissingTitle,
+	repos.ScanProblemZeroDuration,
+	repos.ScanProblemInvalidDate,
+}
+
+// coverProblemTypes are the problems that are checked whenever a cover is saved.
+var coverProblemTypes = []repos.ScanProblemType{
+	repos.ScanProblemUnsupportedCover,
+}
+
+type fileProblem struct {
+	typ     repos.ScanProblemType
+	message string
+}
+
+// problemReport contains the files that were checked and the problems found in them.
+// The stored problems of checked files are replaced when the report is saved.
+type problemReport struct {
+	checkedFiles  []string
+	checkedCovers []string
+	problems      []repos.CreateScanProblemParams
+}
+
+// reportFileProblems records that the tags of the media file at path were read and found to have problems.
+func (s *Scanner) reportFileProblems(path string, musicFolderID int, problems []fileProblem) {
+	s.problemsLock.Lock()
+	defer s.problemsLock.Unlock()
+	s.problems.checkedFiles = append(s.problems.checkedFiles, path)
+	for _, p := range problems {
+		s.problems.problems = append(s.problems.problems, repos.CreateScanProblemParams{
+			Path:          path,
+			Type:          p.typ,
+			Message:       p.message,
+			MusicFolderID: musicFolderID,
+		})
+	}
+}
+
+// reportCover records that the cover at path was saved. If err is not nil, the cover is reported as unsupported.
+func (s *Scanner) reportCover(path string, musicFolderID int, err error) {
+	s.problemsLock.Lock()
+	defer s.problemsLock.Unlock()
+	s.problems.checkedCovers = append(s.problems.checkedCovers, path)
+	if err != nil {
+		s.problems.problems = append(s.problems.problems, repos.CreateScanProblemParams{
+			Path:          path,
+			Type:          repos.ScanProblemUnsupportedCover,
+			Message:       err.Error(),
+			MusicFolderID: musicFolderID,
+		})
+	}
+}
+
+// saveProblems replaces the stored problems of all files checked since the last call.
+func (s *Scanner) saveProblems(ctx context.Context) error {
+	s.problemsLock.Lock()
+	report := s.problems
+	s.problems = problemReport{}
+	s.problemsLock.Unlock()
+
+	err := s.tx.ScanProblem().DeleteByPaths(ctx, report.checkedFiles, fileProblemTypes)
+	if err != nil {
+		return fmt.Errorf("delete file problems: %w", err)
+	}
+	err = s.tx.ScanProblem().DeleteByPaths(ctx, report.checkedCovers, coverProblemTypes)
+	if err != nil {
+		return fmt.Errorf("delete cover problems: %w", err)
+	}
+	err = s.tx.ScanProblem().CreateAll(ctx, report.problems)
+	if err != nil {
+		return fmt.Errorf("create problems: %w", err)
+	}
+	return nil
+}
+
+// deleteProblemsOfMissingFiles deletes the problems of files that no longer exist or are excluded from scans.
+func (s *Scanner) deleteProblemsOfMissingFiles(ctx context.Context) error {
+	paths, err := s.tx.ScanProblem().FindPaths(ctx, s.targetPaths())
+	if err != nil {
+		return fmt.Errorf("find paths: %w", err)
+	}
+	missing := make([]string, 0, len(paths))
+	for _, p := range paths {
+		_, err := os.Stat(p)
+		if errors.Is(err, os.ErrNotExist) || (err == nil && s.ignore.ignored(p, false)) {
+			missing = append(missing, p)
+		}
+	}
+	return s.tx.ScanProblem().DeleteByPaths(ctx, missing, nil)
+}
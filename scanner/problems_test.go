@@ -0,0 +1,54 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/juho05/crossonic-server/repos/mockdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanner_saveProblems(t *testing.T) {
+	type deleted struct {
+		paths []string
+		types []repos.ScanProblemType
+	}
+	var deletes []deleted
+	var created []repos.CreateScanProblemParams
+	s := &Scanner{
+		tx: &mockdb.DB{
+			ScanProblemRepository: mockdb.ScanProblemRepository{
+				DeleteByPathsMock: func(ctx context.Context, paths []string, types []repos.ScanProblemType) error {
+					deletes = append(deletes, deleted{paths: paths, types: types})
+					return nil
+				},
+				CreateAllMock: func(ctx context.Context, params []repos.CreateScanProblemParams) error {
+					created = append(created, params...)
+					return nil
+				},
+			},
+		},
+	}
+
+	s.reportFileProblems("/music/a.mp3", 1, nil)
+	s.reportFileProblems("/music/b.mp3", 1, []fileProblem{{typ: repos.ScanProblemMissingTitle, message: "no title"}})
+	s.reportCover("/music/cover.jpg", 2, errors.New("unsupported"))
+	require.NoError(t, s.saveProblems(context.Background()))
+
+	assert.Equal(t, []deleted{
+		{paths: []string{"/music/a.mp3", "/music/b.mp3"}, types: fileProblemTypes},
+		{paths: []string{"/music/cover.jpg"}, types: coverProblemTypes},
+	}, deletes)
+	assert.Equal(t, []repos.CreateScanProblemParams{
+		{Path: "/music/b.mp3", Type: repos.ScanProblemMissingTitle, Message: "no title", MusicFolderID: 1},
+		{Path: "/music/cover.jpg", Type: repos.ScanProblemUnsupportedCover, Message: "unsupported", MusicFolderID: 2},
+	}, created)
+
+	deletes, created = nil, nil
+	require.NoError(t, s.saveProblems(context.Background()))
+	assert.Equal(t, []deleted{{types: fileProblemTypes}, {types: coverProblemTypes}}, deletes)
+	assert.Empty(t, created, "saved problems should not be saved again")
+}
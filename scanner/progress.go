@@ -0,0 +1,162 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/juho05/crossonic-server/util"
+	"github.com/juho05/log"
+)
+
+// maxScanErrors is the maximum number of errors kept in the error list of a scan.
+// Further errors are still logged and counted.
+const maxScanErrors = 1000
+
+// Phase is a step of a scan. Phases run in the order they are declared.
+type Phase string
+
+const (
+	// PhasePreparing loads the state of the library from the DB.
+	PhasePreparing Phase = "preparing"
+	// PhaseScanningFiles walks the music dirs and reads the tags of new and changed files.
+	PhaseScanningFiles Phase = "scanningFiles"
+	// PhaseSavingSongs waits until all read files are saved.
+	PhaseSavingSongs Phase = "savingSongs"
+	// PhaseUpdatingLibrary updates directories, album artists and music folder associations.
+	PhaseUpdatingLibrary Phase = "updatingLibrary"
+	// PhaseCleaningUp deletes orphaned entities and fixes playlists and scrobbles.
+	PhaseCleaningUp Phase = "cleaningUp"
+	// PhaseArtistImages finds and saves artist images.
+	PhaseArtistImages Phase = "artistImages"
+	// PhaseCommitting commits all changes of the scan.
+	PhaseCommitting Phase = "committing"
+)
+
+// Progress describes the state of the running scan.
+type Progress struct {
+	Phase Phase
+	// DiscoveredFiles is the number of media files found so far.
+	DiscoveredFiles int
+	// ProcessedFiles is the number of discovered files that were saved or skipped because they did not change.
+	ProcessedFiles int
+	// Errors is the number of problems that did not stop the scan.
+	Errors int
+	// Canceling is true if the scan was canceled and is rolling back its changes.
+	Canceling bool
+}
+
+// ScanError is a problem that did not stop the scan.
+type ScanError struct {
+	// Path is the file or directory the error occurred with, empty if the error is not related to a file.
+	Path    string
+	Message string
+	Time    time.Time
+}
+
+// ScanErrors returns the errors of the running scan or, if no scan is running, of the last scan.
+func (s *Scanner) ScanErrors() []ScanError {
+	s.progressLock.Lock()
+	defer s.progressLock.Unlock()
+	return slices.Clone(s.scanErrors)
+}
+
+// Progress returns the progress of the running scan. The result is meaningless if no scan is running.
+func (s *Scanner) Progress() Progress {
+	s.progressLock.Lock()
+	phase := s.phase
+	canceling := s.canceled
+	s.progressLock.Unlock()
+	return Progress{
+		Phase:           phase,
+		DiscoveredFiles: int(s.counter.Load()),
+		ProcessedFiles:  int(s.processed.Load()),
+		Errors:          int(s.errorCount.Load()),
+		Canceling:       canceling,
+	}
+}
+
+// Cancel stops the running scan. Changes since the last checkpoint are rolled back and the scan returns ErrScanCanceled.
+// The next scan resumes the canceled scan from the last checkpoint. Returns ErrNotScanning if no scan is running.
+func (s *Scanner) Cancel() error {
+	s.progressLock.Lock()
+	defer s.progressLock.Unlock()
+	if s.cancelScan == nil {
+		return ErrNotScanning
+	}
+	if !s.canceled {
+		log.Infof("Canceling scan...")
+		s.canceled = true
+		s.cancelScan()
+	}
+	return nil
+}
+
+// startProgress resets the progress for a new scan that can be canceled with cancel.
+func (s *Scanner) startProgress(cancel context.CancelFunc) {
+	s.progressLock.Lock()
+	defer s.progressLock.Unlock()
+	s.phase = PhasePreparing
+	s.cancelScan = cancel
+	s.canceled = false
+	s.scanErrors = nil
+	s.failedPaths = make(map[string]struct{})
+	s.counter.Store(0)
+	s.processed.Store(0)
+	s.errorCount.Store(0)
+}
+
+// stopProgress makes the scan no longer cancelable and reports whether it was canceled.
+func (s *Scanner) stopProgress() (canceled bool) {
+	s.progressLock.Lock()
+	defer s.progressLock.Unlock()
+	s.cancelScan = nil
+	return s.canceled
+}
+
+func (s *Scanner) setPhase(phase Phase) {
+	s.progressLock.Lock()
+	defer s.progressLock.Unlock()
+	log.Tracef("scan phase: %s", phase)
+	s.phase = phase
+}
+
+// logError logs a problem that does not stop the scan and adds it to the error list.
+func (s *Scanner) logError(format string, args ...any) {
+	s.addError("", fmt.Sprintf(format, args...))
+}
+
+// logFileError logs a problem with the file or directory at path that does not stop the scan and adds it to the error list.
+// Full scans keep the songs of failed files instead of deleting them as missing.
+func (s *Scanner) logFileError(path string, format string, args ...any) {
+	s.addError(path, fmt.Sprintf(format, args...))
+}
+
+// failedFilePaths returns the paths of all files and directories with errors during the current scan.
+func (s *Scanner) failedFilePaths() []string {
+	s.progressLock.Lock()
+	defer s.progressLock.Unlock()
+	return util.MapKeys(s.failedPaths)
+}
+
+func (s *Scanner) addError(path, message string) {
+	s.errorCount.Add(1)
+	if path != "" {
+		log.Errorf("%s: %s", path, message)
+	} else {
+		log.Error(message)
+	}
+	s.progressLock.Lock()
+	defer s.progressLock.Unlock()
+	if path != "" {
+		s.failedPaths[path] = struct{}{}
+	}
+	if len(s.scanErrors) < maxScanErrors {
+		s.scanErrors = append(s.scanErrors, ScanError{
+			Path:    path,
+			Message: message,
+			Time:    time.Now(),
+		})
+	}
+}
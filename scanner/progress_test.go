@@ -0,0 +1,63 @@
+package scanner
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanner_Cancel(t *testing.T) {
+	s := &Scanner{}
+	assert.ErrorIs(t, s.Cancel(), ErrNotScanning)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.startProgress(cancel)
+	s.counter.Store(3)
+	s.processed.Store(1)
+	s.logError("test error")
+	s.setPhase(PhaseSavingSongs)
+	assert.Equal(t, Progress{Phase: PhaseSavingSongs, DiscoveredFiles: 3, ProcessedFiles: 1, Errors: 1}, s.Progress())
+
+	require.NoError(t, s.Cancel())
+	require.NoError(t, s.Cancel())
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	assert.True(t, s.Progress().Canceling)
+
+	assert.True(t, s.stopProgress())
+	assert.ErrorIs(t, s.Cancel(), ErrNotScanning)
+}
+
+func TestScanner_ScanErrors(t *testing.T) {
+	s := &Scanner{}
+	s.startProgress(func() {})
+	s.logError("error %d", 1)
+	s.logFileError("/music/song.mp3", "read tags: %s", "invalid")
+	for range maxScanErrors {
+		s.logFileError("/music/other.mp3", "failed")
+	}
+
+	scanErrors := s.ScanErrors()
+	require.Len(t, scanErrors, maxScanErrors)
+	assert.Equal(t, "", scanErrors[0].Path)
+	assert.Equal(t, "error 1", scanErrors[0].Message)
+	assert.Equal(t, "/music/song.mp3", scanErrors[1].Path)
+	assert.Equal(t, "read tags: invalid", scanErrors[1].Message)
+	assert.Equal(t, maxScanErrors+2, s.Progress().Errors)
+	assert.ElementsMatch(t, []string{"/music/song.mp3", "/music/other.mp3"}, s.failedFilePaths())
+
+	s.stopProgress()
+	assert.Len(t, s.ScanErrors(), maxScanErrors)
+}
+
+func TestScanner_scanMediaFilesInDir_unreadableDir(t *testing.T) {
+	s := &Scanner{}
+	s.startProgress(func() {})
+	dir := filepath.Join(t.TempDir(), "missing")
+
+	err := s.scanMediaFilesInDir(context.Background(), dir, "", true, 1)
+	require.NoError(t, err, "directory errors should not stop the scan")
+	assert.Equal(t, []string{dir}, s.failedFilePaths())
+}
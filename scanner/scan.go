@@ -9,6 +9,7 @@ import (
 	"mime"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -18,6 +19,7 @@ import (
 	"github.com/itlightning/dateparse"
 	"github.com/juho05/crossonic-server/audiotags"
 	"github.com/juho05/crossonic-server/config"
+	"github.com/juho05/crossonic-server/fingerprint"
 	"github.com/juho05/crossonic-server/repos"
 	"github.com/juho05/crossonic-server/util"
 	"github.com/juho05/log"
@@ -31,13 +33,61 @@ const songQueueBatchSize = 100
 const deleteOrphanedSongsByPathWorkerCount = 10
 const deleteOrphanedSongsByPathBatchSize = 300
 
-func (s *Scanner) Scan(db repos.DB, fullScan bool) (err error) {
+// Scan scans all music dirs. Changes are committed at checkpoints, so an interrupted scan keeps most of its progress.
+// If the last scan was interrupted, it is resumed before the requested scan starts.
+func (s *Scanner) Scan(db repos.DB, fullScan bool) error {
+	return s.scan(db, fullScan, nil)
+}
+
+// ScanPaths scans only the directory trees at paths. Paths that no longer exist remove their songs from the library.
+// Paths outside the music dirs are ignored. If fullScan is true, all files in the trees are read again.
+// If the whole library needs a full scan, all music dirs are scanned instead.
+// Targeted scans do not update the last scan time, so changes elsewhere are still picked up by the next scan.
+func (s *Scanner) ScanPaths(db repos.DB, paths []string, fullScan bool) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	return s.scan(db, fullScan, paths)
+}
+
+func (s *Scanner) scan(db repos.DB, fullScan bool, paths []string) error {
 	if !s.lock.TryLock() {
 		return ErrAlreadyScanning
 	}
+	defer s.lock.Unlock()
 	s.scanning = true
+	defer func() {
+		s.scanning = false
+	}()
+
+	checkpoint, err := db.System().ScanCheckpoint(context.Background())
+	if err != nil {
+		if !errors.Is(err, repos.ErrNotFound) {
+			return fmt.Errorf("get scan checkpoint: %w", err)
+		}
+		return s.runScan(db, fullScan, paths, nil)
+	}
+
+	if fullScan && paths == nil && (!checkpoint.FullScan || checkpoint.Paths != nil) {
+		log.Infof("Not resuming interrupted scan because a full scan was requested.")
+		return s.runScan(db, fullScan, paths, nil)
+	}
+
+	log.Infof("Resuming interrupted scan started at %s...", checkpoint.Start.Format(time.DateTime))
+	err = s.runScan(db, checkpoint.FullScan, checkpoint.Paths, checkpoint)
+	if err != nil {
+		return fmt.Errorf("resume scan: %w", err)
+	}
+	if fullScan == checkpoint.FullScan && slices.Equal(paths, checkpoint.Paths) {
+		return nil
+	}
+	return s.runScan(db, fullScan, paths, nil)
+}
+
+// runScan scans the music dirs or only the paths if paths is not nil.
+// If resume is not nil, runScan continues the interrupted scan and skips all songs saved before it was interrupted.
+func (s *Scanner) runScan(db repos.DB, fullScan bool, paths []string, resume *repos.ScanCheckpoint) (err error) {
 	s.fullScan = fullScan
-	defer s.lock.Unlock()
 	defer func() {
 		if !s.songQueueClosed && s.songQueue != nil {
 			s.songQueueClosed = true
@@ -47,23 +97,35 @@ func (s *Scanner) Scan(db repos.DB, fullScan bool) (err error) {
 			s.setAlbumCoverClosed = true
 			close(s.setAlbumCover)
 		}
-		s.scanning = false
 		s.albums = nil
 		s.artists = nil
+		s.directories = nil
+		s.targets = nil
+		s.savedPaths = nil
+		s.contentHashes = nil
+		s.problems = problemReport{}
+		s.ignore = nil
+		s.db = nil
 	}()
 
 	s.scanStart = time.Now()
 	previousCount := s.counter.Load()
+
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+	s.startProgress(cancelCtx)
 	defer func() {
+		// runs after the transaction has been rolled back
+		if s.stopProgress() && err != nil {
+			log.Infof("Scan canceled, changes since the last checkpoint have been rolled back.")
+			err = ErrScanCanceled
+		}
 		if err != nil {
 			s.counter.Store(previousCount)
 		}
 	}()
-	s.counter.Store(0)
-
-	ctx, cancelCtx := context.WithCancel(context.Background())
-	defer cancelCtx()
 
+	s.db = db
 	s.tx, err = db.NewTransaction(ctx)
 	if err != nil {
 		return fmt.Errorf("new transaction: %w", err)
@@ -80,49 +142,96 @@ func (s *Scanner) Scan(db repos.DB, fullScan bool) (err error) {
 	if err != nil && !errors.Is(err, repos.ErrNotFound) {
 		return fmt.Errorf("get last scan: %w", err)
 	}
+	// a full scan of the whole library is required, even if only some paths were requested
+	libraryFullScan := false
 	if errors.Is(err, repos.ErrNotFound) {
 		s.fullScan = true
-		s.firstScan = true
-		log.Infof("No last scan time found: enabling full scan and marking as first scan")
-	} else {
-		songCount, err := s.tx.Song().Count(ctx)
-		if err != nil {
-			return fmt.Errorf("get song count: %w", err)
-		}
-		s.firstScan = songCount == 0
-		if s.firstScan {
-			log.Tracef("detected first scan")
-		}
+		libraryFullScan = true
+		log.Infof("No last scan time found: enabling full scan")
+	}
+	// songs of an interrupted first scan must not be created twice, so the first scan is detected by the song count
+	songCount, err := s.tx.Song().Count(ctx)
+	if err != nil {
+		return fmt.Errorf("get song count: %w", err)
+	}
+	s.firstScan = songCount == 0
+	if s.firstScan {
+		log.Tracef("detected first scan")
 	}
 
 	musicDirConfigChanged, err := s.LoadMusicDirs(s.tx)
 	if err != nil {
 		return fmt.Errorf("load music dirs: %w", err)
 	}
+	if s.firstScan || musicDirConfigChanged {
+		libraryFullScan = true
+	}
 	if musicDirConfigChanged {
 		log.Tracef("music dir config changed, requesting full-scan")
 		s.fullScan = true
 	}
+	s.ignore = newIgnoreMatcher(s.musicDirs, s.logFileError)
 
-	if !s.fullScan {
-		needsFullScan, err := s.tx.System().NeedsFullScan(ctx)
-		if err != nil {
-			return fmt.Errorf("check if full scan is needed: %w", err)
-		}
-		s.fullScan = needsFullScan
+	needsFullScan, err := s.tx.System().NeedsFullScan(ctx)
+	if err != nil {
+		return fmt.Errorf("check if full scan is needed: %w", err)
+	}
+	if needsFullScan {
+		libraryFullScan = true
+		s.fullScan = true
 	}
 	err = s.tx.System().ResetNeedsFullScan(ctx)
 	if err != nil {
 		return fmt.Errorf("reset needs full scan: %w", err)
 	}
 
+	s.previousScan = s.lastScan
 	if s.fullScan || s.firstScan {
 		s.lastScan = time.Time{}
 	}
 
-	log.Infof("Scanning (full scan: %t)...", s.fullScan)
+	if paths != nil && !libraryFullScan {
+		s.targets = s.resolveTargets(paths)
+		if len(s.targets) == 0 {
+			// the checkpoint of a resumed scan without targets must not be resumed again
+			err = s.tx.System().DeleteScanCheckpoint(ctx)
+			if err != nil {
+				return fmt.Errorf("delete scan checkpoint: %w", err)
+			}
+			return s.tx.Commit()
+		}
+		log.Infof("Scanning %d directories (full scan: %t)...", len(s.targets), s.fullScan)
+	} else {
+		log.Infof("Scanning (full scan: %t)...", s.fullScan)
+	}
+
+	// songs saved before the scan was interrupted can only be skipped if the scan still does the same work
+	if resume != nil && !musicDirConfigChanged && !needsFullScan && resume.FullScan == s.fullScan && slices.Equal(resume.Paths, s.targetPaths()) {
+		s.scanStart = resume.Start
+		err = s.loadSavedPaths(ctx)
+		if err != nil {
+			return fmt.Errorf("load saved paths: %w", err)
+		}
+		log.Tracef("skipping %d songs saved before the scan was interrupted", len(s.savedPaths))
+	}
 
-	if s.fullScan {
+	if s.conf.ReadOnlyLibrary {
+		err = s.loadContentHashes(ctx)
+		if err != nil {
+			return fmt.Errorf("load content hashes: %w", err)
+		}
+	}
+
+	err = s.tx.System().SetScanCheckpoint(ctx, repos.ScanCheckpoint{
+		Start:    s.scanStart,
+		FullScan: s.fullScan,
+		Paths:    s.targetPaths(),
+	})
+	if err != nil {
+		return fmt.Errorf("set scan checkpoint: %w", err)
+	}
+
+	if s.fullScan && s.targets == nil {
 		log.Tracef("clearing cover cache...")
 		err = s.coverCache.Clear()
 		if err != nil {
@@ -171,7 +280,7 @@ func (s *Scanner) Scan(db repos.DB, fullScan bool) (err error) {
 			s.setAlbumCoverClosed = true
 			close(s.setAlbumCover)
 		}
-		if err != nil {
+		if err != nil && ctx.Err() == nil {
 			log.Errorf("scan: run save songs loop: %s", err)
 			cancelCtx()
 		}
@@ -186,12 +295,13 @@ func (s *Scanner) Scan(db repos.DB, fullScan bool) (err error) {
 			close(s.setAlbumCover)
 		}
 		setAlbumCovers <- err
-		if err != nil {
+		if err != nil && ctx.Err() == nil {
 			log.Errorf("scan: set album covers loop: %s", err)
 			cancelCtx()
 		}
 	}()
 
+	s.setPhase(PhaseScanningFiles)
 	log.Tracef("scanning media dir with %d workers...", maxParallelDirs)
 	err = s.scanMediaDirs(ctx)
 	close(s.songQueue)
@@ -200,6 +310,7 @@ func (s *Scanner) Scan(db repos.DB, fullScan bool) (err error) {
 		return fmt.Errorf("scan dir: %w", err)
 	}
 
+	s.setPhase(PhaseSavingSongs)
 	log.Tracef("waiting until save songs worker is done...")
 	err = <-saveSongsDone
 	if err != nil {
@@ -211,6 +322,21 @@ func (s *Scanner) Scan(db repos.DB, fullScan bool) (err error) {
 	if err != nil {
 		return fmt.Errorf("run set album covers loop: %w", err)
 	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	err = s.saveProblems(ctx)
+	if err != nil {
+		return fmt.Errorf("save problems: %w", err)
+	}
+
+	s.setPhase(PhaseUpdatingLibrary)
+	log.Tracef("updating directories...")
+	err = s.updateDirectories(ctx)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		return fmt.Errorf("update directories: %w", err)
+	}
 
 	log.Tracef("updating album artists...")
 	err = s.albums.updateArtists(ctx, s)
@@ -224,6 +350,7 @@ func (s *Scanner) Scan(db repos.DB, fullScan bool) (err error) {
 		return fmt.Errorf("update artist music folder associations: %w", err)
 	}
 
+	s.setPhase(PhaseCleaningUp)
 	log.Tracef("deleting orphaned songs/albums/artists...")
 	err = s.deleteOrphaned(ctx)
 	if err != nil {
@@ -263,11 +390,14 @@ func (s *Scanner) Scan(db repos.DB, fullScan bool) (err error) {
 		return fmt.Errorf("fix scrobble metadata: %w", err)
 	}
 
-	err = s.tx.System().SetLastScan(ctx, time.Now())
-	if err != nil {
-		return fmt.Errorf("update last scan: %w", err)
+	if s.targets == nil {
+		err = s.tx.System().SetLastScan(ctx, time.Now())
+		if err != nil {
+			return fmt.Errorf("update last scan: %w", err)
+		}
 	}
 
+	s.setPhase(PhaseArtistImages)
 	waitFindArtistImages.Wait()
 	if findArtistImagesErr != nil {
 		return fmt.Errorf("find artist images: %w", findArtistImagesErr)
@@ -278,19 +408,30 @@ func (s *Scanner) Scan(db repos.DB, fullScan bool) (err error) {
 		return fmt.Errorf("save artist images: %w", err)
 	}
 
+	err = s.tx.System().DeleteScanCheckpoint(ctx)
+	if err != nil {
+		return fmt.Errorf("delete scan checkpoint: %w", err)
+	}
+
+	s.setPhase(PhaseCommitting)
 	log.Tracef("committing changes...")
 	err = s.tx.Commit()
 	if err != nil {
 		return fmt.Errorf("commit tx: %w", err)
 	}
 	log.Infof("Scanned %d files in %s.", s.counter.Load(), time.Since(s.scanStart).Round(time.Millisecond))
+	for _, fn := range s.onScanCompleted {
+		go fn()
+	}
 	return nil
 }
 
 func (s *Scanner) scanMediaDirs(ctx context.Context) error {
 	type dir struct {
-		changed       bool
-		path          string
+		changed bool
+		path    string
+		// file restricts the scan of the directory to a single file if not empty
+		file          string
 		musicFolderId int
 	}
 	dirs := make(chan dir, maxParallelDirs)
@@ -309,9 +450,11 @@ func (s *Scanner) scanMediaDirs(ctx context.Context) error {
 		go func() {
 			defer waitGroup.Done()
 			for dir := range dirs {
-				err := s.scanMediaFilesInDir(ctx, dir.path, dir.changed, dir.musicFolderId)
+				err := s.scanMediaFilesInDir(ctx, dir.path, dir.file, dir.changed, dir.musicFolderId)
 				if err != nil {
-					log.Errorf("scan media dir: %s", err)
+					if !errors.Is(err, context.Canceled) {
+						log.Errorf("scan media dir: %s", err)
+					}
 					if scanDirError == nil {
 						scanDirError = err
 					}
@@ -321,14 +464,25 @@ func (s *Scanner) scanMediaDirs(ctx context.Context) error {
 		}()
 	}
 
-	for _, musicDir := range s.musicDirs {
-		info, err := os.Lstat(musicDir.Path)
+	for _, root := range s.scanRoots() {
+		info, err := os.Lstat(root.path)
 		if err != nil {
+			if s.targets != nil && errors.Is(err, os.ErrNotExist) {
+				// deleted, orphaned songs are removed after scanning
+				continue
+			}
 			return fmt.Errorf("stat media dir: %w", err)
 		}
-		err = s.walkDir(musicDir.Path, fs.FileInfoToDirEntry(info), s.checkIfChanged(musicDir.Path, info), func(path string, d fs.DirEntry, parentChanged bool, err error) error {
+		if s.ignore.ignored(root.path, info.IsDir()) {
+			// excluded, orphaned songs are removed after scanning
+			continue
+		}
+		musicDir := root.musicDir
+		rootPath := root.path
+		err = s.walkDir(root.path, fs.FileInfoToDirEntry(info), s.checkIfChanged(root.path, info), func(path string, d fs.DirEntry, parentChanged bool, err error) error {
 			if err != nil {
-				return err
+				// the directory was already queued, the read error is reported by scanMediaFilesInDir
+				return filepath.SkipDir
 			}
 			select {
 			case <-ctx.Done():
@@ -336,6 +490,15 @@ func (s *Scanner) scanMediaDirs(ctx context.Context) error {
 			default:
 			}
 			if !d.IsDir() {
+				if path == rootPath && !dirsClosed {
+					// the target of the scan is a single file, the rest of its directory is left unchanged
+					dirs <- dir{
+						changed:       parentChanged,
+						path:          filepath.Dir(path),
+						file:          path,
+						musicFolderId: musicDir.ID,
+					}
+				}
 				return nil
 			}
 			if scanDirError != nil {
@@ -344,6 +507,10 @@ func (s *Scanner) scanMediaDirs(ctx context.Context) error {
 			if !s.conf.ScanHidden && d.Name()[0] == '.' {
 				return filepath.SkipDir
 			}
+			if path != rootPath && s.ignore.ignoredInDir(path, true) {
+				return filepath.SkipDir
+			}
+			s.addDirectory(path, musicDir.Path, musicDir.ID)
 			if !dirsClosed {
 				dirs <- dir{
 					changed:       parentChanged || s.checkIfChangedByPath(path),
@@ -377,9 +544,15 @@ func (s *Scanner) walkDir(path string, d fs.DirEntry, parentChanged bool, walkDi
 	if !changed {
 		stat, err := times.Stat(path)
 		if err != nil {
-			return err
+			// skipped, the songs inside of failed directories are kept
+			if !errors.Is(err, os.ErrNotExist) {
+				s.logFileError(path, "stat: %s", err)
+			}
+			return nil
 		}
 		changed = stat.ModTime().After(s.lastScan) || !stat.HasChangeTime() || stat.ChangeTime().After(s.lastScan)
+		// files that were excluded before have to be read again
+		changed = changed || (d.IsDir() && s.ignoreFileChanged(path))
 	}
 	if err := walkDirFn(path, d, changed, nil); err != nil || !d.IsDir() {
 		if errors.Is(err, filepath.SkipDir) && d.IsDir() {
@@ -413,10 +586,14 @@ func (s *Scanner) walkDir(path string, d fs.DirEntry, parentChanged bool, walkDi
 	return nil
 }
 
-func (s *Scanner) scanMediaFilesInDir(ctx context.Context, dir string, changed bool, musicFolderId int) error {
+// scanMediaFilesInDir processes the media files in dir or only file if it is not empty.
+// The other files of dir are still used to find the cover of file.
+func (s *Scanner) scanMediaFilesInDir(ctx context.Context, dir, file string, changed bool, musicFolderId int) error {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
-		return fmt.Errorf("read dir: %w", err)
+		// skipped, the songs inside of failed directories are kept
+		s.logFileError(dir, "read dir: %s", err)
+		return nil
 	}
 
 	var cover *string
@@ -431,6 +608,10 @@ findCoverLoop:
 			continue
 		}
 
+		if s.ignore.ignoredInDir(filepath.Join(dir, e.Name()), false) {
+			continue
+		}
+
 		ext := filepath.Ext(e.Name())
 		fileType := mime.TypeByExtension(ext)
 		if fileType == "image/jpeg" || fileType == "image/png" {
@@ -466,12 +647,22 @@ findCoverLoop:
 		default:
 		}
 
-		err := s.processFile(filepath.Join(dir, e.Name()), cover, prioritizeEmbedded, changed, musicFolderId)
+		path := filepath.Join(dir, e.Name())
+		if file != "" && path != file {
+			continue
+		}
+		if s.ignore.ignoredInDir(path, false) {
+			continue
+		}
+		err := s.processFile(ctx, path, cover, prioritizeEmbedded, changed, musicFolderId)
 		if errors.Is(err, errNotAMediaFile) {
 			continue
 		}
 		if err != nil {
-			return fmt.Errorf("process file: %w", err)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			s.logFileError(path, "process file: %s", err)
 		}
 	}
 	return nil
@@ -479,9 +670,12 @@ findCoverLoop:
 
 var errNotAMediaFile = errors.New("not a media file")
 
-func (s *Scanner) processFile(path string, cover *string, prioritizeEmbeddedCover, parentDirChanged bool, musicFolderId int) error {
+// playlistContentTypes are audio content types of files that do not contain audio.
+var playlistContentTypes = []string{"audio/mpegurl", "audio/x-mpegurl", "audio/x-scpls"}
+
+func (s *Scanner) processFile(ctx context.Context, path string, cover *string, prioritizeEmbeddedCover, parentDirChanged bool, musicFolderId int) error {
 	ext := filepath.Ext(path)
-	if !strings.HasPrefix(mime.TypeByExtension(ext), "audio/") {
+	if !strings.HasPrefix(mime.TypeByExtension(ext), "audio/") || slices.Contains(playlistContentTypes, mime.TypeByExtension(ext)) {
 		return errNotAMediaFile
 	}
 
@@ -498,10 +692,37 @@ func (s *Scanner) processFile(path string, cover *string, prioritizeEmbeddedCove
 	}
 
 	s.counter.Add(1)
+	queued := false
+	defer func() {
+		if !queued {
+			s.processed.Add(1)
+		}
+	}()
+
+	if s.savedBeforeInterruption(path, info) {
+		return nil
+	}
 
 	lyricsPath, lyricsModified := s.findLyricsSidecar(path)
 
-	if !s.fullScan && !parentDirChanged && info.ModTime().Before(s.lastScan) && !lyricsModified {
+	changed := info.ModTime().After(s.lastScan)
+	// the audio of the file may have changed since it was analyzed
+	audioChanged := !s.firstScan && info.ModTime().After(s.previousScan)
+	var fp fingerprint.Fingerprint
+	if s.conf.ReadOnlyLibrary {
+		// mtimes of read-only libraries are not reliable
+		fp, err = fingerprint.File(path)
+		if err != nil {
+			return fmt.Errorf("fingerprint: %w", err)
+		}
+		storedHash, ok := s.contentHashes[path]
+		changed = storedHash != fp.Content
+		// songs without a stored hash were analyzed before the library became read-only
+		audioChanged = ok && changed
+		if !s.fullScan && !parentDirChanged && !changed && !lyricsModified {
+			return nil
+		}
+	} else if !s.fullScan && !parentDirChanged && info.ModTime().Before(s.lastScan) && !lyricsModified {
 		timeStat, err := times.Stat(path)
 		if err != nil {
 			return fmt.Errorf("times stat: %w", err)
@@ -514,15 +735,23 @@ func (s *Scanner) processFile(path string, cover *string, prioritizeEmbeddedCove
 	tags, props, hasImage, err := audiotags.Read(path, prioritizeEmbeddedCover || cover == nil)
 	if err != nil {
 		if errors.Is(err, audiotags.ErrNoMetadata) {
+			s.reportFileProblems(path, musicFolderId, []fileProblem{{typ: repos.ScanProblemUnreadableTags, message: "no metadata found"}})
 			return errNotAMediaFile
 		}
+		s.reportFileProblems(path, musicFolderId, []fileProblem{{typ: repos.ScanProblemUnreadableTags, message: err.Error()}})
 		return fmt.Errorf("read tags: %w", err)
 	}
 
 	if props.IsEmpty() {
+		s.reportFileProblems(path, musicFolderId, []fileProblem{{typ: repos.ScanProblemUnreadableTags, message: "no audio properties found"}})
 		return errNotAMediaFile
 	}
 
+	var problems []fileProblem
+	if props.LengthMs == 0 {
+		problems = append(problems, fileProblem{typ: repos.ScanProblemZeroDuration, message: "duration is zero"})
+	}
+
 	if prioritizeEmbeddedCover && cover != nil && hasImage {
 		cover = nil
 	}
@@ -536,6 +765,7 @@ func (s *Scanner) processFile(path string, cover *string, prioritizeEmbeddedCove
 	title, ok := readSingleTag(tags, "TITLE")
 	if !ok {
 		title = strings.TrimSuffix(filepath.Base(path), ext)
+		problems = append(problems, fileProblem{typ: repos.ScanProblemMissingTitle, message: "no title tag, using the file name"})
 	}
 
 	lyrics := s.scanLyrics(lyricsPath, tags)
@@ -566,18 +796,27 @@ func (s *Scanner) processFile(path string, cover *string, prioritizeEmbeddedCove
 		}
 	}
 
+	if invalidDates := findInvalidDateTags(tags); len(invalidDates) > 0 {
+		problems = append(problems, fileProblem{typ: repos.ScanProblemInvalidDate, message: "invalid date: " + strings.Join(invalidDates, ", ")})
+	}
+
+	s.reportFileProblems(path, musicFolderId, problems)
+
 	albumMBID := readSingleTagOptional(tags, "MUSICBRAINZ_RELEASEGROUPID")
 	releaseMBID := readSingleTagOptional(tags, "MUSICBRAINZ_ALBUMID")
 
 	albumVersion := readSingleTagFirstOptional(tags, "ALBUMVERSION", "VERSION")
 
 	if !s.songQueueClosed {
-		s.songQueue <- &mediaFile{
+		file := &mediaFile{
 			id:                  songID,
 			path:                path,
 			size:                info.Size(),
 			contentType:         contentType,
-			lastModified:        info.ModTime(),
+			changed:             changed,
+			audioChanged:        audioChanged,
+			fingerprint:         fp.Audio,
+			contentHash:         fp.Content,
 			cover:               cover,
 			bitrate:             props.BitRate,
 			channels:            props.Channels,
@@ -610,6 +849,12 @@ func (s *Scanner) processFile(path string, cover *string, prioritizeEmbeddedCove
 			albumVersion:        albumVersion,
 			musicFolderID:       musicFolderId,
 		}
+		select {
+		case s.songQueue <- file:
+			queued = true
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
 
 	return nil
@@ -625,7 +870,7 @@ func (s *Scanner) checkIfChanged(path string, info fs.FileInfo) bool {
 func (s *Scanner) checkIfChangedByPath(path string) bool {
 	stat, err := times.Stat(path)
 	if err != nil {
-		log.Errorf("check if file changed: %s", err)
+		s.logFileError(path, "check if file changed: %s", err)
 		return true
 	}
 	return stat.ModTime().After(s.lastScan) || !stat.HasChangeTime() || stat.ChangeTime().After(s.lastScan)
@@ -711,6 +956,22 @@ func readDateTagFirstOptional(tags map[string][]string, keys ...string) *repos.D
 	return nil
 }
 
+var dateTags = []string{"ORIGINALDATE", "ORIGINALYEAR", "RELEASEDATE", "RELEASEYEAR", "DATE", "YEAR"}
+
+// findInvalidDateTags returns the date tags that cannot be parsed, formatted as KEY="value".
+func findInvalidDateTags(tags map[string][]string) []string {
+	var invalid []string
+	for _, k := range dateTags {
+		for _, v := range tags[k] {
+			_, err := parseDate(v)
+			if err != nil {
+				invalid = append(invalid, fmt.Sprintf("%s=%q", k, v))
+			}
+		}
+	}
+	return invalid
+}
+
 func parseDate(str string) (repos.Date, error) {
 	str = strings.TrimSpace(str)
 
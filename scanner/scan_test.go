@@ -38,3 +38,14 @@ func Test_parseDate(t *testing.T) {
 		})
 	}
 }
+
+func Test_findInvalidDateTags(t *testing.T) {
+	tags := map[string][]string{
+		"DATE":         {"2025-11-03", "someday"},
+		"ORIGINALYEAR": {"?"},
+		"YEAR":         {"2025"},
+		"TITLE":        {"not a date"},
+	}
+	assert.Equal(t, []string{`ORIGINALYEAR="?"`, `DATE="someday"`}, findInvalidDateTags(tags))
+	assert.Empty(t, findInvalidDateTags(map[string][]string{"YEAR": {"1998"}}))
+}
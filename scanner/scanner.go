@@ -16,6 +16,11 @@ import (
 
 var (
 	ErrAlreadyScanning = errors.New("scan already in progress")
+	ErrNotScanning     = errors.New("no scan in progress")
+	ErrScanCanceled    = errors.New("scan canceled")
+
+	ErrUnknownMusicFolder     = errors.New("unknown music folder")
+	ErrPathOutsideMusicFolder = errors.New("path outside of music folder")
 )
 
 type Scanner struct {
@@ -23,21 +28,37 @@ type Scanner struct {
 
 	conf config.Config
 
+	// db is the database of the running scan, tx is replaced with a new transaction of db at every checkpoint
+	db repos.DB
 	tx repos.Transaction
 
 	coverDir string
 
 	coverCache     *cache.Cache
 	transcodeCache *cache.Cache
-
-	scanning  bool
-	counter   atomic.Uint32
-	scanStart time.Time
-	fullScan  bool
+	waveformCache  *cache.Cache
+
+	scanning   bool
+	counter    atomic.Uint32
+	processed  atomic.Uint32
+	errorCount atomic.Uint32
+	scanStart  time.Time
+	fullScan   bool
+
+	// progressLock protects phase, cancelScan, canceled, scanErrors and failedPaths
+	progressLock sync.Mutex
+	phase        Phase
+	cancelScan   context.CancelFunc
+	canceled     bool
+	scanErrors   []ScanError
+	// paths of files and directories with errors during the current scan
+	failedPaths map[string]struct{}
 
 	instanceID string
 	firstScan  bool
 	lastScan   time.Time
+	// previousScan is the time of the last completed scan, unlike lastScan it is not reset for full scans
+	previousScan time.Time
 
 	artists *artistMap
 	albums  *albumMap
@@ -48,9 +69,36 @@ type Scanner struct {
 	setAlbumCoverClosed bool
 
 	musicDirs []config.MusicDir
+
+	// directories found by walkDir during the current scan
+	directories []directory
+
+	// savedPaths contains the files that were already saved before the resumed scan was interrupted
+	savedPaths map[string]struct{}
+
+	// contentHashes contains the stored content hashes of the songs in the targets of the current scan by path,
+	// only loaded for read-only libraries
+	contentHashes map[string]string
+
+	// ignore decides which files of the music dirs are excluded from the current scan
+	ignore *ignoreMatcher
+
+	// pendingCovers counts the album covers that were queued but not saved yet
+	pendingCovers sync.WaitGroup
+
+	problemsLock sync.Mutex
+	// problems found since the last checkpoint
+	problems problemReport
+
+	// targets restricts the current scan to subtrees of the music dirs, nil if all music dirs are scanned
+	targets []scanRoot
+
+	onScanCompleted []func()
+
+	stopWatching context.CancelFunc
 }
 
-func New(db repos.DB, conf config.Config, coverCache *cache.Cache, transcodeCache *cache.Cache) (*Scanner, error) {
+func New(db repos.DB, conf config.Config, coverCache *cache.Cache, transcodeCache *cache.Cache, waveformCache *cache.Cache) (*Scanner, error) {
 	instanceID, err := db.System().InstanceID(context.Background())
 	if err != nil {
 		return nil, fmt.Errorf("get instance id: %w", err)
@@ -59,11 +107,18 @@ func New(db repos.DB, conf config.Config, coverCache *cache.Cache, transcodeCach
 		coverDir:       filepath.Join(conf.DataDir, "covers"),
 		coverCache:     coverCache,
 		transcodeCache: transcodeCache,
+		waveformCache:  waveformCache,
 		instanceID:     instanceID,
 		conf:           conf,
 	}, nil
 }
 
+// OnScanCompleted registers fn to be called in a new goroutine after every successful scan.
+// It must not be called while a scan is running.
+func (s *Scanner) OnScanCompleted(fn func()) {
+	s.onScanCompleted = append(s.onScanCompleted, fn)
+}
+
 func (s *Scanner) Scanning() bool {
 	return s.scanning
 }
@@ -6,20 +6,26 @@ import (
 	"slices"
 	"strings"
 	"sync"
-	"time"
 
 	"github.com/juho05/crossonic-server"
 	"github.com/juho05/crossonic-server/audiotags"
+	"github.com/juho05/crossonic-server/cache"
 	"github.com/juho05/crossonic-server/repos"
 	"github.com/juho05/crossonic-server/util"
 )
 
 type mediaFile struct {
-	id           *string
-	path         string
-	size         int64
-	contentType  string
-	lastModified time.Time
+	id          *string
+	path        string
+	size        int64
+	contentType string
+	// changed is true if the file was modified since the last scan
+	changed bool
+	// audioChanged is true if computed values of the song have to be analyzed again
+	audioChanged bool
+	// fingerprint and contentHash are only set for read-only libraries
+	fingerprint string
+	contentHash string
 
 	cover *string
 
@@ -65,7 +71,10 @@ type song struct {
 	path         string
 	size         int64
 	contentType  string
-	lastModified time.Time
+	changed      bool
+	audioChanged bool
+	fingerprint  string
+	contentHash  string
 
 	bitrate    int
 	channels   int
@@ -98,29 +107,28 @@ func (s *Scanner) runSaveSongsLoop(ctx context.Context) error {
 
 	updateSongFiles := make(chan *song, updateSongFilesWorkerCount)
 	var updateSongFilesWait sync.WaitGroup
-	var updateSongFilesErr error
 	for range updateSongFilesWorkerCount {
 		updateSongFilesWait.Add(1)
 		go func() {
 			defer updateSongFilesWait.Done()
 			for song := range updateSongFiles {
-				// store id
-				err := s.setCrossonicID(song.path, *song.id)
-				if err != nil {
-					if updateSongFilesErr == nil {
-						updateSongFilesErr = fmt.Errorf("write crossonic id to file: %w", err)
+				// store id, songs of read-only libraries are identified by their fingerprint instead
+				if !s.conf.ReadOnlyLibrary {
+					err := s.setCrossonicID(song.path, *song.id)
+					if err != nil {
+						s.logFileError(song.path, "write crossonic id to file: %s", err)
 					}
-					return
 				}
 
 				// clear cache
-				if song.lastModified.After(s.lastScan) {
-					for _, key := range s.transcodeCache.Keys() {
-						if strings.HasPrefix(key, *song.id) {
-							err = s.transcodeCache.DeleteObject(key)
-							if err != nil {
-								updateSongFilesErr = fmt.Errorf("clear cache for song %s: %w", *song.id, err)
-								return
+				if song.changed {
+					for _, c := range []*cache.Cache{s.transcodeCache, s.waveformCache} {
+						for _, key := range c.Keys() {
+							if strings.HasPrefix(key, *song.id) {
+								err := c.DeleteObject(key)
+								if err != nil {
+									s.logError("clear cache for song %s: %s", *song.id, err)
+								}
 							}
 						}
 					}
@@ -128,31 +136,37 @@ func (s *Scanner) runSaveSongsLoop(ctx context.Context) error {
 			}
 		}()
 	}
+	defer func() {
+		close(updateSongFiles)
+		updateSongFilesWait.Wait()
+	}()
 
+	var uncommitted int
 	for song := range s.songQueue {
-		if updateSongFilesErr != nil {
-			break
-		}
 		songs = append(songs, song)
 		if len(songs) == songQueueBatchSize {
 			err := s.createOrUpdateSongs(ctx, songs, updateSongFiles)
 			if err != nil {
 				return fmt.Errorf("create or update songs: %w", err)
 			}
+			uncommitted += len(songs)
 			songs = songs[:0]
-		}
-	}
 
-	if updateSongFilesErr == nil {
-		err := s.createOrUpdateSongs(ctx, songs, updateSongFiles)
-		if err != nil {
-			return fmt.Errorf("create or update songs: %w", err)
+			if uncommitted >= checkpointInterval {
+				err = s.checkpoint(ctx)
+				if err != nil {
+					return fmt.Errorf("checkpoint: %w", err)
+				}
+				uncommitted = 0
+			}
 		}
 	}
 
-	close(updateSongFiles)
-	updateSongFilesWait.Wait()
-	return updateSongFilesErr
+	err := s.createOrUpdateSongs(ctx, songs, updateSongFiles)
+	if err != nil {
+		return fmt.Errorf("create or update songs: %w", err)
+	}
+	return nil
 }
 
 func (s *Scanner) createOrUpdateSongs(ctx context.Context, mediaFiles []*mediaFile, updateSongFiles chan<- *song) error {
@@ -170,7 +184,10 @@ func (s *Scanner) createOrUpdateSongs(ctx context.Context, mediaFiles []*mediaFi
 			path:                      media.path,
 			size:                      media.size,
 			contentType:               media.contentType,
-			lastModified:              media.lastModified,
+			changed:                   media.changed,
+			audioChanged:              media.audioChanged,
+			fingerprint:               media.fingerprint,
+			contentHash:               media.contentHash,
 			bitrate:                   media.bitrate,
 			channels:                  media.channels,
 			sampleRate:                media.sampleRate,
@@ -258,7 +275,7 @@ func (s *Scanner) createOrUpdateSongs(ctx context.Context, mediaFiles []*mediaFi
 		} else {
 			update = append(update, song)
 		}
-		if song.lastModified.After(s.lastScan) {
+		if song.changed {
 			changedCount++
 		}
 	}
@@ -273,6 +290,18 @@ func (s *Scanner) createOrUpdateSongs(ctx context.Context, mediaFiles []*mediaFi
 		return fmt.Errorf("create songs: %w", err)
 	}
 
+	err = s.resetAnalysis(ctx, slices.Concat(create, update))
+	if err != nil {
+		return fmt.Errorf("reset analysis: %w", err)
+	}
+
+	if s.conf.ReadOnlyLibrary {
+		err = s.saveFingerprints(ctx, slices.Concat(create, update))
+		if err != nil {
+			return fmt.Errorf("save fingerprints: %w", err)
+		}
+	}
+
 	for _, s := range update {
 		if !s.hasIDTag {
 			updateSongFiles <- s
@@ -291,13 +320,13 @@ func (s *Scanner) createOrUpdateSongs(ctx context.Context, mediaFiles []*mediaFi
 		changedSongs := make([]*song, 0, changedCount)
 		changedSongIDs := make([]string, 0, changedCount)
 		for _, song := range create {
-			if song.lastModified.After(s.lastScan) {
+			if song.changed {
 				changedSongs = append(changedSongs, song)
 				changedSongIDs = append(changedSongIDs, *song.id)
 			}
 		}
 		for _, song := range update {
-			if song.lastModified.After(s.lastScan) {
+			if song.changed {
 				changedSongs = append(changedSongs, song)
 				changedSongIDs = append(changedSongIDs, *song.id)
 			}
@@ -355,6 +384,7 @@ func (s *Scanner) createOrUpdateSongs(ctx context.Context, mediaFiles []*mediaFi
 		}
 	}
 
+	s.processed.Add(uint32(len(mediaFiles)))
 	return nil
 }
 
@@ -459,39 +489,55 @@ func (s *Scanner) createSongs(ctx context.Context, songs []*song) error {
 		}
 	}
 
+	fingerprintMatches, err := s.findFingerprintMatches(ctx, songs)
+	if err != nil {
+		return fmt.Errorf("find fingerprint matches: %w", err)
+	}
+	// songs of moved files that were already assigned to a file of this batch
+	moved := make(map[string]struct{})
+
 	create := make([]*song, 0, len(songs))
 	update := make([]*song, 0)
 
 songLoop:
-	for _, s := range songs {
-		if id, ok := pathMatches[s.path]; ok {
-			s.id = &id
-			update = append(update, s)
+	for _, song := range songs {
+		if id, ok := pathMatches[song.path]; ok {
+			song.id = &id
+			update = append(update, song)
 			continue
 		}
-		if s.musicBrainzID != nil {
-			if matches, ok := mbidMatches[*s.musicBrainzID]; ok {
+		for _, m := range fingerprintMatches[song.fingerprint] {
+			if _, ok := moved[m.ID]; ok || !s.movedFrom(m, song.path) {
+				continue
+			}
+			moved[m.ID] = struct{}{}
+			song.id = &m.ID
+			update = append(update, song)
+			continue songLoop
+		}
+		if song.musicBrainzID != nil {
+			if matches, ok := mbidMatches[*song.musicBrainzID]; ok {
 				// match by album release MBID
-				if s.albumReleaseMusicBrainzID != nil {
+				if song.albumReleaseMusicBrainzID != nil {
 					for _, m := range matches {
-						if util.EqPtrVals(m.releaseMBID, s.albumReleaseMusicBrainzID) {
-							s.id = &m.id
-							update = append(update, s)
+						if util.EqPtrVals(m.releaseMBID, song.albumReleaseMusicBrainzID) {
+							song.id = &m.id
+							update = append(update, song)
 							continue songLoop
 						}
 					}
 				}
 				// match by album MBID
 				for _, m := range matches {
-					if m.releaseMBID == nil && util.EqPtrVals(m.albumMBID, s.albumMusicBrainzID) {
-						s.id = &m.id
-						update = append(update, s)
+					if m.releaseMBID == nil && util.EqPtrVals(m.albumMBID, song.albumMusicBrainzID) {
+						song.id = &m.id
+						update = append(update, song)
 						continue songLoop
 					}
 				}
 			}
 		}
-		create = append(create, s)
+		create = append(create, song)
 	}
 
 	failed, err := s.updateSongs(ctx, update)
@@ -546,6 +592,25 @@ func (s *Scanner) createSongsInDB(ctx context.Context, songs []*song) error {
 	return nil
 }
 
+// resetAnalysis removes the computed values of songs whose audio changed, so that they are analyzed again.
+func (s *Scanner) resetAnalysis(ctx context.Context, songs []*song) error {
+	ids := make([]string, 0, len(songs))
+	for _, song := range songs {
+		if song.audioChanged {
+			ids = append(ids, *song.id)
+		}
+	}
+	err := s.tx.Song().ResetComputedReplayGain(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("reset computed replay gain: %w", err)
+	}
+	err = s.tx.Song().ResetDetectedBPM(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("reset detected bpm: %w", err)
+	}
+	return nil
+}
+
 func (s *Scanner) setCrossonicID(path, id string) error {
 	success := audiotags.WriteTag(path, "crossonic_id_"+s.instanceID, id)
 	if !success {
@@ -0,0 +1,115 @@
+package scanner
+
+import (
+	"cmp"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/juho05/crossonic-server/config"
+)
+
+// scanRoot is a directory tree that is walked during a scan.
+type scanRoot struct {
+	path     string
+	musicDir config.MusicDir
+}
+
+// scanRoots returns the targets of the current scan or all music dirs if the scan is not targeted.
+func (s *Scanner) scanRoots() []scanRoot {
+	if s.targets != nil {
+		return s.targets
+	}
+	roots := make([]scanRoot, 0, len(s.musicDirs))
+	for _, dir := range s.musicDirs {
+		roots = append(roots, scanRoot{
+			path:     dir.Path,
+			musicDir: dir,
+		})
+	}
+	return roots
+}
+
+// inScanRoots reports whether path is part of one of the trees walked during the current scan.
+func (s *Scanner) inScanRoots(path string) bool {
+	if s.targets == nil {
+		return true
+	}
+	return slices.ContainsFunc(s.targets, func(r scanRoot) bool {
+		return isSubPath(r.path, path)
+	})
+}
+
+// MusicFolderPath returns the absolute path of the file or directory at path relative to the root of the music folder.
+// An empty path returns the root of the music folder. The path does not need to exist.
+// Returns ErrUnknownMusicFolder if no music folder has the ID and ErrPathOutsideMusicFolder if path leaves the music folder.
+func (s *Scanner) MusicFolderPath(musicFolderID int, path string) (string, error) {
+	musicDirs, err := s.conf.GetMusicDirs()
+	if err != nil {
+		return "", fmt.Errorf("music folder path: get music dirs: %w", err)
+	}
+	i := slices.IndexFunc(musicDirs, func(d config.MusicDir) bool {
+		return d.ID == musicFolderID
+	})
+	if i < 0 {
+		return "", ErrUnknownMusicFolder
+	}
+	root := filepath.Clean(musicDirs[i].Path)
+	absPath := filepath.Join(root, path)
+	if !isSubPath(root, absPath) {
+		return "", ErrPathOutsideMusicFolder
+	}
+	return absPath, nil
+}
+
+// targetPaths returns the paths of the targets of the current scan or nil if the scan is not targeted.
+func (s *Scanner) targetPaths() []string {
+	if s.targets == nil {
+		return nil
+	}
+	paths := make([]string, 0, len(s.targets))
+	for _, t := range s.targets {
+		paths = append(paths, t.path)
+	}
+	return paths
+}
+
+// resolveTargets assigns the paths to their music dirs and removes paths outside the music dirs
+// as well as paths that are already contained in other paths.
+func (s *Scanner) resolveTargets(paths []string) []scanRoot {
+	paths = slices.Clone(paths)
+	for i := range paths {
+		paths[i] = filepath.Clean(paths[i])
+	}
+	// parents are shorter than their children
+	slices.SortFunc(paths, func(a, b string) int {
+		return cmp.Compare(len(a), len(b))
+	})
+
+	targets := make([]scanRoot, 0, len(paths))
+	for _, p := range paths {
+		if slices.ContainsFunc(targets, func(t scanRoot) bool { return isSubPath(t.path, p) }) {
+			continue
+		}
+		for _, dir := range s.musicDirs {
+			if isSubPath(filepath.Clean(dir.Path), p) {
+				targets = append(targets, scanRoot{
+					path:     p,
+					musicDir: dir,
+				})
+				break
+			}
+		}
+	}
+	return targets
+}
+
+// isSubPath reports whether path is parent or inside of parent. Both paths must be clean.
+func isSubPath(parent, path string) bool {
+	rel, err := filepath.Rel(parent, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,99 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/juho05/crossonic-server/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_isSubPath(t *testing.T) {
+	tests := []struct {
+		parent string
+		path   string
+		want   bool
+	}{
+		{"/music", "/music", true},
+		{"/music", "/music/artist/album", true},
+		{"/music", "/music2", false},
+		{"/music", "/", false},
+		{"/music/a", "/music/b", false},
+		{"/music", "/music/..album", true},
+		{"/", "/music", true},
+	}
+	for _, tt := range tests {
+		assert.Equalf(t, tt.want, isSubPath(tt.parent, tt.path), "isSubPath(%q, %q)", tt.parent, tt.path)
+	}
+}
+
+func TestScanner_resolveTargets(t *testing.T) {
+	music := config.MusicDir{ID: 1, Path: "/music"}
+	audiobooks := config.MusicDir{ID: 2, Path: "/audiobooks/"}
+	s := &Scanner{
+		musicDirs: []config.MusicDir{music, audiobooks},
+	}
+	targets := s.resolveTargets([]string{
+		"/music/artist/album/",
+		"/music/artist",
+		"/music/artist 2",
+		"/audiobooks/book",
+		"/other/album",
+	})
+	assert.ElementsMatch(t, []scanRoot{
+		{path: "/music/artist", musicDir: music},
+		{path: "/music/artist 2", musicDir: music},
+		{path: "/audiobooks/book", musicDir: audiobooks},
+	}, targets)
+
+	s.targets = targets
+	assert.True(t, s.inScanRoots("/music/artist/album"))
+	assert.False(t, s.inScanRoots("/music/artist 3"))
+	assert.False(t, s.inScanRoots("/music"))
+	assert.ElementsMatch(t, []string{"/music/artist", "/music/artist 2", "/audiobooks/book"}, s.targetPaths())
+
+	s.targets = nil
+	assert.Nil(t, s.targetPaths())
+}
+
+func TestScanner_scanMediaDirs_fileTarget(t *testing.T) {
+	tests := []struct {
+		name     string
+		fullScan bool
+	}{
+		{"quick scan", false},
+		{"full scan", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			musicDir := config.MusicDir{ID: 1, Path: t.TempDir()}
+			album := filepath.Join(musicDir.Path, "album")
+			require.NoError(t, os.Mkdir(album, 0o755))
+			target := filepath.Join(album, "a.mp3")
+			for _, name := range []string{"a.mp3", "b.mp3"} {
+				require.NoError(t, os.WriteFile(filepath.Join(album, name), nil, 0o644))
+			}
+
+			s := &Scanner{
+				musicDirs: []config.MusicDir{musicDir},
+				fullScan:  tt.fullScan,
+			}
+			if !tt.fullScan {
+				s.lastScan = time.Now().Add(-time.Hour)
+			}
+			s.ignore = newIgnoreMatcher(s.musicDirs, s.logFileError)
+			s.startProgress(func() {})
+			s.targets = s.resolveTargets([]string{target})
+			require.Len(t, s.targets, 1)
+
+			require.NoError(t, s.scanMediaDirs(context.Background()))
+			assert.EqualValues(t, 1, s.counter.Load(), "only the target file should be scanned")
+			assert.Equal(t, []string{target}, s.problems.checkedFiles, "the target file should be processed")
+			assert.Empty(t, s.directories, "the directory of the target file is not part of the scan")
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"time"
+
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/juho05/log"
+)
+
+const (
+	// watchDebounce is how long the file system has to be quiet before changed directories are scanned.
+	watchDebounce = 5 * time.Second
+	// maxWatchDelay limits how long changes wait for a scan while files keep changing, e.g. during a large copy.
+	maxWatchDelay = time.Minute
+)
+
+// fsEvent is a change of a file or directory reported by the file system.
+// An fsEvent without a path means that events were lost.
+type fsEvent struct {
+	path  string
+	isDir bool
+	// removed is true if path was deleted or moved away.
+	removed bool
+}
+
+// fsWatcher reports changes of the direct children of watched directories.
+type fsWatcher interface {
+	add(path string) error
+	// remove stops watching path and all watched directories below it.
+	remove(path string)
+	events() <-chan fsEvent
+	close() error
+}
+
+// StartWatching scans changed directories as soon as the file system reports changes if WATCH_MUSIC_DIRS is enabled.
+// File systems that do not report changes, e.g. network mounts, need scheduled scans (SCHEDULE_QUICK_SCAN).
+func (s *Scanner) StartWatching(db repos.DB) {
+	if !s.conf.WatchMusicDirs {
+		return
+	}
+	watcher, err := s.newMusicDirWatcher()
+	if err != nil {
+		log.Errorf("watch music dirs: %s", err)
+		if s.conf.QuickScanSchedule == nil {
+			log.Warnf("Changes will only be detected by manual scans. Configure SCHEDULE_QUICK_SCAN to scan periodically.")
+		}
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stopWatching = cancel
+	go s.watch(ctx, db, watcher)
+}
+
+// StopWatching stops scanning changed directories.
+func (s *Scanner) StopWatching() {
+	if s.stopWatching != nil {
+		s.stopWatching()
+	}
+}
+
+// newMusicDirWatcher watches all directories of the music dirs. Music dirs that cannot be watched are only updated by scheduled and manual scans.
+func (s *Scanner) newMusicDirWatcher() (fsWatcher, error) {
+	musicDirs, err := s.conf.GetMusicDirs()
+	if err != nil {
+		return nil, err
+	}
+	watcher, err := newFSWatcher()
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range musicDirs {
+		err = s.watchTree(watcher, dir.Path)
+		if err != nil {
+			log.Errorf("watch music dir %s: %s", dir.Path, err)
+		}
+	}
+	log.Infof("Watching music dirs for changes.")
+	return watcher, nil
+}
+
+// watchTree watches path and all directories below it that are scanned.
+func (s *Scanner) watchTree(watcher fsWatcher, path string) error {
+	return filepath.WalkDir(path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				// removed in the meantime
+				return nil
+			}
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if !s.conf.ScanHidden && d.Name()[0] == '.' {
+			return filepath.SkipDir
+		}
+		return watcher.add(path)
+	})
+}
+
+func (s *Scanner) watch(ctx context.Context, db repos.DB, watcher fsWatcher) {
+	defer watcher.close()
+	events := watcher.events()
+
+	// changed paths that have not been scanned yet
+	pending := make(map[string]struct{})
+	scanAll := false
+	var firstChange time.Time
+	var debounce <-chan time.Time
+
+	scanning := false
+	scanDone := make(chan []string, 1)
+	startScan := func() {
+		paths := make([]string, 0, len(pending))
+		for p := range pending {
+			paths = append(paths, p)
+		}
+		all := scanAll
+		clear(pending)
+		scanAll = false
+		firstChange = time.Time{}
+		scanning = true
+		go func() {
+			var err error
+			if all {
+				err = s.Scan(db, false)
+			} else {
+				err = s.ScanPaths(db, paths, false)
+			}
+			if errors.Is(err, ErrAlreadyScanning) {
+				// try again once the other scan is done
+				scanDone <- paths
+				return
+			}
+			if err != nil && !errors.Is(err, ErrScanCanceled) {
+				log.Errorf("scan changed music dirs: %s", err)
+			}
+			scanDone <- nil
+		}()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				log.Warnf("Stopped watching music dirs for changes.")
+				events = nil
+				continue
+			}
+			if event.path == "" {
+				log.Warnf("Missed file system events, scanning all music dirs...")
+				scanAll = true
+			} else {
+				// changed ignore files require their directory to be scanned again
+				if !s.conf.ScanHidden && filepath.Base(event.path)[0] == '.' && filepath.Base(event.path) != ignoreFileName {
+					continue
+				}
+				target := event.path
+				if event.isDir {
+					if event.removed {
+						watcher.remove(event.path)
+					} else if err := s.watchTree(watcher, event.path); err != nil {
+						log.Errorf("watch new directory %s: %s", event.path, err)
+					}
+				} else {
+					target = filepath.Dir(event.path)
+				}
+				pending[target] = struct{}{}
+			}
+			if firstChange.IsZero() {
+				firstChange = time.Now()
+			}
+			if !scanning {
+				debounce = time.After(min(watchDebounce, maxWatchDelay-time.Since(firstChange)))
+			}
+		case <-debounce:
+			debounce = nil
+			if !scanning && (scanAll || len(pending) > 0) {
+				startScan()
+			}
+		case retry := <-scanDone:
+			scanning = false
+			for _, p := range retry {
+				pending[p] = struct{}{}
+			}
+			if scanAll || len(pending) > 0 {
+				if firstChange.IsZero() {
+					firstChange = time.Now()
+				}
+				debounce = time.After(watchDebounce)
+			}
+		}
+	}
+}
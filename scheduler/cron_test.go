@@ -0,0 +1,87 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		wantErr bool
+	}{
+		{"every minute", "* * * * *", false},
+		{"macro", "@daily", false},
+		{"macro upper case", "@WEEKLY", false},
+		{"lists ranges and steps", "0,30 8-18/2 1-15 */3 mon-fri", false},
+		{"names", "0 0 * jan,jul sun", false},
+		{"sunday as 7", "0 0 * * 7", false},
+		{"too few fields", "* * * *", true},
+		{"too many fields", "* * * * * *", true},
+		{"unknown macro", "@often", true},
+		{"value out of range", "60 * * * *", true},
+		{"day of month zero", "0 0 0 * *", true},
+		{"invalid range", "0 10-5 * * *", true},
+		{"invalid step", "*/0 * * * *", true},
+		{"invalid name", "0 0 * foo *", true},
+		{"empty list entry", "0, * * * *", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.expr)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expr, s.String())
+		})
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	// Wednesday
+	base := time.Date(2026, time.January, 14, 10, 17, 30, 0, time.UTC)
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"every minute", "* * * * *", base, time.Date(2026, time.January, 14, 10, 18, 0, 0, time.UTC)},
+		{"strictly after", "18 10 * * *", time.Date(2026, time.January, 14, 10, 18, 0, 0, time.UTC), time.Date(2026, time.January, 15, 10, 18, 0, 0, time.UTC)},
+		{"every 15 minutes", "*/15 * * * *", base, time.Date(2026, time.January, 14, 10, 30, 0, 0, time.UTC)},
+		{"every 3 hours", "0 */3 * * *", base, time.Date(2026, time.January, 14, 12, 0, 0, 0, time.UTC)},
+		{"daily", "@daily", base, time.Date(2026, time.January, 15, 0, 0, 0, 0, time.UTC)},
+		{"next weekday", "30 4 * * mon", base, time.Date(2026, time.January, 19, 4, 30, 0, 0, time.UTC)},
+		{"sunday as 7", "0 0 * * 7", base, time.Date(2026, time.January, 18, 0, 0, 0, 0, time.UTC)},
+		{"next month", "0 0 1 * *", base, time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"next year", "0 0 1 jan *", base, time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"leap day", "0 0 29 2 *", base, time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"day of month or day of week", "0 0 20 * fri", base, time.Date(2026, time.January, 16, 0, 0, 0, 0, time.UTC)},
+		{"never", "0 0 30 feb *", base, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, s.Next(tt.from))
+		})
+	}
+}
+
+func TestScheduleNextDST(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data not available: %s", err)
+	}
+	s, err := Parse("30 2 * * *")
+	require.NoError(t, err)
+	// 02:30 does not exist on March 29th 2026 in Berlin
+	next := s.Next(time.Date(2026, time.March, 28, 12, 0, 0, 0, loc))
+	assert.Equal(t, time.Date(2026, time.March, 30, 2, 30, 0, 0, loc), next)
+}
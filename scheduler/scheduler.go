@@ -0,0 +1,194 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/juho05/log"
+)
+
+var (
+	ErrUnknownJob     = errors.New("unknown job")
+	ErrAlreadyRunning = errors.New("job is already running")
+)
+
+// Scheduler runs jobs according to their cron schedules.
+// A job never runs more than once at the same time. Runs that are due while the job is still running are skipped.
+type Scheduler struct {
+	lock sync.Mutex
+	jobs []*job
+
+	started bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+type job struct {
+	name     string
+	schedule *Schedule
+	run      func(ctx context.Context) error
+
+	running      bool
+	lastRun      *time.Time
+	lastDuration time.Duration
+	lastErr      error
+	nextRun      *time.Time
+}
+
+// JobStatus describes the state of a job.
+type JobStatus struct {
+	Name string
+	// Schedule is nil if the job is disabled and only runs when triggered with RunNow.
+	Schedule *Schedule
+	Running  bool
+	// LastRun is the start time of the last completed run.
+	LastRun      *time.Time
+	LastDuration time.Duration
+	// LastError is the error returned by the last completed run.
+	LastError error
+	NextRun   *time.Time
+}
+
+func New() *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Add registers a job. If schedule is nil, the job only runs when triggered with RunNow.
+// Jobs must be added before calling Start.
+func (s *Scheduler) Add(name string, schedule *Schedule, run func(ctx context.Context) error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.started {
+		panic("scheduler: add job " + name + " after start")
+	}
+	s.jobs = append(s.jobs, &job{
+		name:     name,
+		schedule: schedule,
+		run:      run,
+	})
+}
+
+// Start starts running all jobs with a schedule.
+func (s *Scheduler) Start() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.started {
+		return
+	}
+	s.started = true
+	for _, j := range s.jobs {
+		if j.schedule == nil {
+			continue
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.runScheduled(j)
+		}()
+	}
+}
+
+// RunNow runs the job with the given name in the background.
+// Returns ErrUnknownJob if there is no such job and ErrAlreadyRunning if the job is currently running.
+func (s *Scheduler) RunNow(name string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, j := range s.jobs {
+		if j.name != name {
+			continue
+		}
+		if j.running {
+			return ErrAlreadyRunning
+		}
+		j.running = true
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.execute(j)
+		}()
+		return nil
+	}
+	return ErrUnknownJob
+}
+
+// Status returns the state of all jobs in the order they were added.
+func (s *Scheduler) Status() []JobStatus {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	status := make([]JobStatus, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		status = append(status, JobStatus{
+			Name:         j.name,
+			Schedule:     j.schedule,
+			Running:      j.running,
+			LastRun:      j.lastRun,
+			LastDuration: j.lastDuration,
+			LastError:    j.lastErr,
+			NextRun:      j.nextRun,
+		})
+	}
+	return status
+}
+
+// Close cancels running jobs and waits for them to return.
+func (s *Scheduler) Close() error {
+	s.cancel()
+	s.wg.Wait()
+	return nil
+}
+
+func (s *Scheduler) runScheduled(j *job) {
+	for {
+		next := j.schedule.Next(time.Now())
+		if next.IsZero() {
+			log.Warnf("scheduler: %s (%s) never runs", j.name, j.schedule)
+			return
+		}
+		s.lock.Lock()
+		j.nextRun = &next
+		s.lock.Unlock()
+
+		// sleep in intervals to notice clock changes, e.g. after a suspend
+		for wait := time.Until(next); wait > 0; wait = time.Until(next) {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-time.After(min(wait, time.Minute)):
+			}
+		}
+
+		s.lock.Lock()
+		if j.running {
+			s.lock.Unlock()
+			log.Infof("scheduler: skipping %s because it is still running", j.name)
+			continue
+		}
+		j.running = true
+		s.lock.Unlock()
+		s.execute(j)
+	}
+}
+
+// execute runs the job and stores the result. j.running must be set by the caller.
+func (s *Scheduler) execute(j *job) {
+	start := time.Now()
+	log.Tracef("scheduler: running %s...", j.name)
+	err := j.run(s.ctx)
+	if err != nil && s.ctx.Err() == nil {
+		log.Errorf("scheduler: %s: %s", j.name, err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	j.running = false
+	j.lastRun = &start
+	j.lastDuration = time.Since(start)
+	j.lastErr = err
+}
@@ -0,0 +1,69 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedulerRunNow(t *testing.T) {
+	s := New()
+	defer s.Close()
+
+	release := make(chan struct{})
+	jobErr := errors.New("failed")
+	s.Add("job", nil, func(ctx context.Context) error {
+		<-release
+		return jobErr
+	})
+	s.Start()
+
+	assert.ErrorIs(t, s.RunNow("other"), ErrUnknownJob)
+	require.NoError(t, s.RunNow("job"))
+	assert.ErrorIs(t, s.RunNow("job"), ErrAlreadyRunning)
+
+	status := s.Status()
+	require.Len(t, status, 1)
+	assert.Equal(t, "job", status[0].Name)
+	assert.True(t, status[0].Running)
+	assert.Nil(t, status[0].LastRun)
+	assert.Nil(t, status[0].NextRun)
+
+	close(release)
+	assert.Eventually(t, func() bool {
+		return !s.Status()[0].Running
+	}, time.Second, time.Millisecond)
+	status = s.Status()
+	assert.NotNil(t, status[0].LastRun)
+	assert.ErrorIs(t, status[0].LastError, jobErr)
+}
+
+func TestSchedulerNextRun(t *testing.T) {
+	s := New()
+	schedule, err := Parse("@yearly")
+	require.NoError(t, err)
+	s.Add("job", schedule, func(ctx context.Context) error {
+		return nil
+	})
+	s.Start()
+
+	assert.Eventually(t, func() bool {
+		return s.Status()[0].NextRun != nil
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, schedule.Next(time.Now()), *s.Status()[0].NextRun)
+
+	done := make(chan struct{})
+	go func() {
+		_ = s.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Close did not stop waiting jobs")
+	}
+}
@@ -0,0 +1,177 @@
+package similarity
+
+import (
+	"math"
+
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/juho05/crossonic-server/util"
+)
+
+// weights of the individual similarity signals
+const (
+	weightGenre      = 3.0
+	weightArtist     = 1.5
+	weightRelation   = 2.0
+	weightCoOccurred = 3.0
+	weightBPM        = 1.0
+	weightYear       = 1.0
+	weightLastFM     = 2.0
+)
+
+const (
+	// bpmTolerance is the BPM difference at which two tempos are considered unrelated.
+	bpmTolerance = 20.0
+	// yearTolerance is the difference in years at which two release dates are considered unrelated.
+	yearTolerance = 15.0
+)
+
+// jaccard returns the size of the intersection divided by the size of the union of a and b.
+func jaccard(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	var intersection int
+	for k := range a {
+		if _, ok := b[k]; ok {
+			intersection++
+		}
+	}
+	return float64(intersection) / float64(len(a)+len(b)-intersection)
+}
+
+// cosine returns the cosine similarity of the weighted sets a and b.
+func cosine(a, b map[string]int) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for k, va := range a {
+		normA += float64(va * va)
+		if vb, ok := b[k]; ok {
+			dot += float64(va * vb)
+		}
+	}
+	for _, vb := range b {
+		normB += float64(vb * vb)
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// bpmSimilarity returns 1 for identical tempos and 0 for tempos that differ by at least bpmTolerance.
+// Half and double tempos are treated as equivalent because BPM detection is often off by a factor of two.
+func bpmSimilarity(a, b float64) float64 {
+	if a <= 0 || b <= 0 {
+		return 0
+	}
+	diff := min(math.Abs(a-b), math.Abs(a-2*b), math.Abs(2*a-b))
+	return max(0, 1-diff/bpmTolerance)
+}
+
+// yearSimilarity returns 1 for identical years and 0 for years that are at least yearTolerance apart.
+func yearSimilarity(a, b float64) float64 {
+	return max(0, 1-math.Abs(a-b)/yearTolerance)
+}
+
+// logNormalize maps count into the range [0,1] relative to maxCount on a logarithmic scale
+// to prevent a few heavily related entries from hiding all others.
+func logNormalize(count, maxCount int) float64 {
+	if count <= 0 || maxCount <= 0 {
+		return 0
+	}
+	return math.Log1p(float64(count)) / math.Log1p(float64(maxCount))
+}
+
+// songProfile combines the properties of one or more seed songs.
+type songProfile struct {
+	genres  map[string]struct{}
+	artists map[string]struct{}
+	bpm     *float64
+	year    *float64
+}
+
+func newSongProfile(seeds []*repos.CompleteSong) songProfile {
+	p := songProfile{
+		genres:  make(map[string]struct{}),
+		artists: make(map[string]struct{}),
+	}
+	var bpmSum, yearSum float64
+	var bpmCount, yearCount int
+	for _, s := range seeds {
+		if s.SongLists != nil {
+			for _, g := range s.Genres {
+				p.genres[g] = struct{}{}
+			}
+		}
+		for _, a := range songArtists(s) {
+			p.artists[a.ID] = struct{}{}
+		}
+		if s.BPM != nil && *s.BPM > 0 {
+			bpmSum += float64(*s.BPM)
+			bpmCount++
+		}
+		if year, ok := songYear(s); ok {
+			yearSum += float64(year)
+			yearCount++
+		}
+	}
+	if bpmCount > 0 {
+		p.bpm = util.ToPtr(bpmSum / float64(bpmCount))
+	}
+	if yearCount > 0 {
+		p.year = util.ToPtr(yearSum / float64(yearCount))
+	}
+	return p
+}
+
+// score returns the similarity of song to the profile.
+// coOccurrence and lastFMMatch must be in the range [0,1].
+func (p songProfile) score(song *repos.CompleteSong, coOccurrence, lastFMMatch float64) float64 {
+	genres := make(map[string]struct{})
+	if song.SongLists != nil {
+		for _, g := range song.Genres {
+			genres[g] = struct{}{}
+		}
+	}
+	artists := make(map[string]struct{})
+	for _, a := range songArtists(song) {
+		artists[a.ID] = struct{}{}
+	}
+
+	score := weightGenre*jaccard(p.genres, genres) +
+		weightArtist*jaccard(p.artists, artists) +
+		weightCoOccurred*coOccurrence +
+		weightLastFM*lastFMMatch
+	if p.bpm != nil && song.BPM != nil {
+		score += weightBPM * bpmSimilarity(*p.bpm, float64(*song.BPM))
+	}
+	if year, ok := songYear(song); ok && p.year != nil {
+		score += weightYear * yearSimilarity(*p.year, float64(year))
+	}
+	return score
+}
+
+// scoreArtist returns the similarity of candidate to seed.
+// relation, coOccurrence and lastFMMatch must be in the range [0,1].
+func scoreArtist(seed, candidate *repos.ArtistFeatures, relation, coOccurrence, lastFMMatch float64) float64 {
+	score := weightGenre*cosine(seed.Genres, candidate.Genres) +
+		weightRelation*relation +
+		weightCoOccurred*coOccurrence +
+		weightLastFM*lastFMMatch
+	if seed.AvgBPM != nil && candidate.AvgBPM != nil {
+		score += weightBPM * bpmSimilarity(*seed.AvgBPM, *candidate.AvgBPM)
+	}
+	if seed.AvgYear != nil && candidate.AvgYear != nil {
+		score += weightYear * yearSimilarity(*seed.AvgYear, *candidate.AvgYear)
+	}
+	return score
+}
+
+func songYear(song *repos.CompleteSong) (int, bool) {
+	if song.OriginalDate != nil {
+		return song.OriginalDate.Year(), true
+	}
+	if song.ReleaseDate != nil {
+		return song.ReleaseDate.Year(), true
+	}
+	return 0, false
+}
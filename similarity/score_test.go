@@ -0,0 +1,84 @@
+package similarity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJaccard(t *testing.T) {
+	type testCase struct {
+		name string
+		a    []string
+		b    []string
+		want float64
+	}
+	tests := []testCase{
+		{"empty sets should be unrelated", nil, nil, 0},
+		{"disjoint sets should be unrelated", []string{"a"}, []string{"b"}, 0},
+		{"identical sets should be equal", []string{"a", "b"}, []string{"b", "a"}, 1},
+		{"overlapping sets", []string{"a", "b"}, []string{"b", "c"}, 1.0 / 3.0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, jaccard(toSet(tt.a), toSet(tt.b)), 1e-9)
+		})
+	}
+}
+
+func TestCosine(t *testing.T) {
+	type testCase struct {
+		name string
+		a    map[string]int
+		b    map[string]int
+		want float64
+	}
+	tests := []testCase{
+		{"empty maps should be unrelated", nil, map[string]int{"a": 1}, 0},
+		{"disjoint maps should be unrelated", map[string]int{"a": 3}, map[string]int{"b": 2}, 0},
+		{"proportional maps should be equal", map[string]int{"a": 1, "b": 2}, map[string]int{"a": 2, "b": 4}, 1},
+		{"partially overlapping maps", map[string]int{"a": 1, "b": 1}, map[string]int{"a": 1}, 0.7071067811865475},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, cosine(tt.a, tt.b), 1e-9)
+		})
+	}
+}
+
+func TestBPMSimilarity(t *testing.T) {
+	type testCase struct {
+		name string
+		a    float64
+		b    float64
+		want float64
+	}
+	tests := []testCase{
+		{"identical tempos should be equal", 120, 120, 1},
+		{"double tempo should be equal", 70, 140, 1},
+		{"half tempo should be equal", 180, 90, 1},
+		{"close tempos should be similar", 120, 130, 0.5},
+		{"distant tempos should be unrelated", 100, 130, 0},
+		{"unknown tempo should be unrelated", 0, 120, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, bpmSimilarity(tt.a, tt.b), 1e-9)
+		})
+	}
+}
+
+func TestLogNormalize(t *testing.T) {
+	assert.Equal(t, 0.0, logNormalize(0, 10))
+	assert.Equal(t, 0.0, logNormalize(5, 0))
+	assert.Equal(t, 1.0, logNormalize(10, 10))
+	assert.Greater(t, logNormalize(1, 100), 1.0/100)
+}
+
+func toSet(s []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(s))
+	for _, v := range s {
+		set[v] = struct{}{}
+	}
+	return set
+}
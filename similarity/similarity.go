@@ -0,0 +1,442 @@
+package similarity
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juho05/crossonic-server/lastfm"
+	"github.com/juho05/crossonic-server/repos"
+	"github.com/juho05/crossonic-server/util"
+	"github.com/juho05/log"
+)
+
+const (
+	// coOccurrenceWindow is the maximum time between two scrobbles of a user for the songs to be considered related.
+	coOccurrenceWindow = 30 * time.Minute
+
+	songCandidateLimit   = 500
+	artistCandidateLimit = 300
+	coOccurrenceLimit    = 200
+	topSongCandidates    = 200
+
+	lastFMSimilarArtistLimit = 50
+	lastFMTopTrackLimit      = 50
+	// lastFMSongSources is the number of last.fm similar artists whose top songs are considered as similar song candidates.
+	lastFMSongSources   = 5
+	lastFMCacheDuration = 24 * time.Hour
+
+	// minArtistScore filters out artists that share only marginal properties with the seed artist.
+	minArtistScore = 0.5
+	// similarArtistSongSources is the number of similar artists whose songs are mixed into the similar songs of an artist.
+	similarArtistSongSources = 10
+)
+
+// Similarity finds similar songs and artists based on the metadata, scrobbles and playlists in the library.
+// If last.fm is configured, its similar artists and top tracks are used to boost the local results.
+type Similarity struct {
+	db     repos.DB
+	lastFM *lastfm.LastFm
+
+	lastFMCache     map[string]lastFMCacheEntry
+	lastFMCacheLock sync.Mutex
+}
+
+type lastFMCacheEntry struct {
+	created time.Time
+	value   any
+}
+
+// New creates a new similarity service. lastFM may be nil.
+func New(db repos.DB, lastFM *lastfm.LastFm) *Similarity {
+	return &Similarity{
+		db:          db,
+		lastFM:      lastFM,
+		lastFMCache: make(map[string]lastFMCacheEntry),
+	}
+}
+
+type scoredID struct {
+	id    string
+	score float64
+}
+
+// SimilarSongs returns the ids of up to count songs in musicFolderIDs that are similar to the seed songs, most similar first.
+// The seed songs are not part of the result.
+func (s *Similarity) SimilarSongs(ctx context.Context, seedIDs []string, musicFolderIDs []int, count int) ([]string, error) {
+	seeds, err := s.db.Song().FindByIDs(ctx, seedIDs, repos.IncludeSongInfo{Lists: true})
+	if err != nil {
+		return nil, fmt.Errorf("similar songs: find seeds: %w", err)
+	}
+	if len(seeds) == 0 {
+		return nil, fmt.Errorf("similar songs: %w", repos.ErrNotFound)
+	}
+	profile := newSongProfile(seeds)
+
+	candidateIDs, err := s.db.Similarity().FindSongCandidates(ctx, util.MapKeys(profile.genres), util.MapKeys(profile.artists), musicFolderIDs, songCandidateLimit)
+	if err != nil {
+		return nil, fmt.Errorf("similar songs: find candidates: %w", err)
+	}
+
+	coOccurrences, err := s.db.Similarity().GetSongCoOccurrences(ctx, seedIDs, coOccurrenceWindow, musicFolderIDs, coOccurrenceLimit)
+	if err != nil {
+		return nil, fmt.Errorf("similar songs: get co-occurrences: %w", err)
+	}
+	coOccurrenceCounts, maxCoOccurrences := countMap(coOccurrences)
+	for _, c := range coOccurrences {
+		candidateIDs = append(candidateIDs, c.ID)
+	}
+
+	var lastFMMatches map[string]float64
+	if artist := primaryArtist(seeds[0]); artist != nil {
+		lastFMMatches = s.lastFMArtistMatches(ctx, artist.Name, artist.MusicBrainzID, musicFolderIDs)
+		for _, id := range topKeys(lastFMMatches, lastFMSongSources) {
+			topSongs, err := s.db.Similarity().GetArtistTopSongs(ctx, id, musicFolderIDs, 10)
+			if err != nil {
+				return nil, fmt.Errorf("similar songs: get top songs of last.fm similar artist: %w", err)
+			}
+			for _, t := range topSongs {
+				candidateIDs = append(candidateIDs, t.ID)
+			}
+		}
+	}
+
+	slices.Sort(candidateIDs)
+	candidateIDs = slices.Compact(candidateIDs)
+	candidates, err := s.db.Song().FindByIDs(ctx, candidateIDs, repos.IncludeSongInfo{Lists: true})
+	if err != nil {
+		return nil, fmt.Errorf("similar songs: find candidates: %w", err)
+	}
+
+	scores := make([]scoredID, 0, len(candidates))
+	for _, c := range candidates {
+		if slices.Contains(seedIDs, c.ID) || c.MusicFolderID == nil || !slices.Contains(musicFolderIDs, *c.MusicFolderID) {
+			continue
+		}
+		var lastFMMatch float64
+		for _, a := range songArtists(c) {
+			lastFMMatch = max(lastFMMatch, lastFMMatches[a.ID])
+		}
+		scores = append(scores, scoredID{
+			id:    c.ID,
+			score: profile.score(c, logNormalize(coOccurrenceCounts[c.ID], maxCoOccurrences), lastFMMatch),
+		})
+	}
+	return bestIDs(scores, count), nil
+}
+
+// SimilarArtists returns the ids of up to count artists in musicFolderIDs that are similar to the artist, most similar first.
+func (s *Similarity) SimilarArtists(ctx context.Context, artistID string, musicFolderIDs []int, count int) ([]string, error) {
+	seedFeatures, err := s.db.Similarity().GetArtistFeatures(ctx, []string{artistID}, musicFolderIDs)
+	if err != nil {
+		return nil, fmt.Errorf("similar artists: get seed features: %w", err)
+	}
+	if len(seedFeatures) == 0 {
+		return nil, fmt.Errorf("similar artists: %w", repos.ErrNotFound)
+	}
+	seed := seedFeatures[0]
+
+	candidateIDs, err := s.db.Similarity().FindArtistCandidates(ctx, util.MapKeys(seed.Genres), musicFolderIDs, artistCandidateLimit)
+	if err != nil {
+		return nil, fmt.Errorf("similar artists: find candidates: %w", err)
+	}
+
+	relations, err := s.db.Similarity().GetArtistRelations(ctx, artistID, musicFolderIDs)
+	if err != nil {
+		return nil, fmt.Errorf("similar artists: get relations: %w", err)
+	}
+	relationCounts, maxRelations := countMap(relations)
+	for _, r := range relations {
+		candidateIDs = append(candidateIDs, r.ID)
+	}
+
+	coOccurrences, err := s.db.Similarity().GetArtistCoOccurrences(ctx, artistID, coOccurrenceWindow, musicFolderIDs, coOccurrenceLimit)
+	if err != nil {
+		return nil, fmt.Errorf("similar artists: get co-occurrences: %w", err)
+	}
+	coOccurrenceCounts, maxCoOccurrences := countMap(coOccurrences)
+	for _, c := range coOccurrences {
+		candidateIDs = append(candidateIDs, c.ID)
+	}
+
+	lastFMMatches := s.lastFMArtistMatches(ctx, seed.Name, seed.MusicBrainzID, musicFolderIDs)
+	for id := range lastFMMatches {
+		candidateIDs = append(candidateIDs, id)
+	}
+
+	slices.Sort(candidateIDs)
+	candidateIDs = slices.Compact(candidateIDs)
+	candidateIDs = slices.DeleteFunc(candidateIDs, func(id string) bool {
+		return id == artistID
+	})
+	candidates, err := s.db.Similarity().GetArtistFeatures(ctx, candidateIDs, musicFolderIDs)
+	if err != nil {
+		return nil, fmt.Errorf("similar artists: get candidate features: %w", err)
+	}
+
+	scores := make([]scoredID, 0, len(candidates))
+	for _, c := range candidates {
+		score := scoreArtist(seed, c, logNormalize(relationCounts[c.ID], maxRelations), logNormalize(coOccurrenceCounts[c.ID], maxCoOccurrences), lastFMMatches[c.ID])
+		if score < minArtistScore {
+			continue
+		}
+		scores = append(scores, scoredID{
+			id:    c.ID,
+			score: score,
+		})
+	}
+	return bestIDs(scores, count), nil
+}
+
+// TopSongs returns the ids of up to count of the most popular songs of the artist in musicFolderIDs.
+// Popularity is based on the play count of all users combined with the last.fm top tracks of the artist.
+func (s *Similarity) TopSongs(ctx context.Context, artistID string, musicFolderIDs []int, count int) ([]string, error) {
+	topSongs, err := s.db.Similarity().GetArtistTopSongs(ctx, artistID, musicFolderIDs, topSongCandidates)
+	if err != nil {
+		return nil, fmt.Errorf("top songs: %w", err)
+	}
+	if s.lastFM == nil || len(topSongs) == 0 {
+		return bestIDs(util.Map(topSongs, func(t *repos.SimilarityCount) scoredID {
+			return scoredID{id: t.ID, score: float64(t.Count)}
+		}), count), nil
+	}
+
+	features, err := s.db.Similarity().GetArtistFeatures(ctx, []string{artistID}, musicFolderIDs)
+	if err != nil {
+		return nil, fmt.Errorf("top songs: get artist features: %w", err)
+	}
+	var lastFMRanks map[string]float64
+	if len(features) > 0 {
+		lastFMRanks = s.lastFMTopTrackRanks(ctx, features[0].Name, features[0].MusicBrainzID)
+	}
+
+	songs, err := s.db.Song().FindByIDs(ctx, util.Map(topSongs, func(t *repos.SimilarityCount) string {
+		return t.ID
+	}), repos.IncludeSongInfoBare())
+	if err != nil {
+		return nil, fmt.Errorf("top songs: find songs: %w", err)
+	}
+	titles := make(map[string]string, len(songs))
+	for _, song := range songs {
+		titles[song.ID] = normalizeName(song.Title)
+	}
+
+	playCounts, maxPlayCount := countMap(topSongs)
+	scores := make([]scoredID, 0, len(topSongs))
+	for _, t := range topSongs {
+		scores = append(scores, scoredID{
+			id:    t.ID,
+			score: logNormalize(playCounts[t.ID], maxPlayCount) + weightLastFM*lastFMRanks[titles[t.ID]],
+		})
+	}
+	return bestIDs(scores, count), nil
+}
+
+// SimilarSongsForArtist returns the ids of up to count songs in musicFolderIDs by the artist and similar artists in random order.
+func (s *Similarity) SimilarSongsForArtist(ctx context.Context, artistID string, musicFolderIDs []int, count int) ([]string, error) {
+	own, err := s.TopSongs(ctx, artistID, musicFolderIDs, count)
+	if err != nil {
+		return nil, fmt.Errorf("similar songs for artist: %w", err)
+	}
+
+	similarArtists, err := s.SimilarArtists(ctx, artistID, musicFolderIDs, similarArtistSongSources)
+	if err != nil && !errors.Is(err, repos.ErrNotFound) {
+		return nil, fmt.Errorf("similar songs for artist: %w", err)
+	}
+
+	ownCount := min(len(own), (count+2)/3)
+	ids := slices.Clone(own[:ownCount])
+	if len(similarArtists) > 0 {
+		perArtist := max(1, (count-ownCount+len(similarArtists)-1)/len(similarArtists))
+		for _, a := range similarArtists {
+			topSongs, err := s.db.Similarity().GetArtistTopSongs(ctx, a, musicFolderIDs, perArtist)
+			if err != nil {
+				return nil, fmt.Errorf("similar songs for artist: get top songs of similar artist: %w", err)
+			}
+			for _, t := range topSongs {
+				if !slices.Contains(ids, t.ID) {
+					ids = append(ids, t.ID)
+				}
+			}
+			if len(ids) >= count {
+				break
+			}
+		}
+	}
+	for _, id := range own[ownCount:] {
+		if len(ids) >= count {
+			break
+		}
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+
+	ids = ids[:min(len(ids), count)]
+	rand.Shuffle(len(ids), func(i, j int) {
+		ids[i], ids[j] = ids[j], ids[i]
+	})
+	return ids, nil
+}
+
+// lastFMArtistMatches returns the ids of local artists in musicFolderIDs that are similar to the artist according to last.fm
+// together with their similarity in the range [0,1].
+func (s *Similarity) lastFMArtistMatches(ctx context.Context, name string, mbid *string, musicFolderIDs []int) map[string]float64 {
+	if s.lastFM == nil {
+		return nil
+	}
+	similar, err := cached(s, "similar:"+normalizeName(name), func() ([]lastfm.SimilarArtist, error) {
+		return s.lastFM.GetSimilarArtists(ctx, name, mbid, lastFMSimilarArtistLimit)
+	})
+	if err != nil {
+		if !errors.Is(err, lastfm.ErrNotFound) {
+			log.Errorf("similarity: %s", err)
+		}
+		return nil
+	}
+	if len(similar) == 0 {
+		return nil
+	}
+
+	matchesByName := make(map[string]float64, len(similar))
+	names := make([]string, 0, len(similar))
+	for _, a := range similar {
+		matchesByName[normalizeName(a.Name)] = a.Match
+		names = append(names, a.Name)
+	}
+	artists, err := s.db.Artist().FindByNames(ctx, names, repos.IncludeArtistInfoBare())
+	if err != nil {
+		log.Errorf("similarity: find last.fm similar artists: %s", err)
+		return nil
+	}
+	if len(artists) == 0 {
+		return nil
+	}
+	artists, err = s.db.Artist().FindAll(ctx, repos.FindArtistsParams{
+		IDs: util.Map(artists, func(a *repos.CompleteArtist) string {
+			return a.ID
+		}),
+		MusicFolderIDs: musicFolderIDs,
+	}, repos.IncludeArtistInfoBare())
+	if err != nil {
+		log.Errorf("similarity: filter last.fm similar artists by music folders: %s", err)
+		return nil
+	}
+	matches := make(map[string]float64, len(artists))
+	for _, a := range artists {
+		matches[a.ID] = matchesByName[normalizeName(a.Name)]
+	}
+	return matches
+}
+
+// lastFMTopTrackRanks maps the normalized titles of the last.fm top tracks of the artist to a score in the range (0,1]
+// that is highest for the most popular track.
+func (s *Similarity) lastFMTopTrackRanks(ctx context.Context, name string, mbid *string) map[string]float64 {
+	tracks, err := cached(s, "top:"+normalizeName(name), func() ([]lastfm.TopTrack, error) {
+		return s.lastFM.GetArtistTopTracks(ctx, name, mbid, lastFMTopTrackLimit)
+	})
+	if err != nil {
+		if !errors.Is(err, lastfm.ErrNotFound) {
+			log.Errorf("similarity: %s", err)
+		}
+		return nil
+	}
+	ranks := make(map[string]float64, len(tracks))
+	for i, t := range tracks {
+		title := normalizeName(t.Name)
+		if _, ok := ranks[title]; !ok {
+			ranks[title] = 1 - float64(i)/float64(len(tracks))
+		}
+	}
+	return ranks
+}
+
+// cached returns the cached result of fn for key or calls fn and caches its result for lastFMCacheDuration.
+func cached[T any](s *Similarity, key string, fn func() (T, error)) (T, error) {
+	s.lastFMCacheLock.Lock()
+	entry, ok := s.lastFMCache[key]
+	s.lastFMCacheLock.Unlock()
+	if ok && time.Since(entry.created) < lastFMCacheDuration {
+		return entry.value.(T), nil
+	}
+
+	value, err := fn()
+	if err != nil {
+		return value, err
+	}
+
+	s.lastFMCacheLock.Lock()
+	defer s.lastFMCacheLock.Unlock()
+	for k, e := range s.lastFMCache {
+		if time.Since(e.created) >= lastFMCacheDuration {
+			delete(s.lastFMCache, k)
+		}
+	}
+	s.lastFMCache[key] = lastFMCacheEntry{
+		created: time.Now(),
+		value:   value,
+	}
+	return value, nil
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+func primaryArtist(song *repos.CompleteSong) *repos.ArtistRef {
+	if song.SongLists == nil {
+		return nil
+	}
+	if len(song.AlbumArtists) > 0 {
+		return &song.AlbumArtists[0]
+	}
+	if len(song.Artists) > 0 {
+		return &song.Artists[0]
+	}
+	return nil
+}
+
+func songArtists(song *repos.CompleteSong) []repos.ArtistRef {
+	if song.SongLists == nil {
+		return nil
+	}
+	return append(slices.Clone(song.Artists), song.AlbumArtists...)
+}
+
+func countMap(counts []*repos.SimilarityCount) (map[string]int, int) {
+	m := make(map[string]int, len(counts))
+	var maxCount int
+	for _, c := range counts {
+		m[c.ID] += c.Count
+		maxCount = max(maxCount, m[c.ID])
+	}
+	return m, maxCount
+}
+
+// bestIDs returns the ids of the count highest scores in descending order.
+func bestIDs(scores []scoredID, count int) []string {
+	slices.SortStableFunc(scores, func(a, b scoredID) int {
+		return cmp.Compare(b.score, a.score)
+	})
+	scores = scores[:min(len(scores), count)]
+	ids := make([]string, len(scores))
+	for i, s := range scores {
+		ids[i] = s.id
+	}
+	return ids
+}
+
+// topKeys returns the keys of the count highest values of m.
+func topKeys(m map[string]float64, count int) []string {
+	scores := make([]scoredID, 0, len(m))
+	for k, v := range m {
+		scores = append(scores, scoredID{id: k, score: v})
+	}
+	return bestIDs(scores, count)
+}
@@ -0,0 +1,55 @@
+package util
+
+import (
+	"context"
+	"sync"
+)
+
+// ForEachParallel calls fn for every element in items with at most workers calls running at the same time.
+// No further calls are started after a call returned an error or ctx was canceled.
+// Returns the first error returned by fn or ctx.Err().
+func ForEachParallel[T any](ctx context.Context, items []T, workers int, fn func(item T) error) error {
+	queue := make(chan T)
+	var errLock sync.Mutex
+	var firstErr error
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range queue {
+				err := fn(item)
+				if err != nil {
+					errLock.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					errLock.Unlock()
+				}
+			}
+		}()
+	}
+
+loop:
+	for _, item := range items {
+		errLock.Lock()
+		failed := firstErr != nil
+		errLock.Unlock()
+		if failed {
+			break
+		}
+		select {
+		case queue <- item:
+		case <-ctx.Done():
+			break loop
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,63 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForEachParallel(t *testing.T) {
+	t.Run("calls fn for every item", func(t *testing.T) {
+		var lock sync.Mutex
+		var got []int
+		err := ForEachParallel(context.Background(), []int{1, 2, 3, 4, 5}, 2, func(item int) error {
+			lock.Lock()
+			defer lock.Unlock()
+			got = append(got, item)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []int{1, 2, 3, 4, 5}, got)
+	})
+
+	t.Run("limits concurrent calls", func(t *testing.T) {
+		var running, maxRunning atomic.Int32
+		err := ForEachParallel(context.Background(), make([]int, 50), 3, func(int) error {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				m := maxRunning.Load()
+				if n <= m || maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, maxRunning.Load(), int32(3))
+	})
+
+	t.Run("stops after error", func(t *testing.T) {
+		errTest := errors.New("test")
+		var calls atomic.Int32
+		err := ForEachParallel(context.Background(), make([]int, 100), 1, func(int) error {
+			calls.Add(1)
+			return errTest
+		})
+		assert.ErrorIs(t, err, errTest)
+		assert.Less(t, calls.Load(), int32(100))
+	})
+
+	t.Run("returns context error", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := ForEachParallel(ctx, make([]int, 100), 1, func(int) error {
+			return nil
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}